@@ -0,0 +1,73 @@
+package cmd
+
+import (
+    "fmt"
+    "github.com/keweegen/notification/internal/channel/email"
+    "github.com/spf13/cobra"
+    "os"
+    "strings"
+)
+
+// txtStringLimit is the maximum length of a character-string of a DNS TXT record.
+const txtStringLimit = 255
+
+var (
+    dkimAlgorithm string
+    dkimKeyFile   string
+)
+
+func init() {
+    dkimKeygenCommand.Flags().StringVar(&dkimAlgorithm, "algorithm", email.DKIMAlgorithmRSA, "rsa-sha256 or ed25519-sha256")
+    dkimKeygenCommand.Flags().StringVar(&dkimKeyFile, "out", "", "private key file, <domain>.pem by default")
+    rootCmd.AddCommand(dkimKeygenCommand)
+}
+
+var dkimKeygenCommand = &cobra.Command{
+    Use:     "dkim:keygen <domain> <selector>",
+    Short:   "Generate a DKIM key pair and print the DNS TXT record",
+    Args:    cobra.ExactArgs(2),
+    Example: "dkim:keygen keweegen.github.io ns2026 --algorithm ed25519-sha256",
+    RunE: func(_ *cobra.Command, args []string) error {
+        domain, selector := args[0], args[1]
+
+        keyFile := dkimKeyFile
+        if keyFile == "" {
+            keyFile = domain + ".pem"
+        }
+
+        privateKey, record, err := email.GenerateDKIMKey(dkimAlgorithm)
+        if err != nil {
+            return err
+        }
+
+        f, err := os.OpenFile(keyFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+        if err != nil {
+            return fmt.Errorf("create key file: %w", err)
+        }
+        defer f.Close()
+
+        if _, err = f.Write(privateKey); err != nil {
+            return fmt.Errorf("write key file: %w", err)
+        }
+
+        fmt.Printf("Private key written to %s\n\n", keyFile)
+        fmt.Printf("%s._domainkey.%s. IN TXT %s\n", selector, domain, quoteTXT(record))
+
+        return nil
+    },
+}
+
+// quoteTXT splits the record into quoted strings of the length allowed by DNS.
+func quoteTXT(record string) string {
+    parts := make([]string, 0, len(record)/txtStringLimit+1)
+    for len(record) > txtStringLimit {
+        parts = append(parts, `"`+record[:txtStringLimit]+`"`)
+        record = record[txtStringLimit:]
+    }
+    parts = append(parts, `"`+record+`"`)
+
+    if len(parts) == 1 {
+        return parts[0]
+    }
+    return "( " + strings.Join(parts, " ") + " )"
+}
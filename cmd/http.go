@@ -8,6 +8,7 @@ import (
 	"github.com/keweegen/notification/internal/repository"
 	"github.com/keweegen/notification/internal/server/http"
 	"github.com/keweegen/notification/internal/service"
+	"github.com/keweegen/notification/internal/webhook"
 	"github.com/keweegen/notification/shutdown"
 	"github.com/spf13/cobra"
 )
@@ -39,11 +40,32 @@ var httpServerCommand = &cobra.Command{
 		s.AddHandler("close app connections", app.Close)
 
 		repositoryStore := repository.NewStore(app.CurrentDatabase(), app.CurrentMessageBroker())
-		channelStore := channel.NewStore(cfg.NotificationChannels)
-		serviceStore := service.NewStore(l, repositoryStore, channelStore)
+		channelStore, err := channel.NewStore(cfg.NotificationChannels)
+		if err != nil {
+			return fmt.Errorf("channel store: %w", err)
+		}
+		s.AddHandler("close channel connections", channelStore.Close)
+		serviceStore := service.NewStore(l, cfg, repositoryStore, channelStore, webhook.New(cfg.Webhooks))
+		if err = serviceStore.Template.LoadDirectory(); err != nil {
+			return fmt.Errorf("load templates: %w", err)
+		}
+		if err = serviceStore.Document.Load(); err != nil {
+			return fmt.Errorf("load documents: %w", err)
+		}
 
 		go serviceStore.Message.HandleMessages(ctx, quit)
 		go serviceStore.MessageChecker.Do(ctx, quit)
+		go serviceStore.Mailbox.Do(ctx, quit)
+		go serviceStore.Template.WatchDirectory(quit)
+
+		switch cfg.NotificationChannels.Telegram.Updates.Mode {
+		case "polling":
+			go serviceStore.Telegram.PollUpdates(ctx, quit)
+		case "webhook":
+			if err := serviceStore.Telegram.SetWebhook(); err != nil {
+				return fmt.Errorf("set telegram webhook: %w", err)
+			}
+		}
 
 		httpServer := http.NewServer(serviceStore)
 		s.AddHandler("close http server connection", httpServer.Close)
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+    "context"
+    "fmt"
+    "github.com/keweegen/notification/config"
+    "github.com/keweegen/notification/internal/app"
+    "github.com/keweegen/notification/internal/messagetemplate"
+    "github.com/keweegen/notification/internal/repository"
+    "github.com/keweegen/notification/internal/service"
+    "github.com/spf13/cobra"
+    "sort"
+)
+
+var (
+    templatesLintDir string
+    templatesLintDB  bool
+)
+
+func init() {
+    templatesLintCommand.Flags().StringVar(&templatesLintDir, "dir", "", "template directory, templates.dir of the config by default")
+    templatesLintCommand.Flags().BoolVar(&templatesLintDB, "db", false, "lint the current versions of the database templates too")
+    rootCmd.AddCommand(templatesLintCommand)
+}
+
+var templatesLintCommand = &cobra.Command{
+    Use:     "templates:lint",
+    Short:   "Render every template for every channel and locale with its example params",
+    Args:    cobra.NoArgs,
+    Example: "templates:lint --dir ./templates --db",
+    // The problems are not usage errors.
+    SilenceUsage: true,
+    RunE: func(_ *cobra.Command, _ []string) error {
+        dir := templatesLintDir
+        if dir == "" {
+            dir = cfg.Templates.Dir
+        }
+
+        var (
+            templates []messagetemplate.LintTemplate
+            errs      map[string]error
+            err       error
+        )
+        if templatesLintDB {
+            templates, errs, err = lintDatabaseTemplates(dir)
+        } else {
+            templates, errs, err = lintDirectoryTemplates(dir)
+        }
+        if err != nil {
+            return err
+        }
+
+        failures := 0
+
+        names := make([]string, 0, len(errs))
+        for name := range errs {
+            names = append(names, name)
+        }
+        sort.Strings(names)
+        for _, name := range names {
+            fmt.Printf("%s: %s\n", name, errs[name])
+            failures++
+        }
+
+        for _, tmpl := range templates {
+            for _, err := range messagetemplate.Lint(tmpl) {
+                fmt.Println(err)
+                failures++
+            }
+        }
+
+        if failures > 0 {
+            return fmt.Errorf("%d problems found in the templates", failures)
+        }
+
+        fmt.Printf("%d templates are fine\n", len(templates))
+        return nil
+    },
+}
+
+// lintDirectoryTemplates returns the compiled templates and the ones of the directory unless it is empty,
+// with the problems of the directory templates failing to load by name.
+func lintDirectoryTemplates(dir string) ([]messagetemplate.LintTemplate, map[string]error, error) {
+    if dir == "" {
+        return messagetemplate.LintTemplates(nil, nil), nil, nil
+    }
+
+    directory := messagetemplate.NewDirectory(dir)
+    errs, err := directory.Reload()
+    if err != nil {
+        return nil, nil, err
+    }
+    return messagetemplate.LintTemplates(directory, nil), errs, nil
+}
+
+// lintDatabaseTemplates returns the templates the service renders messages with, the current versions
+// of the database templates included.
+func lintDatabaseTemplates(dir string) ([]messagetemplate.LintTemplate, map[string]error, error) {
+    app := app.New(cfg, l)
+    if err := app.OpenConnectDatabase(); err != nil {
+        return nil, nil, err
+    }
+    defer func() {
+        if err := app.CloseConnectDatabase(); err != nil {
+            app.Logger.Error("failed to close connection database", "error", err)
+        }
+    }()
+
+    repositoryStore := repository.NewStore(app.CurrentDatabase(), nil)
+    templates := service.NewTemplate(l, config.Templates{Dir: dir}, repositoryStore.Template)
+    return templates.LintTemplates(context.Background())
+}
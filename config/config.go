@@ -1,11 +1,22 @@
 package config
 
-import "github.com/spf13/viper"
+import (
+    "github.com/spf13/viper"
+    "time"
+)
 
 type Config struct {
     Database             Database             `yaml:"database"`
     MessageBroker        MessageBroker        `yaml:"messageBroker"`
     NotificationChannels NotificationChannels `yaml:"notificationChannels"`
+    Webhooks             Webhooks             `yaml:"webhooks"`
+    Actions              Actions              `yaml:"actions"`
+    Files                Files                `yaml:"files"`
+    Bounces              Bounces              `yaml:"bounces"`
+    Unsubscribe          Unsubscribe          `yaml:"unsubscribe"`
+    Templates            Templates            `yaml:"templates"`
+    Locales              Locales              `yaml:"locales"`
+    Documents            Documents            `yaml:"documents"`
 }
 
 type Database struct {
@@ -27,16 +38,135 @@ type NotificationChannels struct {
 }
 
 type Telegram struct {
-    Host   string `yaml:"host"`
-    APIKey string `yaml:"apiKey"`
+    Host    string          `yaml:"host"`
+    APIKey  string          `yaml:"apiKey"`
+    BotName string          `yaml:"botName"`
+    Link    TelegramLink    `yaml:"link"`
+    Updates TelegramUpdates `yaml:"updates"`
+}
+
+type TelegramLink struct {
+    Secret string        `yaml:"secret"`
+    TTL    time.Duration `yaml:"ttl"`
+}
+
+type TelegramUpdates struct {
+    // Mode is one of "webhook", "polling" or empty to not receive updates.
+    Mode        string `yaml:"mode"`
+    WebhookURL string `yaml:"webhookUrl"`
+    // SecretToken authenticates the webhook updates, it is required in webhook mode.
+    SecretToken string `yaml:"secretToken"`
 }
 
 type Email struct {
+    From string `yaml:"from"`
+    // Transport is smtp or http, smtp by default.
+    Transport string    `yaml:"transport"`
+    HTTP      EmailHTTP `yaml:"http"`
+
     Host     string `yaml:"host"`
     Port     uint   `yaml:"port"`
-    From     string `yaml:"from"`
     Username string `yaml:"username"`
+    // Password holds the OAuth 2.0 access token when Auth is xoauth2.
     Password string `yaml:"password"`
+    // Auth is the mechanism: plain, login, cram-md5, xoauth2 or none, plain by default when Username is set.
+    Auth string `yaml:"auth"`
+    // TLS is the mode: implicit, starttls or none, implicit by default for port 465 and starttls otherwise.
+    TLS                string `yaml:"tls"`
+    InsecureSkipVerify bool   `yaml:"insecureSkipVerify"`
+    // CAFile is a PEM bundle verifying the server certificate instead of the system roots.
+    CAFile string `yaml:"caFile"`
+    // PoolSize limits the connections opened at once, idle ones are reused until IdleTimeout.
+    PoolSize    int           `yaml:"poolSize"`
+    IdleTimeout time.Duration `yaml:"idleTimeout"`
+    Timeout     time.Duration `yaml:"timeout"`
+    // DKIM are the signing keys, the key of the From address domain is used.
+    DKIM []DKIM `yaml:"dkim"`
+}
+
+// EmailHTTP is a JSON-over-HTTP mail API.
+type EmailHTTP struct {
+    // URL is the endpoint the messages are posted to.
+    URL string `yaml:"url"`
+    // AuthHeader and AuthValue authenticate requests, e.g. "Authorization" and "Bearer <key>".
+    AuthHeader string        `yaml:"authHeader"`
+    AuthValue  string        `yaml:"authValue"`
+    Timeout    time.Duration `yaml:"timeout"`
+    // Fields map the message fields (from, to, subject, html, text, headers, attachments, messageId)
+    // to dotted paths in the request body, "-" leaves a field out.
+    Fields map[string]string `yaml:"fields"`
+    // MessageIDField is the dotted path of the provider message id in the response body.
+    MessageIDField string `yaml:"messageIdField"`
+}
+
+type DKIM struct {
+    Domain   string `yaml:"domain"`
+    Selector string `yaml:"selector"`
+    // KeyFile is a PEM encoded RSA or Ed25519 private key.
+    KeyFile string `yaml:"keyFile"`
+}
+
+type Webhooks struct {
+    Timeout         time.Duration `yaml:"timeout"`
+    ChannelDisabled string        `yaml:"channelDisabled"`
+    Action          string        `yaml:"action"`
+}
+
+type Actions struct {
+    // BaseURL is the public address of the service, tracked links of buttons point to it.
+    BaseURL string `yaml:"baseUrl"`
+    Secret  string `yaml:"secret"`
+    // TTL is how long presses of the buttons are recorded, 90 days by default. Expired links still redirect.
+    TTL time.Duration `yaml:"ttl"`
+}
+
+type Files struct {
+    // MaxSize is the maximum size of an uploaded file in bytes.
+    MaxSize int64 `yaml:"maxSize"`
+}
+
+type Bounces struct {
+    // Secret authenticates the provider webhook requests, sent in the X-Webhook-Secret header.
+    Secret string `yaml:"secret"`
+    // Maildir is the local mailbox bounce reports are delivered to, it is not read when empty.
+    Maildir string `yaml:"maildir"`
+    // Interval is how often the mailbox is checked.
+    Interval time.Duration `yaml:"interval"`
+}
+
+type Unsubscribe struct {
+    // Secret signs the unsubscribe links, they point to Actions.BaseURL and are not added while it is empty.
+    Secret string `yaml:"secret"`
+    // TTL is how long the links stay valid, 90 days by default.
+    TTL time.Duration `yaml:"ttl"`
+}
+
+type Templates struct {
+    // Dir is the directory templates are loaded from in addition to the database, it is not read when empty.
+    // The templates are reloaded when the files change or the service receives SIGHUP.
+    Dir string `yaml:"dir"`
+}
+
+type Locales struct {
+    // Default is the locale of the users without one, it ends every fallback chain.
+    Default string `yaml:"default"`
+    // Fallbacks map a locale to the one its missing translations are looked up in,
+    // e.g. kk: ru and ru: en make the chain kk-KZ → kk → ru → en.
+    Fallbacks map[string]string `yaml:"fallbacks"`
+    // TimeZone is the IANA time zone dates are formatted in for the users without one, UTC when empty.
+    TimeZone string `yaml:"timeZone"`
+}
+
+// Documents are the PDF documents generated for messages, such as the receipt attached to Receipt messages.
+type Documents struct {
+    // Brand is the name printed in the header of the documents.
+    Brand string `yaml:"brand"`
+    // Color is the #rrggbb color of the header.
+    Color string `yaml:"color"`
+    // Font and BoldFont are TrueType font files, the text is set in Helvetica without them
+    // and the characters out of ASCII are printed as question marks.
+    Font     string `yaml:"font"`
+    BoldFont string `yaml:"boldFont"`
 }
 
 func Read() (*Config, error) {
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE user_channel
+    ADD COLUMN disabled_reason varchar(255) NOT NULL DEFAULT '',
+    ADD COLUMN disabled_at     timestamptz  NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE user_channel
+    DROP COLUMN IF EXISTS disabled_reason,
+    DROP COLUMN IF EXISTS disabled_at;
+-- +goose StatementEnd
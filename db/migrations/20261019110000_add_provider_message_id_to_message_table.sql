@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE message
+    ADD COLUMN provider_message_id varchar(255) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE message
+    DROP COLUMN IF EXISTS provider_message_id;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS file
+(
+    id           varchar(36) PRIMARY KEY,
+    filename     varchar(255) NOT NULL,
+    content_type varchar(255) NOT NULL,
+    size         bigint       NOT NULL,
+    data         bytea        NOT NULL,
+    created_at   timestamptz  NOT NULL DEFAULT now()
+);
+
+ALTER TABLE message
+    ADD COLUMN attachments jsonb NOT NULL DEFAULT '[]'::jsonb;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE message
+    DROP COLUMN IF EXISTS attachments;
+
+DROP TABLE IF EXISTS file;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS suppression
+(
+    id          bigserial PRIMARY KEY,
+    channel     smallint      NOT NULL,
+    recipient   varchar(255)  NOT NULL,
+    reason      varchar(32)   NOT NULL,
+    description varchar(1024) NOT NULL DEFAULT '',
+    message_id  varchar(255)  NOT NULL DEFAULT '',
+    created_at  timestamptz   NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX idx_suppression_channel_recipient ON suppression (channel, recipient);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS suppression;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS user_preference
+(
+    id         bigserial PRIMARY KEY,
+    user_id    bigint      NOT NULL,
+    channel    smallint    NOT NULL,
+    category   varchar(32) NOT NULL,
+    subscribed boolean     NOT NULL DEFAULT true,
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX idx_user_preference_user_id_channel_category ON user_preference (user_id, channel, category);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_preference;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE user_channel
+    ADD COLUMN tracking_opt_out boolean NOT NULL DEFAULT false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE user_channel
+    DROP COLUMN IF EXISTS tracking_opt_out;
+-- +goose StatementEnd
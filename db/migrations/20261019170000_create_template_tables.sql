@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS template
+(
+    id              smallserial PRIMARY KEY,
+    name            varchar(255) NOT NULL,
+    category        varchar(32)  NOT NULL DEFAULT 'transactional',
+    tracking        boolean      NOT NULL DEFAULT false,
+    current_version integer      NOT NULL DEFAULT 0,
+    created_at      timestamptz  NOT NULL DEFAULT now(),
+    updated_at      timestamptz  NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX idx_template_name ON template (lower(name));
+
+CREATE TABLE IF NOT EXISTS template_version
+(
+    id            bigserial PRIMARY KEY,
+    template_id   smallint    NOT NULL REFERENCES template (id) ON DELETE CASCADE,
+    version       integer     NOT NULL,
+    params_schema jsonb       NOT NULL DEFAULT '{}'::jsonb,
+    bodies        jsonb       NOT NULL DEFAULT '{}'::jsonb,
+    created_at    timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX idx_template_version_template_id_version ON template_version (template_id, version);
+
+-- Templates compiled into the service keep their ids, they are rendered from the code until a version is created.
+INSERT INTO template (id, name)
+VALUES (1, 'Receipt')
+ON CONFLICT DO NOTHING;
+
+SELECT setval('template_id_seq', (SELECT max(id) FROM template));
+
+ALTER TABLE message
+    ADD COLUMN template_version integer NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE message
+    DROP COLUMN IF EXISTS template_version;
+
+DROP TABLE IF EXISTS template_version;
+DROP TABLE IF EXISTS template;
+-- +goose StatementEnd
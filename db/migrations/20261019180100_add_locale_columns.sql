@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE message
+    ADD COLUMN locale varchar(35) NOT NULL DEFAULT '';
+
+ALTER TABLE template_version
+    ADD COLUMN catalog jsonb NOT NULL DEFAULT '{}'::jsonb;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE template_version
+    DROP COLUMN IF EXISTS catalog;
+
+ALTER TABLE message
+    DROP COLUMN IF EXISTS locale;
+-- +goose StatementEnd
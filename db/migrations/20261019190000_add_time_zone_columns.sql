@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE user_locale
+    ADD COLUMN time_zone varchar(64) NOT NULL DEFAULT '';
+
+ALTER TABLE message
+    ADD COLUMN time_zone varchar(64) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE message
+    DROP COLUMN IF EXISTS time_zone;
+
+ALTER TABLE user_locale
+    DROP COLUMN IF EXISTS time_zone;
+-- +goose StatementEnd
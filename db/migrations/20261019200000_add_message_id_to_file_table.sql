@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE file
+    ADD COLUMN message_id varchar(255) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_file_message_id ON file (message_id) WHERE message_id <> '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_file_message_id;
+
+ALTER TABLE file
+    DROP COLUMN IF EXISTS message_id;
+-- +goose StatementEnd
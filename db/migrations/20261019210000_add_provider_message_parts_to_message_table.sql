@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE message
+    ADD COLUMN provider_message_parts jsonb NOT NULL DEFAULT '[]'::jsonb;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE message
+    DROP COLUMN IF EXISTS provider_message_parts;
+-- +goose StatementEnd
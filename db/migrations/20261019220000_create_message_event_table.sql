@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS message_event
+(
+    id          bigserial PRIMARY KEY,
+    message_id  varchar(255) NOT NULL REFERENCES message (id),
+    type        varchar(32)  NOT NULL,
+    description varchar(255) NOT NULL,
+    created_at  timestamptz  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_message_event_message_id ON message_event (message_id);
+
+-- Engagement events were recorded as statuses of the message and hid its delivery status.
+INSERT INTO message_event (message_id, type, description, created_at)
+SELECT message_id, status, description, created_at
+FROM message_status
+WHERE status IN ('opened', 'clicked', 'action', 'bounced', 'complained');
+
+DELETE
+FROM message_status
+WHERE status IN ('opened', 'clicked', 'action', 'bounced', 'complained');
+
+UPDATE message_status
+SET is_last = true
+WHERE id IN (SELECT DISTINCT ON (message_id) id
+             FROM message_status
+             WHERE message_id NOT IN (SELECT message_id FROM message_status WHERE is_last)
+             ORDER BY message_id, created_at DESC, id DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+INSERT INTO message_status (message_id, status, description, created_at)
+SELECT message_id, type, description, created_at
+FROM message_event;
+
+DROP TABLE IF EXISTS message_event;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE template_version
+    ADD COLUMN example_params jsonb;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE template_version
+    DROP COLUMN IF EXISTS example_params;
+-- +goose StatementEnd
@@ -23,6 +23,12 @@ func (i Channel) IsValid() bool {
 	return false
 }
 
+// HasCallbacks reports whether the channel reports pressed buttons by itself,
+// buttons of other channels are delivered as tracked links.
+func (i Channel) HasCallbacks() bool {
+	return i == Telegram
+}
+
 func GetChannelTypeFromString(s string) (Channel, bool) {
 	switch strings.ToLower(s) {
 	case strings.ToLower(Telegram.String()):
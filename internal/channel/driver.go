@@ -2,29 +2,72 @@ package channel
 
 import (
 	"errors"
+	"fmt"
 	"github.com/keweegen/notification/config"
 	"github.com/keweegen/notification/internal/channel/email"
+	"github.com/keweegen/notification/internal/channel/outbound"
 	"github.com/keweegen/notification/internal/channel/telegram"
+	"io"
 )
 
 var (
-	DriverNotFoundErr = errors.New("channel driver not found")
+	DriverNotFoundErr  = errors.New("channel driver not found")
+	EditUnsupportedErr = errors.New("channel does not support editing or deleting sent messages")
 )
 
 //go:generate mockgen -source=driver.go -destination=./mock/driver.go
 type Driver interface {
-	Send(receiver, message string) error
+	// Send delivers the message and returns the ids assigned to it by the provider. When the message
+	// is delivered in part, the parts delivered before the failure are returned with the error.
+	Send(receiver string, message *outbound.Message) (outbound.Delivery, error)
+}
+
+// Editor is implemented by drivers able to change messages that have already been delivered.
+type Editor interface {
+	Edit(receiver string, delivery outbound.Delivery, message *outbound.Message) error
+	Delete(receiver string, delivery outbound.Delivery) error
 }
 
 type Store struct {
 	Drivers map[Channel]Driver
 }
 
-func NewStore(cfg config.NotificationChannels) *Store {
+func NewStore(cfg config.NotificationChannels) (*Store, error) {
+	emailDriver, err := email.New(cfg.Email)
+	if err != nil {
+		return nil, fmt.Errorf("email driver: %w", err)
+	}
+
 	return &Store{Drivers: map[Channel]Driver{
 		Telegram: telegram.New(cfg.Telegram),
-		Email:    email.New(cfg.Email),
-	}}
+		Email:    emailDriver,
+	}}, nil
+}
+
+// IsRecipientUnreachable reports whether a driver error means that the recipient
+// permanently refuses messages, so retrying the delivery is pointless.
+func IsRecipientUnreachable(err error) bool {
+	var e interface{ RecipientUnreachable() bool }
+	return errors.As(err, &e) && e.RecipientUnreachable()
+}
+
+// IsTemporary reports whether the delivery failed for a transient reason, such as a 4xx SMTP reply,
+// and may succeed when retried later.
+func IsTemporary(err error) bool {
+	var e interface{ Temporary() bool }
+	return errors.As(err, &e) && e.Temporary()
+}
+
+// Close releases the connections held by the drivers.
+func (s *Store) Close() error {
+	for _, driver := range s.Drivers {
+		if closer, ok := driver.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
 func (s *Store) Get(channel Channel) (Driver, error) {
@@ -34,3 +77,29 @@ func (s *Store) Get(channel Channel) (Driver, error) {
 	}
 	return driver, nil
 }
+
+func (s *Store) GetEditor(channel Channel) (Editor, error) {
+	driver, err := s.Get(channel)
+	if err != nil {
+		return nil, err
+	}
+
+	editor, ok := driver.(Editor)
+	if !ok {
+		return nil, EditUnsupportedErr
+	}
+	return editor, nil
+}
+
+func (s *Store) TelegramBot() (telegram.Bot, error) {
+	driver, err := s.Get(Telegram)
+	if err != nil {
+		return nil, err
+	}
+
+	bot, ok := driver.(telegram.Bot)
+	if !ok {
+		return nil, DriverNotFoundErr
+	}
+	return bot, nil
+}
@@ -1,13 +1,18 @@
 package channel
 
 import (
+    "errors"
+    "fmt"
     "github.com/keweegen/notification/config"
+    "github.com/keweegen/notification/internal/channel/email"
+    "github.com/keweegen/notification/internal/channel/telegram"
     "github.com/stretchr/testify/assert"
     "testing"
 )
 
 func TestStore_Get(t *testing.T) {
-    store := NewStore(config.NotificationChannels{})
+    store, err := NewStore(config.NotificationChannels{})
+    assert.Nil(t, err)
     driverTelegram, _ := store.Get(Telegram)
     driverEmail, _ := store.Get(Email)
 
@@ -45,3 +50,78 @@ func TestStore_Get(t *testing.T) {
         })
     }
 }
+
+func TestIsRecipientUnreachable(t *testing.T) {
+    cases := []struct {
+        name     string
+        err      error
+        expected bool
+    }{
+        {
+            name:     "nil error",
+            err:      nil,
+            expected: false,
+        },
+        {
+            name:     "unknown error",
+            err:      errors.New("connection reset"),
+            expected: false,
+        },
+        {
+            name:     "telegram bot blocked",
+            err:      fmt.Errorf("send: %w", &telegram.Error{Code: 403, Description: "Forbidden: bot was blocked by the user"}),
+            expected: true,
+        },
+        {
+            name:     "telegram chat not found",
+            err:      &telegram.Error{Code: 400, Description: "Bad Request: chat not found"},
+            expected: true,
+        },
+        {
+            name:     "smtp user unknown",
+            err:      &email.Error{Command: "RCPT", Code: 550, Message: "5.1.1 User unknown"},
+            expected: true,
+        },
+        {
+            name:     "telegram too many requests",
+            err:      &telegram.Error{Code: 429, Description: "Too Many Requests: retry after 5"},
+            expected: false,
+        },
+    }
+
+    for _, tc := range cases {
+        t.Run(tc.name, func(t *testing.T) {
+            assert.Equal(t, tc.expected, IsRecipientUnreachable(tc.err))
+        })
+    }
+}
+
+func TestIsTemporary(t *testing.T) {
+    cases := []struct {
+        name     string
+        err      error
+        expected bool
+    }{
+        {
+            name:     "nil error",
+            err:      nil,
+            expected: false,
+        },
+        {
+            name:     "smtp mailbox busy",
+            err:      fmt.Errorf("send: %w", &email.Error{Command: "RCPT", Code: 450, Message: "4.2.1 Mailbox busy"}),
+            expected: true,
+        },
+        {
+            name:     "smtp user unknown",
+            err:      &email.Error{Command: "RCPT", Code: 550, Message: "5.1.1 User unknown"},
+            expected: false,
+        },
+    }
+
+    for _, tc := range cases {
+        t.Run(tc.name, func(t *testing.T) {
+            assert.Equal(t, tc.expected, IsTemporary(tc.err))
+        })
+    }
+}
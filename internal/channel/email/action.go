@@ -0,0 +1,51 @@
+package email
+
+import (
+	"fmt"
+	"github.com/keweegen/notification/internal/channel/outbound"
+	"html/template"
+	"strings"
+)
+
+const buttonStyle = "display:inline-block;padding:10px 20px;margin:4px 0;border-radius:4px;" +
+	"background-color:#1a73e8;color:#ffffff;font-weight:bold;text-decoration:none"
+
+// renderActions renders the actions as link buttons appended to the HTML body.
+// Email cannot report callbacks by itself, such actions are skipped unless they were given a tracked URL.
+func renderActions(actions []outbound.Action) string {
+	var b strings.Builder
+
+	for _, action := range actions {
+		if action.IsCallback() {
+			continue
+		}
+		b.WriteString(fmt.Sprintf(`<p><a href="%s" style="%s">%s</a></p>`,
+			template.HTMLEscapeString(action.URL),
+			buttonStyle,
+			template.HTMLEscapeString(action.Label)))
+	}
+
+	if b.Len() == 0 {
+		return ""
+	}
+
+	return "\n\n" + b.String()
+}
+
+// renderTextActions lists the actions with their links for the plain text alternative.
+func renderTextActions(actions []outbound.Action) string {
+	var b strings.Builder
+
+	for _, action := range actions {
+		if action.IsCallback() {
+			continue
+		}
+		b.WriteString(fmt.Sprintf("\n%s: %s", action.Label, action.URL))
+	}
+
+	if b.Len() == 0 {
+		return ""
+	}
+
+	return "\n" + b.String()
+}
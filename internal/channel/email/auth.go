@@ -0,0 +1,93 @@
+package email
+
+import (
+	"errors"
+	"fmt"
+	"net/smtp"
+	"strings"
+)
+
+const (
+	AuthNone    = "none"
+	AuthPlain   = "plain"
+	AuthLogin   = "login"
+	AuthCRAMMD5 = "cram-md5"
+	AuthXOAuth2 = "xoauth2"
+)
+
+// makeAuth returns the SMTP authentication of the mechanism, for XOAUTH2 the password is the access token.
+// Without a mechanism PLAIN is used when the username is set.
+func makeAuth(mechanism, username, password, host string) (smtp.Auth, error) {
+	if mechanism == "" {
+		mechanism = AuthPlain
+		if username == "" {
+			mechanism = AuthNone
+		}
+	}
+
+	switch strings.ToLower(mechanism) {
+	case AuthNone:
+		return nil, nil
+	case AuthPlain:
+		return smtp.PlainAuth("", username, password, host), nil
+	case AuthLogin:
+		return &loginAuth{username: username, password: password}, nil
+	case AuthCRAMMD5:
+		return smtp.CRAMMD5Auth(username, password), nil
+	case AuthXOAuth2:
+		return &xoauth2Auth{username: username, token: password}, nil
+	default:
+		return nil, fmt.Errorf("unknown smtp auth mechanism '%s'", mechanism)
+	}
+}
+
+// loginAuth implements the LOGIN mechanism still required by some providers, the server
+// prompts for the username and the password in turn.
+type loginAuth struct {
+	username string
+	password string
+}
+
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS {
+		return "", nil, errors.New("unencrypted connection")
+	}
+	return "LOGIN", nil, nil
+}
+
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+
+	prompt := strings.ToLower(strings.TrimSpace(string(fromServer)))
+	switch {
+	case strings.HasPrefix(prompt, "username"):
+		return []byte(a.username), nil
+	case strings.HasPrefix(prompt, "password"):
+		return []byte(a.password), nil
+	default:
+		return nil, fmt.Errorf("unexpected server challenge '%s'", fromServer)
+	}
+}
+
+// xoauth2Auth implements the XOAUTH2 mechanism of Gmail and Outlook with an OAuth 2.0 access token.
+type xoauth2Auth struct {
+	username string
+	token    string
+}
+
+func (a *xoauth2Auth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS {
+		return "", nil, errors.New("unencrypted connection")
+	}
+	return "XOAUTH2", []byte("user=" + a.username + "\x01auth=Bearer " + a.token + "\x01\x01"), nil
+}
+
+// Next answers the error challenge with an empty response, the server then replies with the failure.
+func (a *xoauth2Auth) Next(_ []byte, more bool) ([]byte, error) {
+	if more {
+		return []byte{}, nil
+	}
+	return nil, nil
+}
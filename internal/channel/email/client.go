@@ -1,53 +1,85 @@
 package email
 
 import (
-    "bytes"
     "fmt"
     "github.com/google/uuid"
-    "net/smtp"
-    "time"
+    "github.com/keweegen/notification/internal/channel/outbound"
 )
 
 type client struct {
-    host string
-    port uint
-    from string
-    auth smtp.Auth
+    from      string
+    transport transport
 }
 
-func (c *client) init(host string, port uint, from, username, password string) *client {
-    c.host = host
-    c.port = port
+func (c *client) init(from string, transport transport) *client {
     c.from = from
-    c.auth = smtp.PlainAuth("", username, password, c.host)
+    c.transport = transport
     return c
 }
 
-func (c *client) do(to, content string) error {
-    var body bytes.Buffer
-    body.WriteString(fmt.Sprintf("%s\n\n%s", c.makeHeaders(to), content))
+func (c *client) do(to string, message *outbound.Message) (string, error) {
+    mid := fmt.Sprintf("<%s:%s>", uuid.NewString(), c.from)
+
+    e, err := c.makeEnvelope(mid, to, message)
+    if err != nil {
+        return "", err
+    }
 
-    if err := smtp.SendMail(c.smtpAddress(), c.auth, c.from, []string{to}, body.Bytes()); err != nil {
-        return fmt.Errorf("failed send email: %w", err)
+    providerMessageID, err := c.transport.send(e)
+    if err != nil {
+        return "", fmt.Errorf("failed send email: %w", err)
     }
 
-    return nil
+    return providerMessageID, nil
 }
 
-func (c *client) makeHeaders(to string) string {
-    mid := fmt.Sprintf("<%s:%s>", uuid.NewString(), c.from)
-    subject := "Notification Service"
-    mime := "MIME-version: 1.0;\nContent-Type: text/html; charset=\"UTF-8\";\n\n"
-
-    return fmt.Sprintf("Message-ID: %s\nDate: %s\nFrom: %s\nTo: %s\nSubject: %s\n%s",
-        mid,
-        time.Now().Format(time.RFC1123Z),
-        c.from,
-        to,
-        subject,
-        mime)
+func (c *client) makeEnvelope(mid, to string, message *outbound.Message) (*envelope, error) {
+    headers, err := c.makeHeaders(mid, to, message)
+    if err != nil {
+        return nil, fmt.Errorf("failed to make email headers: %w", err)
+    }
+
+    inline, attachments, err := loadMedia(message.Media)
+    if err != nil {
+        return nil, fmt.Errorf("failed to make email body: %w", err)
+    }
+
+    subject := message.Subject
+    if subject == "" {
+        subject = defaultSubject
+    }
+
+    return &envelope{
+        from:        c.from,
+        to:          to,
+        messageID:   mid,
+        subject:     subject,
+        headers:     headers,
+        custom:      message.Headers,
+        text:        c.makeText(message),
+        html:        c.makeHTML(message),
+        inline:      inline,
+        attachments: attachments,
+    }, nil
+}
+
+// makeHTML prepends the preheader, hidden from the body but shown by mail clients
+// in the inbox next to the subject, and appends the buttons.
+func (c *client) makeHTML(message *outbound.Message) string {
+    html := message.Text + renderActions(message.Actions)
+    if message.Preheader == "" {
+        return html
+    }
+
+    return renderPreheader(message.Preheader) + html
 }
 
-func (c *client) smtpAddress() string {
-    return fmt.Sprintf("%s:%d", c.host, c.port)
+// makeText returns the plain text alternative, generated from the HTML unless the template provides it.
+func (c *client) makeText(message *outbound.Message) string {
+    text := message.PlainText
+    if text == "" {
+        text = htmlToText(message.Text)
+    }
+
+    return text + renderTextActions(message.Actions)
 }
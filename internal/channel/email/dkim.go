@@ -0,0 +1,269 @@
+package email
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/keweegen/notification/config"
+	"net/mail"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	DKIMAlgorithmRSA     = "rsa-sha256"
+	DKIMAlgorithmEd25519 = "ed25519-sha256"
+
+	dkimRSAKeyBits = 2048
+	// dkimChunkSize splits the signature so that the header can be folded.
+	dkimChunkSize = 64
+)
+
+// dkimSignedHeaders are signed when present, "from" is listed twice so that adding
+// a second From header breaks the signature.
+var dkimSignedHeaders = []string{
+	"from", "to", "cc", "subject", "date", "message-id", "reply-to", "sender",
+	"mime-version", "content-type", "list-unsubscribe", "list-unsubscribe-post", "from",
+}
+
+var UnsupportedDKIMKeyErr = errors.New("unsupported dkim key")
+
+// dkimSigner signs messages of a domain with the relaxed/relaxed canonicalization (RFC 6376).
+type dkimSigner struct {
+	domain    string
+	selector  string
+	algorithm string
+	key       crypto.Signer
+}
+
+// makeDKIMSigners loads the keys by the domain they sign.
+func makeDKIMSigners(keys []config.DKIM) (map[string]*dkimSigner, error) {
+	signers := make(map[string]*dkimSigner, len(keys))
+
+	for _, key := range keys {
+		data, err := os.ReadFile(key.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read dkim key of '%s': %w", key.Domain, err)
+		}
+		signer, algorithm, err := parseDKIMKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse dkim key of '%s': %w", key.Domain, err)
+		}
+
+		domain := strings.ToLower(key.Domain)
+		signers[domain] = &dkimSigner{
+			domain:    domain,
+			selector:  key.Selector,
+			algorithm: algorithm,
+			key:       signer,
+		}
+	}
+
+	return signers, nil
+}
+
+// parseDKIMKey reads a PEM encoded RSA (PKCS #1 or PKCS #8) or Ed25519 (PKCS #8) private key.
+func parseDKIMKey(data []byte) (crypto.Signer, string, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, "", fmt.Errorf("%w: no PEM block", UnsupportedDKIMKeyErr)
+	}
+
+	if block.Type == "RSA PRIVATE KEY" {
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, "", err
+		}
+		return key, DKIMAlgorithmRSA, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, "", err
+	}
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k, DKIMAlgorithmRSA, nil
+	case ed25519.PrivateKey:
+		return k, DKIMAlgorithmEd25519, nil
+	default:
+		return nil, "", fmt.Errorf("%w: %T", UnsupportedDKIMKeyErr, key)
+	}
+}
+
+// GenerateDKIMKey creates a key pair for the algorithm and returns the PEM encoded
+// private key with the value of the DNS TXT record publishing the public key.
+func GenerateDKIMKey(algorithm string) ([]byte, string, error) {
+	var (
+		private crypto.Signer
+		keyType string
+		public  []byte
+		err     error
+	)
+
+	switch algorithm {
+	case DKIMAlgorithmRSA:
+		var key *rsa.PrivateKey
+		if key, err = rsa.GenerateKey(rand.Reader, dkimRSAKeyBits); err != nil {
+			return nil, "", err
+		}
+		if public, err = x509.MarshalPKIXPublicKey(&key.PublicKey); err != nil {
+			return nil, "", err
+		}
+		private, keyType = key, "rsa"
+	case DKIMAlgorithmEd25519:
+		var publicKey ed25519.PublicKey
+		var key ed25519.PrivateKey
+		if publicKey, key, err = ed25519.GenerateKey(rand.Reader); err != nil {
+			return nil, "", err
+		}
+		private, keyType, public = key, "ed25519", publicKey
+	default:
+		return nil, "", fmt.Errorf("%w: algorithm '%s'", UnsupportedDKIMKeyErr, algorithm)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, "", err
+	}
+
+	record := fmt.Sprintf("v=DKIM1; k=%s; p=%s", keyType, base64.StdEncoding.EncodeToString(public))
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), record, nil
+}
+
+// signerFor returns the signer of the domain of the From address, messages
+// of domains without a key are sent unsigned.
+func signerFor(signers map[string]*dkimSigner, from string) *dkimSigner {
+	address, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil
+	}
+
+	at := strings.LastIndexByte(address.Address, '@')
+	return signers[strings.ToLower(address.Address[at+1:])]
+}
+
+// sign returns the DKIM-Signature header of the message.
+func (s *dkimSigner) sign(h headers, body []byte, now time.Time) (header, error) {
+	bodyHash := sha256.Sum256(canonicalBody(body))
+
+	signed := make([]string, 0, len(dkimSignedHeaders))
+	hash := sha256.New()
+	used := make(map[string]int)
+
+	for _, name := range dkimSignedHeaders {
+		// Repeated names select the instances from the bottom up, missing ones sign their absence.
+		item, ok := h.nthFromBottom(name, used[name])
+		used[name]++
+		if ok {
+			hash.Write([]byte(canonicalHeader(item.name, item.value)))
+		} else if !h.has(name) {
+			continue
+		}
+		signed = append(signed, name)
+	}
+
+	value := fmt.Sprintf("v=1; a=%s; c=relaxed/relaxed; d=%s; s=%s; t=%d; h=%s; bh=%s; b=",
+		s.algorithm, s.domain, s.selector, now.Unix(), strings.Join(signed, ":"),
+		base64.StdEncoding.EncodeToString(bodyHash[:]))
+	hash.Write([]byte(strings.TrimSuffix(canonicalHeader("DKIM-Signature", value), "\r\n")))
+
+	var (
+		signature []byte
+		err       error
+	)
+	if s.algorithm == DKIMAlgorithmEd25519 {
+		// Ed25519 signs the SHA-256 hash itself (RFC 8463).
+		signature, err = s.key.Sign(rand.Reader, hash.Sum(nil), crypto.Hash(0))
+	} else {
+		signature, err = s.key.Sign(rand.Reader, hash.Sum(nil), crypto.SHA256)
+	}
+	if err != nil {
+		return header{}, fmt.Errorf("failed to sign dkim: %w", err)
+	}
+
+	return header{name: "DKIM-Signature", value: value + chunk(base64.StdEncoding.EncodeToString(signature), dkimChunkSize)}, nil
+}
+
+// canonicalHeader applies the relaxed header canonicalization: the name is lowercased,
+// the value unfolded and whitespace runs are reduced to a single space.
+func canonicalHeader(name, value string) string {
+	value = strings.NewReplacer("\r\n", "", "\n", "").Replace(value)
+	return strings.ToLower(strings.TrimSpace(name)) + ":" + strings.TrimSpace(compressSpace(value)) + "\r\n"
+}
+
+// canonicalBody applies the relaxed body canonicalization: whitespace runs are reduced
+// to a single space, trailing whitespace of lines and empty lines at the end are removed.
+func canonicalBody(body []byte) []byte {
+	lines := strings.Split(strings.ReplaceAll(string(body), "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(compressSpace(line), " ")
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	return []byte(strings.Join(lines, "\r\n") + "\r\n")
+}
+
+func compressSpace(s string) string {
+	var b strings.Builder
+	space := false
+
+	for _, r := range s {
+		if r == ' ' || r == '\t' {
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	if space {
+		b.WriteByte(' ')
+	}
+
+	return b.String()
+}
+
+func chunk(s string, size int) string {
+	chunks := make([]string, 0, len(s)/size+1)
+	for len(s) > size {
+		chunks = append(chunks, s[:size])
+		s = s[size:]
+	}
+	return strings.Join(append(chunks, s), " ")
+}
+
+// nthFromBottom returns the n-th header of the name counting from the last one.
+func (h headers) nthFromBottom(name string, n int) (header, bool) {
+	for i := len(h) - 1; i >= 0; i-- {
+		if !strings.EqualFold(h[i].name, name) {
+			continue
+		}
+		if n == 0 {
+			return h[i], true
+		}
+		n--
+	}
+	return header{}, false
+}
+
+func (h headers) has(name string) bool {
+	_, ok := h.nthFromBottom(name, 0)
+	return ok
+}
@@ -0,0 +1,98 @@
+package email
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCanonicalBody(t *testing.T) {
+	// The body of the examples of RFC 6376 and RFC 8463.
+	body := "Hi.\r\n\r\nWe lost the game.  Are you hungry yet?\r\n\r\nJoe.\r\n\r\n\r\n"
+
+	hash := sha256.Sum256(canonicalBody([]byte(body)))
+	assert.Equal(t, "2jUSOH9NhtVGCQWNr9BrIAPreKQjO6Sn7XIkfJVOzv8=", base64.StdEncoding.EncodeToString(hash[:]))
+
+	assert.Equal(t, " C\r\nD E\r\n", string(canonicalBody([]byte(" C \r\nD \t E\r\n\r\n\r\n"))))
+	assert.Empty(t, canonicalBody([]byte("\r\n\r\n")))
+}
+
+func TestCanonicalHeader(t *testing.T) {
+	assert.Equal(t, "a:X\r\n", canonicalHeader("A", " X\r\n"))
+	assert.Equal(t, "b:Y Z\r\n", canonicalHeader("B ", " Y\t\r\n\tZ  "))
+}
+
+func TestDKIMSigner_Sign(t *testing.T) {
+	h := headers{
+		{name: "Message-ID", value: "<1@example.com>"},
+		{name: "Date", value: "Mon, 19 Oct 2026 12:00:00 +0000"},
+		{name: "From", value: "Сервис <no-reply@example.com>"},
+		{name: "To", value: "user@example.net"},
+		{name: "Subject", value: "Чек   по заказу"},
+		{name: "MIME-Version", value: "1.0"},
+		{name: "Content-Type", value: "text/plain; charset=UTF-8"},
+	}
+	body := []byte("Заказ оплачен.\r\n")
+
+	for _, algorithm := range []string{DKIMAlgorithmRSA, DKIMAlgorithmEd25519} {
+		t.Run(algorithm, func(t *testing.T) {
+			privateKey, record, err := GenerateDKIMKey(algorithm)
+			assert.Nil(t, err)
+
+			key, parsedAlgorithm, err := parseDKIMKey(privateKey)
+			assert.Nil(t, err)
+			assert.Equal(t, algorithm, parsedAlgorithm)
+
+			signer := &dkimSigner{domain: "example.com", selector: "ns", algorithm: algorithm, key: key}
+			assert.Equal(t, signer, signerFor(map[string]*dkimSigner{"example.com": signer}, "Сервис <no-reply@Example.com>"))
+
+			signature, err := signer.sign(h, body, time.Unix(1792411200, 0))
+			assert.Nil(t, err)
+			assert.Equal(t, "DKIM-Signature", signature.name)
+
+			tags := parseTags(signature.value)
+			assert.Equal(t, "from:to:subject:date:message-id:mime-version:content-type:from", tags["h"])
+			assert.Equal(t, "relaxed/relaxed", tags["c"])
+			assert.Equal(t, "1792411200", tags["t"])
+
+			unsigned := signature.value[:strings.LastIndex(signature.value, "b=")+2]
+			data := ""
+			for _, name := range strings.Split(tags["h"], ":")[:7] {
+				item, _ := h.nthFromBottom(name, 0)
+				data += canonicalHeader(item.name, item.value)
+			}
+			data += strings.TrimSuffix(canonicalHeader(signature.name, unsigned), "\r\n")
+			digest := sha256.Sum256([]byte(data))
+
+			sig, err := base64.StdEncoding.DecodeString(tags["b"])
+			assert.Nil(t, err)
+
+			public, err := base64.StdEncoding.DecodeString(parseTags(record)["p"])
+			assert.Nil(t, err)
+
+			if algorithm == DKIMAlgorithmEd25519 {
+				assert.True(t, ed25519.Verify(public, digest[:], sig))
+				return
+			}
+			publicKey, err := x509.ParsePKIXPublicKey(public)
+			assert.Nil(t, err)
+			assert.Nil(t, rsa.VerifyPKCS1v15(publicKey.(*rsa.PublicKey), crypto.SHA256, digest[:], sig))
+		})
+	}
+}
+
+func parseTags(value string) map[string]string {
+	tags := make(map[string]string)
+	for _, tag := range strings.Split(value, ";") {
+		name, tagValue, _ := strings.Cut(tag, "=")
+		tags[strings.TrimSpace(name)] = strings.ReplaceAll(strings.TrimSpace(tagValue), " ", "")
+	}
+	return tags
+}
@@ -1,17 +1,34 @@
 package email
 
-import "github.com/keweegen/notification/config"
+import (
+    "github.com/keweegen/notification/config"
+    "github.com/keweegen/notification/internal/channel/outbound"
+)
 
 type Driver struct {
     client *client
 }
 
-func New(cfg config.Email) *Driver {
-    return &Driver{
-        client: new(client).init(cfg.Host, cfg.Port, cfg.From, cfg.Username, cfg.Password),
+func New(cfg config.Email) (*Driver, error) {
+    transport, err := newTransport(cfg)
+    if err != nil {
+        return nil, err
     }
+
+    return &Driver{
+        client: new(client).init(cfg.From, transport),
+    }, nil
 }
 
-func (d *Driver) Send(receiver, message string) error {
-    return d.client.do(receiver, message)
+// Close releases the connections of the transport.
+func (d *Driver) Close() error {
+    return d.client.transport.Close()
+}
+
+func (d *Driver) Send(receiver string, message *outbound.Message) (outbound.Delivery, error) {
+    messageID, err := d.client.do(receiver, message)
+    if err != nil {
+        return outbound.Delivery{}, err
+    }
+    return outbound.Delivery{ID: messageID}, nil
 }
@@ -0,0 +1,174 @@
+package email
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"strings"
+)
+
+const (
+	FeedbackBounce    = "bounce"
+	FeedbackComplaint = "complaint"
+)
+
+var NotReportErr = errors.New("not a delivery status or feedback report")
+
+// Feedback is a bounce or a spam complaint reported for a sent message.
+type Feedback struct {
+	Type      string
+	Recipient string
+	// MessageID is the Message-ID header of the original message.
+	MessageID   string
+	Description string
+	// Permanent is false for soft bounces (4.x.x), the recipient may accept messages later.
+	Permanent bool
+}
+
+// ParseReport reads a bounce, a delivery status notification (RFC 3464), or a complaint,
+// an abuse feedback report (RFC 5965). Bounces are returned for the failed recipients only.
+func ParseReport(r io.Reader) ([]Feedback, error) {
+	message, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/report" {
+		return nil, NotReportErr
+	}
+
+	var (
+		statuses   []textproto.MIMEHeader
+		feedback   textproto.MIMEHeader
+		original   textproto.MIMEHeader
+		isFeedback = strings.EqualFold(params["report-type"], "feedback-report")
+	)
+
+	parts := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := parts.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read report part: %w", err)
+		}
+
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		switch partType {
+		case "message/delivery-status", "message/global-delivery-status":
+			if statuses, err = readFieldGroups(part); err != nil {
+				return nil, err
+			}
+		case "message/feedback-report":
+			groups, err := readFieldGroups(part)
+			if err != nil {
+				return nil, err
+			}
+			if len(groups) > 0 {
+				feedback = groups[0]
+			}
+		case "message/rfc822", "message/global", "text/rfc822-headers", "message/rfc822-headers":
+			if original, err = textproto.NewReader(bufio.NewReader(part)).ReadMIMEHeader(); err != nil && !errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("failed to read original message: %w", err)
+			}
+		}
+	}
+
+	messageID := strings.TrimSpace(original.Get("Message-Id"))
+
+	if isFeedback {
+		if feedback == nil {
+			return nil, NotReportErr
+		}
+		return []Feedback{makeComplaint(feedback, original, messageID)}, nil
+	}
+
+	if len(statuses) == 0 {
+		return nil, NotReportErr
+	}
+
+	// The first group holds the per-message fields, the others describe the recipients.
+	result := make([]Feedback, 0, len(statuses)-1)
+	for _, status := range statuses[1:] {
+		if !strings.EqualFold(status.Get("Action"), "failed") {
+			continue
+		}
+
+		code := strings.TrimSpace(status.Get("Status"))
+		description := strings.TrimSpace(status.Get("Diagnostic-Code"))
+		if description == "" {
+			description = code
+		}
+
+		result = append(result, Feedback{
+			Type:        FeedbackBounce,
+			Recipient:   addressField(status.Get("Final-Recipient"), status.Get("Original-Recipient")),
+			MessageID:   messageID,
+			Description: description,
+			Permanent:   strings.HasPrefix(code, "5"),
+		})
+	}
+
+	return result, nil
+}
+
+func makeComplaint(feedback, original textproto.MIMEHeader, messageID string) Feedback {
+	recipient := addressField(feedback.Get("Original-Rcpt-To"))
+	if recipient == "" {
+		if address, err := mail.ParseAddress(original.Get("To")); err == nil {
+			recipient = address.Address
+		}
+	}
+
+	description := "Feedback-Type: " + feedback.Get("Feedback-Type")
+	if agent := feedback.Get("User-Agent"); agent != "" {
+		description += ", User-Agent: " + agent
+	}
+
+	return Feedback{
+		Type:        FeedbackComplaint,
+		Recipient:   recipient,
+		MessageID:   messageID,
+		Description: description,
+		Permanent:   true,
+	}
+}
+
+// readFieldGroups reads the blank line separated groups of header fields.
+func readFieldGroups(r io.Reader) ([]textproto.MIMEHeader, error) {
+	reader := textproto.NewReader(bufio.NewReader(r))
+	groups := make([]textproto.MIMEHeader, 0)
+
+	for {
+		group, err := reader.ReadMIMEHeader()
+		if len(group) > 0 {
+			groups = append(groups, group)
+		}
+		if errors.Is(err, io.EOF) {
+			return groups, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read report fields: %w", err)
+		}
+	}
+}
+
+// addressField returns the address of the first non-empty field of the "rfc822; user@example.com" form.
+func addressField(fields ...string) string {
+	for _, field := range fields {
+		if i := strings.IndexByte(field, ';'); i >= 0 {
+			field = field[i+1:]
+		}
+		if address := strings.Trim(strings.TrimSpace(field), "<>"); address != "" {
+			return address
+		}
+	}
+	return ""
+}
@@ -0,0 +1,126 @@
+package email
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+const bounceReport = "From: MAILER-DAEMON@mx.example.com\r\n" +
+	"To: no-reply@example.com\r\n" +
+	"Subject: Undelivered Mail Returned to Sender\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/report; report-type=delivery-status; boundary=\"b1\"\r\n" +
+	"\r\n" +
+	"--b1\r\n" +
+	"Content-Type: text/plain; charset=us-ascii\r\n" +
+	"\r\n" +
+	"I'm sorry to have to inform you that your message could not be delivered.\r\n" +
+	"--b1\r\n" +
+	"Content-Type: message/delivery-status\r\n" +
+	"\r\n" +
+	"Reporting-MTA: dns; mx.example.com\r\n" +
+	"Arrival-Date: Mon, 19 Oct 2026 12:00:00 +0000\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; unknown@example.net\r\n" +
+	"Original-Recipient: rfc822;Unknown@example.net\r\n" +
+	"Action: failed\r\n" +
+	"Status: 5.1.1\r\n" +
+	"Diagnostic-Code: smtp; 550 5.1.1 <unknown@example.net>: Recipient address rejected\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; full@example.net\r\n" +
+	"Action: failed\r\n" +
+	"Status: 4.2.2\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; late@example.net\r\n" +
+	"Action: delayed\r\n" +
+	"Status: 4.4.1\r\n" +
+	"--b1\r\n" +
+	"Content-Type: text/rfc822-headers\r\n" +
+	"\r\n" +
+	"Message-ID: <5b0e7c1a:no-reply@example.com>\r\n" +
+	"From: no-reply@example.com\r\n" +
+	"To: unknown@example.net\r\n" +
+	"--b1--\r\n"
+
+const complaintReport = "From: feedback@isp.example\r\n" +
+	"To: abuse@example.com\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/report; report-type=feedback-report; boundary=\"b2\"\r\n" +
+	"\r\n" +
+	"--b2\r\n" +
+	"Content-Type: text/plain\r\n" +
+	"\r\n" +
+	"This is an email abuse report.\r\n" +
+	"--b2\r\n" +
+	"Content-Type: message/feedback-report\r\n" +
+	"\r\n" +
+	"Feedback-Type: abuse\r\n" +
+	"User-Agent: SomeGenerator/1.0\r\n" +
+	"Version: 1\r\n" +
+	"--b2\r\n" +
+	"Content-Type: message/rfc822\r\n" +
+	"\r\n" +
+	"Message-ID: <7c9d:no-reply@example.com>\r\n" +
+	"From: no-reply@example.com\r\n" +
+	"To: User <user@example.net>\r\n" +
+	"Subject: Receipt\r\n" +
+	"\r\n" +
+	"Body\r\n" +
+	"--b2--\r\n"
+
+func TestParseReport(t *testing.T) {
+	cases := []struct {
+		name          string
+		report        string
+		expected      []Feedback
+		expectedError error
+	}{
+		{
+			name:   "bounce",
+			report: bounceReport,
+			expected: []Feedback{
+				{
+					Type:        FeedbackBounce,
+					Recipient:   "unknown@example.net",
+					MessageID:   "<5b0e7c1a:no-reply@example.com>",
+					Description: "smtp; 550 5.1.1 <unknown@example.net>: Recipient address rejected",
+					Permanent:   true,
+				},
+				{
+					Type:        FeedbackBounce,
+					Recipient:   "full@example.net",
+					MessageID:   "<5b0e7c1a:no-reply@example.com>",
+					Description: "4.2.2",
+					Permanent:   false,
+				},
+			},
+		},
+		{
+			name:   "complaint",
+			report: complaintReport,
+			expected: []Feedback{
+				{
+					Type:        FeedbackComplaint,
+					Recipient:   "user@example.net",
+					MessageID:   "<7c9d:no-reply@example.com>",
+					Description: "Feedback-Type: abuse, User-Agent: SomeGenerator/1.0",
+					Permanent:   true,
+				},
+			},
+		},
+		{
+			name:          "auto reply",
+			report:        "From: user@example.net\r\nSubject: Out of office\r\n\r\nI'm away.\r\n",
+			expectedError: NotReportErr,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			feedback, err := ParseReport(strings.NewReader(tc.report))
+			assert.Equal(t, tc.expectedError, err)
+			assert.Equal(t, tc.expected, feedback)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package email
+
+import (
+	"bytes"
+	"github.com/keweegen/notification/internal/channel/outbound"
+	"time"
+)
+
+// envelope is a message ready to be delivered, transports either compose it into
+// a MIME message or pass its parts to the provider API.
+type envelope struct {
+	from      string
+	to        string
+	messageID string
+	subject   string
+	// headers are the encoded header fields, custom are the template headers as is.
+	headers     headers
+	custom      map[string]string
+	text        string
+	html        string
+	inline      []outbound.Media
+	attachments []outbound.Media
+}
+
+// compose builds the MIME message signed by the signer when given.
+func (e *envelope) compose(signer *dkimSigner, now time.Time) ([]byte, error) {
+	content, err := e.makeContent()
+	if err != nil {
+		return nil, err
+	}
+
+	headers := append(e.headers[:len(e.headers):len(e.headers)],
+		header{name: "MIME-Version", value: "1.0"},
+		header{name: "Content-Type", value: content.contentType})
+
+	if signer != nil {
+		signature, err := signer.sign(headers, content.body, now)
+		if err != nil {
+			return nil, err
+		}
+		headers = append([]header{signature}, headers...)
+	}
+
+	var message bytes.Buffer
+	headers.writeTo(&message)
+	message.WriteString("\r\n")
+	message.Write(content.body)
+
+	return message.Bytes(), nil
+}
+
+// makeContent builds the body: the alternative text and HTML versions, wrapped with the inline
+// images into multipart/related and with the attachments into multipart/mixed.
+func (e *envelope) makeContent() (*entity, error) {
+	content, err := makeAlternative(e.text, e.html)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(e.inline) > 0 {
+		if content, err = makeRelated(content, e.inline); err != nil {
+			return nil, err
+		}
+	}
+	if len(e.attachments) > 0 {
+		if content, err = makeMixed(content, e.attachments); err != nil {
+			return nil, err
+		}
+	}
+
+	return content, nil
+}
@@ -0,0 +1,57 @@
+package email
+
+import (
+	"errors"
+	"fmt"
+	"net/textproto"
+	"strings"
+)
+
+// recipientUnreachableCodes are the enhanced status codes (RFC 3463) of replies
+// to RCPT meaning that the mailbox does not exist or cannot receive mail.
+var recipientUnreachableCodes = []string{"5.1.1", "5.1.2", "5.1.6", "5.1.10"}
+
+// Error is a negative SMTP reply to a command.
+type Error struct {
+	Command string
+	Code    int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("smtp %s error %d: %s", e.Command, e.Code, e.Message)
+}
+
+// Temporary reports whether the server asked to retry later (4xx).
+func (e *Error) Temporary() bool {
+	return e.Code >= 400 && e.Code < 500
+}
+
+// Permanent reports whether the server refused the message for good (5xx).
+func (e *Error) Permanent() bool {
+	return e.Code >= 500 && e.Code < 600
+}
+
+// RecipientUnreachable reports whether the recipient address was rejected as non-existent.
+func (e *Error) RecipientUnreachable() bool {
+	if e.Command != "RCPT" || !e.Permanent() {
+		return false
+	}
+
+	for _, code := range recipientUnreachableCodes {
+		if strings.HasPrefix(e.Message, code+" ") || e.Message == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// wrapError converts the reply of the command into Error, other errors are returned as is.
+func wrapError(command string, err error) error {
+	var protocolErr *textproto.Error
+	if errors.As(err, &protocolErr) {
+		return &Error{Command: command, Code: protocolErr.Code, Message: protocolErr.Msg}
+	}
+	return err
+}
@@ -0,0 +1,182 @@
+package email
+
+import (
+	"errors"
+	"fmt"
+	"github.com/keweegen/notification/internal/channel/outbound"
+	"html/template"
+	"io"
+	"mime"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSubject = "Notification Service"
+	lineLimit      = 78
+)
+
+// reservedHeaders are set by the driver and cannot be overridden by templates.
+var reservedHeaders = map[string]bool{
+	"Message-Id":                true,
+	"Date":                      true,
+	"From":                      true,
+	"To":                        true,
+	"Cc":                        true,
+	"Bcc":                       true,
+	"Subject":                   true,
+	"Mime-Version":              true,
+	"Content-Type":              true,
+	"Content-Transfer-Encoding": true,
+}
+
+// addressHeaders hold address lists, their display names are encoded separately from the addresses.
+var addressHeaders = map[string]bool{
+	"Reply-To": true,
+	"Sender":   true,
+}
+
+var InvalidHeaderErr = errors.New("invalid header")
+
+type header struct {
+	name  string
+	value string
+}
+
+type headers []header
+
+func (c *client) makeHeaders(mid, to string, message *outbound.Message) (headers, error) {
+	from, err := formatAddressList(c.from)
+	if err != nil {
+		return nil, fmt.Errorf("from: %w", err)
+	}
+	recipient, err := formatAddressList(to)
+	if err != nil {
+		return nil, fmt.Errorf("to: %w", err)
+	}
+
+	subject := message.Subject
+	if subject == "" {
+		subject = defaultSubject
+	}
+
+	h := headers{
+		{name: "Message-ID", value: mid},
+		{name: "Date", value: time.Now().Format(time.RFC1123Z)},
+		{name: "From", value: from},
+		{name: "To", value: recipient},
+		{name: "Subject", value: encodeText(subject)},
+	}
+
+	names := make([]string, 0, len(message.Headers))
+	for name := range message.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		custom, err := makeCustomHeader(name, message.Headers[name])
+		if err != nil {
+			return nil, err
+		}
+		if custom.value != "" {
+			h = append(h, custom)
+		}
+	}
+
+	return h, nil
+}
+
+func makeCustomHeader(name, value string) (header, error) {
+	canonical := textproto.CanonicalMIMEHeaderKey(name)
+	if !isToken(name) || reservedHeaders[canonical] {
+		return header{}, fmt.Errorf("%w: '%s'", InvalidHeaderErr, name)
+	}
+	if strings.ContainsAny(value, "\r\n") {
+		return header{}, fmt.Errorf("%w: '%s' contains a line break", InvalidHeaderErr, name)
+	}
+	if value == "" {
+		return header{name: name}, nil
+	}
+
+	if addressHeaders[canonical] {
+		addresses, err := formatAddressList(value)
+		if err != nil {
+			return header{}, fmt.Errorf("%w: '%s': %s", InvalidHeaderErr, name, err)
+		}
+		return header{name: name, value: addresses}, nil
+	}
+
+	return header{name: name, value: encodeText(value)}, nil
+}
+
+// formatAddressList parses the addresses and formats them with RFC 2047 encoded display names.
+func formatAddressList(list string) (string, error) {
+	addresses, err := mail.ParseAddressList(list)
+	if err != nil {
+		return "", err
+	}
+
+	formatted := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		formatted = append(formatted, address.String())
+	}
+
+	return strings.Join(formatted, ", "), nil
+}
+
+// encodeText encodes non-ASCII text as RFC 2047 encoded-words, ASCII text is left as is.
+func encodeText(s string) string {
+	return mime.BEncoding.Encode("UTF-8", s)
+}
+
+// writeTo writes the headers folding lines longer than the RFC 5322 recommended limit at spaces.
+func (h headers) writeTo(w io.StringWriter) {
+	for _, item := range h {
+		_, _ = w.WriteString(foldHeader(item.name+": "+item.value) + "\r\n")
+	}
+}
+
+func foldHeader(line string) string {
+	if len(line) <= lineLimit {
+		return line
+	}
+
+	var b strings.Builder
+	current := 0
+
+	for i, word := range strings.Split(line, " ") {
+		if i > 0 {
+			if current+1+len(word) > lineLimit {
+				b.WriteString("\r\n")
+				current = 0
+			}
+			b.WriteString(" ")
+			current++
+		}
+		b.WriteString(word)
+		current += len(word)
+	}
+
+	return b.String()
+}
+
+func isToken(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r <= ' ' || r >= 0x7f || r == ':' {
+			return false
+		}
+	}
+	return true
+}
+
+func renderPreheader(preheader string) string {
+	return `<div style="display:none;max-height:0;overflow:hidden;mso-hide:all">` +
+		template.HTMLEscapeString(preheader) + "</div>\n"
+}
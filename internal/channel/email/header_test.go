@@ -0,0 +1,69 @@
+package email
+
+import (
+	"github.com/keweegen/notification/internal/channel/outbound"
+	"github.com/stretchr/testify/assert"
+	"mime"
+	"strings"
+	"testing"
+)
+
+func TestClient_makeHeaders(t *testing.T) {
+	c := new(client).init("Сервис уведомлений <no-reply@example.com>", nil)
+
+	h, err := c.makeHeaders("<1:no-reply@example.com>", "user@example.com", &outbound.Message{
+		Subject: "Чек по заказу 123",
+		Headers: map[string]string{
+			"Reply-To":   "Поддержка <support@example.com>",
+			"List-Id":    "Receipts <receipts.example.com>",
+			"X-Campaign": "",
+		},
+	})
+	assert.Nil(t, err)
+
+	var b strings.Builder
+	h.writeTo(&b)
+	raw := b.String()
+
+	decoder := new(mime.WordDecoder)
+	values := map[string]string{}
+	for _, line := range strings.Split(strings.ReplaceAll(raw, "\r\n ", " "), "\r\n") {
+		name, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			continue
+		}
+		decoded, err := decoder.DecodeHeader(value)
+		assert.Nil(t, err)
+		values[name] = decoded
+	}
+
+	assert.Equal(t, "Чек по заказу 123", values["Subject"])
+	assert.Equal(t, "Сервис уведомлений <no-reply@example.com>", values["From"])
+	assert.Equal(t, "<user@example.com>", values["To"])
+	assert.Equal(t, "Поддержка <support@example.com>", values["Reply-To"])
+	assert.Equal(t, "Receipts <receipts.example.com>", values["List-Id"])
+	assert.NotContains(t, raw, "X-Campaign")
+
+	for _, line := range strings.Split(raw, "\r\n") {
+		assert.LessOrEqual(t, len(line), lineLimit)
+		for _, r := range line {
+			assert.Less(t, r, rune(0x80))
+		}
+	}
+}
+
+func TestClient_makeHeaders_Invalid(t *testing.T) {
+	c := new(client).init("no-reply@example.com", nil)
+
+	for name, value := range map[string]string{
+		"Subject":    "override",
+		"X-Bad Name": "value",
+		"X-Inject":   "value\r\nBcc: victim@example.com",
+		"Reply-To":   "not an address",
+	} {
+		_, err := c.makeHeaders("<1:no-reply@example.com>", "user@example.com", &outbound.Message{
+			Headers: map[string]string{name: value},
+		})
+		assert.ErrorIs(t, err, InvalidHeaderErr, name)
+	}
+}
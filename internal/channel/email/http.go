@@ -0,0 +1,205 @@
+package email
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/keweegen/notification/config"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// fieldSkipped leaves the field out of the request body.
+const fieldSkipped = "-"
+
+// errorBodyLimit is how much of an error response is kept in the error.
+const errorBodyLimit = 1024
+
+// HTTPError is an unsuccessful response of the mail API.
+type HTTPError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("mail api error %d: %s", e.StatusCode, e.Body)
+}
+
+// Temporary reports whether the API is rate limiting or failing on its side.
+func (e *HTTPError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+// httpTransport posts the messages as JSON to a mail API, the shape of the body is set by the field mapping.
+type httpTransport struct {
+	cfg    config.EmailHTTP
+	client *http.Client
+}
+
+type httpAttachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"contentType"`
+	Content     string `json:"content"`
+	Disposition string `json:"disposition"`
+	ContentID   string `json:"contentId,omitempty"`
+}
+
+func newHTTPTransport(cfg config.EmailHTTP) (*httpTransport, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("email http transport: url is not set")
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	return &httpTransport{cfg: cfg, client: &http.Client{Timeout: timeout}}, nil
+}
+
+// send posts the message and returns the id from the response, the Message-ID header
+// is returned when the response does not have one.
+func (t *httpTransport) send(e *envelope) (string, error) {
+	body, err := json.Marshal(t.makeBody(e))
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal mail api request: %w", err)
+	}
+
+	request, err := http.NewRequest(http.MethodPost, t.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if t.cfg.AuthHeader != "" {
+		request.Header.Set(t.cfg.AuthHeader, t.cfg.AuthValue)
+	}
+
+	response, err := t.client.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to call mail api: %w", err)
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read mail api response: %w", err)
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		if len(data) > errorBodyLimit {
+			data = data[:errorBodyLimit]
+		}
+		return "", &HTTPError{StatusCode: response.StatusCode, Body: string(data)}
+	}
+
+	if id := t.messageID(data); id != "" {
+		return id, nil
+	}
+	return e.messageID, nil
+}
+
+func (t *httpTransport) Close() error {
+	t.client.CloseIdleConnections()
+	return nil
+}
+
+func (t *httpTransport) makeBody(e *envelope) map[string]any {
+	attachments := make([]httpAttachment, 0, len(e.inline)+len(e.attachments))
+	for _, m := range append(e.inline[:len(e.inline):len(e.inline)], e.attachments...) {
+		disposition := "attachment"
+		if m.IsInline() {
+			disposition = "inline"
+		}
+		attachments = append(attachments, httpAttachment{
+			Filename:    m.Filename,
+			ContentType: m.ContentType,
+			Content:     base64.StdEncoding.EncodeToString(m.Data),
+			Disposition: disposition,
+			ContentID:   m.ContentID,
+		})
+	}
+
+	headers := e.custom
+	if headers == nil {
+		headers = map[string]string{}
+	}
+
+	fields := []struct {
+		name  string
+		value any
+	}{
+		{name: "from", value: e.from},
+		{name: "to", value: e.to},
+		{name: "subject", value: e.subject},
+		{name: "html", value: e.html},
+		{name: "text", value: e.text},
+		{name: "headers", value: headers},
+		{name: "attachments", value: attachments},
+		{name: "messageId", value: e.messageID},
+	}
+
+	body := make(map[string]any)
+	for _, f := range fields {
+		path := t.fieldPath(f.name)
+		if path == fieldSkipped {
+			continue
+		}
+		setPath(body, path, f.value)
+	}
+
+	return body
+}
+
+// fieldPath returns the mapped path of the field, the config keys are case-insensitive.
+func (t *httpTransport) fieldPath(name string) string {
+	for field, path := range t.cfg.Fields {
+		if strings.EqualFold(field, name) && path != "" {
+			return path
+		}
+	}
+	return name
+}
+
+func (t *httpTransport) messageID(data []byte) string {
+	if t.cfg.MessageIDField == "" {
+		return ""
+	}
+
+	var body any
+	if err := json.Unmarshal(data, &body); err != nil {
+		return ""
+	}
+
+	for _, key := range strings.Split(t.cfg.MessageIDField, ".") {
+		object, ok := body.(map[string]any)
+		if !ok {
+			return ""
+		}
+		body = object[key]
+	}
+
+	switch id := body.(type) {
+	case string:
+		return id
+	case float64:
+		return fmt.Sprintf("%.0f", id)
+	default:
+		return ""
+	}
+}
+
+// setPath sets the value at the dotted path creating the intermediate objects.
+func setPath(body map[string]any, path string, value any) {
+	keys := strings.Split(path, ".")
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := body[key].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			body[key] = next
+		}
+		body = next
+	}
+	body[keys[len(keys)-1]] = value
+}
@@ -0,0 +1,103 @@
+package email
+
+import (
+	"encoding/json"
+	"github.com/keweegen/notification/config"
+	"github.com/keweegen/notification/internal/channel/outbound"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPTransport_Send(t *testing.T) {
+	var request map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer key", r.Header.Get("Authorization"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		body, _ := io.ReadAll(r.Body)
+		assert.Nil(t, json.Unmarshal(body, &request))
+
+		_, _ = w.Write([]byte(`{"data":{"id":"provider-1"}}`))
+	}))
+	defer server.Close()
+
+	tr, err := newHTTPTransport(config.EmailHTTP{
+		URL:            server.URL,
+		AuthHeader:     "Authorization",
+		AuthValue:      "Bearer key",
+		Fields:         map[string]string{"from": "from.email", "messageid": "-"},
+		MessageIDField: "data.id",
+	})
+	assert.Nil(t, err)
+
+	e := testEnvelope("user@example.com", "Чек")
+	e.custom = map[string]string{"X-Campaign": "receipt"}
+	e.attachments = []outbound.Media{{Filename: "receipt.pdf", ContentType: "application/pdf", Data: []byte("pdf")}}
+
+	id, err := tr.send(e)
+	assert.Nil(t, err)
+	assert.Equal(t, "provider-1", id)
+
+	assert.Equal(t, map[string]any{"email": "no-reply@example.com"}, request["from"])
+	assert.Equal(t, "user@example.com", request["to"])
+	assert.Equal(t, "Чек", request["subject"])
+	assert.Equal(t, "<p>Чек</p>", request["html"])
+	assert.Equal(t, map[string]any{"X-Campaign": "receipt"}, request["headers"])
+	assert.Equal(t, []any{map[string]any{
+		"filename":    "receipt.pdf",
+		"contentType": "application/pdf",
+		"content":     "cGRm",
+		"disposition": "attachment",
+	}}, request["attachments"])
+	assert.NotContains(t, request, "messageId")
+}
+
+func TestHTTPTransport_SendError(t *testing.T) {
+	cases := []struct {
+		name              string
+		statusCode        int
+		expectedTemporary bool
+	}{
+		{name: "rate limited", statusCode: http.StatusTooManyRequests, expectedTemporary: true},
+		{name: "provider failure", statusCode: http.StatusBadGateway, expectedTemporary: true},
+		{name: "invalid request", statusCode: http.StatusBadRequest, expectedTemporary: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.statusCode)
+				_, _ = w.Write([]byte(`{"error":"failed"}`))
+			}))
+			defer server.Close()
+
+			tr, err := newHTTPTransport(config.EmailHTTP{URL: server.URL})
+			assert.Nil(t, err)
+
+			_, err = tr.send(testEnvelope("user@example.com", "text"))
+
+			var httpErr *HTTPError
+			assert.ErrorAs(t, err, &httpErr)
+			assert.Equal(t, tc.statusCode, httpErr.StatusCode)
+			assert.Equal(t, `{"error":"failed"}`, httpErr.Body)
+			assert.Equal(t, tc.expectedTemporary, httpErr.Temporary())
+		})
+	}
+}
+
+func TestHTTPTransport_SendWithoutMessageID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	tr, err := newHTTPTransport(config.EmailHTTP{URL: server.URL, MessageIDField: "id"})
+	assert.Nil(t, err)
+
+	id, err := tr.send(testEnvelope("user@example.com", "text"))
+	assert.Nil(t, err)
+	assert.Equal(t, "<user@example.com>", id)
+}
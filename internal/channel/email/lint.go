@@ -0,0 +1,120 @@
+package email
+
+import (
+	"fmt"
+	"strings"
+)
+
+// voidTags have no closing tag.
+var voidTags = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// rawTextTags hold text up to their closing tag, such as CSS.
+var rawTextTags = map[string]bool{"style": true, "script": true, "title": true}
+
+// Lint returns the well-formedness problems of the HTML body: unterminated tags, comments and
+// attribute values, closing tags not matching the open element and elements left open.
+// The closing tags HTML allows to omit, e.g. </p>, are required, mail clients differ on them.
+func Lint(body string) []string {
+	problems := make([]string, 0)
+	open := make([]string, 0)
+
+	for i := 0; i < len(body); {
+		if body[i] != '<' {
+			next := strings.IndexByte(body[i:], '<')
+			if next < 0 {
+				break
+			}
+			i += next
+			continue
+		}
+
+		if strings.HasPrefix(body[i:], "<!--") {
+			end := strings.Index(body[i+4:], "-->")
+			if end < 0 {
+				problems = append(problems, "unterminated comment")
+				break
+			}
+			i += 4 + end + 3
+			continue
+		}
+
+		if i+1 == len(body) || !isTagStart(body[i+1]) {
+			i++
+			continue
+		}
+
+		end := tagEnd(body, i)
+		if end < 0 {
+			problems = append(problems, fmt.Sprintf("unterminated tag at offset %d", i))
+			break
+		}
+		raw := body[i:end]
+		i = end
+
+		name, closing := tagName(raw)
+		switch {
+		case name == "" || strings.HasPrefix(raw, "<!"):
+			continue
+		case closing && voidTags[name]:
+			problems = append(problems, fmt.Sprintf("closing tag </%s> of a void element", name))
+		case closing:
+			j := len(open) - 1
+			for j >= 0 && open[j] != name {
+				j--
+			}
+			if j < 0 {
+				problems = append(problems, fmt.Sprintf("closing tag </%s> without an open element", name))
+				continue
+			}
+			for _, unclosed := range open[j+1:] {
+				problems = append(problems, fmt.Sprintf("<%s> closed by </%s>", unclosed, name))
+			}
+			open = open[:j]
+		case voidTags[name] || strings.HasSuffix(raw, "/>"):
+		case rawTextTags[name]:
+			text := strings.Index(strings.ToLower(body[i:]), "</"+name)
+			if text < 0 {
+				problems = append(problems, fmt.Sprintf("unclosed <%s>", name))
+				return problems
+			}
+			i += text
+			open = append(open, name)
+		default:
+			open = append(open, name)
+		}
+	}
+
+	for _, unclosed := range open {
+		problems = append(problems, fmt.Sprintf("unclosed <%s>", unclosed))
+	}
+	return problems
+}
+
+// tagEnd returns the index following the tag starting at i, skipping the quoted attribute values,
+// -1 when the tag is not terminated.
+func tagEnd(s string, i int) int {
+	quote := byte(0)
+	for j := i + 1; j < len(s); j++ {
+		switch {
+		case quote != 0:
+			if s[j] == quote {
+				quote = 0
+			}
+		case s[j] == '"' || s[j] == '\'':
+			quote = s[j]
+		case s[j] == '>':
+			return j + 1
+		case s[j] == '<':
+			return -1
+		}
+	}
+	return -1
+}
+
+// isTagStart reports whether the character following "<" starts a tag, a comment or a declaration.
+func isTagStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '/' || c == '!'
+}
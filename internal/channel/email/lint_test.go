@@ -0,0 +1,33 @@
+package email
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	cases := []struct {
+		name     string
+		html     string
+		expected []string
+	}{
+		{
+			name: "well-formed",
+			html: `<!DOCTYPE html><html><head><meta charset="utf-8"><style>a > b {}</style></head>` +
+				`<body><!-- <p> --><p title="a > b">1 < 2<br><img src="x" /></p></body></html>`,
+			expected: []string{},
+		},
+		{name: "unclosed", html: "<div><p>Order</div>", expected: []string{"<p> closed by </div>"}},
+		{name: "stray closing tag", html: "<p>Order</p></b>", expected: []string{"closing tag </b> without an open element"}},
+		{name: "void closing tag", html: "<br></br>", expected: []string{"closing tag </br> of a void element"}},
+		{name: "left open", html: "<table><tr><td>1", expected: []string{"unclosed <table>", "unclosed <tr>", "unclosed <td>"}},
+		{name: "unterminated tag", html: `<a href="x">Order</a`, expected: []string{"unterminated tag at offset 17", "unclosed <a>"}},
+		{name: "unterminated comment", html: "<!-- Order", expected: []string{"unterminated comment"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Lint(tc.html))
+		})
+	}
+}
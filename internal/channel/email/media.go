@@ -0,0 +1,87 @@
+package email
+
+import (
+	"errors"
+	"fmt"
+	"github.com/keweegen/notification/internal/channel/outbound"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"time"
+)
+
+// attachmentsLimit is the maximum total size of the media of an email, most servers reject larger messages.
+const attachmentsLimit = 20 << 20
+
+var AttachmentsTooLargeErr = errors.New("attachments exceed the email size limit")
+
+var httpClient = &http.Client{
+	Timeout: 30 * time.Second,
+}
+
+// loadMedia downloads the media given by URL and fills in missing content types,
+// then splits the media into inline ones and attachments.
+func loadMedia(media []outbound.Media) ([]outbound.Media, []outbound.Media, error) {
+	inline := make([]outbound.Media, 0)
+	attachments := make([]outbound.Media, 0)
+	total := 0
+
+	for _, m := range media {
+		if !m.IsUpload() {
+			data, contentType, err := download(m.URL, attachmentsLimit-total)
+			if err != nil {
+				return nil, nil, err
+			}
+			m.Data = data
+			if m.ContentType == "" {
+				m.ContentType = contentType
+			}
+			if m.Filename == "" {
+				m.Filename = path.Base(m.URL)
+			}
+		}
+
+		total += len(m.Data)
+		if total > attachmentsLimit {
+			return nil, nil, AttachmentsTooLargeErr
+		}
+
+		if m.ContentType == "" {
+			m.ContentType = mime.TypeByExtension(path.Ext(m.Filename))
+		}
+		if m.ContentType == "" {
+			m.ContentType = http.DetectContentType(m.Data)
+		}
+
+		if m.IsInline() {
+			inline = append(inline, m)
+		} else {
+			attachments = append(attachments, m)
+		}
+	}
+
+	return inline, attachments, nil
+}
+
+func download(url string, limit int) ([]byte, string, error) {
+	response, err := httpClient.Get(url)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download media: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("failed to download media '%s': status %d", url, response.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(response.Body, int64(limit)+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download media: %w", err)
+	}
+	if len(data) > limit {
+		return nil, "", AttachmentsTooLargeErr
+	}
+
+	return data, response.Header.Get("Content-Type"), nil
+}
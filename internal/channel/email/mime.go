@@ -0,0 +1,140 @@
+package email
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"github.com/keweegen/notification/internal/channel/outbound"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+)
+
+const (
+	// textLineLimit is the line length the plain text alternative is wrapped to.
+	textLineLimit = 78
+	// base64LineLimit is the line length of base64 encoded parts required by RFC 2045.
+	base64LineLimit = 76
+)
+
+// entity is an encoded MIME body with its content type.
+type entity struct {
+	contentType string
+	body        []byte
+}
+
+// part is a body part of a multipart entity with its encoded content.
+type part struct {
+	header textproto.MIMEHeader
+	body   []byte
+}
+
+// makeAlternative builds the multipart/alternative entity holding the plain text and the HTML
+// versions of the message, both quoted-printable encoded. Clients display the last part they support.
+func makeAlternative(text, htmlText string) (*entity, error) {
+	textPart, err := makeTextPart("text/plain", wrapText(text, textLineLimit))
+	if err != nil {
+		return nil, err
+	}
+	htmlPart, err := makeTextPart("text/html", htmlText)
+	if err != nil {
+		return nil, err
+	}
+
+	return makeMultipart("alternative", textPart, htmlPart)
+}
+
+// makeRelated wraps the entity with the inline media it references by Content-ID.
+func makeRelated(content *entity, media []outbound.Media) (*entity, error) {
+	parts := []*part{makeEntityPart(content)}
+	for _, m := range media {
+		parts = append(parts, makeMediaPart(m, "inline"))
+	}
+
+	return makeMultipart("related", parts...)
+}
+
+// makeMixed wraps the entity with the attachments.
+func makeMixed(content *entity, media []outbound.Media) (*entity, error) {
+	parts := []*part{makeEntityPart(content)}
+	for _, m := range media {
+		parts = append(parts, makeMediaPart(m, "attachment"))
+	}
+
+	return makeMultipart("mixed", parts...)
+}
+
+func makeMultipart(subtype string, parts ...*part) (*entity, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	for _, p := range parts {
+		w, err := writer.CreatePart(p.header)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s part: %w", p.header.Get("Content-Type"), err)
+		}
+		if _, err = w.Write(p.body); err != nil {
+			return nil, fmt.Errorf("failed to write %s part: %w", p.header.Get("Content-Type"), err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close multipart: %w", err)
+	}
+
+	return &entity{
+		contentType: mime.FormatMediaType("multipart/"+subtype, map[string]string{"boundary": writer.Boundary()}),
+		body:        body.Bytes(),
+	}, nil
+}
+
+func makeEntityPart(e *entity) *part {
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Type", e.contentType)
+
+	return &part{header: header, body: e.body}
+}
+
+func makeTextPart(contentType, text string) (*part, error) {
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Type", mime.FormatMediaType(contentType, map[string]string{"charset": "UTF-8"}))
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+
+	var body bytes.Buffer
+	encoder := quotedprintable.NewWriter(&body)
+	if _, err := encoder.Write([]byte(text)); err != nil {
+		return nil, fmt.Errorf("failed to encode %s part: %w", contentType, err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode %s part: %w", contentType, err)
+	}
+
+	return &part{header: header, body: body.Bytes()}, nil
+}
+
+// makeMediaPart encodes the media as base64, the disposition is "inline" or "attachment".
+// Non-ASCII file names are encoded as defined by RFC 2231.
+func makeMediaPart(m outbound.Media, disposition string) *part {
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Type", m.ContentType)
+	header.Set("Content-Transfer-Encoding", "base64")
+	if m.Filename != "" {
+		header.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": m.Filename}))
+	} else {
+		header.Set("Content-Disposition", disposition)
+	}
+	if m.IsInline() {
+		header.Set("Content-ID", "<"+m.ContentID+">")
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(m.Data)
+
+	var body bytes.Buffer
+	for len(encoded) > base64LineLimit {
+		body.WriteString(encoded[:base64LineLimit] + "\r\n")
+		encoded = encoded[base64LineLimit:]
+	}
+	body.WriteString(encoded)
+
+	return &part{header: header, body: body.Bytes()}
+}
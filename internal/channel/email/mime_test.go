@@ -0,0 +1,122 @@
+package email
+
+import (
+	"bytes"
+	"encoding/base64"
+	"github.com/keweegen/notification/internal/channel/outbound"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"mime"
+	"mime/multipart"
+	"strings"
+	"testing"
+)
+
+func TestMakeAlternative(t *testing.T) {
+	htmlText := "<p>" + strings.Repeat("Заказ успешно оплачен. ", 20) + "</p>"
+	text := htmlToText(htmlText)
+
+	content, err := makeAlternative(text, htmlText)
+	assert.Nil(t, err)
+
+	mediaType, params, err := mime.ParseMediaType(content.contentType)
+	assert.Nil(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	for _, line := range strings.Split(string(content.body), "\r\n") {
+		assert.LessOrEqual(t, len(line), 76)
+	}
+
+	reader := multipart.NewReader(bytes.NewReader(content.body), params["boundary"])
+	expected := []struct {
+		contentType string
+		body        string
+	}{
+		{contentType: "text/plain; charset=UTF-8", body: strings.ReplaceAll(wrapText(text, textLineLimit), "\n", "\r\n")},
+		{contentType: "text/html; charset=UTF-8", body: htmlText},
+	}
+
+	for _, e := range expected {
+		part, err := reader.NextPart()
+		assert.Nil(t, err)
+		assert.Equal(t, e.contentType, part.Header.Get("Content-Type"))
+
+		body, err := io.ReadAll(part)
+		assert.Nil(t, err)
+		assert.Equal(t, e.body, string(body))
+	}
+
+	_, err = reader.NextPart()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestEnvelope_MakeContent(t *testing.T) {
+	logo := outbound.Media{Kind: outbound.Photo, Filename: "logo.png", ContentType: "image/png", Data: bytes.Repeat([]byte("png"), 100), ContentID: "logo"}
+	receipt := outbound.Media{Kind: outbound.Document, Filename: "Чек.pdf", Data: []byte("%PDF-1.4\n")}
+
+	c := new(client).init("no-reply@example.com", nil)
+	e, err := c.makeEnvelope("<1@example.com>", "user@example.com", &outbound.Message{Text: `<img src="cid:logo" alt="Logo">`, Media: []outbound.Media{logo, receipt}})
+	assert.Nil(t, err)
+
+	content, err := e.makeContent()
+	assert.Nil(t, err)
+
+	mediaType, params, err := mime.ParseMediaType(content.contentType)
+	assert.Nil(t, err)
+	assert.Equal(t, "multipart/mixed", mediaType)
+
+	mixed := multipart.NewReader(bytes.NewReader(content.body), params["boundary"])
+
+	related, err := mixed.NextPart()
+	assert.Nil(t, err)
+	mediaType, params, err = mime.ParseMediaType(related.Header.Get("Content-Type"))
+	assert.Nil(t, err)
+	assert.Equal(t, "multipart/related", mediaType)
+
+	relatedReader := multipart.NewReader(related, params["boundary"])
+	alternative, err := relatedReader.NextPart()
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(alternative.Header.Get("Content-Type"), "multipart/alternative"))
+
+	inline, err := relatedReader.NextPart()
+	assert.Nil(t, err)
+	assert.Equal(t, "<logo>", inline.Header.Get("Content-Id"))
+	assert.Equal(t, `inline; filename=logo.png`, inline.Header.Get("Content-Disposition"))
+	assertBase64Body(t, inline, logo.Data)
+
+	_, err = relatedReader.NextPart()
+	assert.Equal(t, io.EOF, err)
+
+	attachment, err := mixed.NextPart()
+	assert.Nil(t, err)
+	assert.Equal(t, "application/pdf", attachment.Header.Get("Content-Type"))
+	_, dispositionParams, err := mime.ParseMediaType(attachment.Header.Get("Content-Disposition"))
+	assert.Nil(t, err)
+	assert.Equal(t, "Чек.pdf", dispositionParams["filename"])
+	assertBase64Body(t, attachment, receipt.Data)
+
+	_, err = mixed.NextPart()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestClient_MakeEnvelope_AttachmentsTooLarge(t *testing.T) {
+	large := outbound.Media{Kind: outbound.Document, Filename: "large.bin", Data: make([]byte, attachmentsLimit+1)}
+
+	c := new(client).init("no-reply@example.com", nil)
+	_, err := c.makeEnvelope("<1@example.com>", "user@example.com", &outbound.Message{Text: "text", Media: []outbound.Media{large}})
+	assert.ErrorIs(t, err, AttachmentsTooLargeErr)
+}
+
+func assertBase64Body(t *testing.T, part *multipart.Part, expected []byte) {
+	t.Helper()
+
+	raw, err := io.ReadAll(part)
+	assert.Nil(t, err)
+	for _, line := range strings.Split(string(raw), "\r\n") {
+		assert.LessOrEqual(t, len(line), base64LineLimit)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(raw), "\r\n", ""))
+	assert.Nil(t, err)
+	assert.Equal(t, expected, data)
+}
@@ -0,0 +1,305 @@
+package email
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/keweegen/notification/config"
+	"net"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// TLSImplicit connects over TLS from the start, usually to port 465.
+	TLSImplicit = "implicit"
+	// TLSStartTLS upgrades a plain connection with the STARTTLS command, usually on ports 587 and 25.
+	TLSStartTLS = "starttls"
+	TLSNone     = "none"
+)
+
+const (
+	implicitTLSPort    = 465
+	defaultPoolSize    = 4
+	defaultIdleTimeout = time.Minute
+	defaultTimeout     = 30 * time.Second
+)
+
+// smtpTransport delivers messages over a pool of authenticated SMTP connections reused between messages,
+// the messages are DKIM-signed when there is a key for the sender domain.
+type smtpTransport struct {
+	cfg     config.Email
+	signers map[string]*dkimSigner
+	slots   chan struct{}
+
+	mx   sync.Mutex
+	idle []*connection
+}
+
+type connection struct {
+	conn     net.Conn
+	client   *smtp.Client
+	lastUsed time.Time
+}
+
+func newSMTPTransport(cfg config.Email) (*smtpTransport, error) {
+	signers, err := makeDKIMSigners(cfg.DKIM)
+	if err != nil {
+		return nil, err
+	}
+
+	size := cfg.PoolSize
+	if size <= 0 {
+		size = defaultPoolSize
+	}
+
+	return &smtpTransport{
+		cfg:     cfg,
+		signers: signers,
+		slots:   make(chan struct{}, size),
+		idle:    make([]*connection, 0, size),
+	}, nil
+}
+
+// send delivers the message, at most PoolSize messages are sent at once.
+// The Message-ID header is the provider message id.
+func (t *smtpTransport) send(e *envelope) (string, error) {
+	message, err := e.compose(signerFor(t.signers, e.from), time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	t.slots <- struct{}{}
+	defer func() { <-t.slots }()
+
+	c, err := t.get()
+	if err != nil {
+		return "", err
+	}
+
+	err = t.deliver(c, e.from, e.to, message)
+	t.put(c, err)
+	if err != nil {
+		return "", err
+	}
+
+	return e.messageID, nil
+}
+
+// Close ends the idle connections.
+func (t *smtpTransport) Close() error {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	for _, c := range t.idle {
+		c.quit()
+	}
+	t.idle = t.idle[:0]
+
+	return nil
+}
+
+func (t *smtpTransport) deliver(c *connection, from, to string, message []byte) error {
+	c.extendDeadline(t.timeout())
+
+	if err := c.client.Mail(from); err != nil {
+		return wrapError("MAIL", err)
+	}
+	if err := c.client.Rcpt(to); err != nil {
+		return wrapError("RCPT", err)
+	}
+
+	w, err := c.client.Data()
+	if err != nil {
+		return wrapError("DATA", err)
+	}
+	if _, err = w.Write(message); err != nil {
+		return wrapError("DATA", err)
+	}
+
+	return wrapError("DATA", w.Close())
+}
+
+// get takes the most recently used idle connection, expired connections and the ones closed
+// by the server are dropped. A new connection is opened when none is left.
+func (t *smtpTransport) get() (*connection, error) {
+	for {
+		c := t.pop()
+		if c == nil {
+			return t.dial()
+		}
+
+		c.extendDeadline(t.timeout())
+		if err := c.client.Noop(); err == nil {
+			return c, nil
+		}
+		c.close()
+	}
+}
+
+func (t *smtpTransport) pop() *connection {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	t.closeExpired()
+	if len(t.idle) == 0 {
+		return nil
+	}
+
+	c := t.idle[len(t.idle)-1]
+	t.idle = t.idle[:len(t.idle)-1]
+
+	return c
+}
+
+// put returns the connection to the pool unless it failed, a refused message leaves the
+// connection usable once the transaction is reset.
+func (t *smtpTransport) put(c *connection, err error) {
+	var replyErr *Error
+	if err != nil && (!errors.As(err, &replyErr) || c.client.Reset() != nil) {
+		c.close()
+		return
+	}
+
+	c.lastUsed = time.Now()
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	t.idle = append(t.idle, c)
+	t.closeExpired()
+}
+
+// closeExpired ends the connections idle longer than IdleTimeout, the oldest are at the start.
+func (t *smtpTransport) closeExpired() {
+	expired := 0
+	for expired < len(t.idle) && time.Since(t.idle[expired].lastUsed) > t.idleTimeout() {
+		t.idle[expired].quit()
+		expired++
+	}
+	t.idle = append(t.idle[:0], t.idle[expired:]...)
+}
+
+func (t *smtpTransport) dial() (*connection, error) {
+	tlsConfig, err := t.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	auth, err := makeAuth(t.cfg.Auth, t.cfg.Username, t.cfg.Password, t.cfg.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := t.tlsMode()
+	address := net.JoinHostPort(t.cfg.Host, strconv.Itoa(int(t.cfg.Port)))
+	dialer := &net.Dialer{Timeout: t.timeout()}
+
+	var conn net.Conn
+	switch mode {
+	case TLSImplicit:
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, tlsConfig)
+	case TLSStartTLS, TLSNone:
+		conn, err = dialer.Dial("tcp", address)
+	default:
+		return nil, fmt.Errorf("unknown smtp tls mode '%s'", mode)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	_ = conn.SetDeadline(time.Now().Add(t.timeout()))
+
+	client, err := smtp.NewClient(conn, t.cfg.Host)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to connect to smtp server: %w", wrapError("greeting", err))
+	}
+	c := &connection{conn: conn, client: client}
+
+	if mode == TLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			c.close()
+			return nil, errors.New("smtp server does not support STARTTLS")
+		}
+		if err = client.StartTLS(tlsConfig); err != nil {
+			c.close()
+			return nil, fmt.Errorf("failed to start tls: %w", wrapError("STARTTLS", err))
+		}
+	}
+
+	if auth != nil {
+		if err = client.Auth(auth); err != nil {
+			c.close()
+			return nil, fmt.Errorf("failed to authenticate: %w", wrapError("AUTH", err))
+		}
+	}
+
+	return c, nil
+}
+
+func (t *smtpTransport) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         t.cfg.Host,
+		InsecureSkipVerify: t.cfg.InsecureSkipVerify,
+	}
+	if t.cfg.CAFile == "" {
+		return tlsConfig, nil
+	}
+
+	pem, err := os.ReadFile(t.cfg.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read smtp ca file: %w", err)
+	}
+
+	tlsConfig.RootCAs = x509.NewCertPool()
+	if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates in smtp ca file '%s'", t.cfg.CAFile)
+	}
+
+	return tlsConfig, nil
+}
+
+// tlsMode defaults to implicit TLS on port 465 and STARTTLS on other ports.
+func (t *smtpTransport) tlsMode() string {
+	if t.cfg.TLS != "" {
+		return strings.ToLower(t.cfg.TLS)
+	}
+	if t.cfg.Port == implicitTLSPort {
+		return TLSImplicit
+	}
+	return TLSStartTLS
+}
+
+func (t *smtpTransport) timeout() time.Duration {
+	if t.cfg.Timeout <= 0 {
+		return defaultTimeout
+	}
+	return t.cfg.Timeout
+}
+
+func (t *smtpTransport) idleTimeout() time.Duration {
+	if t.cfg.IdleTimeout <= 0 {
+		return defaultIdleTimeout
+	}
+	return t.cfg.IdleTimeout
+}
+
+func (c *connection) extendDeadline(timeout time.Duration) {
+	_ = c.conn.SetDeadline(time.Now().Add(timeout))
+}
+
+// quit ends the session politely, the server may have closed the connection already.
+func (c *connection) quit() {
+	c.extendDeadline(time.Second)
+	if err := c.client.Quit(); err != nil {
+		c.close()
+	}
+}
+
+func (c *connection) close() {
+	_ = c.client.Close()
+}
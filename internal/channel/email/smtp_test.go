@@ -0,0 +1,192 @@
+package email
+
+import (
+	"github.com/keweegen/notification/config"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// fakeSMTP accepts messages over plain connections, recipients containing "busy"
+// are refused temporarily and recipients containing "unknown" permanently.
+type fakeSMTP struct {
+	listener    net.Listener
+	connections int32
+
+	mx       sync.Mutex
+	messages []string
+}
+
+func startFakeSMTP(t *testing.T) *fakeSMTP {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+
+	s := &fakeSMTP{listener: listener}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			atomic.AddInt32(&s.connections, 1)
+			go s.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { _ = listener.Close() })
+
+	return s
+}
+
+func (s *fakeSMTP) config() config.Email {
+	host, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	p, _ := strconv.Atoi(port)
+
+	return config.Email{Host: host, Port: uint(p), TLS: TLSNone, PoolSize: 1}
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+
+	c := textproto.NewConn(conn)
+	_ = c.PrintfLine("220 localhost ESMTP")
+
+	for {
+		line, err := c.ReadLine()
+		if err != nil {
+			return
+		}
+
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch {
+		case command == "EHLO":
+			_ = c.PrintfLine("250-localhost")
+			_ = c.PrintfLine("250 8BITMIME")
+		case command == "RCPT" && strings.Contains(line, "busy"):
+			_ = c.PrintfLine("450 4.2.1 Mailbox busy")
+		case command == "RCPT" && strings.Contains(line, "unknown"):
+			_ = c.PrintfLine("550 5.1.1 User unknown")
+		case command == "DATA":
+			_ = c.PrintfLine("354 Go ahead")
+			data, err := c.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.mx.Lock()
+			s.messages = append(s.messages, string(data))
+			s.mx.Unlock()
+			_ = c.PrintfLine("250 2.0.0 Queued")
+		case command == "QUIT":
+			_ = c.PrintfLine("221 Bye")
+			return
+		default:
+			_ = c.PrintfLine("250 OK")
+		}
+	}
+}
+
+func testEnvelope(to, text string) *envelope {
+	return &envelope{
+		from:      "no-reply@example.com",
+		to:        to,
+		messageID: "<" + to + ">",
+		subject:   text,
+		headers:   headers{{name: "Subject", value: text}},
+		text:      text,
+		html:      "<p>" + text + "</p>",
+	}
+}
+
+func TestSMTPTransport_Send(t *testing.T) {
+	server := startFakeSMTP(t)
+	tr, err := newSMTPTransport(server.config())
+	assert.Nil(t, err)
+	defer tr.Close()
+
+	id, err := tr.send(testEnvelope("first@example.com", "first"))
+	assert.Nil(t, err)
+	assert.Equal(t, "<first@example.com>", id)
+
+	_, err = tr.send(testEnvelope("busy@example.com", "busy"))
+	var replyErr *Error
+	assert.ErrorAs(t, err, &replyErr)
+	assert.Equal(t, 450, replyErr.Code)
+	assert.True(t, replyErr.Temporary())
+	assert.False(t, replyErr.RecipientUnreachable())
+
+	_, err = tr.send(testEnvelope("unknown@example.com", "unknown"))
+	assert.ErrorAs(t, err, &replyErr)
+	assert.True(t, replyErr.Permanent())
+	assert.True(t, replyErr.RecipientUnreachable())
+
+	_, err = tr.send(testEnvelope("second@example.com", "second"))
+	assert.Nil(t, err)
+
+	assert.Len(t, server.messages, 2)
+	assert.Contains(t, server.messages[0], "first")
+	assert.Contains(t, server.messages[1], "second")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&server.connections))
+}
+
+func TestSMTPTransport_ReconnectsClosedConnection(t *testing.T) {
+	server := startFakeSMTP(t)
+	tr, err := newSMTPTransport(server.config())
+	assert.Nil(t, err)
+	defer tr.Close()
+
+	_, err = tr.send(testEnvelope("first@example.com", "first"))
+	assert.Nil(t, err)
+	tr.idle[0].close()
+	_, err = tr.send(testEnvelope("second@example.com", "second"))
+	assert.Nil(t, err)
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&server.connections))
+}
+
+func TestSMTPTransport_StartTLSUnsupported(t *testing.T) {
+	server := startFakeSMTP(t)
+	cfg := server.config()
+	cfg.TLS = TLSStartTLS
+
+	tr, err := newSMTPTransport(cfg)
+	assert.Nil(t, err)
+
+	_, err = tr.send(testEnvelope("first@example.com", "first"))
+	assert.EqualError(t, err, "smtp server does not support STARTTLS")
+}
+
+func TestLoginAuth(t *testing.T) {
+	auth := &loginAuth{username: "user", password: "secret"}
+
+	mechanism, initial, err := auth.Start(&smtp.ServerInfo{TLS: true})
+	assert.Nil(t, err)
+	assert.Equal(t, "LOGIN", mechanism)
+	assert.Nil(t, initial)
+
+	username, err := auth.Next([]byte("Username:"), true)
+	assert.Nil(t, err)
+	assert.Equal(t, "user", string(username))
+
+	password, err := auth.Next([]byte("Password:"), true)
+	assert.Nil(t, err)
+	assert.Equal(t, "secret", string(password))
+
+	_, _, err = auth.Start(&smtp.ServerInfo{TLS: false})
+	assert.NotNil(t, err)
+}
+
+func TestXOAuth2Auth(t *testing.T) {
+	auth := &xoauth2Auth{username: "user@example.com", token: "token"}
+
+	mechanism, initial, err := auth.Start(&smtp.ServerInfo{TLS: true})
+	assert.Nil(t, err)
+	assert.Equal(t, "XOAUTH2", mechanism)
+	assert.Equal(t, "user=user@example.com\x01auth=Bearer token\x01\x01", string(initial))
+}
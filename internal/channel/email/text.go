@@ -0,0 +1,221 @@
+package email
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	hrefPattern = regexp.MustCompile(`(?is)\shref\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+	altPattern  = regexp.MustCompile(`(?is)\salt\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+	blankLines  = regexp.MustCompile(`\n{3,}`)
+)
+
+// paragraphTags are separated by a blank line, lineTags start on a new line.
+var (
+	paragraphTags = map[string]bool{
+		"p": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+		"table": true, "ul": true, "ol": true, "blockquote": true, "pre": true, "hr": true,
+	}
+	lineTags = map[string]bool{
+		"div": true, "tr": true, "li": true, "section": true, "header": true, "footer": true,
+	}
+	hiddenTags = map[string]bool{
+		"head": true, "style": true, "script": true, "title": true,
+	}
+)
+
+// htmlToText converts the HTML body into its plain text alternative: block elements become
+// line breaks, links keep their address after the label and images are replaced by their alt text.
+func htmlToText(s string) string {
+	var b strings.Builder
+	hidden := ""
+	hrefs := make([]string, 0)
+	labelStarts := make([]int, 0)
+
+	for i := 0; i < len(s); {
+		if s[i] != '<' {
+			next := strings.IndexByte(s[i:], '<')
+			if next < 0 {
+				next = len(s) - i
+			}
+			if hidden == "" {
+				writeCollapsed(&b, html.UnescapeString(s[i:i+next]))
+			}
+			i += next
+			continue
+		}
+
+		if strings.HasPrefix(s[i:], "<!--") {
+			end := strings.Index(s[i:], "-->")
+			if end < 0 {
+				break
+			}
+			i += end + 3
+			continue
+		}
+
+		end := strings.IndexByte(s[i:], '>')
+		if end < 0 {
+			if hidden == "" {
+				writeCollapsed(&b, s[i:])
+			}
+			break
+		}
+		raw := s[i : i+end+1]
+		i += end + 1
+
+		name, closing := tagName(raw)
+		if name == "" {
+			if hidden == "" && !strings.HasPrefix(raw, "<!") {
+				writeCollapsed(&b, html.UnescapeString(raw))
+			}
+			continue
+		}
+		if hidden != "" {
+			if closing && name == hidden {
+				hidden = ""
+			}
+			continue
+		}
+
+		switch {
+		case hiddenTags[name] && !closing:
+			hidden = name
+		case name == "br":
+			b.WriteString("\n")
+		case paragraphTags[name]:
+			breakLines(&b, 2)
+		case lineTags[name]:
+			breakLines(&b, 1)
+			if name == "li" && !closing {
+				b.WriteString("- ")
+			}
+		case name == "td" || name == "th":
+			if closing {
+				b.WriteString(" ")
+			}
+		case name == "img" && !closing:
+			writeCollapsed(&b, html.UnescapeString(attribute(altPattern, raw)))
+		case name == "a" && !closing:
+			hrefs = append(hrefs, html.UnescapeString(attribute(hrefPattern, raw)))
+			labelStarts = append(labelStarts, b.Len())
+		case name == "a" && closing && len(hrefs) > 0:
+			last := len(hrefs) - 1
+			href, label := hrefs[last], strings.TrimSpace(b.String()[labelStarts[last]:])
+			hrefs, labelStarts = hrefs[:last], labelStarts[:last]
+
+			if href != "" && href != label && !strings.HasPrefix(href, "#") && !strings.HasPrefix(href, "mailto:") {
+				if label == "" {
+					b.WriteString(href)
+				} else {
+					b.WriteString(" (" + href + ")")
+				}
+			}
+		}
+	}
+
+	lines := strings.Split(b.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// wrapText wraps lines longer than the limit at spaces, words longer than the limit such as links are kept whole.
+func wrapText(text string, limit int) string {
+	lines := strings.Split(text, "\n")
+
+	for i, line := range lines {
+		if utf8.RuneCountInString(line) <= limit {
+			continue
+		}
+
+		var b strings.Builder
+		length := 0
+		for j, word := range strings.Fields(line) {
+			wordLength := utf8.RuneCountInString(word)
+			if j > 0 {
+				if length+1+wordLength > limit {
+					b.WriteString("\n")
+					length = 0
+				} else {
+					b.WriteString(" ")
+					length++
+				}
+			}
+			b.WriteString(word)
+			length += wordLength
+		}
+		lines[i] = b.String()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// writeCollapsed writes the text collapsing whitespace runs into a single space as HTML renders it.
+func writeCollapsed(b *strings.Builder, text string) {
+	space := b.Len() > 0 && isSpaceEnd(b.String())
+
+	for _, r := range text {
+		if unicode.IsSpace(r) {
+			if !space {
+				b.WriteByte(' ')
+				space = true
+			}
+			continue
+		}
+		b.WriteRune(r)
+		space = false
+	}
+}
+
+// breakLines ends the text with at least n line breaks.
+func breakLines(b *strings.Builder, n int) {
+	text := strings.TrimRight(b.String(), " ")
+	if text == "" {
+		return
+	}
+
+	for i := len(text) - 1; i >= 0 && text[i] == '\n' && n > 0; i-- {
+		n--
+	}
+	b.WriteString(strings.Repeat("\n", n))
+}
+
+func isSpaceEnd(s string) bool {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return unicode.IsSpace(r)
+}
+
+func tagName(raw string) (string, bool) {
+	s := strings.TrimPrefix(raw, "<")
+	closing := strings.HasPrefix(s, "/")
+	s = strings.TrimPrefix(s, "/")
+
+	end := strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+	if end < 0 {
+		end = len(s)
+	}
+
+	return strings.ToLower(s[:end]), closing
+}
+
+func attribute(pattern *regexp.Regexp, raw string) string {
+	match := pattern.FindStringSubmatch(raw)
+	if match == nil {
+		return ""
+	}
+	for _, value := range match[1:] {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
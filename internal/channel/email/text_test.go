@@ -0,0 +1,64 @@
+package email
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestHtmlToText(t *testing.T) {
+	cases := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{
+			name: "receipt",
+			html: `<h3>Чек</h3>
+
+<p>Заказ <b>123</b> успешно оплачен</p>
+
+<p>
+    Комиссия: 300,00 KZT <br/>
+    Сумма к списанию: 1300,00 KZT
+</p>`,
+			expected: "Чек\n\nЗаказ 123 успешно оплачен\n\nКомиссия: 300,00 KZT\nСумма к списанию: 1300,00 KZT",
+		},
+		{
+			name:     "links",
+			html:     `<a href="https://example.com/orders/1?a=1&amp;b=2">Заказ</a>, <a href="https://example.com">https://example.com</a>, <a href="#top">наверх</a>`,
+			expected: "Заказ (https://example.com/orders/1?a=1&b=2), https://example.com, наверх",
+		},
+		{
+			name:     "hidden content and images",
+			html:     `<html><head><title>t</title><style>p {color: red}</style></head><body><img src="cid:logo" alt="Logo"><!-- c --><ul><li>one</li><li>two</li></ul></body></html>`,
+			expected: "Logo\n\n- one\n- two",
+		},
+		{
+			name:     "stray brackets",
+			html:     "1 < 2 &amp; 3 > 2",
+			expected: "1 < 2 & 3 > 2",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, htmlToText(tc.html))
+		})
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	link := "https://example.com/" + strings.Repeat("a", 100)
+	text := strings.Repeat("слово ", 30) + link + "\n\nкоротко"
+
+	wrapped := wrapText(text, textLineLimit)
+	for _, line := range strings.Split(wrapped, "\n") {
+		if line != link {
+			assert.LessOrEqual(t, utf8.RuneCountInString(line), textLineLimit)
+		}
+	}
+	assert.Contains(t, wrapped, "\n"+link+"\n")
+	assert.True(t, strings.HasSuffix(wrapped, "\n\nкоротко"))
+}
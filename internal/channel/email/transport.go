@@ -0,0 +1,30 @@
+package email
+
+import (
+	"fmt"
+	"github.com/keweegen/notification/config"
+	"strings"
+)
+
+const (
+	TransportSMTP = "smtp"
+	TransportHTTP = "http"
+)
+
+// transport delivers composed messages and returns the id assigned to them by the provider.
+type transport interface {
+	send(e *envelope) (string, error)
+	Close() error
+}
+
+// newTransport returns the transport selected by the config, SMTP by default.
+func newTransport(cfg config.Email) (transport, error) {
+	switch strings.ToLower(cfg.Transport) {
+	case "", TransportSMTP:
+		return newSMTPTransport(cfg)
+	case TransportHTTP:
+		return newHTTPTransport(cfg.HTTP)
+	default:
+		return nil, fmt.Errorf("unknown email transport '%s'", cfg.Transport)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	outbound "github.com/keweegen/notification/internal/channel/outbound"
 )
 
 // MockDriver is a mock of Driver interface.
@@ -34,11 +35,12 @@ func (m *MockDriver) EXPECT() *MockDriverMockRecorder {
 }
 
 // Send mocks base method.
-func (m *MockDriver) Send(receiver, message string) error {
+func (m *MockDriver) Send(receiver string, message *outbound.Message) (outbound.Delivery, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Send", receiver, message)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(outbound.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Send indicates an expected call of Send.
@@ -46,3 +48,54 @@ func (mr *MockDriverMockRecorder) Send(receiver, message interface{}) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockDriver)(nil).Send), receiver, message)
 }
+
+// MockEditor is a mock of Editor interface.
+type MockEditor struct {
+	ctrl     *gomock.Controller
+	recorder *MockEditorMockRecorder
+}
+
+// MockEditorMockRecorder is the mock recorder for MockEditor.
+type MockEditorMockRecorder struct {
+	mock *MockEditor
+}
+
+// NewMockEditor creates a new mock instance.
+func NewMockEditor(ctrl *gomock.Controller) *MockEditor {
+	mock := &MockEditor{ctrl: ctrl}
+	mock.recorder = &MockEditorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEditor) EXPECT() *MockEditorMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockEditor) Delete(receiver string, delivery outbound.Delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", receiver, delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockEditorMockRecorder) Delete(receiver, delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockEditor)(nil).Delete), receiver, delivery)
+}
+
+// Edit mocks base method.
+func (m *MockEditor) Edit(receiver string, delivery outbound.Delivery, message *outbound.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Edit", receiver, delivery, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Edit indicates an expected call of Edit.
+func (mr *MockEditorMockRecorder) Edit(receiver, delivery, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Edit", reflect.TypeOf((*MockEditor)(nil).Edit), receiver, delivery, message)
+}
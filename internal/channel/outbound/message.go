@@ -0,0 +1,99 @@
+package outbound
+
+// Message is the rendered content handed to a channel driver.
+type Message struct {
+	Text    string
+	Format  Format
+	Media   []Media
+	Actions []Action
+
+	// Subject, Preheader, Headers and PlainText are used by email only.
+	// Without PlainText the plain text alternative is generated from the HTML text.
+	Subject   string
+	Preheader string
+	Headers   map[string]string
+	PlainText string
+}
+
+// Format is the markup the text is written in.
+type Format int
+
+const (
+	FormatPlain Format = iota
+	FormatHTML
+	FormatMarkdownV2
+)
+
+type MediaKind int
+
+const (
+	Photo MediaKind = iota + 1
+	Document
+)
+
+// Media is a file attached to the message, either by URL or by its content.
+// Media with ContentID are shown inline by email, referenced from the HTML as "cid:<ContentID>".
+type Media struct {
+	Kind        MediaKind
+	URL         string
+	Filename    string
+	ContentType string
+	Data        []byte
+	ContentID   string
+}
+
+func (m Media) IsUpload() bool {
+	return m.URL == ""
+}
+
+func (m Media) IsInline() bool {
+	return m.ContentID != ""
+}
+
+// Action is a button attached to the message. It opens the URL, actions without URL
+// are callbacks reported back to the producer by their ID.
+type Action struct {
+	ID    string
+	Label string
+	URL   string
+}
+
+func (a Action) IsCallback() bool {
+	return a.URL == ""
+}
+
+// Delivery is what the provider assigned to a delivered message, needed to edit or delete it later.
+type Delivery struct {
+	// ID is the message the provider refers to in callbacks and reports, the one holding the text and the buttons.
+	ID string
+	// Parts are all the messages the message was sent as, in order, when the provider splits it.
+	Parts []Part
+}
+
+type PartKind string
+
+const (
+	// PartText is a message holding a chunk of the text.
+	PartText PartKind = "text"
+	// PartCaption is a media holding the whole text as its caption.
+	PartCaption PartKind = "caption"
+	// PartMedia is a media without text.
+	PartMedia PartKind = "media"
+)
+
+// Part is one of the messages a message was sent as.
+type Part struct {
+	ID   string   `json:"id"`
+	Kind PartKind `json:"kind"`
+}
+
+// TextParts returns the parts holding the text, in order.
+func (d Delivery) TextParts() []Part {
+	parts := make([]Part, 0, len(d.Parts))
+	for _, p := range d.Parts {
+		if p.Kind != PartMedia {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
@@ -0,0 +1,18 @@
+package telegram
+
+import (
+	"context"
+	"github.com/keweegen/notification/internal/channel/outbound"
+	"time"
+)
+
+//go:generate mockgen -source=bot.go -destination=./mock/bot.go
+type Bot interface {
+	Send(receiver string, message *outbound.Message) (outbound.Delivery, error)
+	Edit(receiver string, delivery outbound.Delivery, message *outbound.Message) error
+	Delete(receiver string, delivery outbound.Delivery) error
+	GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]Update, error)
+	SetWebhook(url, secretToken string) error
+	DeleteWebhook() error
+	AnswerCallbackQuery(callbackQueryID, text string) error
+}
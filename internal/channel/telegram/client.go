@@ -1,22 +1,94 @@
 package telegram
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/keweegen/notification/internal/channel/outbound"
 	"io"
 	"net/http"
 	"net/url"
 	"path"
+	"strconv"
+	"strings"
 	"time"
 )
 
 const (
-	sendMessageAction = "sendMessage"
+	sendMessageAction     = "sendMessage"
+	editMessageTextAction = "editMessageText"
+	editCaptionAction     = "editMessageCaption"
+	deleteMessageAction   = "deleteMessage"
+	getUpdatesAction      = "getUpdates"
+	setWebhookAction      = "setWebhook"
+	deleteWebhookAction   = "deleteWebhook"
+	answerCallbackAction  = "answerCallbackQuery"
+
+	allowedUpdates = `["message","callback_query"]`
+)
+
+var (
+	EmptyTextErr   = errors.New("text is empty")
+	TextTooLongErr = errors.New("text does not fit into the sent messages")
 )
 
 var httpClient = &http.Client{
 	Timeout: 10 * time.Second,
 }
 
+// pollingHTTPClient has no own timeout, getUpdates requests are bounded by their context.
+var pollingHTTPClient = &http.Client{}
+
+// recipientUnreachableDescriptions are the error descriptions returned by the Bot API
+// when the chat can no longer receive messages from the bot.
+var recipientUnreachableDescriptions = []string{
+	"bot was blocked by the user",
+	"chat not found",
+	"user is deactivated",
+	"bot was kicked",
+}
+
+type response struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	ErrorCode   int             `json:"error_code"`
+	Description string          `json:"description"`
+}
+
+// Error is an unsuccessful Bot API response.
+type Error struct {
+	Code        int
+	Description string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("telegram api error %d: %s", e.Code, e.Description)
+}
+
+// isNotModified reports whether the edit was refused because the message already has the new content.
+func isNotModified(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Code == http.StatusBadRequest &&
+		strings.Contains(strings.ToLower(e.Description), "message is not modified")
+}
+
+// RecipientUnreachable reports whether the chat will never accept messages from the bot again.
+func (e *Error) RecipientUnreachable() bool {
+	if e.Code != http.StatusForbidden && e.Code != http.StatusBadRequest {
+		return false
+	}
+
+	description := strings.ToLower(e.Description)
+	for _, d := range recipientUnreachableDescriptions {
+		if strings.Contains(description, d) {
+			return true
+		}
+	}
+
+	return false
+}
+
 type client struct {
 	host   string
 	apiKey string
@@ -28,33 +100,239 @@ func (c *client) init(host, apiKey string) *client {
 	return c
 }
 
-func (c *client) SendMessage(chatId string, message string, format string) error {
+// SendText sends the text split into as many messages as the length limit requires,
+// the buttons are attached to the last one. It returns the sent messages in order,
+// the ones sent before a failing message are returned with the error.
+func (c *client) SendText(chatId, text string, format outbound.Format, actions []outbound.Action) ([]outbound.Part, error) {
+	chunks := Split(text, format)
+	if len(chunks) == 0 {
+		return nil, EmptyTextErr
+	}
+
+	markup, err := replyMarkup(actions)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]outbound.Part, 0, len(chunks))
+	for i, chunk := range chunks {
+		chunkMarkup := ""
+		if i == len(chunks)-1 {
+			chunkMarkup = markup
+		}
+
+		messageId, err := c.SendMessage(chatId, chunk, parseMode(format), chunkMarkup)
+		if err != nil {
+			return parts, err
+		}
+		parts = append(parts, outbound.Part{ID: messageId, Kind: outbound.PartText})
+	}
+
+	return parts, nil
+}
+
+func (c *client) SendMessage(chatId, message, parseMode, replyMarkup string) (string, error) {
 	params := url.Values{}
 	params.Add("chat_id", chatId)
 	params.Add("text", message)
-	params.Add("parse_mode", format)
+	addParseMode(params, parseMode)
+	addReplyMarkup(params, replyMarkup)
+
+	result, err := c.do(sendMessageAction, params)
+	if err != nil {
+		return "", fmt.Errorf("failed to send message: %w", err)
+	}
+
+	sent := new(Message)
+	if err = json.Unmarshal(result, sent); err != nil {
+		return "", fmt.Errorf("failed to decode sent message: %w", err)
+	}
+
+	return strconv.FormatInt(sent.ID, 10), nil
+}
+
+// EditText replaces the text of the messages it was sent as, the buttons stay on the last one.
+// A shorter text takes fewer messages and the rest are deleted, a text needing more messages than were sent
+// cannot be edited in place.
+func (c *client) EditText(chatId string, parts []outbound.Part, text string, format outbound.Format, actions []outbound.Action) error {
+	chunks := Split(text, format)
+	if len(chunks) == 0 {
+		return EmptyTextErr
+	}
+	if len(chunks) > len(parts) {
+		return TextTooLongErr
+	}
+
+	markup, err := replyMarkup(actions)
+	if err != nil {
+		return err
+	}
+
+	last := len(chunks) - 1
+	for i, chunk := range chunks[:last] {
+		if err = c.EditMessageText(chatId, parts[i].ID, chunk, parseMode(format), ""); err != nil {
+			return err
+		}
+	}
+	if err = c.EditMessageText(chatId, parts[len(parts)-1].ID, chunks[last], parseMode(format), markup); err != nil {
+		return err
+	}
+
+	for _, p := range parts[last : len(parts)-1] {
+		if err = c.DeleteMessage(chatId, p.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// EditMessageText replaces the text and the buttons of a single message, an unchanged text is not an error.
+func (c *client) EditMessageText(chatId, messageId, text, parseMode, replyMarkup string) error {
+	params := url.Values{}
+	params.Add("chat_id", chatId)
+	params.Add("message_id", messageId)
+	params.Add("text", text)
+	addParseMode(params, parseMode)
+	addReplyMarkup(params, replyMarkup)
+
+	if _, err := c.do(editMessageTextAction, params); err != nil && !isNotModified(err) {
+		return fmt.Errorf("failed to edit message: %w", err)
+	}
+
+	return nil
+}
+
+func (c *client) EditMessageCaption(chatId, messageId, caption string, format outbound.Format, actions []outbound.Action) error {
+	caption = prepareText(caption, format)
+	if textLength(caption, format) > captionLimit {
+		return TextTooLongErr
+	}
+
+	markup, err := replyMarkup(actions)
+	if err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	params.Add("chat_id", chatId)
+	params.Add("message_id", messageId)
+	params.Add("caption", caption)
+	addParseMode(params, parseMode(format))
+	addReplyMarkup(params, markup)
+
+	if _, err = c.do(editCaptionAction, params); err != nil && !isNotModified(err) {
+		return fmt.Errorf("failed to edit message caption: %w", err)
+	}
+
+	return nil
+}
+
+func (c *client) DeleteMessage(chatId, messageId string) error {
+	params := url.Values{}
+	params.Add("chat_id", chatId)
+	params.Add("message_id", messageId)
+
+	if _, err := c.do(deleteMessageAction, params); err != nil {
+		return fmt.Errorf("failed to delete message: %w", err)
+	}
+
+	return nil
+}
+
+// AnswerCallbackQuery stops the loading indicator on the pressed button, showing the text as a notification.
+func (c *client) AnswerCallbackQuery(callbackQueryId, text string) error {
+	params := url.Values{}
+	params.Add("callback_query_id", callbackQueryId)
+	if text != "" {
+		params.Add("text", text)
+	}
+
+	if _, err := c.do(answerCallbackAction, params); err != nil {
+		return fmt.Errorf("failed to answer callback query: %w", err)
+	}
+
+	return nil
+}
+
+func (c *client) GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]Update, error) {
+	params := url.Values{}
+	params.Add("offset", strconv.FormatInt(offset, 10))
+	params.Add("timeout", strconv.Itoa(int(timeout.Seconds())))
+	params.Add("allowed_updates", allowedUpdates)
 
-	_, err := c.do(sendMessageAction, params)
+	ctx, cancel := context.WithTimeout(ctx, timeout+10*time.Second)
+	defer cancel()
+
+	result, err := c.doWithClient(ctx, pollingHTTPClient, getUpdatesAction, params)
 	if err != nil {
-		return fmt.Errorf("failed to send message: %w", err)
+		return nil, fmt.Errorf("failed to get updates: %w", err)
+	}
+
+	var updates []Update
+	if err = json.Unmarshal(result, &updates); err != nil {
+		return nil, fmt.Errorf("failed to decode updates: %w", err)
+	}
+
+	return updates, nil
+}
+
+func (c *client) SetWebhook(webhookURL, secretToken string) error {
+	params := url.Values{}
+	params.Add("url", webhookURL)
+	params.Add("allowed_updates", allowedUpdates)
+	if secretToken != "" {
+		params.Add("secret_token", secretToken)
+	}
+
+	if _, err := c.do(setWebhookAction, params); err != nil {
+		return fmt.Errorf("failed to set webhook: %w", err)
+	}
+
+	return nil
+}
+
+func (c *client) DeleteWebhook() error {
+	if _, err := c.do(deleteWebhookAction, url.Values{}); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
 	}
 
 	return nil
 }
 
-func (c *client) makeRequest(action string, params url.Values) (*http.Request, error) {
+func addParseMode(params url.Values, parseMode string) {
+	if parseMode != "" {
+		params.Add("parse_mode", parseMode)
+	}
+}
+
+func addReplyMarkup(params url.Values, replyMarkup string) {
+	if replyMarkup != "" {
+		params.Add("reply_markup", replyMarkup)
+	}
+}
+
+func (c *client) makeRequest(ctx context.Context, action string, params url.Values) (*http.Request, error) {
 	apiURL := c.makeURL(action)
 	apiURL.RawQuery = params.Encode()
 
-	return http.NewRequest(http.MethodGet, apiURL.String(), nil)
+	return http.NewRequestWithContext(ctx, http.MethodGet, apiURL.String(), nil)
 }
 
 func (c *client) do(action string, params url.Values) ([]byte, error) {
-	request, err := c.makeRequest(action, params)
+	return c.doWithClient(context.Background(), httpClient, action, params)
+}
+
+func (c *client) doWithClient(ctx context.Context, httpClient *http.Client, action string, params url.Values) ([]byte, error) {
+	request, err := c.makeRequest(ctx, action, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make http request: %w", err)
 	}
 
+	return c.send(httpClient, request)
+}
+
+func (c *client) send(httpClient *http.Client, request *http.Request) ([]byte, error) {
 	response, err := httpClient.Do(request)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send http request: %w", err)
@@ -66,7 +344,19 @@ func (c *client) do(action string, params url.Values) ([]byte, error) {
 		return nil, fmt.Errorf("failed to read http response body: %w", err)
 	}
 
-	return body, nil
+	return c.parseResponse(body)
+}
+
+func (c *client) parseResponse(body []byte) ([]byte, error) {
+	data := new(response)
+	if err := json.Unmarshal(body, data); err != nil {
+		return nil, fmt.Errorf("failed to decode http response body: %w", err)
+	}
+	if !data.OK {
+		return nil, &Error{Code: data.ErrorCode, Description: data.Description}
+	}
+
+	return data.Result, nil
 }
 
 func (c *client) makeURL(action string) *url.URL {
@@ -1,6 +1,11 @@
 package telegram
 
-import "github.com/keweegen/notification/config"
+import (
+    "context"
+    "github.com/keweegen/notification/config"
+    "github.com/keweegen/notification/internal/channel/outbound"
+    "time"
+)
 
 type Driver struct {
     client *client
@@ -10,6 +15,70 @@ func New(cfg config.Telegram) *Driver {
     return &Driver{client: new(client).init(cfg.Host, cfg.APIKey)}
 }
 
-func (d *Driver) Send(receiver, message string) error {
-    return d.client.SendMessage(receiver, message, "HTML")
+// Send delivers the message, its id is the message holding the text and the buttons,
+// the one callback queries refer to.
+func (d *Driver) Send(receiver string, message *outbound.Message) (outbound.Delivery, error) {
+    var (
+        parts []outbound.Part
+        err   error
+    )
+    if len(message.Media) > 0 {
+        parts, err = d.client.SendMedia(receiver, message.Media, message.Text, message.Format, message.Actions)
+    } else {
+        parts, err = d.client.SendText(receiver, message.Text, message.Format, message.Actions)
+    }
+    if len(parts) == 0 {
+        return outbound.Delivery{}, err
+    }
+
+    // The messages sent before a failure are returned with the error, so they can be deleted.
+    delivery := outbound.Delivery{ID: parts[0].ID, Parts: parts}
+    if textParts := delivery.TextParts(); len(textParts) > 0 {
+        delivery.ID = textParts[len(textParts)-1].ID
+    }
+    return delivery, err
+}
+
+// Edit replaces the text and the buttons of a delivered message, the media stays as it was sent.
+// The text is edited as a caption only when it was sent as one.
+func (d *Driver) Edit(receiver string, delivery outbound.Delivery, message *outbound.Message) error {
+    textParts := delivery.TextParts()
+    if len(textParts) == 0 {
+        // Messages delivered before the parts were stored were sent as a single message.
+        return d.client.EditText(receiver, []outbound.Part{{ID: delivery.ID, Kind: outbound.PartText}},
+            message.Text, message.Format, message.Actions)
+    }
+    if textParts[0].Kind == outbound.PartCaption {
+        return d.client.EditMessageCaption(receiver, textParts[0].ID, message.Text, message.Format, message.Actions)
+    }
+    return d.client.EditText(receiver, textParts, message.Text, message.Format, message.Actions)
+}
+
+// Delete removes all the messages the message was sent as.
+func (d *Driver) Delete(receiver string, delivery outbound.Delivery) error {
+    if len(delivery.Parts) == 0 {
+        return d.client.DeleteMessage(receiver, delivery.ID)
+    }
+    for _, p := range delivery.Parts {
+        if err := d.client.DeleteMessage(receiver, p.ID); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+func (d *Driver) GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]Update, error) {
+    return d.client.GetUpdates(ctx, offset, timeout)
+}
+
+func (d *Driver) SetWebhook(url, secretToken string) error {
+    return d.client.SetWebhook(url, secretToken)
+}
+
+func (d *Driver) DeleteWebhook() error {
+    return d.client.DeleteWebhook()
+}
+
+func (d *Driver) AnswerCallbackQuery(callbackQueryID, text string) error {
+    return d.client.AnswerCallbackQuery(callbackQueryID, text)
 }
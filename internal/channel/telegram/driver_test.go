@@ -0,0 +1,147 @@
+package telegram
+
+import (
+	"github.com/keweegen/notification/internal/channel/outbound"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestDriver_Edit(t *testing.T) {
+	longText := strings.Repeat("a", messageLimit) + "\n\nb"
+	actions := []outbound.Action{{ID: "confirm", Label: "Confirm delivery"}}
+
+	cases := []struct {
+		name            string
+		delivery        outbound.Delivery
+		text            string
+		media           []outbound.Media
+		expectedCalls   []string
+		expectedMessage []string
+		expectedError   error
+	}{
+		{
+			name:            "sent before the parts were stored",
+			delivery:        outbound.Delivery{ID: "42"},
+			text:            "Shipped",
+			media:           []outbound.Media{{Kind: outbound.Document, Filename: "receipt.pdf"}},
+			expectedCalls:   []string{editMessageTextAction},
+			expectedMessage: []string{"42"},
+		},
+		{
+			name: "caption",
+			delivery: outbound.Delivery{ID: "1", Parts: []outbound.Part{
+				{ID: "1", Kind: outbound.PartCaption},
+				{ID: "2", Kind: outbound.PartMedia},
+			}},
+			text:            "Shipped",
+			expectedCalls:   []string{editCaptionAction},
+			expectedMessage: []string{"1"},
+		},
+		{
+			name: "text following the media",
+			delivery: outbound.Delivery{ID: "2", Parts: []outbound.Part{
+				{ID: "1", Kind: outbound.PartMedia},
+				{ID: "2", Kind: outbound.PartText},
+			}},
+			text:            "Shipped",
+			media:           []outbound.Media{{Kind: outbound.Document, Filename: "receipt.pdf"}},
+			expectedCalls:   []string{editMessageTextAction},
+			expectedMessage: []string{"2"},
+		},
+		{
+			name: "all chunks",
+			delivery: outbound.Delivery{ID: "2", Parts: []outbound.Part{
+				{ID: "1", Kind: outbound.PartText},
+				{ID: "2", Kind: outbound.PartText},
+			}},
+			text:            longText,
+			expectedCalls:   []string{editMessageTextAction, editMessageTextAction},
+			expectedMessage: []string{"1", "2"},
+		},
+		{
+			name: "shorter text",
+			delivery: outbound.Delivery{ID: "2", Parts: []outbound.Part{
+				{ID: "1", Kind: outbound.PartText},
+				{ID: "2", Kind: outbound.PartText},
+			}},
+			text:            "Shipped",
+			expectedCalls:   []string{editMessageTextAction, deleteMessageAction},
+			expectedMessage: []string{"2", "1"},
+		},
+		{
+			name:          "longer text",
+			delivery:      outbound.Delivery{ID: "1", Parts: []outbound.Part{{ID: "1", Kind: outbound.PartText}}},
+			text:          longText,
+			expectedCalls: []string{},
+			expectedError: TextTooLongErr,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, calls := mockAPI(t)
+			d := &Driver{client: c}
+
+			err := d.Edit("1", tc.delivery, &outbound.Message{Text: tc.text, Media: tc.media, Actions: actions})
+			assert.ErrorIs(t, err, tc.expectedError)
+
+			called, messages := make([]string, 0), make([]string, 0)
+			for _, call := range *calls {
+				called = append(called, call.action)
+				messages = append(messages, call.fields["message_id"])
+			}
+			assert.Equal(t, tc.expectedCalls, called)
+			if tc.expectedError == nil {
+				assert.Equal(t, tc.expectedMessage, messages)
+			}
+
+			for _, call := range *calls {
+				if call.action == deleteMessageAction {
+					continue
+				}
+				assert.Equal(t, call.fields["message_id"] == tc.delivery.ID, call.fields["reply_markup"] != "")
+			}
+		})
+	}
+}
+
+func TestDriver_Delete(t *testing.T) {
+	c, calls := mockAPI(t)
+	d := &Driver{client: c}
+
+	err := d.Delete("1", outbound.Delivery{ID: "3", Parts: []outbound.Part{
+		{ID: "1", Kind: outbound.PartMedia},
+		{ID: "2", Kind: outbound.PartText},
+		{ID: "3", Kind: outbound.PartText},
+	}})
+	assert.Nil(t, err)
+
+	messages := make([]string, 0, len(*calls))
+	for _, call := range *calls {
+		assert.Equal(t, deleteMessageAction, call.action)
+		messages = append(messages, call.fields["message_id"])
+	}
+	assert.Equal(t, []string{"1", "2", "3"}, messages)
+}
+
+func TestDriver_Send_Failure(t *testing.T) {
+	message := &outbound.Message{
+		Text:   strings.Repeat("a", messageLimit) + "\n\nb",
+		Format: outbound.FormatPlain,
+		Media:  []outbound.Media{{Kind: outbound.Document, Filename: "receipt.pdf", Data: []byte("%PDF")}},
+	}
+
+	c, _ := mockFailingAPI(t, 3)
+	delivery, err := (&Driver{client: c}).Send("1", message)
+	assert.NotNil(t, err)
+	assert.Equal(t, outbound.Delivery{ID: "2", Parts: []outbound.Part{
+		{ID: "1", Kind: outbound.PartMedia},
+		{ID: "2", Kind: outbound.PartText},
+	}}, delivery)
+
+	c, _ = mockFailingAPI(t, 1)
+	delivery, err = (&Driver{client: c}).Send("1", message)
+	assert.NotNil(t, err)
+	assert.Equal(t, outbound.Delivery{}, delivery)
+}
@@ -0,0 +1,513 @@
+package telegram
+
+import (
+	"fmt"
+	"github.com/keweegen/notification/internal/channel/outbound"
+	"html"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// messageLimit is the maximum text length of a message, in UTF-16 code units after entities parsing.
+const messageLimit = 4096
+
+// entityLimit is the maximum number of formatting entities of a message, the ones over it are ignored.
+const entityLimit = 100
+
+// allowedTags are the tags supported by the HTML parse mode with the attributes kept for each of them.
+var allowedTags = map[string][]string{
+	"b":          nil,
+	"strong":     nil,
+	"i":          nil,
+	"em":         nil,
+	"u":          nil,
+	"ins":        nil,
+	"s":          nil,
+	"strike":     nil,
+	"del":        nil,
+	"a":          {"href"},
+	"code":       {"class"},
+	"pre":        nil,
+	"span":       {"class"},
+	"tg-spoiler": nil,
+	"tg-emoji":   {"emoji-id"},
+	"blockquote": {"expandable"},
+}
+
+var allowedURLSchemes = map[string]bool{
+	"http":   true,
+	"https":  true,
+	"tg":     true,
+	"mailto": true,
+}
+
+var (
+	textEscaper      = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	attributeEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+)
+
+const markdownV2Special = "_*[]()~`>#+-=|{}.!\\"
+
+type element struct {
+	name string
+	open string
+}
+
+// run is a piece of visible text with the elements it is enclosed in.
+type run struct {
+	text     []rune
+	elements []*element
+}
+
+type tag struct {
+	name        string
+	closing     bool
+	selfClosing bool
+	attributes  map[string]string
+}
+
+// Sanitize rewrites HTML into the subset accepted by the Bot API: unsupported tags are dropped
+// keeping their text, <br> becomes a line break, unclosed tags are closed and stray "<", ">", "&" are escaped.
+func Sanitize(text string) string {
+	runs, _ := parseHTML(text)
+	return renderHTML(runs)
+}
+
+// UnsupportedTags returns the names of the tags Sanitize drops from the text.
+func UnsupportedTags(text string) []string {
+	_, dropped := parseHTML(text)
+	return dropped
+}
+
+// Split prepares the text for the format and cuts it into messages fitting the length limit.
+// Cuts are made at paragraph, line or word boundaries when possible, HTML tags open at a cut
+// are closed at the end of the message and reopened at the start of the next one.
+func Split(text string, format outbound.Format) []string {
+	return split(text, format, messageLimit)
+}
+
+// EscapeMarkdownV2 escapes the characters reserved by the MarkdownV2 parse mode.
+func EscapeMarkdownV2(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(markdownV2Special, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Lint returns the problems of the text sent in the format: the length over the limit of a message,
+// such a text is split into several messages, and for HTML the tags Sanitize drops and the formatting
+// entities over the limit of a message.
+func Lint(text string, format outbound.Format) []string {
+	problems := make([]string, 0)
+	if length := textLength(text, format); length > messageLimit {
+		problems = append(problems, fmt.Sprintf("text is %d characters long, over the limit of %d", length, messageLimit))
+	}
+	if format != outbound.FormatHTML {
+		return problems
+	}
+
+	runs, dropped := parseHTML(text)
+	reported := make(map[string]bool, len(dropped))
+	for _, name := range dropped {
+		if !reported[name] {
+			reported[name] = true
+			problems = append(problems, fmt.Sprintf("unsupported tag <%s>", name))
+		}
+	}
+
+	entities := make(map[*element]bool)
+	for _, r := range runs {
+		for _, e := range r.elements {
+			entities[e] = true
+		}
+	}
+	if len(entities) > entityLimit {
+		problems = append(problems, fmt.Sprintf("%d formatting entities, over the limit of %d", len(entities), entityLimit))
+	}
+
+	return problems
+}
+
+func split(text string, format outbound.Format, limit int) []string {
+	if format != outbound.FormatHTML {
+		raw := []rune(text)
+		escape := rune(0)
+		if format == outbound.FormatMarkdownV2 {
+			escape = '\\'
+		}
+
+		chunks := make([]string, 0, 1)
+		for _, bounds := range chunkBounds(raw, limit, escape) {
+			chunks = append(chunks, string(raw[bounds[0]:bounds[1]]))
+		}
+		return chunks
+	}
+
+	runs, _ := parseHTML(text)
+	plain := make([]rune, 0, len(text))
+	for _, r := range runs {
+		plain = append(plain, r.text...)
+	}
+
+	chunks := make([]string, 0, 1)
+	for _, bounds := range chunkBounds(plain, limit, 0) {
+		chunks = append(chunks, renderHTML(sliceRuns(runs, bounds[0], bounds[1])))
+	}
+	return chunks
+}
+
+// textLength returns the length of the text as counted by the Bot API.
+func textLength(text string, format outbound.Format) int {
+	if format != outbound.FormatHTML {
+		return utf16Length([]rune(text))
+	}
+
+	runs, _ := parseHTML(text)
+	length := 0
+	for _, r := range runs {
+		length += utf16Length(r.text)
+	}
+	return length
+}
+
+// prepareText sanitizes HTML, texts in other formats are sent as is.
+func prepareText(text string, format outbound.Format) string {
+	if format == outbound.FormatHTML {
+		return Sanitize(text)
+	}
+	return text
+}
+
+func parseMode(format outbound.Format) string {
+	switch format {
+	case outbound.FormatHTML:
+		return "HTML"
+	case outbound.FormatMarkdownV2:
+		return "MarkdownV2"
+	default:
+		return ""
+	}
+}
+
+func chunkBounds(text []rune, limit int, escape rune) [][2]int {
+	bounds := make([][2]int, 0, 1)
+
+	for start := skipSpace(text, 0); start < len(text); start = skipSpace(text, start) {
+		end, size := start, 0
+		for end < len(text) && size+utf16Length(text[end:end+1]) <= limit {
+			size += utf16Length(text[end : end+1])
+			end++
+		}
+		if end < len(text) {
+			end = breakPoint(text, start, end, escape)
+		}
+
+		last := end
+		for last > start && unicode.IsSpace(text[last-1]) {
+			last--
+		}
+		if last > start {
+			bounds = append(bounds, [2]int{start, last})
+		}
+		start = end
+	}
+
+	return bounds
+}
+
+// breakPoint finds where to cut the text[start:end] window, preferring a paragraph break,
+// then a line break, then a space in the second half of the window.
+func breakPoint(text []rune, start, end int, escape rune) int {
+	lower := start + (end-start)/2
+
+	for _, separator := range []string{"\n\n", "\n", " "} {
+		sep := []rune(separator)
+		for i := end; i > lower; i-- {
+			if i+len(sep) <= len(text) && string(text[i:i+len(sep)]) == separator {
+				return i
+			}
+		}
+	}
+
+	if escape != 0 {
+		escapes := 0
+		for i := end - 1; i >= start && text[i] == escape; i-- {
+			escapes++
+		}
+		if escapes%2 == 1 && end-1 > start {
+			return end - 1
+		}
+	}
+
+	return end
+}
+
+func skipSpace(text []rune, i int) int {
+	for i < len(text) && unicode.IsSpace(text[i]) {
+		i++
+	}
+	return i
+}
+
+func utf16Length(text []rune) int {
+	length := 0
+	for _, r := range text {
+		if r >= 0x10000 {
+			length += 2
+			continue
+		}
+		length++
+	}
+	return length
+}
+
+func sliceRuns(runs []run, start, end int) []run {
+	sliced := make([]run, 0, len(runs))
+	offset := 0
+
+	for _, r := range runs {
+		base := offset
+		offset += len(r.text)
+
+		from, to := start, end
+		if from < base {
+			from = base
+		}
+		if to > offset {
+			to = offset
+		}
+		if from >= to {
+			continue
+		}
+		sliced = append(sliced, run{text: r.text[from-base : to-base], elements: r.elements})
+	}
+
+	return sliced
+}
+
+func renderHTML(runs []run) string {
+	var b strings.Builder
+	open := make([]*element, 0)
+
+	for _, r := range runs {
+		common := 0
+		for common < len(open) && common < len(r.elements) && open[common] == r.elements[common] {
+			common++
+		}
+		for i := len(open) - 1; i >= common; i-- {
+			b.WriteString("</" + open[i].name + ">")
+		}
+		for _, e := range r.elements[common:] {
+			b.WriteString(e.open)
+		}
+		open = r.elements
+
+		b.WriteString(textEscaper.Replace(string(r.text)))
+	}
+
+	for i := len(open) - 1; i >= 0; i-- {
+		b.WriteString("</" + open[i].name + ">")
+	}
+
+	return b.String()
+}
+
+func parseHTML(s string) ([]run, []string) {
+	runs := make([]run, 0)
+	dropped := make([]string, 0)
+	stack := make([]*element, 0)
+
+	var text strings.Builder
+	flush := func() {
+		if text.Len() == 0 {
+			return
+		}
+		runs = append(runs, run{
+			text:     []rune(html.UnescapeString(text.String())),
+			elements: append([]*element(nil), stack...),
+		})
+		text.Reset()
+	}
+
+	for i := 0; i < len(s); {
+		if s[i] != '<' {
+			next := strings.IndexByte(s[i:], '<')
+			if next < 0 {
+				next = len(s) - i
+			}
+			text.WriteString(s[i : i+next])
+			i += next
+			continue
+		}
+
+		if strings.HasPrefix(s[i:], "<!--") {
+			end := strings.Index(s[i+4:], "-->")
+			if end < 0 {
+				break
+			}
+			i += 4 + end + 3
+			continue
+		}
+
+		t, size, ok := parseTag(s[i:])
+		if !ok {
+			text.WriteString("&lt;")
+			i++
+			continue
+		}
+		i += size
+
+		if t.name == "br" {
+			text.WriteString("\n")
+			continue
+		}
+
+		attributes, allowed := allowedTags[t.name]
+		if !allowed {
+			dropped = append(dropped, t.name)
+			continue
+		}
+		if t.selfClosing {
+			continue
+		}
+
+		if t.closing {
+			for j := len(stack) - 1; j >= 0; j-- {
+				if stack[j].name == t.name {
+					flush()
+					stack = stack[:j]
+					break
+				}
+			}
+			continue
+		}
+
+		open, ok := openTag(t, attributes)
+		if !ok {
+			dropped = append(dropped, t.name)
+			continue
+		}
+		flush()
+		stack = append(stack, &element{name: t.name, open: open})
+	}
+	flush()
+
+	return runs, dropped
+}
+
+// openTag renders the opening tag with the allowed attributes, it fails for tags
+// whose required attributes are missing or invalid.
+func openTag(t tag, attributes []string) (string, bool) {
+	switch t.name {
+	case "a":
+		u, err := url.Parse(t.attributes["href"])
+		if err != nil || !allowedURLSchemes[strings.ToLower(u.Scheme)] {
+			return "", false
+		}
+	case "span":
+		if t.attributes["class"] != "tg-spoiler" {
+			return "", false
+		}
+	case "code":
+		if !strings.HasPrefix(t.attributes["class"], "language-") {
+			delete(t.attributes, "class")
+		}
+	case "tg-emoji":
+		if t.attributes["emoji-id"] == "" {
+			return "", false
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("<" + t.name)
+	for _, name := range attributes {
+		value, ok := t.attributes[name]
+		if !ok {
+			continue
+		}
+		if value == "" {
+			b.WriteString(" " + name)
+			continue
+		}
+		b.WriteString(" " + name + `="` + attributeEscaper.Replace(value) + `"`)
+	}
+	b.WriteString(">")
+
+	return b.String(), true
+}
+
+// parseTag reads the tag at the start of s and returns it with its length in bytes.
+func parseTag(s string) (tag, int, bool) {
+	t := tag{attributes: map[string]string{}}
+	i := 1
+
+	if i < len(s) && s[i] == '/' {
+		t.closing = true
+		i++
+	}
+
+	start := i
+	for i < len(s) && (isLetter(s[i]) || i > start && (isDigit(s[i]) || s[i] == '-')) {
+		i++
+	}
+	if i == start {
+		return tag{}, 0, false
+	}
+	t.name = strings.ToLower(s[start:i])
+
+	for i < len(s) {
+		switch {
+		case s[i] == '>':
+			return t, i + 1, true
+		case s[i] == '/' && i+1 < len(s) && s[i+1] == '>':
+			t.selfClosing = true
+			return t, i + 2, true
+		case s[i] == ' ' || s[i] == '\t' || s[i] == '\n' || s[i] == '\r' || s[i] == '/':
+			i++
+			continue
+		}
+
+		nameStart := i
+		for i < len(s) && !strings.ContainsRune(" \t\n\r=>/", rune(s[i])) {
+			i++
+		}
+		name := strings.ToLower(s[nameStart:i])
+		if i >= len(s) || s[i] != '=' {
+			t.attributes[name] = ""
+			continue
+		}
+		i++
+
+		var value string
+		if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+			end := strings.IndexByte(s[i+1:], s[i])
+			if end < 0 {
+				return tag{}, 0, false
+			}
+			value = s[i+1 : i+1+end]
+			i += end + 2
+		} else {
+			valueStart := i
+			for i < len(s) && !strings.ContainsRune(" \t\n\r>", rune(s[i])) {
+				i++
+			}
+			value = s[valueStart:i]
+		}
+		t.attributes[name] = html.UnescapeString(value)
+	}
+
+	return tag{}, 0, false
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,148 @@
+package telegram
+
+import (
+	"github.com/keweegen/notification/internal/channel/outbound"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	cases := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "allowed tags",
+			text:     `<b>Чек</b> <i>1</i> <code class="language-go">x</code> <span class="tg-spoiler">s</span>`,
+			expected: `<b>Чек</b> <i>1</i> <code class="language-go">x</code> <span class="tg-spoiler">s</span>`,
+		},
+		{
+			name:     "unsupported tags keep text",
+			text:     `<h3>Чек</h3><p>Заказ <b>1</b></p><div class="x">ok</div>`,
+			expected: `ЧекЗаказ <b>1</b>ok`,
+		},
+		{
+			name:     "line breaks",
+			text:     "a<br>b<br/>c",
+			expected: "a\nb\nc",
+		},
+		{
+			name:     "unclosed and stray closing tags",
+			text:     "<b>bold <i>both</b> tail</i>",
+			expected: "<b>bold <i>both</i></b> tail",
+		},
+		{
+			name:     "links",
+			text:     `<a href="https://example.com/?a=1&amp;b=2" target="_blank">ok</a> <a href="javascript:alert(1)">bad</a>`,
+			expected: `<a href="https://example.com/?a=1&amp;b=2">ok</a> bad`,
+		},
+		{
+			name:     "escaping",
+			text:     "1 < 2 & 3 > 2 &#39;q&#39; &nbsp;",
+			expected: "1 &lt; 2 &amp; 3 &gt; 2 'q'  ",
+		},
+		{
+			name:     "comments",
+			text:     "a<!-- hidden -->b",
+			expected: "ab",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Sanitize(tc.text))
+		})
+	}
+}
+
+func TestUnsupportedTags(t *testing.T) {
+	assert.Equal(t, []string{"h3", "h3", "a"}, UnsupportedTags(`<h3>Чек</h3><b>1</b><a href="ftp://x">x</a>`))
+}
+
+func TestLint(t *testing.T) {
+	assert.Empty(t, Lint("<b>Order</b> <a href=\"https://shop.kz\">shipped</a>", outbound.FormatHTML))
+	assert.Equal(t, []string{"unsupported tag <h1>", "unsupported tag <div>"},
+		Lint("<h1>Order</h1><div>shipped</div>", outbound.FormatHTML))
+	assert.Equal(t, []string{"text is 4097 characters long, over the limit of 4096"},
+		Lint(strings.Repeat("a", messageLimit+1), outbound.FormatMarkdownV2))
+	assert.Equal(t, []string{"101 formatting entities, over the limit of 100"},
+		Lint(strings.Repeat("<b>a</b>", entityLimit+1), outbound.FormatHTML))
+}
+
+func TestSplit(t *testing.T) {
+	cases := []struct {
+		name     string
+		text     string
+		format   outbound.Format
+		limit    int
+		expected []string
+	}{
+		{
+			name:     "fits",
+			text:     "<b>short</b>",
+			format:   outbound.FormatHTML,
+			limit:    20,
+			expected: []string{"<b>short</b>"},
+		},
+		{
+			name:     "paragraphs",
+			text:     "first paragraph\n\nsecond one",
+			format:   outbound.FormatPlain,
+			limit:    20,
+			expected: []string{"first paragraph", "second one"},
+		},
+		{
+			name:     "words",
+			text:     "one two three four five",
+			format:   outbound.FormatPlain,
+			limit:    10,
+			expected: []string{"one two", "three four", "five"},
+		},
+		{
+			name:     "tags reopened",
+			text:     `<b>bold <a href="https://example.com">link text</a></b> tail`,
+			format:   outbound.FormatHTML,
+			limit:    10,
+			expected: []string{`<b>bold <a href="https://example.com">link</a></b>`, `<b><a href="https://example.com">text</a></b> tail`},
+		},
+		{
+			name:     "entities count as one character",
+			text:     "&lt;&lt;&lt;&lt; &amp;&amp;",
+			format:   outbound.FormatHTML,
+			limit:    7,
+			expected: []string{"&lt;&lt;&lt;&lt; &amp;&amp;"},
+		},
+		{
+			name:     "hard cut keeps markdown escapes",
+			text:     `aaaa\.bbbb`,
+			format:   outbound.FormatMarkdownV2,
+			limit:    5,
+			expected: []string{"aaaa", `\.bbb`, "b"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, split(tc.text, tc.format, tc.limit))
+		})
+	}
+}
+
+func TestSplit_Limit(t *testing.T) {
+	text := strings.Repeat("<b>Заказ</b> успешно оплачен 😀\n", 500)
+
+	chunks := Split(text, outbound.FormatHTML)
+	assert.Greater(t, len(chunks), 1)
+
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, textLength(chunk, outbound.FormatHTML), messageLimit)
+		assert.Equal(t, Sanitize(chunk), chunk)
+		assert.True(t, strings.HasSuffix(chunk, "😀"))
+	}
+}
+
+func TestEscapeMarkdownV2(t *testing.T) {
+	assert.Equal(t, `1\.5 \* 2 \= 3\!`, EscapeMarkdownV2("1.5 * 2 = 3!"))
+}
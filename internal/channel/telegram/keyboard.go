@@ -0,0 +1,48 @@
+package telegram
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/keweegen/notification/internal/channel/outbound"
+)
+
+// callbackDataLimit is the maximum size of the callback data of an inline button, in bytes.
+const callbackDataLimit = 64
+
+type inlineKeyboardMarkup struct {
+	InlineKeyboard [][]inlineKeyboardButton `json:"inline_keyboard"`
+}
+
+type inlineKeyboardButton struct {
+	Text         string `json:"text"`
+	URL          string `json:"url,omitempty"`
+	CallbackData string `json:"callback_data,omitempty"`
+}
+
+// replyMarkup renders the actions as an inline keyboard with a button per row.
+// Callback buttons carry the action id, reported back with the callback_query update.
+func replyMarkup(actions []outbound.Action) (string, error) {
+	if len(actions) == 0 {
+		return "", nil
+	}
+
+	markup := inlineKeyboardMarkup{InlineKeyboard: make([][]inlineKeyboardButton, 0, len(actions))}
+
+	for _, action := range actions {
+		button := inlineKeyboardButton{Text: action.Label, URL: action.URL}
+		if action.IsCallback() {
+			if len(action.ID) == 0 || len(action.ID) > callbackDataLimit {
+				return "", fmt.Errorf("action '%s': callback data must be 1-%d bytes", action.ID, callbackDataLimit)
+			}
+			button.CallbackData = action.ID
+		}
+		markup.InlineKeyboard = append(markup.InlineKeyboard, []inlineKeyboardButton{button})
+	}
+
+	data, err := json.Marshal(markup)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode reply markup: %w", err)
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,53 @@
+package telegram
+
+import (
+	"github.com/keweegen/notification/internal/channel/outbound"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestReplyMarkup(t *testing.T) {
+	markup, err := replyMarkup(nil)
+	assert.Nil(t, err)
+	assert.Empty(t, markup)
+
+	markup, err = replyMarkup([]outbound.Action{
+		{ID: "view", Label: "View order", URL: "https://example.com/orders/1"},
+		{ID: "confirm", Label: "Confirm delivery"},
+	})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"inline_keyboard":[
+		[{"text":"View order","url":"https://example.com/orders/1"}],
+		[{"text":"Confirm delivery","callback_data":"confirm"}]
+	]}`, markup)
+
+	_, err = replyMarkup([]outbound.Action{{ID: strings.Repeat("a", callbackDataLimit+1), Label: "Too long"}})
+	assert.NotNil(t, err)
+}
+
+func TestClient_SendText_Actions(t *testing.T) {
+	c, calls := mockAPI(t)
+	text := strings.Repeat("a", messageLimit) + "\n\nb"
+
+	parts, err := c.SendText("1", text, outbound.FormatPlain, []outbound.Action{{ID: "confirm", Label: "Confirm delivery"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []outbound.Part{{ID: "1", Kind: outbound.PartText}, {ID: "2", Kind: outbound.PartText}}, parts)
+
+	assert.Len(t, *calls, 2)
+	assert.Empty(t, (*calls)[0].fields["reply_markup"])
+	assert.Empty(t, (*calls)[0].fields["parse_mode"])
+	assert.Contains(t, (*calls)[1].fields["reply_markup"], `"callback_data":"confirm"`)
+	assert.Equal(t, "b", (*calls)[1].fields["text"])
+}
+
+func TestClient_SendText_Failure(t *testing.T) {
+	c, calls := mockFailingAPI(t, 2)
+	text := strings.Repeat("a", messageLimit) + "\n\nb"
+
+	parts, err := c.SendText("1", text, outbound.FormatPlain, nil)
+	var apiErr *Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, []outbound.Part{{ID: "1", Kind: outbound.PartText}}, parts)
+	assert.Len(t, *calls, 2)
+}
@@ -0,0 +1,250 @@
+package telegram
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/keweegen/notification/internal/channel/outbound"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+const (
+	sendPhotoAction      = "sendPhoto"
+	sendDocumentAction   = "sendDocument"
+	sendMediaGroupAction = "sendMediaGroup"
+
+	captionLimit    = 1024
+	mediaGroupLimit = 10
+)
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+type inputMedia struct {
+	Type      string `json:"type"`
+	Media     string `json:"media"`
+	Caption   string `json:"caption,omitempty"`
+	ParseMode string `json:"parse_mode,omitempty"`
+}
+
+type multipartFile struct {
+	field string
+	media outbound.Media
+}
+
+// SendMedia sends the media with the text as caption. When the text does not fit into a caption,
+// or the buttons cannot be attached to the first album, the media is sent without it
+// and the text follows as separate messages.
+// It returns the sent messages in order, telling which of them hold the text, the ones sent
+// before a failing message are returned with the error.
+func (c *client) SendMedia(
+	chatId string,
+	media []outbound.Media,
+	text string,
+	format outbound.Format,
+	actions []outbound.Action,
+) ([]outbound.Part, error) {
+	markup, err := replyMarkup(actions)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := c.groupMedia(media)
+
+	text = prepareText(text, format)
+	caption := text
+	if textLength(text, format) > captionLimit || markup != "" && len(groups[0]) > 1 {
+		caption = ""
+	}
+
+	parts := make([]outbound.Part, 0, len(media))
+
+	for i, group := range groups {
+		groupCaption, groupMarkup := "", ""
+		if i == 0 {
+			groupCaption = caption
+			groupMarkup = markup
+		}
+
+		ids, err := c.sendMediaGroup(chatId, group, groupCaption, parseMode(format), groupMarkup)
+		if err != nil {
+			return parts, err
+		}
+		for j, id := range ids {
+			kind := outbound.PartMedia
+			if i == 0 && j == 0 && groupCaption != "" {
+				kind = outbound.PartCaption
+			}
+			parts = append(parts, outbound.Part{ID: id, Kind: kind})
+		}
+	}
+
+	if caption == "" && strings.TrimSpace(text) != "" {
+		textParts, err := c.SendText(chatId, text, format, actions)
+		parts = append(parts, textParts...)
+		if err != nil {
+			return parts, err
+		}
+	}
+
+	return parts, nil
+}
+
+// groupMedia splits media into albums, Telegram does not mix photos and documents in one album.
+func (c *client) groupMedia(media []outbound.Media) [][]outbound.Media {
+	groups := make([][]outbound.Media, 0)
+
+	for _, m := range media {
+		last := len(groups) - 1
+		if last < 0 || groups[last][0].Kind != m.Kind || len(groups[last]) == mediaGroupLimit {
+			groups = append(groups, []outbound.Media{m})
+			continue
+		}
+		groups[last] = append(groups[last], m)
+	}
+
+	return groups
+}
+
+// sendMediaGroup sends the media as an album, the reply markup is only supported for a single media.
+func (c *client) sendMediaGroup(chatId string, media []outbound.Media, caption, parseMode, replyMarkup string) ([]string, error) {
+	if len(media) == 1 {
+		id, err := c.sendSingleMedia(chatId, media[0], caption, parseMode, replyMarkup)
+		if err != nil {
+			return nil, err
+		}
+		return []string{id}, nil
+	}
+
+	items := make([]inputMedia, 0, len(media))
+	files := make([]multipartFile, 0, len(media))
+
+	for i, m := range media {
+		item := inputMedia{Type: c.mediaType(m.Kind), Media: m.URL}
+		if m.IsUpload() {
+			field := fmt.Sprintf("file%d", i)
+			item.Media = "attach://" + field
+			files = append(files, multipartFile{field: field, media: m})
+		}
+		if i == 0 && caption != "" {
+			item.Caption = caption
+			item.ParseMode = parseMode
+		}
+		items = append(items, item)
+	}
+
+	itemsJSON, err := json.Marshal(items)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode media group: %w", err)
+	}
+
+	result, err := c.doMultipart(sendMediaGroupAction, map[string]string{
+		"chat_id": chatId,
+		"media":   string(itemsJSON),
+	}, files)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send media group: %w", err)
+	}
+
+	var sent []Message
+	if err = json.Unmarshal(result, &sent); err != nil {
+		return nil, fmt.Errorf("failed to decode sent media group: %w", err)
+	}
+
+	ids := make([]string, 0, len(sent))
+	for _, m := range sent {
+		ids = append(ids, fmt.Sprintf("%d", m.ID))
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("failed to send media group: empty result")
+	}
+
+	return ids, nil
+}
+
+func (c *client) sendSingleMedia(chatId string, media outbound.Media, caption, parseMode, replyMarkup string) (string, error) {
+	field := c.mediaType(media.Kind)
+	action := sendPhotoAction
+	if media.Kind == outbound.Document {
+		action = sendDocumentAction
+	}
+
+	fields := map[string]string{"chat_id": chatId}
+	if caption != "" {
+		fields["caption"] = caption
+	}
+	if caption != "" && parseMode != "" {
+		fields["parse_mode"] = parseMode
+	}
+	if replyMarkup != "" {
+		fields["reply_markup"] = replyMarkup
+	}
+
+	files := make([]multipartFile, 0, 1)
+	if media.IsUpload() {
+		files = append(files, multipartFile{field: field, media: media})
+	} else {
+		fields[field] = media.URL
+	}
+
+	result, err := c.doMultipart(action, fields, files)
+	if err != nil {
+		return "", fmt.Errorf("failed to send %s: %w", field, err)
+	}
+
+	sent := new(Message)
+	if err = json.Unmarshal(result, sent); err != nil {
+		return "", fmt.Errorf("failed to decode sent %s: %w", field, err)
+	}
+
+	return fmt.Sprintf("%d", sent.ID), nil
+}
+
+func (c *client) mediaType(kind outbound.MediaKind) string {
+	if kind == outbound.Document {
+		return "document"
+	}
+	return "photo"
+}
+
+func (c *client) doMultipart(action string, fields map[string]string, files []multipartFile) ([]byte, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			return nil, fmt.Errorf("failed to write form field: %w", err)
+		}
+	}
+
+	for _, f := range files {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			quoteEscaper.Replace(f.field), quoteEscaper.Replace(f.media.Filename)))
+		if f.media.ContentType != "" {
+			header.Set("Content-Type", f.media.ContentType)
+		}
+
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create form file: %w", err)
+		}
+		if _, err = part.Write(f.media.Data); err != nil {
+			return nil, fmt.Errorf("failed to write form file: %w", err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close form: %w", err)
+	}
+
+	request, err := http.NewRequest(http.MethodPost, c.makeURL(action).String(), &body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make http request: %w", err)
+	}
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return c.send(httpClient, request)
+}
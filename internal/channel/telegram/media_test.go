@@ -0,0 +1,152 @@
+package telegram
+
+import (
+	"fmt"
+	"github.com/keweegen/notification/internal/channel/outbound"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type apiCall struct {
+	action string
+	fields map[string]string
+	files  map[string]string
+}
+
+func mockAPI(t *testing.T) (*client, *[]apiCall) {
+	t.Helper()
+	return mockFailingAPI(t, 0)
+}
+
+// mockFailingAPI returns the client of an API refusing the call of the number, counting from 1.
+func mockFailingAPI(t *testing.T, failingCall int) (*client, *[]apiCall) {
+	t.Helper()
+
+	calls := make([]apiCall, 0)
+	messageID := 0
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := apiCall{action: r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:], fields: map[string]string{}, files: map[string]string{}}
+
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+			assert.Nil(t, r.ParseMultipartForm(1<<20))
+			for name, values := range r.MultipartForm.Value {
+				call.fields[name] = values[0]
+			}
+			for name, headers := range r.MultipartForm.File {
+				f, _ := headers[0].Open()
+				data, _ := io.ReadAll(f)
+				call.files[name] = headers[0].Filename + ":" + string(data)
+			}
+		} else {
+			for name, values := range r.URL.Query() {
+				call.fields[name] = values[0]
+			}
+		}
+		calls = append(calls, call)
+		if len(calls) == failingCall {
+			_, _ = fmt.Fprint(w, `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 5"}`)
+			return
+		}
+
+		messageID++
+		if call.action == sendMediaGroupAction {
+			_, _ = fmt.Fprintf(w, `{"ok":true,"result":[{"message_id":%d},{"message_id":%d}]}`, messageID, messageID+100)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"ok":true,"result":{"message_id":%d}}`, messageID)
+	}))
+	t.Cleanup(server.Close)
+
+	previous := httpClient
+	httpClient = server.Client()
+	t.Cleanup(func() { httpClient = previous })
+
+	u, _ := url.Parse(server.URL)
+
+	return new(client).init(u.Host, "token"), &calls
+}
+
+func TestClient_SendMedia(t *testing.T) {
+	photoURL := outbound.Media{Kind: outbound.Photo, URL: "https://example.com/promo.png"}
+	photoUpload := outbound.Media{Kind: outbound.Photo, Filename: "promo.png", Data: []byte("png")}
+	document := outbound.Media{Kind: outbound.Document, Filename: "receipt.pdf", ContentType: "application/pdf", Data: []byte("pdf")}
+	longText := strings.Repeat("a", captionLimit+1)
+
+	cases := []struct {
+		name            string
+		media           []outbound.Media
+		text            string
+		expectedActions []string
+		expectedParts   []outbound.Part
+	}{
+		{
+			name:            "photo by url with caption",
+			media:           []outbound.Media{photoURL},
+			text:            "<b>Promo</b>",
+			expectedActions: []string{sendPhotoAction},
+			expectedParts:   []outbound.Part{{ID: "1", Kind: outbound.PartCaption}},
+		},
+		{
+			name:            "document upload with long text",
+			media:           []outbound.Media{document},
+			text:            longText,
+			expectedActions: []string{sendDocumentAction, sendMessageAction},
+			expectedParts:   []outbound.Part{{ID: "1", Kind: outbound.PartMedia}, {ID: "2", Kind: outbound.PartText}},
+		},
+		{
+			name:            "album",
+			media:           []outbound.Media{photoURL, photoUpload},
+			text:            "Promo",
+			expectedActions: []string{sendMediaGroupAction},
+			expectedParts:   []outbound.Part{{ID: "1", Kind: outbound.PartCaption}, {ID: "101", Kind: outbound.PartMedia}},
+		},
+		{
+			name:            "photos and document",
+			media:           []outbound.Media{photoURL, photoUpload, document},
+			text:            "Receipt",
+			expectedActions: []string{sendMediaGroupAction, sendDocumentAction},
+			expectedParts: []outbound.Part{
+				{ID: "1", Kind: outbound.PartCaption},
+				{ID: "101", Kind: outbound.PartMedia},
+				{ID: "2", Kind: outbound.PartMedia},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, calls := mockAPI(t)
+
+			parts, err := c.SendMedia("1", tc.media, tc.text, outbound.FormatHTML, nil)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expectedParts, parts)
+
+			actions := make([]string, 0, len(*calls))
+			for _, call := range *calls {
+				actions = append(actions, call.action)
+			}
+			assert.Equal(t, tc.expectedActions, actions)
+
+			first := (*calls)[0]
+			switch first.action {
+			case sendPhotoAction:
+				assert.Equal(t, photoURL.URL, first.fields["photo"])
+				assert.Equal(t, tc.text, first.fields["caption"])
+			case sendDocumentAction:
+				assert.Equal(t, "receipt.pdf:pdf", first.files["document"])
+				assert.Empty(t, first.fields["caption"])
+				assert.Equal(t, tc.text, (*calls)[1].fields["text"])
+			case sendMediaGroupAction:
+				assert.Contains(t, first.fields["media"], `"media":"attach://file1"`)
+				assert.Contains(t, first.fields["media"], `"caption":"`+tc.text+`"`)
+				assert.Equal(t, "promo.png:png", first.files["file1"])
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: bot.go
+
+// Package mock_telegram is a generated GoMock package.
+package mock_telegram
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	outbound "github.com/keweegen/notification/internal/channel/outbound"
+	telegram "github.com/keweegen/notification/internal/channel/telegram"
+)
+
+// MockBot is a mock of Bot interface.
+type MockBot struct {
+	ctrl     *gomock.Controller
+	recorder *MockBotMockRecorder
+}
+
+// MockBotMockRecorder is the mock recorder for MockBot.
+type MockBotMockRecorder struct {
+	mock *MockBot
+}
+
+// NewMockBot creates a new mock instance.
+func NewMockBot(ctrl *gomock.Controller) *MockBot {
+	mock := &MockBot{ctrl: ctrl}
+	mock.recorder = &MockBotMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBot) EXPECT() *MockBotMockRecorder {
+	return m.recorder
+}
+
+// AnswerCallbackQuery mocks base method.
+func (m *MockBot) AnswerCallbackQuery(callbackQueryID, text string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnswerCallbackQuery", callbackQueryID, text)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AnswerCallbackQuery indicates an expected call of AnswerCallbackQuery.
+func (mr *MockBotMockRecorder) AnswerCallbackQuery(callbackQueryID, text interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnswerCallbackQuery", reflect.TypeOf((*MockBot)(nil).AnswerCallbackQuery), callbackQueryID, text)
+}
+
+// Delete mocks base method.
+func (m *MockBot) Delete(receiver string, delivery outbound.Delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", receiver, delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockBotMockRecorder) Delete(receiver, delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBot)(nil).Delete), receiver, delivery)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockBot) DeleteWebhook() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockBotMockRecorder) DeleteWebhook() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockBot)(nil).DeleteWebhook))
+}
+
+// Edit mocks base method.
+func (m *MockBot) Edit(receiver string, delivery outbound.Delivery, message *outbound.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Edit", receiver, delivery, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Edit indicates an expected call of Edit.
+func (mr *MockBotMockRecorder) Edit(receiver, delivery, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Edit", reflect.TypeOf((*MockBot)(nil).Edit), receiver, delivery, message)
+}
+
+// GetUpdates mocks base method.
+func (m *MockBot) GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]telegram.Update, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUpdates", ctx, offset, timeout)
+	ret0, _ := ret[0].([]telegram.Update)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUpdates indicates an expected call of GetUpdates.
+func (mr *MockBotMockRecorder) GetUpdates(ctx, offset, timeout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUpdates", reflect.TypeOf((*MockBot)(nil).GetUpdates), ctx, offset, timeout)
+}
+
+// Send mocks base method.
+func (m *MockBot) Send(receiver string, message *outbound.Message) (outbound.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", receiver, message)
+	ret0, _ := ret[0].(outbound.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockBotMockRecorder) Send(receiver, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockBot)(nil).Send), receiver, message)
+}
+
+// SetWebhook mocks base method.
+func (m *MockBot) SetWebhook(url, secretToken string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWebhook", url, secretToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetWebhook indicates an expected call of SetWebhook.
+func (mr *MockBotMockRecorder) SetWebhook(url, secretToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWebhook", reflect.TypeOf((*MockBot)(nil).SetWebhook), url, secretToken)
+}
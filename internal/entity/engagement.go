@@ -0,0 +1,16 @@
+package entity
+
+import "github.com/keweegen/notification/internal/messagetemplate"
+
+// Engagement counts the messages of a template sent, opened and clicked at least once,
+// Opens and Clicks are the numbers of recorded events.
+type Engagement struct {
+	MessageTemplate messagetemplate.MessageTemplate
+	Sent            int64
+	Opened          int64
+	Clicked         int64
+	Opens           int64
+	Clicks          int64
+}
+
+type Engagements []*Engagement
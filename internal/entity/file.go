@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// File is an uploaded file messages can reference as attachment, or a document generated for a message.
+type File struct {
+	ID          string
+	Filename    string
+	ContentType string
+	Size        int64
+	Data        []byte
+	// MessageID is the message the document is generated for, empty for uploaded files.
+	MessageID string
+	CreatedAt time.Time
+}
@@ -2,6 +2,7 @@ package entity
 
 import (
 	"github.com/keweegen/notification/internal/channel"
+	"github.com/keweegen/notification/internal/channel/outbound"
 	"github.com/keweegen/notification/internal/messagetemplate"
 	"github.com/volatiletech/sqlboiler/v4/types"
 	"time"
@@ -12,16 +13,48 @@ const (
 	MessageStatusSending = "sending"
 	MessageStatusSent    = "sent"
 	MessageStatusFailed  = "failed"
+	MessageStatusEdited  = "edited"
+	MessageStatusDeleted = "deleted"
 )
 
+// Events happen to a delivered message and are recorded apart from its statuses, they do not change
+// the delivery status.
+const (
+	MessageEventAction = "action"
+	// MessageEventBounced and MessageEventComplained are reported by the provider after delivery.
+	MessageEventBounced    = "bounced"
+	MessageEventComplained = "complained"
+	// MessageEventOpened and MessageEventClicked are recorded by the tracking of emails.
+	MessageEventOpened  = "opened"
+	MessageEventClicked = "clicked"
+)
+
+// MessageAttemptDescription is the description of the sending status created when a delivery attempt starts.
+const MessageAttemptDescription = "Sending a message"
+
 type Message struct {
 	ID              string
 	UserID          int64
 	Channel         channel.Channel
 	MessageTemplate messagetemplate.MessageTemplate
-	Timestamp       int64
-	ExternalID      int64
-	Params          types.JSON
+	// TemplateVersion is the version the message is rendered with, 0 for templates compiled into the service.
+	TemplateVersion int
+	// Locale is the locale the message is rendered in, empty for templates that are not localized.
+	Locale string
+	// TimeZone is the IANA time zone the dates of the message are formatted in.
+	TimeZone          string
+	Timestamp         int64
+	ExternalID        int64
+	Params            types.JSON
+	ProviderMessageID string
+	// ProviderMessageParts are the messages the provider split the message into, empty when it was sent as one.
+	ProviderMessageParts []outbound.Part
+	Attachments          []string
+}
+
+// Delivery returns what the provider assigned to the delivered message.
+func (m *Message) Delivery() outbound.Delivery {
+	return outbound.Delivery{ID: m.ProviderMessageID, Parts: m.ProviderMessageParts}
 }
 
 type Messages []*Message
@@ -33,3 +66,13 @@ type MessageStatus struct {
 	Description string
 	CreatedAt   time.Time
 }
+
+type MessageEvent struct {
+	ID          int64
+	MessageID   string
+	Type        string
+	Description string
+	CreatedAt   time.Time
+}
+
+type MessageEvents []*MessageEvent
@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"github.com/keweegen/notification/internal/channel"
+	"github.com/keweegen/notification/internal/messagetemplate"
+	"time"
+)
+
+// UserPreference is the user's subscription to a category of messages in a channel,
+// users without a preference are subscribed.
+type UserPreference struct {
+	ID         int64
+	UserID     int64
+	Channel    channel.Channel
+	Category   messagetemplate.Category
+	Subscribed bool
+	UpdatedAt  time.Time
+}
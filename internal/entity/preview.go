@@ -0,0 +1,16 @@
+package entity
+
+import (
+	"github.com/keweegen/notification/internal/channel"
+	"github.com/keweegen/notification/internal/channel/outbound"
+	"github.com/keweegen/notification/internal/messagetemplate"
+)
+
+// Preview is a message rendered without being sent. Warnings list the params not matching
+// the params schema and the requested locale the message is not rendered in.
+type Preview struct {
+	Channel  channel.Channel
+	Locale   string
+	Content  *outbound.Message
+	Warnings []messagetemplate.FieldError
+}
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"github.com/keweegen/notification/internal/channel"
+	"time"
+)
+
+const (
+	SuppressionReasonBounce    = "bounce"
+	SuppressionReasonComplaint = "complaint"
+)
+
+// Suppression is a recipient messages are no longer sent to after a hard bounce or a spam complaint.
+type Suppression struct {
+	ID          int64
+	Channel     channel.Channel
+	Recipient   string
+	Reason      string
+	Description string
+	MessageID   string
+	CreatedAt   time.Time
+}
+
+type Suppressions []*Suppression
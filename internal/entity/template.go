@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"github.com/keweegen/notification/internal/messagetemplate"
+	"github.com/volatiletech/sqlboiler/v4/types"
+	"time"
+)
+
+// Template is a message template managed through the API. Templates compiled into the service
+// are rendered from the code while CurrentVersion is 0.
+type Template struct {
+	ID             messagetemplate.MessageTemplate
+	Name           string
+	Category       messagetemplate.Category
+	Tracking       bool
+	CurrentVersion int
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type Templates []*Template
+
+// TemplateVersion is an immutable revision of the template bodies, messages are rendered
+// with the version current when they were sent.
+type TemplateVersion struct {
+	ID           int64
+	TemplateID   messagetemplate.MessageTemplate
+	Version      int
+	ParamsSchema types.JSON
+	Bodies       messagetemplate.Bodies
+	Catalog      *messagetemplate.Catalog
+	// ExampleParams are the params the version is linted with, nil when it has none.
+	ExampleParams types.JSON
+	CreatedAt     time.Time
+}
+
+type TemplateVersions []*TemplateVersion
@@ -1,13 +1,28 @@
 package entity
 
-import "github.com/keweegen/notification/internal/channel"
+import (
+	"github.com/keweegen/notification/internal/channel"
+	"time"
+)
 
 type UserChannel struct {
-	ID        int64
-	UserID    int64
-	Channel   channel.Channel
-	Recipient string
-	CanNotify bool
+	ID             int64
+	UserID         int64
+	Channel        channel.Channel
+	Recipient      string
+	CanNotify      bool
+	DisabledReason string
+	DisabledAt     *time.Time
+	// TrackingOptOut disables open and click tracking of the messages sent to the channel.
+	TrackingOptOut bool
 }
 
 type UserChannels []*UserChannel
+
+// UserLocale is the locale and the IANA time zone messages are rendered in for the user,
+// empty when they are not set.
+type UserLocale struct {
+	UserID   int64
+	Locale   string
+	TimeZone string
+}
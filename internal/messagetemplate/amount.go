@@ -0,0 +1,101 @@
+package messagetemplate
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var InvalidAmountErr = errors.New("template: invalid amount")
+
+// amountPattern is an amount given as a string: a number with group and decimal separators,
+// with the currency code before or after it, e.g. 300,00 KZT, 1 300 KZT or KZT 1,300.00.
+var amountPattern = regexp.MustCompile(
+	`^(?:([A-Z]{3})[ \x{00a0}]?)?([0-9][0-9 .,\x{00a0}\x{202f}]*?)(?:[ \x{00a0}]?([A-Z]{3}))?$`)
+
+// Amount is a money amount of the params. It is kept as the decimal it was given in, the amounts
+// are not rounded through float64. It is given as a JSON number or as a string: a decimal, or
+// an amount formatted with its currency code, as the receipt amounts were before the currency param.
+type Amount struct {
+	decimal string
+	// Currency is the code the amount was formatted with, empty for the numbers.
+	Currency string
+}
+
+// ParseAmount parses the amount given as a string, the decimal separator is the last dot or comma
+// unless the separator is repeated, e.g. 1,300,000, or both are used, e.g. 1.300,50 and 1,300.50.
+func ParseAmount(s string) (Amount, error) {
+	parts := amountPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if parts == nil || parts[1] != "" && parts[3] != "" {
+		return Amount{}, fmt.Errorf("%w: '%s'", InvalidAmountErr, s)
+	}
+
+	number := strings.NewReplacer(" ", "", "\u00a0", "", "\u202f", "").Replace(parts[2])
+	integer, fraction := number, ""
+	if i := strings.LastIndexAny(number, ".,"); i >= 0 {
+		separator, other := number[i:i+1], ","
+		if separator == "," {
+			other = "."
+		}
+		switch {
+		case strings.Count(number, separator) == 1:
+			integer, fraction = number[:i], number[i+1:]
+		case strings.Contains(number, other):
+			return Amount{}, fmt.Errorf("%w: '%s'", InvalidAmountErr, s)
+		}
+	}
+	integer = strings.NewReplacer(".", "", ",", "").Replace(integer)
+	if integer == "" {
+		return Amount{}, fmt.Errorf("%w: '%s'", InvalidAmountErr, s)
+	}
+
+	decimal := integer
+	if fraction != "" {
+		decimal += "." + fraction
+	}
+	return Amount{decimal: decimal, Currency: parts[1] + parts[3]}, nil
+}
+
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		s, err := strconv.Unquote(string(data))
+		if err != nil {
+			return fmt.Errorf("%w: %s", InvalidAmountErr, data)
+		}
+		*a, err = ParseAmount(s)
+		return err
+	}
+
+	r, ok := new(big.Rat).SetString(string(data))
+	if !ok {
+		return fmt.Errorf("%w: %s", InvalidAmountErr, data)
+	}
+	decimal := string(data)
+	if strings.ContainsAny(decimal, "eE") {
+		decimal = strings.TrimRight(strings.TrimRight(r.FloatString(20), "0"), ".")
+	}
+	*a = Amount{decimal: decimal}
+	return nil
+}
+
+// MarshalJSON writes the amount as a number, the currency code of a formatted amount is dropped.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// String returns the decimal of the amount, e.g. 1300.50.
+func (a Amount) String() string {
+	if a.decimal == "" {
+		return "0"
+	}
+	return a.decimal
+}
+
+// Rat returns the exact value of the amount.
+func (a Amount) Rat() *big.Rat {
+	r, _ := new(big.Rat).SetString(a.String())
+	return r
+}
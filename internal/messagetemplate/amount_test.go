@@ -0,0 +1,62 @@
+package messagetemplate
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	cases := []struct {
+		input            string
+		expectedDecimal  string
+		expectedCurrency string
+	}{
+		{input: "300", expectedDecimal: "300"},
+		{input: "300 KZT", expectedDecimal: "300", expectedCurrency: "KZT"},
+		{input: "300,00 KZT", expectedDecimal: "300.00", expectedCurrency: "KZT"},
+		{input: "1\u00a0300,50\u00a0KZT", expectedDecimal: "1300.50", expectedCurrency: "KZT"},
+		{input: "KZT 1,300.50", expectedDecimal: "1300.50", expectedCurrency: "KZT"},
+		{input: "1.300,50", expectedDecimal: "1300.50"},
+		{input: "1,300,000", expectedDecimal: "1300000"},
+		{input: "0.1", expectedDecimal: "0.1"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			amount, err := ParseAmount(tc.input)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expectedDecimal, amount.String())
+			assert.Equal(t, tc.expectedCurrency, amount.Currency)
+		})
+	}
+
+	for _, input := range []string{"", "KZT", "-300", "about 300", "KZT 300 USD", "1,300.00.5"} {
+		_, err := ParseAmount(input)
+		assert.ErrorIs(t, err, InvalidAmountErr, input)
+	}
+}
+
+func TestAmount_UnmarshalJSON(t *testing.T) {
+	var params struct {
+		Number   Amount `json:"number"`
+		Exponent Amount `json:"exponent"`
+		Legacy   Amount `json:"legacy"`
+	}
+	assert.Nil(t, json.Unmarshal([]byte(`{"number": 9007199254740993.01, "exponent": 1.5e3, "legacy": "300,00 KZT"}`), &params))
+	assert.Equal(t, "9007199254740993.01", params.Number.String())
+	assert.Equal(t, "1500", params.Exponent.String())
+	assert.Equal(t, Amount{decimal: "300.00", Currency: "KZT"}, params.Legacy)
+
+	data, err := json.Marshal(params.Legacy)
+	assert.Nil(t, err)
+	assert.Equal(t, "300.00", string(data))
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`"300 dollars"`), new(Amount)), InvalidAmountErr)
+}
+
+func TestFormatCurrencyIn_Exact(t *testing.T) {
+	amount, err := ParseAmount("9007199254740993.005")
+	assert.Nil(t, err)
+	assert.Equal(t, "9\u00a0007\u00a0199\u00a0254\u00a0740\u00a0993,01\u00a0KZT", formatCurrencyIn("ru", amount.Rat(), "KZT"))
+}
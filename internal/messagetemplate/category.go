@@ -0,0 +1,39 @@
+package messagetemplate
+
+// Category groups templates by purpose, users may unsubscribe from every category but transactional.
+type Category string
+
+const (
+	CategoryTransactional Category = "transactional"
+	CategoryMarketing     Category = "marketing"
+)
+
+var Categories = []Category{CategoryTransactional, CategoryMarketing}
+
+func (c Category) IsValid() bool {
+	for _, category := range Categories {
+		if category == c {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanUnsubscribe reports whether users may opt out of the category, transactional messages are always sent.
+func (c Category) CanUnsubscribe() bool {
+	return c != CategoryTransactional
+}
+
+// CategoryTemplate is implemented by templates that are not transactional.
+type CategoryTemplate interface {
+	Category() Category
+}
+
+// GetCategory returns the category of the template, transactional when it does not declare one.
+func GetCategory(t Template) Category {
+	if ct, ok := t.(CategoryTemplate); ok {
+		return ct.Category()
+	}
+	return CategoryTransactional
+}
@@ -0,0 +1,413 @@
+package messagetemplate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/keweegen/notification/internal/channel"
+	"github.com/volatiletech/sqlboiler/v4/types"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// ManifestFile is the name of the manifest in a template directory.
+	ManifestFile = "manifest.yml"
+	// CatalogFile is the name of the optional message catalog in a template directory.
+	CatalogFile = "catalog.yml"
+)
+
+var InvalidManifestErr = errors.New("template: invalid manifest")
+
+// extensions are the formats of the body files by extension, default.md is Markdown though.
+var extensions = map[string]string{
+	".html": "html",
+	".md":   "markdownV2",
+	".txt":  "plain",
+}
+
+// Manifest describes a template of the directory, the bodies are read from the <channel>.<ext> files
+// next to it. An email.txt next to an email.html is the plain text alternative of the email.
+// A default.md is the Markdown body of the channels without a file of their own.
+type Manifest struct {
+	// ID is the message template id of the template, a compiled template is overridden by its id.
+	ID       int      `yaml:"id"`
+	Name     string   `yaml:"name"`
+	Category Category `yaml:"category"`
+	Tracking bool     `yaml:"tracking"`
+	// Channels are the channels the template is sent to, every one of them needs a body file or default.md.
+	// All the channels with a body file are allowed when empty.
+	Channels  []string          `yaml:"channels"`
+	Subject   string            `yaml:"subject"`
+	Preheader string            `yaml:"preheader"`
+	Headers   map[string]string `yaml:"headers"`
+	// Targets are the formats default.md is compiled into by channel name, see Body.
+	Targets      map[string]string `yaml:"targets"`
+	ParamsSchema map[string]any    `yaml:"paramsSchema"`
+	// ExampleParams are the params the template is linted with.
+	ExampleParams map[string]any `yaml:"exampleParams"`
+}
+
+// ConflictFunc reports why a template of the directory cannot take the id or the name, such as them
+// belonging to a template stored elsewhere.
+type ConflictFunc func(id MessageTemplate, name string) error
+
+type directoryTemplate struct {
+	id            MessageTemplate
+	template      *StoredTemplate
+	paramsSchema  types.JSON
+	exampleParams types.JSON
+}
+
+// Directory holds the templates loaded from a directory laid out as <template>/manifest.yml,
+// <template>/<channel>.<ext> and <template>/catalog.yml, with the layouts and the partials shared by them in
+// _shared/<channel>/<name>.<ext>. The templates are replaced at once on reload, a template failing to
+// load keeps its previous version.
+type Directory struct {
+	path     string
+	conflict ConflictFunc
+
+	mx        sync.RWMutex
+	layouts   Layouts
+	templates map[string]*directoryTemplate
+	ids       map[MessageTemplate]*directoryTemplate
+	names     map[string]*directoryTemplate
+}
+
+func NewDirectory(path string) *Directory {
+	return &Directory{path: path}
+}
+
+func (d *Directory) Path() string {
+	return d.path
+}
+
+// CheckConflicts sets the check of the ids and the names of the templates against the templates the directory
+// is used along with, the templates failing it are rejected on load.
+func (d *Directory) CheckConflicts(conflict ConflictFunc) {
+	d.conflict = conflict
+}
+
+// Load loads the templates, it fails when any of them is invalid.
+func (d *Directory) Load() error {
+	errs, err := d.Reload()
+	if err != nil {
+		return err
+	}
+
+	if names := sortedKeys(errs); len(names) > 0 {
+		return fmt.Errorf("%s: %w", names[0], errs[names[0]])
+	}
+	return nil
+}
+
+// Reload loads the templates again and reports the ones failed to load by directory name,
+// they are served in their previous version if there was one and its id and name are still free.
+func (d *Directory) Reload() (map[string]error, error) {
+	entries, err := os.ReadDir(d.path)
+	if err != nil {
+		return nil, fmt.Errorf("read template directory: %w", err)
+	}
+
+	d.mx.RLock()
+	previous, layouts := d.templates, d.layouts
+	d.mx.RUnlock()
+
+	errs := make(map[string]error)
+	if shared, err := readLayouts(filepath.Join(d.path, SharedDir)); err != nil {
+		errs[SharedDir] = err
+	} else {
+		layouts = shared
+	}
+	templates := make(map[string]*directoryTemplate, len(entries))
+	ids := make(map[MessageTemplate]*directoryTemplate, len(entries))
+	names := make(map[string]*directoryTemplate, len(entries))
+	add := func(dir string, t *directoryTemplate) {
+		templates[dir] = t
+		ids[t.id] = t
+		names[strings.ToLower(t.template.Name())] = t
+	}
+
+	retained := make([]string, 0)
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || entry.Name() == SharedDir {
+			continue
+		}
+
+		dir := entry.Name()
+		t, err := loadDirectoryTemplate(filepath.Join(d.path, dir), dir, layouts)
+		if err == nil {
+			err = checkDuplicates(t, ids, names)
+		}
+		if err == nil && d.conflict != nil {
+			err = d.conflict(t.id, t.template.Name())
+		}
+		if err != nil {
+			errs[dir] = err
+			if previous[dir] != nil {
+				retained = append(retained, dir)
+			}
+			continue
+		}
+		add(dir, t)
+	}
+
+	// The previous versions are checked after the loaded templates, which take precedence over them.
+	for _, dir := range retained {
+		t := previous[dir]
+		if err := checkDuplicates(t, ids, names); err != nil {
+			errs[dir] = fmt.Errorf("%w, the previous version is dropped: %s", errs[dir], err)
+			continue
+		}
+		add(dir, t)
+	}
+
+	d.mx.Lock()
+	d.layouts, d.templates, d.ids, d.names = layouts, templates, ids, names
+	d.mx.Unlock()
+
+	return errs, nil
+}
+
+// checkDuplicates fails when the id or the name of the template is taken by another template of the directory.
+func checkDuplicates(
+	t *directoryTemplate,
+	ids map[MessageTemplate]*directoryTemplate,
+	names map[string]*directoryTemplate,
+) error {
+	if _, ok := ids[t.id]; ok {
+		return fmt.Errorf("%w: duplicate id %d", InvalidManifestErr, t.id)
+	}
+	if _, ok := names[strings.ToLower(t.template.Name())]; ok {
+		return fmt.Errorf("%w: duplicate name '%s'", InvalidManifestErr, t.template.Name())
+	}
+	return nil
+}
+
+// Layouts returns the layouts of the shared directory, DefaultLayouts before the first load.
+func (d *Directory) Layouts() Layouts {
+	d.mx.RLock()
+	defer d.mx.RUnlock()
+
+	if d.layouts == nil {
+		return DefaultLayouts
+	}
+	return d.layouts
+}
+
+func (d *Directory) Get(id MessageTemplate) (Template, bool) {
+	d.mx.RLock()
+	defer d.mx.RUnlock()
+
+	t, ok := d.ids[id]
+	if !ok {
+		return nil, false
+	}
+	return t.template, true
+}
+
+func (d *Directory) Has(id MessageTemplate) bool {
+	d.mx.RLock()
+	defer d.mx.RUnlock()
+
+	_, ok := d.ids[id]
+	return ok
+}
+
+// FindID returns the id of the template by its case-insensitive name.
+func (d *Directory) FindID(name string) (MessageTemplate, bool) {
+	d.mx.RLock()
+	defer d.mx.RUnlock()
+
+	t, ok := d.names[strings.ToLower(name)]
+	if !ok {
+		return 0, false
+	}
+	return t.id, true
+}
+
+// ParamsSchema returns the JSON Schema of the template params, nil without a schema.
+func (d *Directory) ParamsSchema(id MessageTemplate) types.JSON {
+	d.mx.RLock()
+	defer d.mx.RUnlock()
+
+	if t, ok := d.ids[id]; ok {
+		return t.paramsSchema
+	}
+	return nil
+}
+
+func loadDirectoryTemplate(path, dir string, layouts Layouts) (*directoryTemplate, error) {
+	data, err := os.ReadFile(filepath.Join(path, ManifestFile))
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest Manifest
+	if err = yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("%w: %s", InvalidManifestErr, err)
+	}
+	if manifest.ID <= 0 {
+		return nil, fmt.Errorf("%w: id must be positive", InvalidManifestErr)
+	}
+	if manifest.Name == "" {
+		manifest.Name = dir
+	}
+	if manifest.Category == "" {
+		manifest.Category = CategoryTransactional
+	}
+	if !manifest.Category.IsValid() {
+		return nil, fmt.Errorf("%w: unknown category '%s'", InvalidManifestErr, manifest.Category)
+	}
+
+	bodies, err := readBodies(path, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &directoryTemplate{id: MessageTemplate(manifest.ID)}
+	if manifest.ParamsSchema != nil {
+		if t.paramsSchema, err = json.Marshal(manifest.ParamsSchema); err != nil {
+			return nil, fmt.Errorf("%w: params schema: %s", InvalidManifestErr, err)
+		}
+		if _, err = ParseSchema(t.paramsSchema); err != nil {
+			return nil, err
+		}
+	}
+	if manifest.ExampleParams != nil {
+		if t.exampleParams, err = json.Marshal(manifest.ExampleParams); err != nil {
+			return nil, fmt.Errorf("%w: example params: %s", InvalidManifestErr, err)
+		}
+	}
+	catalog, err := readCatalog(path)
+	if err != nil {
+		return nil, err
+	}
+
+	t.template, err = NewStoredTemplate(manifest.Name, manifest.Category, manifest.Tracking, bodies, catalog, layouts)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func readBodies(path string, manifest Manifest) (Bodies, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	bodies := make(Bodies)
+	var emailText string
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || name == ManifestFile || name == CatalogFile || strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		if name == DefaultBody+".md" {
+			data, err := os.ReadFile(filepath.Join(path, name))
+			if err != nil {
+				return nil, err
+			}
+			bodies[DefaultBody] = Body{Body: string(data), Format: markdownFormat, Targets: manifest.Targets}
+			continue
+		}
+
+		ext := filepath.Ext(name)
+		format, ok := extensions[ext]
+		ch, known := channel.GetChannelTypeFromString(strings.TrimSuffix(name, ext))
+		if !ok || !known {
+			return nil, fmt.Errorf("%w: unexpected file '%s'", InvalidBodyErr, name)
+		}
+
+		data, err := os.ReadFile(filepath.Join(path, name))
+		if err != nil {
+			return nil, err
+		}
+
+		key := strings.ToLower(ch.String())
+		if ch == channel.Email && format == "plain" {
+			emailText = string(data)
+			continue
+		}
+		if _, ok = bodies[key]; ok {
+			return nil, fmt.Errorf("%w: several bodies for channel '%s'", InvalidBodyErr, key)
+		}
+		bodies[key] = Body{Body: string(data), Format: format}
+	}
+
+	if body, ok := bodies["email"]; ok {
+		body.Subject = manifest.Subject
+		body.Preheader = manifest.Preheader
+		body.Headers = manifest.Headers
+		body.Text = emailText
+		bodies["email"] = body
+	} else if emailText != "" {
+		bodies["email"] = Body{Body: emailText, Format: "plain", Subject: manifest.Subject,
+			Preheader: manifest.Preheader, Headers: manifest.Headers}
+	} else if body, ok = bodies[DefaultBody]; ok {
+		body.Subject = manifest.Subject
+		body.Preheader = manifest.Preheader
+		body.Headers = manifest.Headers
+		bodies[DefaultBody] = body
+	}
+
+	if len(manifest.Channels) == 0 {
+		return bodies, nil
+	}
+
+	allowed := make(Bodies, len(manifest.Channels))
+	for _, name := range manifest.Channels {
+		ch, ok := channel.GetChannelTypeFromString(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown channel '%s'", InvalidManifestErr, name)
+		}
+		key := strings.ToLower(ch.String())
+		body, ok := bodies[key]
+		if !ok {
+			if body, ok = bodies[DefaultBody]; !ok {
+				return nil, fmt.Errorf("%w: no body for channel '%s'", InvalidBodyErr, key)
+			}
+		}
+		allowed[key] = body
+	}
+	for key := range bodies {
+		if _, ok := allowed[key]; !ok && key != DefaultBody {
+			return nil, fmt.Errorf("%w: bodies for channels not listed in the manifest", InvalidBodyErr)
+		}
+	}
+
+	return allowed, nil
+}
+
+func readCatalog(path string) (*Catalog, error) {
+	data, err := os.ReadFile(filepath.Join(path, CatalogFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	catalog := new(Catalog)
+	if err = yaml.Unmarshal(data, catalog); err != nil {
+		return nil, fmt.Errorf("%w: %s", InvalidCatalogErr, err)
+	}
+	return catalog, nil
+}
+
+func sortedKeys(errs map[string]error) []string {
+	keys := make([]string, 0, len(errs))
+	for key := range errs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
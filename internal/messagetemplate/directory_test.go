@@ -0,0 +1,198 @@
+package messagetemplate
+
+import (
+	"errors"
+	"github.com/keweegen/notification/internal/channel"
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/sqlboiler/v4/types"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTemplateFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	assert.Nil(t, os.MkdirAll(dir, 0o755))
+	for name, content := range files {
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+}
+
+func TestDirectory_Load(t *testing.T) {
+	path := t.TempDir()
+	writeTemplateFiles(t, filepath.Join(path, "shipped"), map[string]string{
+		ManifestFile: "id: 100\nname: OrderShipped\nsubject: 'Order #{{.orderId}} shipped'\n" +
+			"channels: [email, telegram]\nparamsSchema:\n  type: object\n",
+		"email.html":  "<p>Order #{{.orderId}} shipped</p>",
+		"email.txt":   "Order #{{.orderId}} shipped",
+		"telegram.md": "Order *{{.orderId}}* shipped",
+	})
+
+	directory := NewDirectory(path)
+	assert.Nil(t, directory.Load())
+
+	id, ok := directory.FindID("ordershipped")
+	assert.True(t, ok)
+	assert.Equal(t, MessageTemplate(100), id)
+	assert.JSONEq(t, `{"type":"object"}`, string(directory.ParamsSchema(id)))
+
+	tmpl, ok := directory.Get(id)
+	assert.True(t, ok)
+	params := types.JSON(`{"orderId":7}`)
+
+	message, err := Render(tmpl, channel.Email, params, Helpers{})
+	assert.Nil(t, err)
+	assert.Equal(t, "<p>Order #7 shipped</p>", message.Text)
+	assert.Equal(t, "Order #7 shipped", message.Subject)
+	assert.Equal(t, "Order #7 shipped", message.PlainText)
+
+	message, err = Render(tmpl, channel.Telegram, params, Helpers{})
+	assert.Nil(t, err)
+	assert.Equal(t, "Order *7* shipped", message.Text)
+}
+
+func TestDirectory_Load_Invalid(t *testing.T) {
+	cases := []struct {
+		name          string
+		files         map[string]string
+		expectedError error
+	}{
+		{
+			name:          "no id",
+			files:         map[string]string{ManifestFile: "name: Shipped\n", "email.html": "Shipped"},
+			expectedError: InvalidManifestErr,
+		},
+		{
+			name:          "unexpected file",
+			files:         map[string]string{ManifestFile: "id: 100\n", "sms.txt": "Shipped"},
+			expectedError: InvalidBodyErr,
+		},
+		{
+			name:          "missing channel body",
+			files:         map[string]string{ManifestFile: "id: 100\nchannels: [telegram]\n", "email.html": "Shipped"},
+			expectedError: InvalidBodyErr,
+		},
+		{
+			name:          "invalid body",
+			files:         map[string]string{ManifestFile: "id: 100\n", "email.html": "{{.orderId"},
+			expectedError: InvalidBodyErr,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := t.TempDir()
+			writeTemplateFiles(t, filepath.Join(path, "shipped"), tc.files)
+
+			err := NewDirectory(path).Load()
+			assert.True(t, errors.Is(err, tc.expectedError), err)
+		})
+	}
+}
+
+func TestDirectory_Reload(t *testing.T) {
+	path := t.TempDir()
+	shipped := filepath.Join(path, "shipped")
+	writeTemplateFiles(t, shipped, map[string]string{
+		ManifestFile:    "id: 100\n",
+		"telegram.html": "Shipped {{.orderId}}",
+	})
+	writeTemplateFiles(t, filepath.Join(path, "delivered"), map[string]string{
+		ManifestFile:    "id: 101\n",
+		"telegram.html": "Delivered",
+	})
+
+	directory := NewDirectory(path)
+	assert.Nil(t, directory.Load())
+
+	writeTemplateFiles(t, shipped, map[string]string{"telegram.html": "Shipped {{.orderId"})
+	assert.Nil(t, os.RemoveAll(filepath.Join(path, "delivered")))
+
+	errs, err := directory.Reload()
+	assert.Nil(t, err)
+	assert.True(t, errors.Is(errs["shipped"], InvalidBodyErr))
+	assert.False(t, directory.Has(101))
+
+	tmpl, ok := directory.Get(100)
+	assert.True(t, ok)
+	params := types.JSON(`{"orderId":7}`)
+	text, err := Parse(tmpl, channel.Telegram, params, Helpers{})
+	assert.Nil(t, err)
+	assert.Equal(t, "Shipped 7", text)
+
+	writeTemplateFiles(t, shipped, map[string]string{"telegram.html": "Shipped #{{.orderId}}"})
+	errs, err = directory.Reload()
+	assert.Nil(t, err)
+	assert.Empty(t, errs)
+
+	tmpl, _ = directory.Get(100)
+	text, err = Parse(tmpl, channel.Telegram, params, Helpers{})
+	assert.Nil(t, err)
+	assert.Equal(t, "Shipped #7", text)
+}
+
+func TestDirectory_Reload_Duplicate(t *testing.T) {
+	path := t.TempDir()
+	shipped, tracked := filepath.Join(path, "shipped"), filepath.Join(path, "tracked")
+	writeTemplateFiles(t, shipped, map[string]string{
+		ManifestFile:    "id: 100\n",
+		"telegram.html": "Shipped",
+	})
+	writeTemplateFiles(t, tracked, map[string]string{
+		ManifestFile:    "id: 101\n",
+		"telegram.html": "Tracked",
+	})
+
+	directory := NewDirectory(path)
+	assert.Nil(t, directory.Load())
+
+	writeTemplateFiles(t, shipped, map[string]string{"telegram.html": "Shipped {{.orderId"})
+	writeTemplateFiles(t, tracked, map[string]string{ManifestFile: "id: 100\n"})
+
+	errs, err := directory.Reload()
+	assert.Nil(t, err)
+	assert.Len(t, errs, 1)
+	assert.ErrorIs(t, errs["shipped"], InvalidBodyErr)
+	assert.Contains(t, errs["shipped"].Error(), "duplicate id 100")
+	assert.False(t, directory.Has(101))
+
+	tmpl, ok := directory.Get(100)
+	assert.True(t, ok)
+	text, err := Parse(tmpl, channel.Telegram, types.JSON(`{}`), Helpers{})
+	assert.Nil(t, err)
+	assert.Equal(t, "Tracked", text)
+}
+
+func TestDirectory_CheckConflicts(t *testing.T) {
+	path := t.TempDir()
+	writeTemplateFiles(t, filepath.Join(path, "shipped"), map[string]string{
+		ManifestFile:    "id: 100\nname: OrderShipped\n",
+		"telegram.html": "Shipped",
+	})
+
+	conflictErr := errors.New("taken")
+	conflicts := true
+
+	directory := NewDirectory(path)
+	directory.CheckConflicts(func(id MessageTemplate, name string) error {
+		assert.Equal(t, MessageTemplate(100), id)
+		assert.Equal(t, "OrderShipped", name)
+		if conflicts {
+			return conflictErr
+		}
+		return nil
+	})
+	assert.ErrorIs(t, directory.Load(), conflictErr)
+	assert.False(t, directory.Has(100))
+
+	conflicts = false
+	assert.Nil(t, directory.Load())
+	assert.True(t, directory.Has(100))
+
+	conflicts = true
+	errs, err := directory.Reload()
+	assert.Nil(t, err)
+	assert.ErrorIs(t, errs["shipped"], conflictErr)
+	assert.True(t, directory.Has(100))
+}
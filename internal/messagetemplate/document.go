@@ -0,0 +1,75 @@
+package messagetemplate
+
+import (
+	"fmt"
+	"github.com/keweegen/notification/internal/channel/outbound"
+	"github.com/keweegen/notification/internal/pdf"
+	"github.com/volatiletech/sqlboiler/v4/types"
+	"time"
+)
+
+const pdfContentType = "application/pdf"
+
+// DocumentOptions are the branding of the documents and the fonts they are set in, Helvetica when nil.
+type DocumentOptions struct {
+	Brand    string
+	Color    pdf.Color
+	Font     *pdf.Font
+	BoldFont *pdf.Font
+	// IssuedAt is the time printed on the document, formatted in the time zone of the message.
+	IssuedAt time.Time
+}
+
+// DocumentTemplate is implemented by templates generating a PDF document from the params, such as the copy
+// of a receipt. The email channel attaches the document and the Telegram channel sends it with the text.
+type DocumentTemplate interface {
+	Document(data any, h Helpers, o DocumentOptions) (*outbound.Media, error)
+}
+
+// HasDocument reports whether the template generates a document.
+func HasDocument(t Template) bool {
+	_, ok := t.(DocumentTemplate)
+	return ok
+}
+
+// RenderDocument generates the document of the template from the params in the locale of the helpers,
+// it returns nil for templates without a document.
+func RenderDocument(t Template, params types.JSON, h Helpers, o DocumentOptions) (*outbound.Media, error) {
+	dt, ok := t.(DocumentTemplate)
+	if !ok {
+		return nil, nil
+	}
+
+	data, err := t.Data(params)
+	if err != nil {
+		return nil, fmt.Errorf("params: %s", err)
+	}
+	if h, err = h.withMessage(t); err != nil {
+		return nil, err
+	}
+
+	if o.Font == nil {
+		o.Font, o.BoldFont = pdf.Helvetica, pdf.HelveticaBold
+	}
+	if o.BoldFont == nil {
+		o.BoldFont = o.Font
+	}
+	if o.IssuedAt.IsZero() {
+		o.IssuedAt = time.Now()
+	}
+
+	media, err := dt.Document(data, h, o)
+	if err != nil {
+		return nil, fmt.Errorf("document: %w", err)
+	}
+	return media, nil
+}
+
+// pdfMedia returns the document as a media of the message.
+func pdfMedia(filename string, d *pdf.Document) (*outbound.Media, error) {
+	data, err := d.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return &outbound.Media{Kind: outbound.Document, Filename: filename, ContentType: pdfContentType, Data: data}, nil
+}
@@ -0,0 +1,57 @@
+package messagetemplate
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/keweegen/notification/internal/channel/outbound"
+	"github.com/keweegen/notification/internal/pdf"
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/sqlboiler/v4/types"
+	"testing"
+	"time"
+)
+
+func TestRenderDocument(t *testing.T) {
+	params := types.JSON(`{"orderId": 123, "commissionAmount": 15.5, "totalAmount": 1300}`)
+	options := DocumentOptions{Brand: "Shop", IssuedAt: time.Date(2026, 10, 19, 12, 30, 0, 0, time.UTC)}
+
+	media, err := RenderDocument(new(ReceiptTemplate), params, Helpers{Locale: "en"}, options)
+	assert.Nil(t, err)
+	assert.Equal(t, outbound.Document, media.Kind)
+	assert.Equal(t, "receipt-123.pdf", media.Filename)
+	assert.Equal(t, "application/pdf", media.ContentType)
+	assert.True(t, bytes.HasPrefix(media.Data, []byte("%PDF-")))
+	assert.Contains(t, string(media.Data), "/Title "+utf16Hex("Receipt for order 123"))
+	assert.Contains(t, string(media.Data), "/Author "+utf16Hex("Shop"))
+	assert.Contains(t, string(media.Data), "/BaseFont /Helvetica-Bold")
+
+	again, err := RenderDocument(new(ReceiptTemplate), params, Helpers{Locale: "en"}, options)
+	assert.Nil(t, err)
+	assert.Equal(t, media.Data, again.Data)
+
+	if font, err := pdf.LoadFont("/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf"); err == nil {
+		options.Font = font
+		media, err = RenderDocument(new(ReceiptTemplate), params, Helpers{Locale: "ru"}, options)
+		assert.Nil(t, err)
+		assert.Contains(t, string(media.Data), "/Title "+utf16Hex("Чек по заказу 123"))
+		assert.Regexp(t, `/BaseFont /[A-Z]{6}\+DejaVuSans /Encoding /Identity-H`, string(media.Data))
+	}
+
+	_, err = RenderDocument(new(ReceiptTemplate), types.JSON(`{"orderId": "123"}`), Helpers{}, options)
+	assert.NotNil(t, err)
+
+	stored, err := NewStoredTemplate("Shipped", CategoryTransactional, false, Bodies{"telegram": {Body: "Shipped"}}, nil, nil)
+	assert.Nil(t, err)
+	assert.False(t, HasDocument(stored))
+	media, err = RenderDocument(stored, params, Helpers{}, options)
+	assert.Nil(t, err)
+	assert.Nil(t, media)
+}
+
+func utf16Hex(text string) string {
+	hex := "<FEFF"
+	for _, r := range text {
+		hex += fmt.Sprintf("%04X", r)
+	}
+	return hex + ">"
+}
@@ -0,0 +1,288 @@
+package messagetemplate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	// The zone database is embedded, so that the time zones of the users load on hosts without one.
+	_ "time/tzdata"
+	"unicode/utf8"
+)
+
+// localeFormat is how the numbers and the dates are written in a language,
+// the group separators are the no-break spaces where the language uses a space.
+type localeFormat struct {
+	group   string
+	decimal string
+	// currencyFirst puts the currency code before the amount, e.g. KZT 1,300.00 instead of 1 300,00 KZT.
+	currencyFirst bool
+	date          string
+	time          string
+}
+
+// defaultFormat is used for the languages missing from localeFormats: ISO dates and 24-hour time.
+var defaultFormat = localeFormat{group: ",", decimal: ".", currencyFirst: true, date: "2006-01-02", time: "15:04"}
+
+var localeFormats = map[string]localeFormat{
+	"ru": {group: "\u00a0", decimal: ",", date: "02.01.2006", time: "15:04"},
+	"kk": {group: "\u00a0", decimal: ",", date: "02.01.2006", time: "15:04"},
+	"uk": {group: "\u00a0", decimal: ",", date: "02.01.2006", time: "15:04"},
+	"be": {group: "\u00a0", decimal: ",", date: "02.01.2006", time: "15:04"},
+	"pl": {group: "\u00a0", decimal: ",", date: "02.01.2006", time: "15:04"},
+	"cs": {group: "\u00a0", decimal: ",", date: "02.01.2006", time: "15:04"},
+	"de": {group: ".", decimal: ",", date: "02.01.2006", time: "15:04"},
+	"fr": {group: "\u202f", decimal: ",", date: "02/01/2006", time: "15:04"},
+	"en": {group: ",", decimal: ".", currencyFirst: true, date: "01/02/2006", time: "3:04 PM"},
+}
+
+// currencyDigits are the minor unit digits of the currencies without two of them.
+var currencyDigits = map[string]int{
+	"JPY": 0, "KRW": 0, "VND": 0, "CLP": 0, "ISK": 0, "UGX": 0,
+	"BHD": 3, "KWD": 3, "OMR": 3, "JOD": 3, "TND": 3,
+}
+
+// dateLayouts are the layout names the date function takes besides the Go layouts.
+var dateLayouts = map[string]func(f localeFormat) string{
+	"date":     func(f localeFormat) string { return f.date },
+	"time":     func(f localeFormat) string { return f.time },
+	"datetime": func(f localeFormat) string { return f.date + " " + f.time },
+}
+
+func formatFor(locale string) localeFormat {
+	if f, ok := localeFormats[language(locale)]; ok {
+		return f
+	}
+	return defaultFormat
+}
+
+func language(locale string) string {
+	language := strings.ToLower(locale)
+	if i := strings.IndexAny(language, "-_"); i >= 0 {
+		language = language[:i]
+	}
+	return language
+}
+
+// LoadTimeZone returns the location of the IANA time zone, UTC when the name is empty.
+func LoadTimeZone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(name)
+}
+
+// formatNumberIn writes the number in the locale with the given digits after the decimal separator,
+// with as many as needed when the digits are negative.
+func formatNumberIn(locale string, n float64, digits int) string {
+	return groupNumber(formatFor(locale), n < 0, strconv.FormatFloat(math.Abs(n), 'f', digits, 64))
+}
+
+// groupNumber writes the absolute value text, e.g. 1300.50, with the separators of the locale.
+func groupNumber(f localeFormat, negative bool, text string) string {
+	integer, fraction, _ := strings.Cut(text, ".")
+
+	var b strings.Builder
+	if negative && strings.Trim(text, "0.") != "" {
+		b.WriteString("-")
+	}
+	for i, r := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteString(f.group)
+		}
+		b.WriteRune(r)
+	}
+	if fraction != "" {
+		b.WriteString(f.decimal)
+		b.WriteString(fraction)
+	}
+
+	return b.String()
+}
+
+// formatCurrencyIn writes the amount rounded to the minor unit digits of the currency and its code.
+func formatCurrencyIn(locale string, amount *big.Rat, code string) string {
+	code = strings.ToUpper(code)
+	digits, ok := currencyDigits[code]
+	if !ok {
+		digits = 2
+	}
+
+	number := groupNumber(formatFor(locale), amount.Sign() < 0, new(big.Rat).Abs(amount).FloatString(digits))
+	if code == "" {
+		return number
+	}
+	if formatFor(locale).currencyFirst {
+		return code + "\u00a0" + number
+	}
+	return number + "\u00a0" + code
+}
+
+func formatDateIn(locale string, location *time.Location, value any, layout string) (string, error) {
+	t, err := toTime(value)
+	if err != nil {
+		return "", err
+	}
+	if location != nil {
+		t = t.In(location)
+	}
+
+	if named, ok := dateLayouts[layout]; ok {
+		layout = named(formatFor(locale))
+	}
+	return t.Format(layout), nil
+}
+
+// pluralize picks the form of the count plural category from the category and form pairs,
+// e.g. "one" "%d item" "other" "%d items", the other form when the category has none.
+func pluralize(locale string, n any, forms []string) (string, error) {
+	count, err := toInt(n)
+	if err != nil {
+		return "", fmt.Errorf("pluralize: %w", err)
+	}
+	if len(forms)%2 != 0 {
+		return "", errors.New("pluralize: forms must be category and form pairs")
+	}
+
+	var m Message
+	for i := 0; i < len(forms); i += 2 {
+		switch forms[i] {
+		case "zero":
+			m.Zero = forms[i+1]
+		case "one":
+			m.One = forms[i+1]
+		case "two":
+			m.Two = forms[i+1]
+		case "few":
+			m.Few = forms[i+1]
+		case "many":
+			m.Many = forms[i+1]
+		case "other":
+			m.Other = forms[i+1]
+		default:
+			return "", fmt.Errorf("pluralize: unknown plural category '%s'", forms[i])
+		}
+	}
+
+	return format(m.form(PluralCategory(locale, count)), []any{count}), nil
+}
+
+// truncate shortens the text to n characters, the cut text ends with an ellipsis.
+func truncate(n int, s string) string {
+	if n <= 0 || utf8.RuneCountInString(s) <= n {
+		return s
+	}
+
+	runes := []rune(s)
+	return strings.TrimRight(string(runes[:n-1]), " ") + "…"
+}
+
+// buildURL adds the key and value pairs to the query of the http or https base URL, the values are escaped.
+func buildURL(base string, pairs ...any) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("url: unsupported scheme '%s'", u.Scheme)
+	}
+	if len(pairs)%2 != 0 {
+		return "", errors.New("url: query must be key and value pairs")
+	}
+
+	query := u.Query()
+	for i := 0; i < len(pairs); i += 2 {
+		query.Add(fmt.Sprint(pairs[i]), fmt.Sprint(pairs[i+1]))
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+func toFloat(n any) (float64, error) {
+	switch v := n.(type) {
+	case int:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case float32:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case json.Number:
+		return v.Float64()
+	case Amount:
+		f, _ := v.Rat().Float64()
+		return f, nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		return 0, fmt.Errorf("must be a number, got %T", n)
+	}
+}
+
+// toDecimal returns the exact value of the number, the amounts keep the decimals they were given in.
+func toDecimal(n any) (*big.Rat, error) {
+	switch v := n.(type) {
+	case Amount:
+		return v.Rat(), nil
+	case *Amount:
+		return v.Rat(), nil
+	case json.Number:
+		r, ok := new(big.Rat).SetString(v.String())
+		if !ok {
+			return nil, fmt.Errorf("'%s' is not a number", v)
+		}
+		return r, nil
+	case string:
+		amount, err := ParseAmount(v)
+		if err != nil {
+			return nil, err
+		}
+		return amount.Rat(), nil
+	default:
+		f, err := toFloat(n)
+		if err != nil {
+			return nil, err
+		}
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("'%v' is not a number", f)
+		}
+		return new(big.Rat).SetFloat64(f), nil
+	}
+}
+
+// toTime takes times, RFC 3339 strings, dates like 2022-09-08 and unix timestamps in seconds.
+func toTime(value any) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case *time.Time:
+		if v == nil {
+			return time.Time{}, errors.New("date: nil time")
+		}
+		return *v, nil
+	case string:
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t, nil
+		}
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("date: '%s' is neither an RFC 3339 time nor a date", v)
+		}
+		return t, nil
+	default:
+		seconds, err := toFloat(value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("date: %w", err)
+		}
+		return time.Unix(int64(seconds), 0), nil
+	}
+}
@@ -0,0 +1,120 @@
+package messagetemplate
+
+import (
+	"github.com/stretchr/testify/assert"
+	"html/template"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHelpers_Funcs(t *testing.T) {
+	paidAt := "2022-09-08T11:30:00Z"
+
+	cases := []struct {
+		name     string
+		helpers  Helpers
+		body     string
+		expected string
+	}{
+		{
+			name:     "number",
+			helpers:  Helpers{Locale: "ru"},
+			body:     `{{number 1234567}} {{number 0.25}} {{number -1234.5 2}}`,
+			expected: "1\u00a0234\u00a0567 0,25 -1\u00a0234,50",
+		},
+		{
+			name:     "number in english",
+			helpers:  Helpers{Locale: "en-US"},
+			body:     `{{number 1234567.891 2}}`,
+			expected: "1,234,567.89",
+		},
+		{
+			name:     "currency",
+			helpers:  Helpers{Locale: "ru"},
+			body:     `{{currency "KZT" 1300}} {{currency "JPY" 1300}} {{"1300.5" | currency "KZT"}}`,
+			expected: "1\u00a0300,00\u00a0KZT 1\u00a0300\u00a0JPY 1\u00a0300,50\u00a0KZT",
+		},
+		{
+			name:     "currency in other languages",
+			helpers:  Helpers{Locale: "en"},
+			body:     `{{currency "USD" 1300}}`,
+			expected: "USD\u00a01,300.00",
+		},
+		{
+			name:     "date in the time zone",
+			helpers:  Helpers{Locale: "ru", TimeZone: "Asia/Almaty"},
+			body:     `{{date "date" .}} {{date "datetime" .}} {{date "2 Jan 2006 15:04" .}}`,
+			expected: "08.09.2022 08.09.2022 17:30 8 Sep 2022 17:30",
+		},
+		{
+			name:     "date in english",
+			helpers:  Helpers{Locale: "en"},
+			body:     `{{date "datetime" .}}`,
+			expected: "09/08/2022 11:30 AM",
+		},
+		{
+			name:     "date without locale",
+			body:     `{{date "datetime" .}} {{date "date" 0}}`,
+			expected: "2022-09-08 11:30 1970-01-01",
+		},
+		{
+			name:     "pluralize",
+			helpers:  Helpers{Locale: "ru"},
+			body:     `{{pluralize 1 "one" "%d товар" "few" "%d товара" "other" "%d товаров"}}, {{pluralize 22 "one" "%d товар" "few" "%d товара" "other" "%d товаров"}}, {{pluralize 5 "one" "%d товар" "other" "%d товаров"}}`,
+			expected: "1 товар, 22 товара, 5 товаров",
+		},
+		{
+			name:     "truncate",
+			body:     `{{truncate 8 "Order shipped"}} {{truncate 20 "Order shipped"}} {{truncate 3 "Заказ"}}`,
+			expected: "Order s… Order shipped За…",
+		},
+		{
+			name:     "url",
+			body:     `<a href="{{url "https://shop.kz/orders?lang=ru" "id" 7 "q" "a&b c"}}">x</a>`,
+			expected: `<a href="https://shop.kz/orders?id=7&amp;lang=ru&amp;q=a%26b&#43;c">x</a>`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h, err := tc.helpers.withMessage(nil)
+			assert.Nil(t, err)
+
+			tmpl := template.Must(template.New("ns-test.funcs").Funcs(h.funcs()).Parse(tc.body))
+			var result strings.Builder
+			assert.Nil(t, tmpl.Execute(&result, paidAt))
+			assert.Equal(t, tc.expected, result.String())
+		})
+	}
+}
+
+func TestHelpers_Funcs_Errors(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+	}{
+		{name: "not a number", body: `{{number "x"}}`},
+		{name: "not a date", body: `{{date "date" "yesterday"}}`},
+		{name: "odd plural forms", body: `{{pluralize 1 "one"}}`},
+		{name: "unknown plural category", body: `{{pluralize 1 "single" "item"}}`},
+		{name: "unsafe url", body: `{{url "javascript:alert(1)"}}`},
+		{name: "odd query", body: `{{url "https://shop.kz" "id"}}`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpl := template.Must(template.New("ns-test.funcs").Funcs(Funcs).Parse(tc.body))
+			assert.NotNil(t, tmpl.Execute(new(strings.Builder), nil))
+		})
+	}
+}
+
+func TestHelpers_InvalidTimeZone(t *testing.T) {
+	_, err := Helpers{TimeZone: "Asia/Nowhere"}.withMessage(nil)
+	assert.NotNil(t, err)
+
+	location, err := LoadTimeZone("")
+	assert.Nil(t, err)
+	assert.Equal(t, time.UTC, location)
+}
@@ -0,0 +1,264 @@
+package messagetemplate
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// Helpers are the values of the message being rendered, templates get them through helper functions:
+//
+//	<a href="{{unsubscribeURL}}">Unsubscribe</a>
+//	<p>{{t "paid" .OrderID}}</p>
+//
+// The formatting functions write the values the way the locale does:
+//
+//	{{number .Count}}, {{number .Rate 2}}       1 234, 0,25
+//	{{currency .Currency .TotalAmount}}         1 300,00 KZT
+//	{{date "datetime" .PaidAt}}                 08.09.2022 17:30 in the time zone of the user
+//	{{pluralize .Count "one" "%d item" "other" "%d items"}}
+//	{{truncate 40 .Title}}                      at most 40 characters, … when cut
+//	{{url "https://shop.kz/orders" "id" .OrderID}}
+type Helpers struct {
+	// UnsubscribeURL is empty for transactional messages.
+	UnsubscribeURL string
+	// Locale is the locale of the catalog messages, the default locale of the catalog when empty.
+	Locale string
+	// Fallbacks are the locales the messages missing from the locale are looked up in, in order,
+	// before the default locale of the catalog.
+	Fallbacks []string
+	// TimeZone is the IANA time zone the dates are formatted in, UTC when empty.
+	TimeZone string
+
+	catalog  *Catalog
+	location *time.Location
+	// strict fails the render on the params missing from the data, templates are linted with it.
+	strict bool
+}
+
+// Funcs declares the helper functions, templates calling them must be parsed with it.
+// The declared functions return zero values and are replaced with the message ones at render time.
+var Funcs = Helpers{}.funcs()
+
+// TextFuncs is Funcs for text templates.
+var TextFuncs = Helpers{}.textFuncs()
+
+func (h Helpers) funcs() template.FuncMap {
+	return template.FuncMap{
+		"unsubscribeURL": func() string { return h.UnsubscribeURL },
+		"t": func(key string, args ...any) template.HTML {
+			return template.HTML(h.translate(key, escapeArgs(args)...))
+		},
+		"plural": func(key string, n any, args ...any) (template.HTML, error) {
+			text, err := h.plural(key, n, escapeArgs(args))
+			return template.HTML(text), err
+		},
+		"number":    h.number,
+		"currency":  h.currency,
+		"date":      h.date,
+		"pluralize": h.pluralize,
+		"truncate":  truncate,
+		"url":       buildURL,
+		// The escaped values are the markup of the channel, html/template must not escape them as HTML.
+		"escapeMarkdownV2":     func(v any) template.HTML { return template.HTML(escapeMarkdownV2(v)) },
+		"escapeMarkdownV2URL":  func(v any) template.HTML { return template.HTML(escapeMarkdownV2URL(v)) },
+		"escapeMarkdownV2Code": func(v any) template.HTML { return template.HTML(escapeMarkdownV2Code(v)) },
+		"escapeMrkdwn":         func(v any) template.HTML { return template.HTML(escapeMrkdwn(v)) },
+	}
+}
+
+func (h Helpers) textFuncs() texttemplate.FuncMap {
+	return texttemplate.FuncMap{
+		"unsubscribeURL": func() string { return h.UnsubscribeURL },
+		"t":              h.translate,
+		"plural": func(key string, n any, args ...any) (string, error) {
+			return h.plural(key, n, args)
+		},
+		"number":               h.number,
+		"currency":             h.currency,
+		"date":                 h.date,
+		"pluralize":            h.pluralize,
+		"truncate":             truncate,
+		"url":                  buildURL,
+		"escapeMarkdownV2":     escapeMarkdownV2,
+		"escapeMarkdownV2URL":  escapeMarkdownV2URL,
+		"escapeMarkdownV2Code": escapeMarkdownV2Code,
+		"escapeMrkdwn":         escapeMrkdwn,
+	}
+}
+
+// withMessage returns the helpers with the catalog of the template and the location of the time zone.
+func (h Helpers) withMessage(t Template) (Helpers, error) {
+	h.catalog = GetCatalog(t)
+
+	var err error
+	if h.location, err = LoadTimeZone(h.TimeZone); err != nil {
+		return h, fmt.Errorf("time zone: %w", err)
+	}
+	return h, nil
+}
+
+// number writes the number with the given digits after the decimal separator, as many as it has by default.
+func (h Helpers) number(n any, digits ...int) (string, error) {
+	value, err := toFloat(n)
+	if err != nil {
+		return "", fmt.Errorf("number: %w", err)
+	}
+
+	precision := -1
+	if len(digits) > 0 {
+		precision = digits[0]
+	}
+	return formatNumberIn(h.locale(), value, precision), nil
+}
+
+func (h Helpers) currency(code string, amount any) (string, error) {
+	value, err := toDecimal(amount)
+	if err != nil {
+		return "", fmt.Errorf("currency: %w", err)
+	}
+	return formatCurrencyIn(h.locale(), value, code), nil
+}
+
+// date formats the time in the time zone of the message with a named layout, date, time or datetime,
+// or a Go layout such as "2 Jan 2006".
+func (h Helpers) date(layout string, value any) (string, error) {
+	return formatDateIn(h.locale(), h.location, value, layout)
+}
+
+func (h Helpers) pluralize(n any, forms ...string) (string, error) {
+	return pluralize(h.locale(), n, forms)
+}
+
+// translate returns the message of the key, the key itself when the catalog has no such message.
+func (h Helpers) translate(key string, args ...any) string {
+	m, _, ok := h.catalog.message(h.chain(), key)
+	if !ok {
+		return key
+	}
+	return format(m.Other, args)
+}
+
+func (h Helpers) plural(key string, n any, args []any) (string, error) {
+	count, err := toInt(n)
+	if err != nil {
+		return "", fmt.Errorf("plural '%s': %w", key, err)
+	}
+
+	m, locale, ok := h.catalog.message(h.chain(), key)
+	if !ok {
+		return key, nil
+	}
+	return format(m.form(PluralCategory(locale, count)), append([]any{count}, args...)), nil
+}
+
+func (h Helpers) locale() string {
+	if h.Locale == "" && h.catalog != nil {
+		return h.catalog.Default
+	}
+	return h.Locale
+}
+
+// chain returns the locale followed by its fallbacks.
+func (h Helpers) chain() []string {
+	chain := make([]string, 0, len(h.Fallbacks)+1)
+	return append(append(chain, h.locale()), h.Fallbacks...)
+}
+
+// format formats the message with the arguments, messages without verbs are returned as they are.
+// The numbers of the params are float64, they are formatted with the integer verbs as well.
+func format(message string, args []any) string {
+	if len(args) == 0 || !strings.Contains(message, "%") {
+		return message
+	}
+
+	formatted := make([]any, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case float64:
+			formatted[i] = numberArg(v)
+		case float32:
+			formatted[i] = numberArg(v)
+		case json.Number:
+			if f, err := v.Float64(); err == nil {
+				formatted[i] = numberArg(f)
+				continue
+			}
+			formatted[i] = arg
+		default:
+			formatted[i] = arg
+		}
+	}
+	return fmt.Sprintf(message, formatted...)
+}
+
+// numberArg is a number argument of a message, formatted as an integer with the integer verbs
+// and as a float with the others.
+type numberArg float64
+
+func (n numberArg) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'd', 'o', 'O', 'x', 'X', 'b', 'c':
+		fmt.Fprintf(f, formatDirective(f, verb), int64(n))
+	default:
+		fmt.Fprintf(f, formatDirective(f, verb), float64(n))
+	}
+}
+
+// formatDirective rebuilds the directive of the verb with the flags, width and precision of the state.
+func formatDirective(f fmt.State, verb rune) string {
+	var b strings.Builder
+	b.WriteByte('%')
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			b.WriteRune(flag)
+		}
+	}
+	if width, ok := f.Width(); ok {
+		b.WriteString(strconv.Itoa(width))
+	}
+	if precision, ok := f.Precision(); ok {
+		b.WriteByte('.')
+		b.WriteString(strconv.Itoa(precision))
+	}
+	b.WriteRune(verb)
+	return b.String()
+}
+
+// escapeArgs escapes the arguments for HTML, numbers are kept for the numeric verbs.
+func escapeArgs(args []any) []any {
+	escaped := make([]any, len(args))
+	for i, arg := range args {
+		switch arg.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+			escaped[i] = arg
+		default:
+			escaped[i] = template.HTMLEscapeString(fmt.Sprint(arg))
+		}
+	}
+	return escaped
+}
+
+func toInt(n any) (int, error) {
+	switch v := n.(type) {
+	case int:
+		return v, nil
+	case int32:
+		return int(v), nil
+	case int64:
+		return int(v), nil
+	case float64:
+		return int(v), nil
+	case json.Number:
+		i, err := v.Int64()
+		return int(i), err
+	case string:
+		return strconv.Atoi(v)
+	default:
+		return 0, fmt.Errorf("count must be a number, got %T", n)
+	}
+}
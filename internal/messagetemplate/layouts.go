@@ -0,0 +1,155 @@
+package messagetemplate
+
+import (
+	"errors"
+	"fmt"
+	"github.com/keweegen/notification/internal/channel"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// SharedDir is the directory of the template directory holding the layouts and the partials
+	// of every template by channel, e.g. _shared/email/footer.html.
+	SharedDir = "_shared"
+	// LayoutTemplate is the name of the layout a body opts in to, the body defines its content:
+	//
+	//	{{template "layout" .}}
+	//	{{define "content"}}<p>Order {{.orderId}} shipped</p>{{end}}
+	LayoutTemplate = "layout"
+)
+
+var InvalidLayoutErr = errors.New("template: invalid layout")
+
+// Layouts are the templates shared by the bodies of a channel: the layout wrapping the content of a body
+// and the partials, such as the header and the footer, the bodies and the layout include by name.
+// The Mock channel shares the Telegram layouts.
+type Layouts map[channel.Channel]*template.Template
+
+// DefaultLayouts are the layouts compiled into the service. The email layout is an HTML document
+// including the header and the footer partials, empty by default. A shared directory overrides
+// them by name.
+var DefaultLayouts = Layouts{
+	channel.Email: template.Must(template.New("ns.email." + LayoutTemplate).Funcs(Funcs).Parse(`
+{{- define "layout" -}}
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="utf-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+</head>
+<body>
+{{template "header" .}}
+{{block "content" .}}{{end}}
+{{template "footer" .}}
+</body>
+</html>
+{{- end -}}
+{{- define "header"}}{{end -}}
+{{- define "footer"}}{{end -}}`)),
+	channel.Telegram: template.Must(template.New("ns.telegram." + LayoutTemplate).Funcs(Funcs).Parse(`
+{{- define "layout"}}{{template "header" .}}{{block "content" .}}{{end}}{{template "footer" .}}{{end -}}
+{{- define "header"}}{{end -}}
+{{- define "footer"}}{{end -}}`)),
+}
+
+// get returns the layouts of the channel.
+func (l Layouts) get(ch channel.Channel) *template.Template {
+	if ch == channel.Mock {
+		ch = channel.Telegram
+	}
+	if tmpl, ok := l[ch]; ok {
+		return tmpl
+	}
+	return DefaultLayouts[ch]
+}
+
+// parseBody parses the body along with the layouts of the channel. The layouts are cloned,
+// so the bodies redefining the content do not change them.
+func (l Layouts) parseBody(ch channel.Channel, name, body string) (*template.Template, error) {
+	shared := l.get(ch)
+	if shared == nil {
+		return template.New(name).Funcs(Funcs).Parse(body)
+	}
+
+	tmpl, err := shared.Clone()
+	if err != nil {
+		return nil, err
+	}
+	return tmpl.New(name).Parse(body)
+}
+
+// withLayout returns the body defining the content of the layout.
+func withLayout(content string) string {
+	return `{{template "` + LayoutTemplate + `" .}}{{define "content"}}` + content + "{{end}}"
+}
+
+// readLayouts reads the layouts of the <channel>/<name>.<ext> files of the shared directory over the
+// default ones. A file is a template named after the file without the extension, e.g. footer.html
+// replaces the footer partial. The default layouts are used when there is no shared directory.
+func readLayouts(path string) (Layouts, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return DefaultLayouts, nil
+		}
+		return nil, err
+	}
+
+	layouts := make(Layouts, len(DefaultLayouts))
+	for ch, tmpl := range DefaultLayouts {
+		layouts[ch] = tmpl
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		ch, ok := channel.GetChannelTypeFromString(entry.Name())
+		if !ok || ch == channel.Mock {
+			return nil, fmt.Errorf("%w: unknown channel '%s'", InvalidLayoutErr, entry.Name())
+		}
+		if layouts[ch], err = readChannelLayouts(filepath.Join(path, entry.Name()), layouts.get(ch)); err != nil {
+			return nil, fmt.Errorf("%w: %s: %s", InvalidLayoutErr, entry.Name(), err)
+		}
+	}
+
+	return layouts, nil
+}
+
+func readChannelLayouts(path string, base *template.Template) (*template.Template, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := base.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		ext := filepath.Ext(name)
+		if _, ok := extensions[ext]; !ok {
+			return nil, fmt.Errorf("unexpected file '%s'", name)
+		}
+
+		data, err := os.ReadFile(filepath.Join(path, name))
+		if err != nil {
+			return nil, err
+		}
+		if _, err = tmpl.New(strings.TrimSuffix(name, ext)).Parse(string(data)); err != nil {
+			return nil, err
+		}
+	}
+
+	return tmpl, nil
+}
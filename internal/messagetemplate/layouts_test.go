@@ -0,0 +1,83 @@
+package messagetemplate
+
+import (
+	"errors"
+	"github.com/keweegen/notification/internal/channel"
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/sqlboiler/v4/types"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewStoredTemplate_Layout(t *testing.T) {
+	tmpl, err := NewStoredTemplate("Shipped", CategoryTransactional, false, Bodies{
+		"email":    {Body: `{{template "layout" .}}{{define "content"}}<p>Order {{.orderId}} shipped</p>{{end}}`},
+		"telegram": {Body: `Order {{.orderId}} shipped`},
+	}, nil, nil)
+	assert.Nil(t, err)
+
+	params := types.JSON(`{"orderId": 7}`)
+	text, err := Parse(tmpl, channel.Email, params, Helpers{})
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(text, "<!DOCTYPE html>"), text)
+	assert.Contains(t, text, "<p>Order 7 shipped</p>")
+
+	text, err = Parse(tmpl, channel.Telegram, params, Helpers{})
+	assert.Nil(t, err)
+	assert.Equal(t, "Order 7 shipped", text)
+}
+
+func TestDirectory_Load_SharedLayouts(t *testing.T) {
+	path := t.TempDir()
+	writeTemplateFiles(t, filepath.Join(path, SharedDir, "email"), map[string]string{
+		"footer.html": `<p class="footer">{{t "footer"}}</p>`,
+		"button.html": `<a class="button" href="{{.url}}">{{.text}}</a>`,
+	})
+	writeTemplateFiles(t, filepath.Join(path, SharedDir, "telegram"), map[string]string{
+		"footer.md": "\n\n_Shop.kz_",
+	})
+	writeTemplateFiles(t, filepath.Join(path, "shipped"), map[string]string{
+		ManifestFile:  "id: 100\n",
+		"email.html":  `{{template "layout" .}}{{define "content"}}<p>Shipped</p>{{template "button" .}}{{end}}`,
+		"telegram.md": `{{template "layout" .}}{{define "content"}}*Shipped*{{end}}`,
+		CatalogFile:   "default: en\nmessages:\n  en:\n    footer: Shop.kz, Almaty\n",
+	})
+
+	directory := NewDirectory(path)
+	assert.Nil(t, directory.Load())
+
+	tmpl, _ := directory.Get(100)
+	params := types.JSON(`{"url": "https://shop.kz/track/7", "text": "Track"}`)
+
+	text, err := Parse(tmpl, channel.Email, params, Helpers{})
+	assert.Nil(t, err)
+	assert.Contains(t, text, `<a class="button" href="https://shop.kz/track/7">Track</a>`)
+	assert.Contains(t, text, `<p class="footer">Shop.kz, Almaty</p>`)
+
+	message, err := Render(tmpl, channel.Telegram, params, Helpers{})
+	assert.Nil(t, err)
+	assert.Equal(t, "*Shipped*\n\n_Shop.kz_", message.Text)
+}
+
+func TestDirectory_Load_InvalidSharedLayouts(t *testing.T) {
+	cases := []struct {
+		name  string
+		dir   string
+		files map[string]string
+	}{
+		{name: "unknown channel", dir: "sms", files: map[string]string{"footer.txt": "Shop.kz"}},
+		{name: "unexpected file", dir: "email", files: map[string]string{"footer.yml": "Shop.kz"}},
+		{name: "invalid partial", dir: "email", files: map[string]string{"footer.html": "{{.shop"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := t.TempDir()
+			writeTemplateFiles(t, filepath.Join(path, SharedDir, tc.dir), tc.files)
+
+			err := NewDirectory(path).Load()
+			assert.True(t, errors.Is(err, InvalidLayoutErr), err)
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package messagetemplate
+
+import (
+	"errors"
+	"github.com/keweegen/notification/internal/channel"
+	"github.com/keweegen/notification/internal/channel/email"
+	"github.com/keweegen/notification/internal/channel/outbound"
+	"github.com/keweegen/notification/internal/channel/telegram"
+	"github.com/volatiletech/sqlboiler/v4/types"
+	"sort"
+	"strings"
+)
+
+// ExampleTemplate is implemented by templates declaring the params they are linted with.
+type ExampleTemplate interface {
+	ExampleParams() types.JSON
+}
+
+// GetExampleParams returns the example params of the template, nil when the template has none.
+func GetExampleParams(t Template) types.JSON {
+	if et, ok := t.(ExampleTemplate); ok {
+		return et.ExampleParams()
+	}
+	return nil
+}
+
+// LintTemplate is a template to lint with its example params and its params schema, nil without one.
+type LintTemplate struct {
+	ID            MessageTemplate
+	Name          string
+	Template      Template
+	ExampleParams types.JSON
+	ParamsSchema  types.JSON
+}
+
+// LintError is a problem of a template, rendered for the channel in the locale unless they are empty.
+type LintError struct {
+	Template string
+	Channel  channel.Channel
+	Locale   string
+	Problem  string
+}
+
+func (e LintError) Error() string {
+	parts := []string{e.Template}
+	if e.Channel != 0 {
+		parts = append(parts, strings.ToLower(e.Channel.String()))
+	}
+	if e.Locale != "" {
+		parts = append(parts, e.Locale)
+	}
+	return strings.Join(append(parts, e.Problem), ": ")
+}
+
+// LintTemplates returns the compiled templates, the stored ones and the ones of the directory, which may be nil,
+// sorted by name. A template of the directory replaces the stored or compiled one with the same id,
+// a stored template replaces the compiled one.
+func LintTemplates(d *Directory, stored []LintTemplate) []LintTemplate {
+	inDirectory := func(id MessageTemplate) bool { return d != nil && d.Has(id) }
+	isStored := make(map[MessageTemplate]bool, len(stored))
+
+	lint := make([]LintTemplate, 0, len(templates)+len(stored))
+	for _, t := range stored {
+		if inDirectory(t.ID) {
+			continue
+		}
+		isStored[t.ID] = true
+		lint = append(lint, t)
+	}
+
+	for id, t := range templates {
+		if inDirectory(id) || isStored[id] {
+			continue
+		}
+		lint = append(lint, LintTemplate{
+			ID:            id,
+			Name:          t.Name(),
+			Template:      t,
+			ExampleParams: GetExampleParams(t),
+			ParamsSchema:  GetParamsSchema(t),
+		})
+	}
+
+	if d != nil {
+		d.mx.RLock()
+		for id, t := range d.ids {
+			lint = append(lint, LintTemplate{
+				ID:            id,
+				Name:          t.template.Name(),
+				Template:      t.template,
+				ExampleParams: t.exampleParams,
+				ParamsSchema:  t.paramsSchema,
+			})
+		}
+		d.mx.RUnlock()
+	}
+
+	sort.Slice(lint, func(i, j int) bool { return lint[i].Name < lint[j].Name })
+	return lint
+}
+
+// Lint renders the template with its example params for every channel with a body and every locale
+// of its catalog. A render fails on the params referenced by the bodies and missing from the example.
+// The example must match the params schema, the Telegram texts must fit a message and use the tags
+// of the Bot API only, the email HTML must be well-formed. The document of the template is generated too.
+func Lint(t LintTemplate) []LintError {
+	errs := make([]LintError, 0)
+	report := func(ch channel.Channel, locale string, problem string) {
+		errs = append(errs, LintError{Template: t.Name, Channel: ch, Locale: locale, Problem: problem})
+	}
+
+	if len(t.ExampleParams) == 0 {
+		report(0, "", "no example params")
+		return errs
+	}
+	if t.ParamsSchema != nil {
+		var paramsErr *ParamsError
+		if err := ValidateParams(t.ParamsSchema, t.ExampleParams); errors.As(err, &paramsErr) {
+			for _, fieldErr := range paramsErr.Errors {
+				report(0, "", "example params: "+fieldErr.Field+": "+fieldErr.Message)
+			}
+		} else if err != nil {
+			report(0, "", err.Error())
+		}
+	}
+
+	for _, ch := range channel.Channels {
+		if tmpl, err := getChannelTemplateByName(t.Template, ch); ch == channel.Mock || err != nil || tmpl == nil {
+			continue
+		}
+
+		for _, locale := range lintLocales(GetCatalog(t.Template)) {
+			message, err := Render(t.Template, ch, t.ExampleParams, Helpers{Locale: locale, strict: true})
+			if err != nil {
+				report(ch, locale, err.Error())
+				continue
+			}
+			for _, problem := range lintMessage(ch, message) {
+				report(ch, locale, problem)
+			}
+		}
+	}
+
+	if HasDocument(t.Template) {
+		for _, locale := range lintLocales(GetCatalog(t.Template)) {
+			if _, err := RenderDocument(t.Template, t.ExampleParams, Helpers{Locale: locale, strict: true}, DocumentOptions{}); err != nil {
+				report(0, locale, err.Error())
+			}
+		}
+	}
+
+	return errs
+}
+
+func lintMessage(ch channel.Channel, message *outbound.Message) []string {
+	switch {
+	case ch == channel.Telegram:
+		return telegram.Lint(message.Text, message.Format)
+	case ch == channel.Email && message.Format == outbound.FormatHTML:
+		return email.Lint(message.Text)
+	default:
+		return nil
+	}
+}
+
+// lintLocales returns the locales of the catalog sorted, the default locale without a catalog.
+func lintLocales(catalog *Catalog) []string {
+	if catalog == nil || len(catalog.Messages) == 0 {
+		return []string{""}
+	}
+
+	locales := make([]string, 0, len(catalog.Messages))
+	for locale := range catalog.Messages {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
@@ -0,0 +1,123 @@
+package messagetemplate
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/sqlboiler/v4/types"
+	"path/filepath"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	schema := types.JSON(`{"type": "object", "required": ["orderId"], "properties": {"orderId": {"type": "integer"}}}`)
+	catalog := &Catalog{Default: "en", Messages: map[string]map[string]Message{
+		"en": {"order": {Other: "Order %d"}},
+		"ru": {"order": {Other: "Заказ %d"}},
+	}}
+
+	cases := []struct {
+		name     string
+		bodies   Bodies
+		catalog  *Catalog
+		example  types.JSON
+		schema   types.JSON
+		expected []string
+	}{
+		{
+			name:     "fine",
+			bodies:   Bodies{DefaultBody: {Body: `{{t "order" .orderId}} **shipped**`}},
+			catalog:  catalog,
+			example:  types.JSON(`{"orderId": 7}`),
+			schema:   schema,
+			expected: []string{},
+		},
+		{
+			name:     "no example",
+			bodies:   Bodies{"telegram": {Body: "Order {{.orderId}}"}},
+			expected: []string{"shipped: no example params"},
+		},
+		{
+			name:     "example not matching the schema",
+			bodies:   Bodies{"telegram": {Body: "Order {{.orderId}}"}},
+			example:  types.JSON(`{"orderId": "7"}`),
+			schema:   schema,
+			expected: []string{"shipped: example params: orderId: must be of type integer"},
+		},
+		{
+			name:    "missing param",
+			bodies:  Bodies{"telegram": {Body: "Order {{.orderId}} by {{.carrier}}"}},
+			catalog: catalog,
+			example: types.JSON(`{"orderId": 7}`),
+			expected: []string{
+				`shipped: telegram: en: execute: template: ns.telegram.Shipped:1:24: executing "ns.telegram.Shipped" ` +
+					`at <.carrier>: map has no entry for key "carrier"`,
+				`shipped: telegram: ru: execute: template: ns.telegram.Shipped:1:24: executing "ns.telegram.Shipped" ` +
+					`at <.carrier>: map has no entry for key "carrier"`,
+			},
+		},
+		{
+			name: "channel markup",
+			bodies: Bodies{
+				"email":    {Body: "<p>Order {{.orderId}}<div></p>"},
+				"telegram": {Body: "<h1>Order {{.orderId}}</h1>"},
+			},
+			example: types.JSON(`{"orderId": 7}`),
+			expected: []string{
+				"shipped: telegram: unsupported tag <h1>",
+				"shipped: email: <div> closed by </p>",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpl, err := NewStoredTemplate("Shipped", CategoryTransactional, false, tc.bodies, tc.catalog, nil)
+			assert.Nil(t, err)
+
+			errs := Lint(LintTemplate{Name: "shipped", Template: tmpl, ExampleParams: tc.example, ParamsSchema: tc.schema})
+			problems := make([]string, 0, len(errs))
+			for _, err := range errs {
+				problems = append(problems, err.Error())
+			}
+			assert.Equal(t, tc.expected, problems)
+		})
+	}
+}
+
+func TestLintTemplates(t *testing.T) {
+	assert.Empty(t, Lint(LintTemplates(nil, nil)[0]))
+
+	path := t.TempDir()
+	writeTemplateFiles(t, filepath.Join(path, "receipt"), map[string]string{
+		ManifestFile:  "id: 1\nname: Receipt\nexampleParams:\n  orderId: 7\n",
+		"telegram.md": "Order {{.orderId}}",
+	})
+	writeTemplateFiles(t, filepath.Join(path, "shipped"), map[string]string{
+		ManifestFile:  "id: 100\nname: Shipped\n",
+		"telegram.md": "Order {{.orderId}}",
+	})
+	directory := NewDirectory(path)
+	assert.Nil(t, directory.Load())
+
+	templates := LintTemplates(directory, nil)
+	assert.Len(t, templates, 2)
+	assert.Equal(t, "Receipt", templates[0].Name)
+	assert.Equal(t, types.JSON(`{"orderId":7}`), templates[0].ExampleParams)
+	assert.Empty(t, Lint(templates[0]))
+	assert.Equal(t, []LintError{{Template: "Shipped", Problem: "no example params"}}, Lint(templates[1]))
+
+	tmpl, _ := directory.Get(1)
+	assert.Equal(t, tmpl, templates[0].Template)
+
+	stored, err := NewStoredTemplate("Receipt", CategoryTransactional, false,
+		Bodies{"telegram": {Body: "Paid {{.orderId}}"}}, nil, nil)
+	assert.Nil(t, err)
+	receipt := LintTemplate{ID: 1, Name: "Receipt", Template: stored, ExampleParams: types.JSON(`{"orderId":7}`)}
+
+	templates = LintTemplates(nil, []LintTemplate{receipt})
+	assert.Len(t, templates, 1)
+	assert.Equal(t, receipt, templates[0])
+
+	templates = LintTemplates(directory, []LintTemplate{receipt})
+	assert.Len(t, templates, 2)
+	assert.Equal(t, tmpl, templates[0].Template)
+}
@@ -0,0 +1,282 @@
+package messagetemplate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"regexp"
+	"strings"
+)
+
+var InvalidCatalogErr = errors.New("template: invalid catalog")
+
+var localePattern = regexp.MustCompile(`^[A-Za-z]{2,3}([-_][A-Za-z0-9]{2,8})*$`)
+
+// NormalizeLocale returns the BCP 47 form of the locale, e.g. kk-KZ for kk_kz.
+func NormalizeLocale(locale string) (string, bool) {
+	if !localePattern.MatchString(locale) {
+		return "", false
+	}
+
+	parts := strings.FieldsFunc(locale, func(r rune) bool { return r == '-' || r == '_' })
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		switch len(parts[i]) {
+		case 2:
+			parts[i] = strings.ToUpper(parts[i])
+		case 4:
+			parts[i] = strings.ToUpper(parts[i][:1]) + strings.ToLower(parts[i][1:])
+		default:
+			parts[i] = strings.ToLower(parts[i])
+		}
+	}
+
+	return strings.Join(parts, "-"), true
+}
+
+// FallbackChain returns the locales to look a translation up in: the locale and its parents
+// (kk-Cyrl-KZ, kk-Cyrl, kk), then the fallbacks of the first of them having one, followed the same way,
+// and the default locale with its fallbacks last. The fallbacks are keyed by lower case locale, e.g. kk → ru → en.
+func FallbackChain(locale string, fallbacks map[string]string, defaultLocale string) []string {
+	chain := make([]string, 0, 4)
+	seen := make(map[string]bool)
+
+	add := func(locale string) string {
+		var next string
+		for locale != "" {
+			key := strings.ToLower(locale)
+			if !seen[key] {
+				seen[key] = true
+				chain = append(chain, locale)
+			}
+			if next == "" {
+				next = fallbacks[key]
+			}
+
+			i := strings.LastIndexAny(locale, "-_")
+			if i < 0 {
+				break
+			}
+			locale = locale[:i]
+		}
+		return next
+	}
+
+	for _, locale := range []string{locale, defaultLocale} {
+		for locale != "" && !seen[strings.ToLower(locale)] {
+			locale = add(locale)
+		}
+	}
+
+	return chain
+}
+
+// Message is a catalog message. It is formatted with the arguments given by the template as fmt does,
+// plural messages have a form per CLDR plural category and get the count as the first argument:
+//
+//	items:
+//	  one: "%d товар"
+//	  few: "%d товара"
+//	  many: "%d товаров"
+//
+// Messages are markup, like the template bodies, the arguments are escaped.
+type Message struct {
+	Zero  string `json:"zero,omitempty" yaml:"zero"`
+	One   string `json:"one,omitempty" yaml:"one"`
+	Two   string `json:"two,omitempty" yaml:"two"`
+	Few   string `json:"few,omitempty" yaml:"few"`
+	Many  string `json:"many,omitempty" yaml:"many"`
+	Other string `json:"other,omitempty" yaml:"other"`
+}
+
+type pluralMessage Message
+
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*m = Message{Other: s}
+		return nil
+	}
+	return json.Unmarshal(data, (*pluralMessage)(m))
+}
+
+// MarshalJSON keeps the messages without plural forms strings.
+func (m Message) MarshalJSON() ([]byte, error) {
+	if m == (Message{Other: m.Other}) {
+		return json.Marshal(m.Other)
+	}
+	return json.Marshal(pluralMessage(m))
+}
+
+func (m *Message) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*m = Message{Other: value.Value}
+		return nil
+	}
+	return value.Decode((*pluralMessage)(m))
+}
+
+// form returns the message form of the plural category, the other form when the category has none.
+func (m Message) form(category string) string {
+	var form string
+	switch category {
+	case "zero":
+		form = m.Zero
+	case "one":
+		form = m.One
+	case "two":
+		form = m.Two
+	case "few":
+		form = m.Few
+	case "many":
+		form = m.Many
+	}
+	if form == "" {
+		return m.Other
+	}
+	return form
+}
+
+// Catalog holds the messages of a template by locale.
+type Catalog struct {
+	// Default is the locale used when the template has none of the requested locales.
+	Default  string                        `json:"default" yaml:"default"`
+	Messages map[string]map[string]Message `json:"messages" yaml:"messages"`
+}
+
+// LocalizedTemplate is implemented by templates translated with a catalog, their bodies
+// get the messages through the t and plural helper functions:
+//
+//	<p>{{t "paid" .OrderID}}</p>
+//	<p>{{plural "items" .Count}}</p>
+type LocalizedTemplate interface {
+	Catalog() *Catalog
+}
+
+// GetCatalog returns the catalog of the template, nil when the template is not localized.
+func GetCatalog(t Template) *Catalog {
+	if lt, ok := t.(LocalizedTemplate); ok {
+		return lt.Catalog()
+	}
+	return nil
+}
+
+// ResolveLocale returns the first locale of the chain the template is translated to, the default
+// locale of the catalog when there is none, and an empty string when the template is not localized.
+func ResolveLocale(t Template, chain []string) string {
+	c := GetCatalog(t)
+	if c == nil {
+		return ""
+	}
+
+	for _, locale := range chain {
+		if key, ok := c.find(locale); ok {
+			return key
+		}
+	}
+	return c.Default
+}
+
+// Validate checks that the locales are valid and that the default one is translated.
+func (c *Catalog) Validate() error {
+	if c == nil || len(c.Messages) == 0 {
+		return nil
+	}
+
+	for locale := range c.Messages {
+		if _, ok := NormalizeLocale(locale); !ok {
+			return fmt.Errorf("%w: invalid locale '%s'", InvalidCatalogErr, locale)
+		}
+	}
+	if _, ok := c.find(c.Default); !ok {
+		return fmt.Errorf("%w: no messages for the default locale '%s'", InvalidCatalogErr, c.Default)
+	}
+
+	return nil
+}
+
+func (c *Catalog) find(locale string) (string, bool) {
+	if locale == "" {
+		return "", false
+	}
+	if _, ok := c.Messages[locale]; ok {
+		return locale, true
+	}
+	for key := range c.Messages {
+		if strings.EqualFold(key, locale) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// message looks the key up in the locales of the chain in order, then in the default locale.
+func (c *Catalog) message(chain []string, key string) (Message, string, bool) {
+	if c == nil {
+		return Message{}, "", false
+	}
+
+	for _, l := range append(append([]string(nil), chain...), c.Default) {
+		if found, ok := c.find(l); ok {
+			if m, ok := c.Messages[found][key]; ok {
+				return m, found, true
+			}
+		}
+	}
+	return Message{}, "", false
+}
+
+// PluralCategory returns the CLDR plural category of the integer count in the language of the locale.
+func PluralCategory(locale string, n int) string {
+	language := strings.ToLower(locale)
+	if i := strings.IndexAny(language, "-_"); i >= 0 {
+		language = language[:i]
+	}
+	if n < 0 {
+		n = -n
+	}
+	mod10, mod100 := n%10, n%100
+
+	switch language {
+	case "ja", "zh", "ko", "vi", "th", "id", "ms":
+		return "other"
+	case "fr", "pt":
+		if n == 0 || n == 1 {
+			return "one"
+		}
+		return "other"
+	case "ru", "uk", "be":
+		switch {
+		case mod10 == 1 && mod100 != 11:
+			return "one"
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return "few"
+		default:
+			return "many"
+		}
+	case "pl":
+		switch {
+		case n == 1:
+			return "one"
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return "few"
+		default:
+			return "many"
+		}
+	case "cs", "sk":
+		switch {
+		case n == 1:
+			return "one"
+		case n >= 2 && n <= 4:
+			return "few"
+		default:
+			return "other"
+		}
+	default:
+		if n == 1 {
+			return "one"
+		}
+		return "other"
+	}
+}
@@ -0,0 +1,169 @@
+package messagetemplate
+
+import (
+	"encoding/json"
+	"github.com/keweegen/notification/internal/channel"
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/sqlboiler/v4/types"
+	"gopkg.in/yaml.v3"
+	"testing"
+)
+
+func TestNormalizeLocale(t *testing.T) {
+	cases := map[string]string{
+		"kk_kz":      "kk-KZ",
+		"EN":         "en",
+		"sr-latn-rs": "sr-Latn-RS",
+		"":           "",
+		"k":          "",
+		"en US":      "",
+	}
+
+	for input, expected := range cases {
+		locale, ok := NormalizeLocale(input)
+		assert.Equal(t, expected, locale, input)
+		assert.Equal(t, expected != "", ok, input)
+	}
+}
+
+func TestFallbackChain(t *testing.T) {
+	fallbacks := map[string]string{"kk": "ru", "ru": "en", "en": "kk"}
+
+	assert.Equal(t, []string{"kk-KZ", "kk", "ru", "en"}, FallbackChain("kk-KZ", fallbacks, "en"))
+	assert.Equal(t, []string{"de-AT", "de", "ru", "en", "kk"}, FallbackChain("de-AT", fallbacks, "ru"))
+	assert.Equal(t, []string{"ru"}, FallbackChain("", nil, "ru"))
+}
+
+func TestPluralCategory(t *testing.T) {
+	cases := []struct {
+		locale   string
+		n        int
+		expected string
+	}{
+		{locale: "ru", n: 1, expected: "one"},
+		{locale: "ru-RU", n: 3, expected: "few"},
+		{locale: "ru", n: 5, expected: "many"},
+		{locale: "ru", n: 11, expected: "many"},
+		{locale: "ru", n: 21, expected: "one"},
+		{locale: "ru", n: 112, expected: "many"},
+		{locale: "en", n: 1, expected: "one"},
+		{locale: "en", n: 0, expected: "other"},
+		{locale: "kk", n: 2, expected: "other"},
+		{locale: "fr", n: 0, expected: "one"},
+		{locale: "ja", n: 1, expected: "other"},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.expected, PluralCategory(tc.locale, tc.n), "%s %d", tc.locale, tc.n)
+	}
+}
+
+func TestMessage_Unmarshal(t *testing.T) {
+	var catalog Catalog
+	assert.Nil(t, yaml.Unmarshal([]byte(`
+default: ru
+messages:
+  ru:
+    title: Чек
+    items:
+      one: "%d товар"
+      few: "%d товара"
+      many: "%d товаров"
+`), &catalog))
+	assert.Equal(t, Message{Other: "Чек"}, catalog.Messages["ru"]["title"])
+	assert.Equal(t, Message{One: "%d товар", Few: "%d товара", Many: "%d товаров"}, catalog.Messages["ru"]["items"])
+
+	data, err := json.Marshal(catalog)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"default":"ru","messages":{"ru":{"title":"Чек",`+
+		`"items":{"one":"%d товар","few":"%d товара","many":"%d товаров"}}}}`, string(data))
+
+	var decoded Catalog
+	assert.Nil(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, catalog, decoded)
+}
+
+func TestRender_Localized(t *testing.T) {
+	catalog := &Catalog{
+		Default: "en",
+		Messages: map[string]map[string]Message{
+			"en": {
+				"greeting": {Other: "Hello, <b>%s</b>"},
+				"items":    {One: "%d item", Other: "%d items"},
+				"subject":  {Other: "Order %v"},
+			},
+			"ru": {
+				"greeting": {Other: "Привет, <b>%s</b>"},
+				"items":    {One: "%d товар", Few: "%d товара", Many: "%d товаров"},
+			},
+			"kk": {
+				"greeting": {Other: "Сәлем, <b>%s</b>"},
+			},
+		},
+	}
+	tmpl, err := NewStoredTemplate("Shipped", CategoryTransactional, false, Bodies{
+		"email": {
+			Body:    `{{t "greeting" .name}} {{plural "items" .count}} {{t "unknown"}}`,
+			Subject: `{{t "subject" .orderId}}`,
+		},
+	}, catalog, nil)
+	assert.Nil(t, err)
+	params := types.JSON(`{"name":"<Ann>","count":3,"orderId":7}`)
+
+	assert.Equal(t, "ru", ResolveLocale(tmpl, []string{"uz-UZ", "uz", "ru", "en"}))
+	assert.Equal(t, "en", ResolveLocale(tmpl, []string{"de"}))
+
+	message, err := Render(tmpl, channel.Email, params, Helpers{Locale: "ru"})
+	assert.Nil(t, err)
+	assert.Equal(t, "Привет, <b>&lt;Ann&gt;</b> 3 товара unknown", message.Text)
+	assert.Equal(t, "Order 7", message.Subject)
+
+	message, err = Render(tmpl, channel.Email, params, Helpers{})
+	assert.Nil(t, err)
+	assert.Equal(t, "Hello, <b>&lt;Ann&gt;</b> 3 items unknown", message.Text)
+
+	message, err = Render(tmpl, channel.Email, params, Helpers{Locale: "kk", Fallbacks: []string{"kk", "ru", "en"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "Сәлем, <b>&lt;Ann&gt;</b> 3 товара unknown", message.Text)
+	assert.Equal(t, "Order 7", message.Subject)
+}
+
+func TestRender_NumberArgs(t *testing.T) {
+	catalog := &Catalog{Default: "en", Messages: map[string]map[string]Message{
+		"en": {"paid": {Other: "Order #%d, %.2f paid, %v left"}},
+	}}
+	tmpl, err := NewStoredTemplate("Paid", CategoryTransactional, false, Bodies{
+		"telegram": {Body: `{{t "paid" .orderId .amount .left}}`},
+	}, catalog, nil)
+	assert.Nil(t, err)
+
+	text, err := Parse(tmpl, channel.Telegram, types.JSON(`{"orderId":1234567,"amount":1300,"left":2.5}`), Helpers{})
+	assert.Nil(t, err)
+	assert.Equal(t, "Order #1234567, 1300.00 paid, 2.5 left", text)
+}
+
+func TestNewStoredTemplate_InvalidCatalog(t *testing.T) {
+	_, err := NewStoredTemplate("Shipped", CategoryTransactional, false,
+		Bodies{"email": {Body: `{{t "title"}}`}},
+		&Catalog{Default: "de", Messages: map[string]map[string]Message{"en": {"title": {Other: "Title"}}}}, nil)
+	assert.ErrorIs(t, err, InvalidCatalogErr)
+}
+
+func TestReceiptTemplate_Localized(t *testing.T) {
+	params := types.JSON(`{"orderId": 123, "commissionAmount": 1, "totalAmount": 1001}`)
+
+	message, err := Render(new(ReceiptTemplate), channel.Email, params, Helpers{Locale: "en"})
+	assert.Nil(t, err)
+	assert.Equal(t, "Receipt for order 123", message.Subject)
+	assert.Equal(t, "Order 123 has been paid, amount charged KZT\u00a01,001.00", message.Preheader)
+	assert.Contains(t, message.Text, ">Order <b>123</b> has been paid</p>")
+}
+
+func TestReceiptTemplate_LegacyAmounts(t *testing.T) {
+	params := types.JSON(`{"orderId": 123, "commissionAmount": "300,00 USD", "totalAmount": "1 300,10 USD"}`)
+
+	message, err := Render(new(ReceiptTemplate), channel.Email, params, Helpers{Locale: "en"})
+	assert.Nil(t, err)
+	assert.Equal(t, "Order 123 has been paid, amount charged USD\u00a01,300.10", message.Preheader)
+	assert.Contains(t, message.Text, "Fee: USD\u00a0300.00")
+}
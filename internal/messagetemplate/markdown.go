@@ -0,0 +1,812 @@
+package messagetemplate
+
+import (
+	"fmt"
+	"github.com/keweegen/notification/internal/channel/telegram"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MarkdownTarget is the markup a Markdown source is compiled into.
+type MarkdownTarget int
+
+const (
+	// MarkdownEmailHTML is HTML with the styles inlined into the elements, mail clients drop style sheets.
+	MarkdownEmailHTML MarkdownTarget = iota + 1
+	// MarkdownTelegramHTML is the HTML subset of the Bot API, headings become bold lines and lists bullet lines.
+	MarkdownTelegramHTML
+	// MarkdownTelegramV2 is the MarkdownV2 parse mode of the Bot API.
+	MarkdownTelegramV2
+	// MarkdownMrkdwn is the Slack mrkdwn.
+	MarkdownMrkdwn
+	// MarkdownPlain is the text without markup, for SMS and push notifications.
+	MarkdownPlain
+)
+
+// emailStyles are the inline styles of the email elements.
+var emailStyles = map[string]string{
+	"h1":         "margin:0 0 16px;font-size:24px;line-height:32px;font-weight:bold;",
+	"h2":         "margin:0 0 16px;font-size:20px;line-height:28px;font-weight:bold;",
+	"h3":         "margin:0 0 16px;font-size:16px;line-height:24px;font-weight:bold;",
+	"p":          "margin:0 0 16px;font-size:16px;line-height:24px;",
+	"a":          "color:#1a73e8;text-decoration:underline;",
+	"ul":         "margin:0 0 16px;padding-left:24px;",
+	"ol":         "margin:0 0 16px;padding-left:24px;",
+	"li":         "margin:0 0 4px;font-size:16px;line-height:24px;",
+	"blockquote": "margin:0 0 16px;padding:0 0 0 16px;border-left:4px solid #dddddd;color:#555555;",
+	"code":       "font-family:monospace;background:#f4f4f4;padding:2px 4px;",
+	"pre":        "margin:0 0 16px;padding:12px;background:#f4f4f4;font-family:monospace;white-space:pre-wrap;",
+	"hr":         "border:0;border-top:1px solid #dddddd;margin:24px 0;",
+}
+
+const (
+	markdownPunct = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+	ruleText      = "———"
+)
+
+var (
+	htmlTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	htmlAttrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&#34;")
+	mrkdwnEscaper   = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	orderedItem     = regexp.MustCompile(`^(\d{1,9})[.)] +`)
+	controlKeywords = map[string]bool{
+		"if": true, "else": true, "end": true, "range": true, "with": true, "define": true,
+		"template": true, "block": true, "break": true, "continue": true,
+	}
+)
+
+// CompileMarkdown compiles the Markdown source of a template into the target markup, the template
+// actions are kept as they are, so the result is a template body executed with the params:
+//
+//	### {{t "title"}}
+//	Order **{{.orderId}}** has been [shipped]({{.trackURL}})
+//
+// Headings, paragraphs, lists, block quotes, fenced code blocks, rules, bold, italic, strikethrough,
+// inline code and links are supported, a line break in a paragraph is kept. A line made of control
+// actions only, e.g. {{range .items}} or {{end}}, is not wrapped into a paragraph, a list keeps such
+// lines around its items. The values printed by the actions are escaped for the MarkdownV2 and mrkdwn
+// targets, the HTML targets are escaped by html/template.
+func CompileMarkdown(source string, target MarkdownTarget) string {
+	blocks := parseMarkdownBlocks(strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n"))
+	r := &markdownRenderer{target: target}
+
+	lastText := -1
+	for i, block := range blocks {
+		if block.kind != mdRaw {
+			lastText = i
+		}
+	}
+
+	for i, block := range blocks {
+		text := r.block(block)
+		r.b.WriteString(text)
+		if block.kind == mdRaw || i >= lastText {
+			continue
+		}
+		r.b.WriteString(r.separator(block))
+	}
+
+	return r.b.String()
+}
+
+// -- Blocks
+
+type mdBlockKind int
+
+const (
+	mdParagraph mdBlockKind = iota
+	mdHeading
+	mdList
+	mdQuote
+	mdCode
+	mdRule
+	mdRaw
+)
+
+type mdBlock struct {
+	kind  mdBlockKind
+	level int
+	// text is the inline text of paragraphs, headings and quotes, the content of code blocks, the raw line.
+	text     string
+	language string
+	ordered  bool
+	start    int
+	items    []mdItem
+}
+
+// mdItem is a list item, raw items are the control action lines kept between the items.
+type mdItem struct {
+	text string
+	raw  bool
+}
+
+func parseMarkdownBlocks(lines []string) []mdBlock {
+	blocks := make([]mdBlock, 0)
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			i++
+		case strings.HasPrefix(trimmed, "```"):
+			block := mdBlock{kind: mdCode, language: strings.TrimSpace(strings.TrimPrefix(trimmed, "```"))}
+			code := make([]string, 0)
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			block.text = strings.Join(code, "\n")
+			blocks = append(blocks, block)
+			i++
+		case headingLevel(trimmed) > 0:
+			level := headingLevel(trimmed)
+			blocks = append(blocks, mdBlock{kind: mdHeading, level: level, text: strings.TrimSpace(trimmed[level:])})
+			i++
+		case isRule(trimmed):
+			blocks = append(blocks, mdBlock{kind: mdRule})
+			i++
+		case strings.HasPrefix(trimmed, ">"):
+			quote := make([]string, 0)
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				text := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quote = append(quote, strings.TrimPrefix(text, " "))
+			}
+			blocks = append(blocks, mdBlock{kind: mdQuote, text: strings.Join(quote, "\n")})
+		case isListItem(line) || isControlLine(trimmed) && startsList(lines, i):
+			var block mdBlock
+			block, i = parseMarkdownList(lines, i)
+			blocks = append(blocks, block)
+		case isControlLine(trimmed):
+			blocks = append(blocks, mdBlock{kind: mdRaw, text: trimmed})
+			i++
+		default:
+			paragraph := []string{trimmed}
+			for i++; i < len(lines) && !startsBlock(lines[i]); i++ {
+				paragraph = append(paragraph, strings.TrimSpace(lines[i]))
+			}
+			blocks = append(blocks, mdBlock{kind: mdParagraph, text: strings.Join(paragraph, "\n")})
+		}
+	}
+
+	return blocks
+}
+
+func parseMarkdownList(lines []string, i int) (mdBlock, int) {
+	block := mdBlock{kind: mdList, start: 1}
+	first := true
+
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			return block, i
+		case isListItem(line):
+			ordered, number, text := listItem(line)
+			if first {
+				block.ordered, block.start, first = ordered, number, false
+			} else if ordered != block.ordered {
+				return block, i
+			}
+			block.items = append(block.items, mdItem{text: text})
+		case isControlLine(trimmed):
+			block.items = append(block.items, mdItem{text: trimmed, raw: true})
+		case (line[0] == ' ' || line[0] == '\t') && len(block.items) > 0 && !block.items[len(block.items)-1].raw:
+			block.items[len(block.items)-1].text += "\n" + trimmed
+		default:
+			return block, i
+		}
+	}
+
+	return block, i
+}
+
+// startsList reports whether the control action lines at i lead to a list item.
+func startsList(lines []string, i int) bool {
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if isListItem(lines[i]) {
+			return true
+		}
+		if !isControlLine(trimmed) {
+			return false
+		}
+	}
+	return false
+}
+
+func startsBlock(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" || strings.HasPrefix(trimmed, "```") || headingLevel(trimmed) > 0 || isRule(trimmed) ||
+		strings.HasPrefix(trimmed, ">") || isListItem(line) || isControlLine(trimmed)
+}
+
+func headingLevel(line string) int {
+	level := 0
+	for level < len(line) && level < 7 && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || level < len(line) && line[level] != ' ' {
+		return 0
+	}
+	return level
+}
+
+func isRule(line string) bool {
+	compact := strings.ReplaceAll(line, " ", "")
+	if len(compact) < 3 {
+		return false
+	}
+	return strings.Count(compact, compact[:1]) == len(compact) && strings.Contains("-*_", compact[:1])
+}
+
+func isListItem(line string) bool {
+	_, _, text := listItem(line)
+	return text != ""
+}
+
+// listItem returns the kind, the number of ordered items and the text of the list item line,
+// the text is empty when the line is not a list item.
+func listItem(line string) (bool, int, string) {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return false, 0, ""
+	}
+
+	if len(trimmed) > 2 && strings.ContainsRune("-*+", rune(trimmed[0])) && trimmed[1] == ' ' {
+		return false, 0, strings.TrimSpace(trimmed[2:])
+	}
+	if m := orderedItem.FindStringSubmatch(trimmed); m != nil {
+		number, _ := strconv.Atoi(m[1])
+		return true, number, strings.TrimSpace(trimmed[len(m[0]):])
+	}
+	return false, 0, ""
+}
+
+// isControlLine reports whether the line is made of template actions printing nothing, e.g. {{end}}.
+func isControlLine(line string) bool {
+	if !strings.HasPrefix(line, "{{") {
+		return false
+	}
+
+	for i := 0; i < len(line); {
+		if line[i] == ' ' || line[i] == '\t' {
+			i++
+			continue
+		}
+		end := actionEnd(line, i)
+		if !strings.HasPrefix(line[i:], "{{") || end < 0 || isOutputAction(line[i:end]) {
+			return false
+		}
+		i = end
+	}
+	return true
+}
+
+// -- Inlines
+
+type mdInlineKind int
+
+const (
+	mdText mdInlineKind = iota
+	mdAction
+	mdStrong
+	mdEmphasis
+	mdStrike
+	mdCodeSpan
+	mdLink
+	mdLineBreak
+)
+
+type mdInline struct {
+	kind     mdInlineKind
+	text     string
+	url      string
+	children []mdInline
+}
+
+func parseInline(s string) []mdInline {
+	nodes := make([]mdInline, 0)
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, mdInline{kind: mdText, text: text.String()})
+			text.Reset()
+		}
+	}
+	add := func(node mdInline) {
+		flush()
+		nodes = append(nodes, node)
+	}
+
+	for i := 0; i < len(s); {
+		c := s[i]
+
+		switch {
+		case strings.HasPrefix(s[i:], "{{"):
+			end := actionEnd(s, i)
+			if end < 0 {
+				text.WriteString(s[i:])
+				i = len(s)
+				continue
+			}
+			add(mdInline{kind: mdAction, text: s[i:end]})
+			i = end
+			continue
+		case c == '\\' && i+1 < len(s) && strings.IndexByte(markdownPunct, s[i+1]) >= 0:
+			text.WriteByte(s[i+1])
+			i += 2
+			continue
+		case c == '\n':
+			add(mdInline{kind: mdLineBreak})
+			i++
+			continue
+		case c == '`':
+			if end := indexOutsideActions(s, i+1, "`"); end > i+1 {
+				add(mdInline{kind: mdCodeSpan, children: parseActions(s[i+1 : end])})
+				i = end + 1
+				continue
+			}
+		case strings.HasPrefix(s[i:], "**") || strings.HasPrefix(s[i:], "__") || strings.HasPrefix(s[i:], "~~"):
+			delimiter := s[i : i+2]
+			if end := indexOutsideActions(s, i+2, delimiter); end > i+2 && canWrap(s, i, end, 2) {
+				kind := mdStrong
+				if delimiter == "~~" {
+					kind = mdStrike
+				}
+				add(mdInline{kind: kind, children: parseInline(s[i+2 : end])})
+				i = end + 2
+				continue
+			}
+		case c == '*' || c == '_':
+			if end := indexOutsideActions(s, i+1, string(c)); end > i+1 && canWrap(s, i, end, 1) {
+				add(mdInline{kind: mdEmphasis, children: parseInline(s[i+1 : end])})
+				i = end + 1
+				continue
+			}
+		case c == '[':
+			if middle := indexOutsideActions(s, i+1, "]("); middle > i+1 {
+				if end := indexOutsideActions(s, middle+2, ")"); end > middle+2 {
+					add(mdInline{kind: mdLink, url: s[middle+2 : end], children: parseInline(s[i+1 : middle])})
+					i = end + 1
+					continue
+				}
+			}
+		}
+
+		text.WriteByte(c)
+		i++
+	}
+	flush()
+
+	return nodes
+}
+
+// canWrap reports whether the delimiters at start and end enclose text: not spaces next to them,
+// and not inside a word for underscores, e.g. order_id.
+func canWrap(s string, start, end, size int) bool {
+	if s[start+size] == ' ' || s[end-1] == ' ' {
+		return false
+	}
+	if s[start] != '_' {
+		return true
+	}
+	return (start == 0 || !isWordByte(s[start-1])) && (end+size >= len(s) || !isWordByte(s[end+size]))
+}
+
+func isWordByte(c byte) bool {
+	return c >= 0x80 || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// parseActions splits the text of code into text and actions.
+func parseActions(s string) []mdInline {
+	nodes := make([]mdInline, 0)
+	for s != "" {
+		start := strings.Index(s, "{{")
+		end := -1
+		if start >= 0 {
+			end = actionEnd(s, start)
+		}
+		if end < 0 {
+			return append(nodes, mdInline{kind: mdText, text: s})
+		}
+		if start > 0 {
+			nodes = append(nodes, mdInline{kind: mdText, text: s[:start]})
+		}
+		nodes = append(nodes, mdInline{kind: mdAction, text: s[start:end]})
+		s = s[end:]
+	}
+	return nodes
+}
+
+// actionEnd returns the index following the action starting at i, -1 when it is not closed.
+func actionEnd(s string, i int) int {
+	end := strings.Index(s[i+2:], "}}")
+	if end < 0 {
+		return -1
+	}
+	return i + 2 + end + 2
+}
+
+// indexOutsideActions returns the index of the substring from the index on, skipping the actions.
+func indexOutsideActions(s string, from int, substr string) int {
+	for i := from; i < len(s); {
+		if strings.HasPrefix(s[i:], "{{") {
+			end := actionEnd(s, i)
+			if end < 0 {
+				return -1
+			}
+			i = end
+			continue
+		}
+		if strings.HasPrefix(s[i:], substr) {
+			return i
+		}
+		if s[i] == '\\' {
+			i++
+		}
+		i++
+	}
+	return -1
+}
+
+// isOutputAction reports whether the action prints a value: not a control structure,
+// a comment or a variable assignment.
+func isOutputAction(action string) bool {
+	inner := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(action, "{{"), "}}"))
+	inner = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(inner, "-"), "-"))
+	if inner == "" || strings.HasPrefix(inner, "/*") {
+		return false
+	}
+
+	words := strings.FieldsFunc(inner, func(r rune) bool { return r == ' ' || r == '(' || r == '\t' })
+	if len(words) == 0 {
+		return false
+	}
+	word := words[0]
+	if controlKeywords[word] {
+		return false
+	}
+	return !(strings.HasPrefix(word, "$") && (strings.Contains(inner, ":=") || strings.Contains(inner, " = ")))
+}
+
+// escapeAction pipes the value printed by the action to the escaping function.
+func escapeAction(action, function string) string {
+	if !isOutputAction(action) {
+		return action
+	}
+
+	inner := strings.TrimSuffix(strings.TrimPrefix(action, "{{"), "}}")
+	left, right := "", ""
+	if strings.HasPrefix(inner, "- ") {
+		left, inner = "- ", inner[2:]
+	}
+	if strings.HasSuffix(inner, " -") {
+		right, inner = " -", inner[:len(inner)-2]
+	}
+	return "{{" + left + strings.TrimSpace(inner) + " | " + function + right + "}}"
+}
+
+// escapeMarkdownV2 escapes the value printed into MarkdownV2 text.
+func escapeMarkdownV2(value any) string {
+	return telegram.EscapeMarkdownV2(toString(value))
+}
+
+// escapeMarkdownV2URL escapes the value printed into the URL of a MarkdownV2 link.
+func escapeMarkdownV2URL(value any) string {
+	return strings.NewReplacer(`\`, `\\`, ")", `\)`).Replace(toString(value))
+}
+
+// escapeMarkdownV2Code escapes the value printed into MarkdownV2 code.
+func escapeMarkdownV2Code(value any) string {
+	return strings.NewReplacer(`\`, `\\`, "`", "\\`").Replace(toString(value))
+}
+
+// escapeMrkdwn escapes the control characters of Slack mrkdwn.
+func escapeMrkdwn(value any) string {
+	return mrkdwnEscaper.Replace(toString(value))
+}
+
+func toString(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return fmt.Sprint(value)
+}
+
+// -- Rendering
+
+type markdownRenderer struct {
+	target MarkdownTarget
+	b      strings.Builder
+}
+
+func (r *markdownRenderer) isHTML() bool {
+	return r.target == MarkdownEmailHTML || r.target == MarkdownTelegramHTML
+}
+
+// separator returns what follows the block when another block comes next.
+func (r *markdownRenderer) separator(block mdBlock) string {
+	if r.target == MarkdownEmailHTML {
+		return "\n"
+	}
+	if block.kind == mdList && block.items[len(block.items)-1].raw {
+		return "\n"
+	}
+	return "\n\n"
+}
+
+func (r *markdownRenderer) block(block mdBlock) string {
+	switch block.kind {
+	case mdRaw:
+		return block.text
+	case mdRule:
+		if r.target == MarkdownEmailHTML {
+			return `<hr style="` + emailStyles["hr"] + `">`
+		}
+		return ruleText
+	case mdHeading:
+		text := r.inlines(parseInline(block.text))
+		switch r.target {
+		case MarkdownEmailHTML:
+			tag := "h" + strconv.Itoa(block.level)
+			if block.level > 3 {
+				tag = "h3"
+			}
+			return r.element(tag, text)
+		case MarkdownTelegramHTML:
+			return "<b>" + text + "</b>"
+		case MarkdownTelegramV2, MarkdownMrkdwn:
+			return "*" + text + "*"
+		default:
+			return text
+		}
+	case mdQuote:
+		text := r.inlines(parseInline(block.text))
+		switch r.target {
+		case MarkdownEmailHTML:
+			return r.element("blockquote", text)
+		case MarkdownTelegramHTML:
+			return "<blockquote>" + text + "</blockquote>"
+		case MarkdownTelegramV2:
+			return ">" + strings.ReplaceAll(text, "\n", "\n>")
+		case MarkdownMrkdwn:
+			return "> " + strings.ReplaceAll(text, "\n", "\n> ")
+		default:
+			return text
+		}
+	case mdCode:
+		return r.code(block)
+	case mdList:
+		return r.list(block)
+	default:
+		text := r.inlines(parseInline(block.text))
+		if r.target == MarkdownEmailHTML {
+			return r.element("p", text)
+		}
+		return text
+	}
+}
+
+func (r *markdownRenderer) code(block mdBlock) string {
+	text := r.codeText(parseActions(block.text))
+	switch r.target {
+	case MarkdownEmailHTML:
+		return r.element("pre", text)
+	case MarkdownTelegramHTML:
+		if block.language != "" {
+			return `<pre><code class="language-` + htmlAttrEscaper.Replace(block.language) + `">` + text + "</code></pre>"
+		}
+		return "<pre>" + text + "</pre>"
+	case MarkdownTelegramV2:
+		return "```" + block.language + "\n" + text + "\n```"
+	case MarkdownMrkdwn:
+		return "```\n" + text + "\n```"
+	default:
+		return text
+	}
+}
+
+func (r *markdownRenderer) list(block mdBlock) string {
+	var b strings.Builder
+	if r.target == MarkdownEmailHTML {
+		tag := "ul"
+		if block.ordered {
+			tag = "ol"
+		}
+		b.WriteString("<" + tag + r.style(tag))
+		if block.ordered && block.start != 1 {
+			b.WriteString(` start="` + strconv.Itoa(block.start) + `"`)
+		}
+		b.WriteString(">\n")
+		for _, item := range block.items {
+			if item.raw {
+				b.WriteString(item.text + "\n")
+				continue
+			}
+			b.WriteString(r.element("li", r.inlines(parseInline(item.text))) + "\n")
+		}
+		b.WriteString("</" + tag + ">")
+		return b.String()
+	}
+
+	number := block.start
+	for _, item := range block.items {
+		if item.raw {
+			b.WriteString(item.text)
+			continue
+		}
+
+		marker := "• "
+		if block.ordered {
+			marker = strconv.Itoa(number) + ". "
+			if r.target == MarkdownTelegramV2 {
+				marker = strconv.Itoa(number) + "\\. "
+			}
+			number++
+		}
+		text := strings.ReplaceAll(r.inlines(parseInline(item.text)), "\n", "\n   ")
+		b.WriteString(marker + text + "\n")
+	}
+
+	if block.items[len(block.items)-1].raw {
+		return b.String()
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (r *markdownRenderer) inlines(nodes []mdInline) string {
+	var b strings.Builder
+	for _, node := range nodes {
+		b.WriteString(r.inline(node))
+	}
+	return b.String()
+}
+
+func (r *markdownRenderer) inline(node mdInline) string {
+	switch node.kind {
+	case mdText:
+		return r.text(node.text)
+	case mdAction:
+		return r.action(node.text, false)
+	case mdLineBreak:
+		if r.target == MarkdownEmailHTML {
+			return "<br>\n"
+		}
+		return "\n"
+	case mdCodeSpan:
+		text := r.codeText(node.children)
+		switch r.target {
+		case MarkdownEmailHTML:
+			return r.element("code", text)
+		case MarkdownTelegramHTML:
+			return "<code>" + text + "</code>"
+		case MarkdownTelegramV2, MarkdownMrkdwn:
+			return "`" + text + "`"
+		default:
+			return text
+		}
+	case mdLink:
+		return r.link(node)
+	}
+
+	text := r.inlines(node.children)
+	tags := map[mdInlineKind][2]string{mdStrong: {"strong", "b"}, mdEmphasis: {"em", "i"}, mdStrike: {"s", "s"}}
+	marks := map[mdInlineKind]string{mdStrong: "*", mdEmphasis: "_", mdStrike: "~"}
+
+	switch r.target {
+	case MarkdownEmailHTML:
+		return "<" + tags[node.kind][0] + ">" + text + "</" + tags[node.kind][0] + ">"
+	case MarkdownTelegramHTML:
+		return "<" + tags[node.kind][1] + ">" + text + "</" + tags[node.kind][1] + ">"
+	case MarkdownTelegramV2, MarkdownMrkdwn:
+		return marks[node.kind] + text + marks[node.kind]
+	default:
+		return text
+	}
+}
+
+func (r *markdownRenderer) link(node mdInline) string {
+	text := r.inlines(node.children)
+
+	var url strings.Builder
+	for _, part := range parseActions(node.url) {
+		if part.kind == mdAction {
+			url.WriteString(r.action(part.text, true))
+			continue
+		}
+		switch {
+		case r.isHTML():
+			url.WriteString(htmlAttrEscaper.Replace(part.text))
+		case r.target == MarkdownTelegramV2:
+			url.WriteString(escapeMarkdownV2URL(part.text))
+		case r.target == MarkdownMrkdwn:
+			url.WriteString(escapeMrkdwn(part.text))
+		default:
+			url.WriteString(part.text)
+		}
+	}
+
+	switch r.target {
+	case MarkdownEmailHTML:
+		return `<a href="` + url.String() + `"` + r.style("a") + ">" + text + "</a>"
+	case MarkdownTelegramHTML:
+		return `<a href="` + url.String() + `">` + text + "</a>"
+	case MarkdownTelegramV2:
+		return "[" + text + "](" + url.String() + ")"
+	case MarkdownMrkdwn:
+		return "<" + url.String() + "|" + text + ">"
+	default:
+		if text == url.String() {
+			return text
+		}
+		return text + " (" + url.String() + ")"
+	}
+}
+
+func (r *markdownRenderer) codeText(nodes []mdInline) string {
+	var b strings.Builder
+	for _, node := range nodes {
+		if node.kind == mdAction {
+			if r.target == MarkdownTelegramV2 {
+				b.WriteString(escapeAction(node.text, "escapeMarkdownV2Code"))
+			} else {
+				b.WriteString(r.action(node.text, false))
+			}
+			continue
+		}
+
+		switch {
+		case r.isHTML():
+			b.WriteString(htmlTextEscaper.Replace(node.text))
+		case r.target == MarkdownTelegramV2:
+			b.WriteString(escapeMarkdownV2Code(node.text))
+		case r.target == MarkdownMrkdwn:
+			b.WriteString(escapeMrkdwn(node.text))
+		default:
+			b.WriteString(node.text)
+		}
+	}
+	return b.String()
+}
+
+func (r *markdownRenderer) text(text string) string {
+	switch {
+	case r.isHTML():
+		return htmlTextEscaper.Replace(text)
+	case r.target == MarkdownTelegramV2:
+		return escapeMarkdownV2(text)
+	case r.target == MarkdownMrkdwn:
+		return escapeMrkdwn(text)
+	default:
+		return text
+	}
+}
+
+// action keeps the action, the values printed by it are escaped for the markup of the target.
+func (r *markdownRenderer) action(action string, url bool) string {
+	switch {
+	case r.target == MarkdownTelegramV2 && url:
+		return escapeAction(action, "escapeMarkdownV2URL")
+	case r.target == MarkdownTelegramV2:
+		return escapeAction(action, "escapeMarkdownV2")
+	case r.target == MarkdownMrkdwn:
+		return escapeAction(action, "escapeMrkdwn")
+	default:
+		return action
+	}
+}
+
+func (r *markdownRenderer) element(tag, content string) string {
+	return "<" + tag + r.style(tag) + ">" + content + "</" + tag + ">"
+}
+
+func (r *markdownRenderer) style(tag string) string {
+	if r.target != MarkdownEmailHTML {
+		return ""
+	}
+	return ` style="` + emailStyles[tag] + `"`
+}
@@ -0,0 +1,198 @@
+package messagetemplate
+
+import (
+	"errors"
+	"github.com/keweegen/notification/internal/channel"
+	"github.com/keweegen/notification/internal/channel/outbound"
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/sqlboiler/v4/types"
+	"path/filepath"
+	"testing"
+)
+
+const shippedMarkdown = `## Order {{.orderId}}
+
+Shipped by **{{.carrier}}**, [track it]({{.url}})
+Thanks!
+
+{{range .items}}
+- {{.}}
+{{end}}`
+
+func TestCompileMarkdown(t *testing.T) {
+	cases := []struct {
+		name     string
+		target   MarkdownTarget
+		expected string
+	}{
+		{
+			name:   "email html",
+			target: MarkdownEmailHTML,
+			expected: `<h2 style="` + emailStyles["h2"] + `">Order {{.orderId}}</h2>` + "\n" +
+				`<p style="` + emailStyles["p"] + `">Shipped by <strong>{{.carrier}}</strong>, ` +
+				`<a href="{{.url}}" style="` + emailStyles["a"] + `">track it</a><br>` + "\nThanks!</p>\n" +
+				`<ul style="` + emailStyles["ul"] + `">` + "\n{{range .items}}\n" +
+				`<li style="` + emailStyles["li"] + `">{{.}}</li>` + "\n{{end}}\n</ul>",
+		},
+		{
+			name:   "telegram html",
+			target: MarkdownTelegramHTML,
+			expected: "<b>Order {{.orderId}}</b>\n\n" +
+				`Shipped by <b>{{.carrier}}</b>, <a href="{{.url}}">track it</a>` + "\nThanks!\n\n" +
+				"{{range .items}}• {{.}}\n{{end}}",
+		},
+		{
+			name:   "markdownV2",
+			target: MarkdownTelegramV2,
+			expected: "*Order {{.orderId | escapeMarkdownV2}}*\n\n" +
+				"Shipped by *{{.carrier | escapeMarkdownV2}}*, [track it]({{.url | escapeMarkdownV2URL}})\nThanks\\!\n\n" +
+				"{{range .items}}• {{. | escapeMarkdownV2}}\n{{end}}",
+		},
+		{
+			name:   "mrkdwn",
+			target: MarkdownMrkdwn,
+			expected: "*Order {{.orderId | escapeMrkdwn}}*\n\n" +
+				"Shipped by *{{.carrier | escapeMrkdwn}}*, <{{.url | escapeMrkdwn}}|track it>\nThanks!\n\n" +
+				"{{range .items}}• {{. | escapeMrkdwn}}\n{{end}}",
+		},
+		{
+			name:   "plain",
+			target: MarkdownPlain,
+			expected: "Order {{.orderId}}\n\n" +
+				"Shipped by {{.carrier}}, track it ({{.url}})\nThanks!\n\n" +
+				"{{range .items}}• {{.}}\n{{end}}",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, CompileMarkdown(shippedMarkdown, tc.target))
+		})
+	}
+}
+
+func TestCompileMarkdown_Inline(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		target   MarkdownTarget
+		expected string
+	}{
+		{name: "emphasis", source: "_new_ and *hot*", target: MarkdownTelegramHTML, expected: "<i>new</i> and <i>hot</i>"},
+		{name: "underscore in word", source: "order_id_x", target: MarkdownTelegramHTML, expected: "order_id_x"},
+		{name: "strikethrough", source: "~~old~~", target: MarkdownEmailHTML,
+			expected: `<p style="` + emailStyles["p"] + `"><s>old</s></p>`},
+		{name: "escape", source: `\*not bold\*`, target: MarkdownPlain, expected: "*not bold*"},
+		{name: "html text", source: "a < b & c", target: MarkdownTelegramHTML, expected: "a &lt; b &amp; c"},
+		{name: "markdownV2 text", source: "1.5 - 2 = (x)", target: MarkdownTelegramV2, expected: `1\.5 \- 2 \= \(x\)`},
+		{name: "code", source: "`{{.code}} a_b`", target: MarkdownTelegramV2,
+			expected: "`{{.code | escapeMarkdownV2Code}} a_b`"},
+		{name: "trimmed action", source: "{{- .name -}}", target: MarkdownTelegramV2,
+			expected: "{{- .name | escapeMarkdownV2 -}}"},
+		{name: "assignment", source: "{{$n := .name}}Hi {{$n}}", target: MarkdownMrkdwn,
+			expected: "{{$n := .name}}Hi {{$n | escapeMrkdwn}}"},
+		{name: "parenthesis action", source: "{{(}} {{ ( }}", target: MarkdownTelegramV2, expected: "{{(}} {{ ( }}"},
+		{name: "action with markup", source: `{{t "a*b"}} **{{.x}}**`, target: MarkdownTelegramHTML,
+			expected: `{{t "a*b"}} <b>{{.x}}</b>`},
+		{name: "ordered list", source: "3. three\n4. four", target: MarkdownTelegramV2, expected: "3\\. three\n4\\. four"},
+		{name: "quote", source: "> a\n> b", target: MarkdownTelegramV2, expected: ">a\n>b"},
+		{name: "code block", source: "```go\nx := <-ch\n```", target: MarkdownTelegramHTML,
+			expected: `<pre><code class="language-go">x := &lt;-ch</code></pre>`},
+		{name: "rule", source: "a\n\n---\n\nb", target: MarkdownPlain, expected: "a\n\n———\n\nb"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, CompileMarkdown(tc.source, tc.target))
+		})
+	}
+}
+
+func TestNewStoredTemplate_Markdown(t *testing.T) {
+	tmpl, err := NewStoredTemplate("Shipped", CategoryTransactional, false, Bodies{
+		DefaultBody: {
+			Body:    shippedMarkdown,
+			Targets: map[string]string{"telegram": "markdownV2"},
+			Subject: "Order {{.orderId}} shipped",
+		},
+	}, nil, nil)
+	assert.Nil(t, err)
+	params := types.JSON(`{"orderId": 7, "carrier": "<Fast_Post>", "url": "https://shop.kz/t?id=(7)", "items": ["A.1"]}`)
+
+	message, err := Render(tmpl, channel.Email, params, Helpers{})
+	assert.Nil(t, err)
+	assert.Equal(t, outbound.FormatHTML, message.Format)
+	assert.Equal(t, "Order 7 shipped", message.Subject)
+	assert.Contains(t, message.Text, "<!DOCTYPE html>")
+	assert.Contains(t, message.Text, "Shipped by <strong>&lt;Fast_Post&gt;</strong>")
+	assert.Contains(t, message.PlainText, "Shipped by <Fast_Post>, track it (https://shop.kz/t?id=(7))")
+
+	message, err = Render(tmpl, channel.Telegram, params, Helpers{})
+	assert.Nil(t, err)
+	assert.Equal(t, &outbound.Message{
+		Text: "*Order 7*\n\nShipped by *<Fast\\_Post\\>*, [track it](https://shop.kz/t?id=(7\\))\nThanks\\!\n\n" +
+			"• A\\.1\n",
+		Format: outbound.FormatMarkdownV2,
+	}, message)
+}
+
+func TestNewStoredTemplate_MarkdownOverride(t *testing.T) {
+	tmpl, err := NewStoredTemplate("Shipped", CategoryTransactional, false, Bodies{
+		DefaultBody: {Body: "Order **{{.orderId}}** shipped"},
+		"telegram":  {Body: "Order {{.orderId}} shipped", Format: "plain"},
+	}, nil, nil)
+	assert.Nil(t, err)
+	params := types.JSON(`{"orderId": 7}`)
+
+	text, err := Parse(tmpl, channel.Telegram, params, Helpers{})
+	assert.Nil(t, err)
+	assert.Equal(t, "Order 7 shipped", text)
+	assert.Equal(t, outbound.FormatPlain, tmpl.Format(channel.Mock))
+
+	text, err = Parse(tmpl, channel.Email, params, Helpers{})
+	assert.Nil(t, err)
+	assert.Contains(t, text, "Order <strong>7</strong> shipped")
+}
+
+func TestNewStoredTemplate_InvalidMarkdown(t *testing.T) {
+	cases := []struct {
+		name   string
+		bodies Bodies
+	}{
+		{name: "default not markdown", bodies: Bodies{DefaultBody: {Body: "<p>Order</p>", Format: "html"}}},
+		{name: "unknown target", bodies: Bodies{"email": {Body: "Order", Format: "markdown",
+			Targets: map[string]string{"email": "markdownV2"}}}},
+		{name: "invalid action", bodies: Bodies{DefaultBody: {Body: "Order {{.orderId"}}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewStoredTemplate("Shipped", CategoryTransactional, false, tc.bodies, nil, nil)
+			assert.True(t, errors.Is(err, InvalidBodyErr), err)
+		})
+	}
+}
+
+func TestDirectory_Load_Markdown(t *testing.T) {
+	path := t.TempDir()
+	writeTemplateFiles(t, filepath.Join(path, "shipped"), map[string]string{
+		ManifestFile: "id: 100\nchannels: [email, telegram]\nsubject: Order {{.orderId}} shipped\n" +
+			"targets:\n  telegram: plain\n",
+		"default.md": "Order **{{.orderId}}** shipped",
+	})
+
+	directory := NewDirectory(path)
+	assert.Nil(t, directory.Load())
+
+	tmpl, _ := directory.Get(100)
+	params := types.JSON(`{"orderId": 7}`)
+
+	message, err := Render(tmpl, channel.Email, params, Helpers{})
+	assert.Nil(t, err)
+	assert.Equal(t, "Order 7 shipped", message.Subject)
+	assert.Contains(t, message.Text, "Order <strong>7</strong> shipped")
+
+	message, err = Render(tmpl, channel.Telegram, params, Helpers{})
+	assert.Nil(t, err)
+	assert.Equal(t, &outbound.Message{Text: "Order 7 shipped", Format: outbound.FormatPlain}, message)
+}
@@ -35,6 +35,21 @@ func (m *MockTemplate) EXPECT() *MockTemplateMockRecorder {
 	return m.recorder
 }
 
+// Data mocks base method.
+func (m *MockTemplate) Data(params types.JSON) (interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Data", params)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Data indicates an expected call of Data.
+func (mr *MockTemplateMockRecorder) Data(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Data", reflect.TypeOf((*MockTemplate)(nil).Data), params)
+}
+
 // EmailTemplate mocks base method.
 func (m *MockTemplate) EmailTemplate() *template.Template {
 	m.ctrl.T.Helper()
@@ -63,20 +78,6 @@ func (mr *MockTemplateMockRecorder) Name() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockTemplate)(nil).Name))
 }
 
-// SetParams mocks base method.
-func (m *MockTemplate) SetParams(data types.JSON) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetParams", data)
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// SetParams indicates an expected call of SetParams.
-func (mr *MockTemplateMockRecorder) SetParams(data interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetParams", reflect.TypeOf((*MockTemplate)(nil).SetParams), data)
-}
-
 // TelegramTemplate mocks base method.
 func (m *MockTemplate) TelegramTemplate() *template.Template {
 	m.ctrl.T.Helper()
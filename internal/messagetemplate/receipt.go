@@ -1,22 +1,46 @@
 package messagetemplate
 
 import (
+    "fmt"
+    "github.com/keweegen/notification/internal/channel"
+    "github.com/keweegen/notification/internal/channel/outbound"
+    "github.com/keweegen/notification/internal/pdf"
     "github.com/volatiletech/sqlboiler/v4/types"
     "html/template"
+    texttemplate "text/template"
 )
 
-type ReceiptTemplate struct {
+type ReceiptTemplate struct{}
+
+// ReceiptParams are the params the receipt bodies are executed with,
+// the amounts are formatted in the locale of the message.
+type ReceiptParams struct {
     OrderID          int    `json:"orderId"`
-    CommissionAmount string `json:"commissionAmount"`
-    TotalAmount      string `json:"totalAmount"`
+    CommissionAmount Amount `json:"commissionAmount"`
+    TotalAmount      Amount `json:"totalAmount"`
+    // Currency is the ISO 4217 code of the amounts, the one of the formatted amounts
+    // such as "300,00 KZT" or KZT when empty.
+    Currency         string `json:"currency"`
 }
 
+const receiptCurrency = "KZT"
+
 func (r *ReceiptTemplate) Name() string {
     return Receipt.String()
 }
 
-func (r *ReceiptTemplate) SetParams(data types.JSON) error {
-    return data.Unmarshal(&r)
+func (r *ReceiptTemplate) Data(params types.JSON) (any, error) {
+    data := new(ReceiptParams)
+    if err := params.Unmarshal(data); err != nil {
+        return nil, err
+    }
+    if data.Currency == "" {
+        data.Currency = data.TotalAmount.Currency
+    }
+    if data.Currency == "" {
+        data.Currency = receiptCurrency
+    }
+    return data, nil
 }
 
 func (r *ReceiptTemplate) EmailTemplate() *template.Template {
@@ -27,22 +51,153 @@ func (r *ReceiptTemplate) TelegramTemplate() *template.Template {
     return receiptTelegramTemplate
 }
 
-var receiptEmailTemplate = template.Must(template.New("ns.email.receipt").Parse(`<h3>Чек</h3>
+func (r *ReceiptTemplate) EmailSubject() *texttemplate.Template {
+    return receiptEmailSubject
+}
+
+func (r *ReceiptTemplate) EmailPreheader() *texttemplate.Template {
+    return receiptEmailPreheader
+}
+
+func (r *ReceiptTemplate) EmailHeaders() map[string]*texttemplate.Template {
+    return nil
+}
+
+func (r *ReceiptTemplate) Catalog() *Catalog {
+    return receiptCatalog
+}
+
+func (r *ReceiptTemplate) ParamsSchema() types.JSON {
+    return receiptParamsSchema
+}
+
+func (r *ReceiptTemplate) ExampleParams() types.JSON {
+    return types.JSON(`{"orderId": 123, "commissionAmount": 15.5, "totalAmount": 1300, "currency": "KZT"}`)
+}
+
+// Colors of the receipt document besides the brand one.
+var (
+    receiptMuted = pdf.Color{R: 0x6b, G: 0x72, B: 0x80}
+    receiptRule  = pdf.Color{R: 0xd1, G: 0xd5, B: 0xdb}
+)
+
+// Document draws the PDF copy of the receipt: the brand header, the order with the issue date
+// and the amounts, the total being charged set in bold.
+func (r *ReceiptTemplate) Document(data any, h Helpers, o DocumentOptions) (*outbound.Media, error) {
+    params, ok := data.(*ReceiptParams)
+    if !ok {
+        return nil, fmt.Errorf("unexpected data %T", data)
+    }
+
+    commission, err := h.currency(params.Currency, params.CommissionAmount)
+    if err != nil {
+        return nil, err
+    }
+    total, err := h.currency(params.Currency, params.TotalAmount)
+    if err != nil {
+        return nil, err
+    }
+    issuedAt, err := h.date("datetime", o.IssuedAt)
+    if err != nil {
+        return nil, err
+    }
+
+    const margin = 48.0
+    right := pdf.PageWidth - margin
+
+    d := pdf.New()
+    d.Title = h.translate("subject", params.OrderID)
+    d.Author = o.Brand
+    d.CreatedAt = o.IssuedAt
+
+    p := d.AddPage()
+    p.Rect(0, 0, pdf.PageWidth, 96, o.Color)
+    p.Text(margin, 58, o.BoldFont, 22, pdf.White, o.Brand)
+    p.TextRight(right, 58, o.Font, 14, pdf.White, h.translate("title"))
+
+    p.Text(margin, 150, o.BoldFont, 18, pdf.Black, h.translate("document.order", params.OrderID))
+    p.Text(margin, 174, o.Font, 10, receiptMuted, h.translate("document.issued", issuedAt))
+
+    p.Line(margin, 204, right, 204, 0.5, receiptRule)
+    p.Text(margin, 228, o.Font, 11, pdf.Black, h.translate("document.commission"))
+    p.TextRight(right, 228, o.Font, 11, pdf.Black, commission)
+    p.Line(margin, 244, right, 244, 0.5, receiptRule)
+    p.Text(margin, 270, o.BoldFont, 13, pdf.Black, h.translate("document.total"))
+    p.TextRight(right, 270, o.BoldFont, 13, pdf.Black, total)
+    p.Line(margin, 286, right, 286, 1, o.Color)
+
+    p.Text(margin, 326, o.Font, 11, pdf.Black, h.translate("thanks"))
+    p.Text(margin, pdf.PageHeight-margin, o.Font, 8, receiptMuted, h.translate("document.footer"))
+
+    return pdfMedia(fmt.Sprintf("receipt-%d.pdf", params.OrderID), d)
+}
+
+var receiptParamsSchema = types.JSON(`{
+    "type": "object",
+    "required": ["orderId", "commissionAmount", "totalAmount"],
+    "properties": {
+        "orderId": {"type": "integer", "minimum": 1},
+        "commissionAmount": {"type": ["number", "string"], "minimum": 0, "pattern": "^([A-Z]{3}\\W?)?[0-9]"},
+        "totalAmount": {"type": ["number", "string"], "minimum": 0, "pattern": "^([A-Z]{3}\\W?)?[0-9]"},
+        "currency": {"type": "string", "pattern": "^[A-Z]{3}$"}
+    }
+}`)
+
+// receiptCatalog is the Russian source text of the receipt with its translations.
+var receiptCatalog = &Catalog{
+    Default: "ru",
+    Messages: map[string]map[string]Message{
+        "ru": {
+            "title":      {Other: "Чек"},
+            "subject":    {Other: "Чек по заказу %d"},
+            "preheader":  {Other: "Заказ %d успешно оплачен, сумма к списанию %s"},
+            "paid":       {Other: "Заказ <b>%d</b> успешно оплачен"},
+            "commission": {Other: "Комиссия: %s"},
+            "total":      {Other: "Сумма к списанию: %s"},
+            "thanks":     {Other: "Спасибо за покупку!"},
+
+            "document.order":      {Other: "Заказ № %d"},
+            "document.issued":     {Other: "Дата: %s"},
+            "document.commission": {Other: "Комиссия"},
+            "document.total":      {Other: "Сумма к списанию"},
+            "document.footer":     {Other: "Документ сформирован автоматически и не требует подписи."},
+        },
+        "en": {
+            "title":      {Other: "Receipt"},
+            "subject":    {Other: "Receipt for order %d"},
+            "preheader":  {Other: "Order %d has been paid, amount charged %s"},
+            "paid":       {Other: "Order <b>%d</b> has been paid"},
+            "commission": {Other: "Fee: %s"},
+            "total":      {Other: "Amount charged: %s"},
+            "thanks":     {Other: "Thank you for your purchase!"},
+
+            "document.order":      {Other: "Order #%d"},
+            "document.issued":     {Other: "Date: %s"},
+            "document.commission": {Other: "Fee"},
+            "document.total":      {Other: "Amount charged"},
+            "document.footer":     {Other: "This document was generated automatically and requires no signature."},
+        },
+    },
+}
+
+var receiptEmailSubject = texttemplate.Must(texttemplate.New("ns.email.receipt.subject").Funcs(TextFuncs).
+    Parse(`{{t "subject" .OrderID}}`))
 
-<p>Заказ <b>{{.OrderID}}</b> успешно оплачен</p>
+var receiptEmailPreheader = texttemplate.Must(texttemplate.New("ns.email.receipt.preheader").Funcs(TextFuncs).
+    Parse(`{{t "preheader" .OrderID (currency .Currency .TotalAmount)}}`))
 
-<p>
-    Комиссия: {{.CommissionAmount}} <br/>
-    Сумма к списанию: {{.TotalAmount}}
-</p>
+// receiptMarkdown is the body of the receipt compiled for every channel.
+const receiptMarkdown = `### {{t "title"}}
 
-<p>Спасибо за покупку!</p>`))
+{{t "paid" .OrderID}}
 
-var receiptTelegramTemplate = template.Must(template.New("ns.telegram.receipt").Parse(`<b>Чек</b>
+{{t "commission" (currency .Currency .CommissionAmount)}}
+{{t "total" (currency .Currency .TotalAmount)}}
 
-Заказ <code>{{.OrderID}}</code> успешно оплачен
+{{t "thanks"}}`
 
-Комиссия: {{.CommissionAmount}}
-Сумма к списанию: {{.TotalAmount}}
+var receiptEmailTemplate = template.Must(DefaultLayouts.parseBody(channel.Email, "ns.email.receipt",
+    withLayout(CompileMarkdown(receiptMarkdown, MarkdownEmailHTML))))
 
-Спасибо за покупку`))
+var receiptTelegramTemplate = template.Must(template.New("ns.telegram.receipt").Funcs(Funcs).
+    Parse(CompileMarkdown(receiptMarkdown, MarkdownTelegramHTML)))
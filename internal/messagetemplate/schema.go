@@ -0,0 +1,418 @@
+package messagetemplate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/volatiletech/sqlboiler/v4/types"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	InvalidSchemaErr = errors.New("template: invalid params schema")
+	InvalidParamsErr = errors.New("template: invalid params")
+)
+
+// SchemaTemplate is implemented by templates declaring a JSON Schema for their params.
+type SchemaTemplate interface {
+	ParamsSchema() types.JSON
+}
+
+// GetParamsSchema returns the params schema of the template, nil when the template has none.
+func GetParamsSchema(t Template) types.JSON {
+	if st, ok := t.(SchemaTemplate); ok {
+		return st.ParamsSchema()
+	}
+	return nil
+}
+
+// FieldError is a params value not matching the schema, the field is the path of the value,
+// e.g. items[0].price, and is empty for the params themselves.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ParamsError lists the params values not matching the params schema of the template.
+type ParamsError struct {
+	Errors []FieldError
+}
+
+func (e *ParamsError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		if fe.Field == "" {
+			messages[i] = fe.Message
+		} else {
+			messages[i] = fe.Field + " " + fe.Message
+		}
+	}
+	return fmt.Sprintf("%s: %s", InvalidParamsErr, strings.Join(messages, "; "))
+}
+
+func (e *ParamsError) Unwrap() error {
+	return InvalidParamsErr
+}
+
+// Schema is a JSON Schema of the template params. The keywords describing the shape of the values are
+// supported: type, enum, const, properties, required, additionalProperties, items, minItems, maxItems,
+// minLength, maxLength, pattern, minimum, maximum, exclusiveMinimum and exclusiveMaximum.
+// The other keywords, format included, are annotations and are ignored.
+type Schema struct {
+	Type                 schemaTypes        `json:"type"`
+	Enum                 []any              `json:"enum"`
+	Const                json.RawMessage    `json:"const"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+	Items                *Schema            `json:"items"`
+	MinItems             *int               `json:"minItems"`
+	MaxItems             *int               `json:"maxItems"`
+	MinLength            *int               `json:"minLength"`
+	MaxLength            *int               `json:"maxLength"`
+	Pattern              string             `json:"pattern"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum"`
+
+	constValue    any
+	hasConst      bool
+	noAdditional  bool
+	additional    *Schema
+	pattern       *regexp.Regexp
+	propertyNames []string
+}
+
+// schemaTypes is the type keyword, either a type name or a list of them.
+type schemaTypes []string
+
+var schemaTypeNames = map[string]bool{
+	"null": true, "boolean": true, "object": true, "array": true, "number": true, "integer": true, "string": true,
+}
+
+func (t *schemaTypes) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*t = schemaTypes{name}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+// ParseSchema parses and checks the params schema, the schema is nil when the data is empty.
+func ParseSchema(data types.JSON) (*Schema, error) {
+	if len(bytes.TrimSpace(data)) == 0 || string(bytes.TrimSpace(data)) == "null" {
+		return nil, nil
+	}
+
+	schema := new(Schema)
+	if err := decodeJSON(data, schema); err != nil {
+		return nil, fmt.Errorf("%w: %s", InvalidSchemaErr, err)
+	}
+	if err := schema.compile(""); err != nil {
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+// ValidateParams validates the params against the schema, it returns a *ParamsError wrapping
+// InvalidParamsErr when they do not match. Empty params are validated as an empty object.
+func ValidateParams(schema types.JSON, params types.JSON) error {
+	s, err := ParseSchema(schema)
+	if err != nil {
+		return err
+	}
+	return s.Validate(params)
+}
+
+// Validate validates the params against the schema, a nil schema accepts any params.
+func (s *Schema) Validate(params types.JSON) error {
+	if s == nil {
+		return nil
+	}
+
+	var value any = map[string]any{}
+	if len(bytes.TrimSpace(params)) > 0 {
+		if err := decodeJSON(params, &value); err != nil {
+			return &ParamsError{Errors: []FieldError{{Message: "must be valid JSON"}}}
+		}
+	}
+
+	var errs []FieldError
+	s.validate("", value, &errs)
+	if len(errs) > 0 {
+		return &ParamsError{Errors: errs}
+	}
+	return nil
+}
+
+func (s *Schema) compile(path string) error {
+	for _, name := range s.Type {
+		if !schemaTypeNames[name] {
+			return fmt.Errorf("%w: %s: unknown type '%s'", InvalidSchemaErr, schemaPath(path), name)
+		}
+	}
+
+	if len(s.Const) > 0 {
+		if err := decodeJSON(s.Const, &s.constValue); err != nil {
+			return fmt.Errorf("%w: %s: const: %s", InvalidSchemaErr, schemaPath(path), err)
+		}
+		s.hasConst = true
+	}
+
+	if s.Pattern != "" {
+		var err error
+		if s.pattern, err = regexp.Compile(s.Pattern); err != nil {
+			return fmt.Errorf("%w: %s: pattern: %s", InvalidSchemaErr, schemaPath(path), err)
+		}
+	}
+
+	switch additional := strings.TrimSpace(string(s.AdditionalProperties)); additional {
+	case "", "true":
+	case "false":
+		s.noAdditional = true
+	default:
+		s.additional = new(Schema)
+		if err := decodeJSON(s.AdditionalProperties, s.additional); err != nil {
+			return fmt.Errorf("%w: %s: additionalProperties: %s", InvalidSchemaErr, schemaPath(path), err)
+		}
+		if err := s.additional.compile(path + ".*"); err != nil {
+			return err
+		}
+	}
+
+	s.propertyNames = make([]string, 0, len(s.Properties))
+	for name, property := range s.Properties {
+		if property == nil {
+			return fmt.Errorf("%w: %s: schema must be an object", InvalidSchemaErr, schemaPath(fieldPath(path, name)))
+		}
+		if err := property.compile(fieldPath(path, name)); err != nil {
+			return err
+		}
+		s.propertyNames = append(s.propertyNames, name)
+	}
+	sort.Strings(s.propertyNames)
+
+	if s.Items != nil {
+		return s.Items.compile(path + "[]")
+	}
+	return nil
+}
+
+func (s *Schema) validate(path string, value any, errs *[]FieldError) {
+	addError := func(format string, args ...any) {
+		*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(s.Type) > 0 && !s.matchesType(value) {
+		addError("must be of type %s", strings.Join(s.Type, " or "))
+		return
+	}
+	if len(s.Enum) > 0 && !containsJSON(s.Enum, value) {
+		addError("must be one of %s", marshalJSON(s.Enum))
+	}
+	if s.hasConst && !equalJSON(s.constValue, value) {
+		addError("must be equal to %s", marshalJSON(s.constValue))
+	}
+
+	switch v := value.(type) {
+	case string:
+		length := utf8.RuneCountInString(v)
+		if s.MinLength != nil && length < *s.MinLength {
+			addError("must be at least %d characters long", *s.MinLength)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			addError("must be at most %d characters long", *s.MaxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			addError("must match the pattern %s", s.Pattern)
+		}
+	case json.Number:
+		n, _ := v.Float64()
+		if s.Minimum != nil && n < *s.Minimum {
+			addError("must be greater than or equal to %s", formatNumber(*s.Minimum))
+		}
+		if s.Maximum != nil && n > *s.Maximum {
+			addError("must be less than or equal to %s", formatNumber(*s.Maximum))
+		}
+		if s.ExclusiveMinimum != nil && n <= *s.ExclusiveMinimum {
+			addError("must be greater than %s", formatNumber(*s.ExclusiveMinimum))
+		}
+		if s.ExclusiveMaximum != nil && n >= *s.ExclusiveMaximum {
+			addError("must be less than %s", formatNumber(*s.ExclusiveMaximum))
+		}
+	case []any:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			addError("must have at least %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			addError("must have at most %d items", *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.validate(path+"["+strconv.Itoa(i)+"]", item, errs)
+			}
+		}
+	case map[string]any:
+		s.validateObject(path, v, errs)
+	}
+}
+
+func (s *Schema) validateObject(path string, object map[string]any, errs *[]FieldError) {
+	for _, name := range s.Required {
+		if _, ok := object[name]; !ok {
+			*errs = append(*errs, FieldError{Field: fieldPath(path, name), Message: "is required"})
+		}
+	}
+
+	for _, name := range s.propertyNames {
+		if value, ok := object[name]; ok {
+			s.Properties[name].validate(fieldPath(path, name), value, errs)
+		}
+	}
+
+	if !s.noAdditional && s.additional == nil {
+		return
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		if _, ok := s.Properties[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if s.noAdditional {
+			*errs = append(*errs, FieldError{Field: fieldPath(path, name), Message: "is not allowed"})
+			continue
+		}
+		s.additional.validate(fieldPath(path, name), object[name], errs)
+	}
+}
+
+func (s *Schema) matchesType(value any) bool {
+	for _, name := range s.Type {
+		switch v := value.(type) {
+		case nil:
+			if name == "null" {
+				return true
+			}
+		case bool:
+			if name == "boolean" {
+				return true
+			}
+		case string:
+			if name == "string" {
+				return true
+			}
+		case []any:
+			if name == "array" {
+				return true
+			}
+		case map[string]any:
+			if name == "object" {
+				return true
+			}
+		case json.Number:
+			if name == "number" || (name == "integer" && isInteger(v)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isInteger(n json.Number) bool {
+	if _, err := n.Int64(); err == nil {
+		return true
+	}
+	f, err := n.Float64()
+	return err == nil && f == math.Trunc(f)
+}
+
+func containsJSON(values []any, value any) bool {
+	for _, v := range values {
+		if equalJSON(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// equalJSON compares decoded JSON values, numbers are equal by value, e.g. 1 and 1.0.
+func equalJSON(a, b any) bool {
+	switch a := a.(type) {
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		x, errA := a.Float64()
+		y, errB := b.Float64()
+		return errA == nil && errB == nil && x == y
+	case []any:
+		b, ok := b.([]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equalJSON(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for key, value := range a {
+			if other, ok := b[key]; !ok || !equalJSON(value, other) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
+
+// decodeJSON decodes the numbers as json.Number, so that integers keep their precision.
+func decodeJSON(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+func marshalJSON(v any) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+func fieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func schemaPath(path string) string {
+	if path == "" {
+		return "schema"
+	}
+	return path
+}
@@ -0,0 +1,135 @@
+package messagetemplate
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/sqlboiler/v4/types"
+	"testing"
+)
+
+func TestParseSchema(t *testing.T) {
+	cases := []struct {
+		name   string
+		schema string
+		valid  bool
+	}{
+		{name: "empty", schema: ``, valid: true},
+		{name: "object", schema: `{"type": "object", "properties": {"id": {"type": ["integer", "null"]}}}`, valid: true},
+		{name: "not an object", schema: `[]`},
+		{name: "unknown type", schema: `{"type": "decimal"}`},
+		{name: "unknown property type", schema: `{"properties": {"id": {"type": "int"}}}`},
+		{name: "invalid pattern", schema: `{"pattern": "("}`},
+		{name: "invalid additional properties", schema: `{"additionalProperties": 1}`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseSchema(types.JSON(tc.schema))
+			if tc.valid {
+				assert.Nil(t, err)
+			} else {
+				assert.True(t, errors.Is(err, InvalidSchemaErr), err)
+			}
+		})
+	}
+}
+
+func TestSchema_Validate(t *testing.T) {
+	schema, err := ParseSchema(types.JSON(`{
+		"type": "object",
+		"required": ["orderId", "email"],
+		"additionalProperties": false,
+		"properties": {
+			"orderId": {"type": "integer", "minimum": 1},
+			"email": {"type": "string", "pattern": "^[^@]+@[^@]+$"},
+			"carrier": {"enum": ["dhl", "ups"]},
+			"items": {
+				"type": "array",
+				"minItems": 1,
+				"items": {
+					"type": "object",
+					"required": ["name"],
+					"properties": {"name": {"type": "string", "maxLength": 5}, "price": {"exclusiveMinimum": 0}}
+				}
+			}
+		}
+	}`))
+	assert.Nil(t, err)
+
+	cases := []struct {
+		name           string
+		params         string
+		expectedErrors []FieldError
+	}{
+		{
+			name:   "valid",
+			params: `{"orderId": 123, "email": "a@b.kz", "carrier": "dhl", "items": [{"name": "Book", "price": 1.5}]}`,
+		},
+		{
+			name:   "integer as float",
+			params: `{"orderId": 123.0, "email": "a@b.kz"}`,
+		},
+		{
+			name:   "empty params",
+			params: ``,
+			expectedErrors: []FieldError{
+				{Field: "orderId", Message: "is required"},
+				{Field: "email", Message: "is required"},
+			},
+		},
+		{
+			name:           "not an object",
+			params:         `[1]`,
+			expectedErrors: []FieldError{{Message: "must be of type object"}},
+		},
+		{
+			name:   "invalid values",
+			params: `{"orderId": 1.5, "email": "ab.kz", "carrier": "fedex", "coupon": "X"}`,
+			expectedErrors: []FieldError{
+				{Field: "carrier", Message: `must be one of ["dhl","ups"]`},
+				{Field: "email", Message: "must match the pattern ^[^@]+@[^@]+$"},
+				{Field: "orderId", Message: "must be of type integer"},
+				{Field: "coupon", Message: "is not allowed"},
+			},
+		},
+		{
+			name:   "invalid items",
+			params: `{"orderId": 0, "email": "a@b.kz", "items": [{"name": "Notebook", "price": 0}, {}]}`,
+			expectedErrors: []FieldError{
+				{Field: "items[0].name", Message: "must be at most 5 characters long"},
+				{Field: "items[0].price", Message: "must be greater than 0"},
+				{Field: "items[1].name", Message: "is required"},
+				{Field: "orderId", Message: "must be greater than or equal to 1"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := schema.Validate(types.JSON(tc.params))
+			if tc.expectedErrors == nil {
+				assert.Nil(t, err)
+				return
+			}
+
+			assert.True(t, errors.Is(err, InvalidParamsErr), err)
+			var paramsErr *ParamsError
+			if assert.True(t, errors.As(err, &paramsErr)) {
+				assert.Equal(t, tc.expectedErrors, paramsErr.Errors)
+			}
+		})
+	}
+}
+
+func TestReceiptTemplate_ParamsSchema(t *testing.T) {
+	schema := GetParamsSchema(new(ReceiptTemplate))
+
+	assert.Nil(t, ValidateParams(schema, types.JSON(`{"orderId": 123, "commissionAmount": 2, "totalAmount": 1002}`)))
+	assert.Nil(t, ValidateParams(schema, types.JSON(`{"orderId": 123, "commissionAmount": "2,00 KZT", "totalAmount": "1002 KZT"}`)))
+	assert.EqualError(t, ValidateParams(schema, types.JSON(`{"orderId": "123", "totalAmount": "about 1002"}`)),
+		"template: invalid params: commissionAmount is required; orderId must be of type integer; "+
+			"totalAmount must match the pattern ^([A-Z]{3}\\W?)?[0-9]")
+	assert.EqualError(t, ValidateParams(schema, types.JSON(
+		`{"orderId": 123, "commissionAmount": -2, "totalAmount": 1002, "currency": "kzt"}`)),
+		"template: invalid params: commissionAmount must be greater than or equal to 0; currency must match the pattern ^[A-Z]{3}$")
+}
@@ -0,0 +1,296 @@
+package messagetemplate
+
+import (
+	"errors"
+	"fmt"
+	"github.com/keweegen/notification/internal/channel"
+	"github.com/keweegen/notification/internal/channel/outbound"
+	"github.com/volatiletech/sqlboiler/v4/types"
+	"html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+var InvalidBodyErr = errors.New("template: invalid body")
+
+// Body is the content of a stored template for one channel.
+// Subject, Preheader, Headers and Text are used by email only.
+type Body struct {
+	Body string `json:"body"`
+	// Format is html, markdownV2, plain or markdown, html by default. A markdown body is compiled
+	// with CompileMarkdown into the target format of its channel.
+	Format string `json:"format,omitempty"`
+	// Targets are the formats markdown bodies are compiled into by channel name, html by default.
+	// Email takes html or plain, Telegram html, markdownV2 or plain.
+	Targets   map[string]string `json:"targets,omitempty"`
+	Subject   string            `json:"subject,omitempty"`
+	Preheader string            `json:"preheader,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Text      string            `json:"text,omitempty"`
+}
+
+// Bodies are the bodies of a stored template by channel name. The markdown body under DefaultBody
+// is compiled for every channel without a body of its own.
+type Bodies map[string]Body
+
+// DefaultBody is the key of the body shared by the channels.
+const DefaultBody = "default"
+
+// markdownFormat is the format of the bodies compiled from Markdown.
+const markdownFormat = "markdown"
+
+var formats = map[string]outbound.Format{
+	"":           outbound.FormatHTML,
+	"html":       outbound.FormatHTML,
+	"markdownv2": outbound.FormatMarkdownV2,
+	"plain":      outbound.FormatPlain,
+}
+
+// markdownTargets are the targets Markdown is compiled into by channel and format, Mock shares
+// the Telegram ones.
+var markdownTargets = map[channel.Channel]map[string]MarkdownTarget{
+	channel.Email: {
+		"":      MarkdownEmailHTML,
+		"html":  MarkdownEmailHTML,
+		"plain": MarkdownPlain,
+	},
+	channel.Telegram: {
+		"":           MarkdownTelegramHTML,
+		"html":       MarkdownTelegramHTML,
+		"markdownv2": MarkdownTelegramV2,
+		"plain":      MarkdownPlain,
+	},
+}
+
+// StoredTemplate is a template version managed through the API, its bodies are executed with the params
+// as they are sent, e.g. {{.orderId}}.
+type StoredTemplate struct {
+	name     string
+	category Category
+	tracking bool
+	catalog  *Catalog
+
+	email     *template.Template
+	telegram  *template.Template
+	formats   map[channel.Channel]outbound.Format
+	subject   *texttemplate.Template
+	preheader *texttemplate.Template
+	headers   map[string]*texttemplate.Template
+	text      *texttemplate.Template
+}
+
+// NewStoredTemplate compiles the bodies with the layouts of their channels, a body failing to parse
+// is reported with InvalidBodyErr. The catalog may be nil, the layouts are DefaultLayouts when nil.
+func NewStoredTemplate(
+	name string,
+	category Category,
+	tracking bool,
+	bodies Bodies,
+	catalog *Catalog,
+	layouts Layouts,
+) (*StoredTemplate, error) {
+	if len(bodies) == 0 {
+		return nil, fmt.Errorf("%w: no bodies", InvalidBodyErr)
+	}
+	if err := catalog.Validate(); err != nil {
+		return nil, err
+	}
+
+	t := &StoredTemplate{
+		name:     name,
+		category: category,
+		tracking: tracking,
+		catalog:  catalog,
+		formats:  make(map[channel.Channel]outbound.Format, len(bodies)),
+	}
+
+	bodies, err := withDefaultBody(bodies)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, body := range bodies {
+		ch, ok := channel.GetChannelTypeFromString(key)
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown channel '%s'", InvalidBodyErr, key)
+		}
+		if err := t.compile(ch, body, layouts); err != nil {
+			return nil, fmt.Errorf("%w: %s: %s", InvalidBodyErr, key, err)
+		}
+	}
+
+	return t, nil
+}
+
+func (t *StoredTemplate) compile(ch channel.Channel, body Body, layouts Layouts) error {
+	if strings.TrimSpace(body.Body) == "" {
+		return errors.New("empty body")
+	}
+	if strings.EqualFold(body.Format, markdownFormat) {
+		var err error
+		if body, err = compileMarkdownBody(ch, body); err != nil {
+			return err
+		}
+	}
+
+	format, ok := formats[strings.ToLower(body.Format)]
+	if !ok {
+		return fmt.Errorf("unknown format '%s'", body.Format)
+	}
+	t.formats[ch] = format
+
+	name := fmt.Sprintf("ns.%s.%s", strings.ToLower(ch.String()), t.name)
+	tmpl, err := layouts.parseBody(ch, name, body.Body)
+	if err != nil {
+		return err
+	}
+
+	if ch != channel.Email {
+		t.telegram = tmpl
+		return nil
+	}
+	t.email = tmpl
+
+	if t.subject, err = parseText(name+".subject", body.Subject); err != nil {
+		return err
+	}
+	if t.preheader, err = parseText(name+".preheader", body.Preheader); err != nil {
+		return err
+	}
+	if t.text, err = parseText(name+".text", body.Text); err != nil {
+		return err
+	}
+
+	t.headers = make(map[string]*texttemplate.Template, len(body.Headers))
+	for header, value := range body.Headers {
+		if t.headers[header], err = parseText(name+".header."+header, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *StoredTemplate) Name() string {
+	return t.name
+}
+
+// Data returns the params as they are sent, e.g. {{.orderId}}.
+func (t *StoredTemplate) Data(params types.JSON) (any, error) {
+	data := make(map[string]any)
+	if len(params) == 0 {
+		return data, nil
+	}
+	if err := params.Unmarshal(&data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (t *StoredTemplate) EmailTemplate() *template.Template {
+	return t.email
+}
+
+func (t *StoredTemplate) TelegramTemplate() *template.Template {
+	return t.telegram
+}
+
+func (t *StoredTemplate) EmailSubject() *texttemplate.Template {
+	return t.subject
+}
+
+func (t *StoredTemplate) EmailPreheader() *texttemplate.Template {
+	return t.preheader
+}
+
+func (t *StoredTemplate) EmailHeaders() map[string]*texttemplate.Template {
+	return t.headers
+}
+
+// EmailTextTemplate returns nil without a plain text body, the text is generated from the HTML then.
+func (t *StoredTemplate) EmailTextTemplate() *texttemplate.Template {
+	return t.text
+}
+
+func (t *StoredTemplate) Format(ch channel.Channel) outbound.Format {
+	if ch == channel.Mock {
+		ch = channel.Telegram
+	}
+	return t.formats[ch]
+}
+
+func (t *StoredTemplate) Category() Category {
+	return t.category
+}
+
+func (t *StoredTemplate) Tracking() bool {
+	return t.tracking
+}
+
+func (t *StoredTemplate) Catalog() *Catalog {
+	return t.catalog
+}
+
+// withDefaultBody returns the bodies with the default body given to the channels without one.
+func withDefaultBody(bodies Bodies) (Bodies, error) {
+	body, ok := bodies[DefaultBody]
+	if !ok {
+		return bodies, nil
+	}
+	if body.Format == "" {
+		body.Format = markdownFormat
+	}
+	if !strings.EqualFold(body.Format, markdownFormat) {
+		return nil, fmt.Errorf("%w: %s: format must be markdown", InvalidBodyErr, DefaultBody)
+	}
+
+	expanded := make(Bodies, len(channel.Channels))
+	for key, b := range bodies {
+		if key != DefaultBody {
+			expanded[key] = b
+		}
+	}
+	for _, ch := range channel.Channels {
+		key := strings.ToLower(ch.String())
+		if _, ok = expanded[key]; !ok && ch != channel.Mock {
+			expanded[key] = body
+		}
+	}
+
+	return expanded, nil
+}
+
+// compileMarkdownBody compiles the Markdown body into the target format of the channel. The email HTML
+// is the content of the layout, the plain text alternative is compiled from the same source unless set.
+func compileMarkdownBody(ch channel.Channel, body Body) (Body, error) {
+	key := strings.ToLower(ch.String())
+	if ch == channel.Mock {
+		ch = channel.Telegram
+	}
+
+	format := body.Targets[key]
+	target, ok := markdownTargets[ch][strings.ToLower(format)]
+	if !ok {
+		return body, fmt.Errorf("unknown markdown target '%s'", format)
+	}
+
+	source := body.Body
+	body.Format, body.Targets = format, nil
+	body.Body = CompileMarkdown(source, target)
+	if target != MarkdownEmailHTML {
+		return body, nil
+	}
+
+	body.Body = withLayout(body.Body)
+	if body.Text == "" {
+		body.Text = CompileMarkdown(source, MarkdownPlain)
+	}
+	return body, nil
+}
+
+func parseText(name, text string) (*texttemplate.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+	return texttemplate.New(name).Funcs(TextFuncs).Parse(text)
+}
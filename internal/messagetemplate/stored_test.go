@@ -0,0 +1,60 @@
+package messagetemplate
+
+import (
+	"errors"
+	"github.com/keweegen/notification/internal/channel"
+	"github.com/keweegen/notification/internal/channel/outbound"
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/sqlboiler/v4/types"
+	"testing"
+)
+
+func TestNewStoredTemplate(t *testing.T) {
+	cases := []struct {
+		name   string
+		bodies Bodies
+	}{
+		{name: "no bodies"},
+		{name: "unknown channel", bodies: Bodies{"fax": {Body: "Order"}}},
+		{name: "empty body", bodies: Bodies{"email": {Body: " "}}},
+		{name: "unknown format", bodies: Bodies{"telegram": {Body: "Order", Format: "rtf"}}},
+		{name: "invalid body", bodies: Bodies{"email": {Body: "{{.orderId"}}},
+		{name: "invalid subject", bodies: Bodies{"email": {Body: "Order", Subject: "{{end}}"}}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewStoredTemplate("Shipped", CategoryTransactional, false, tc.bodies, nil, nil)
+			assert.True(t, errors.Is(err, InvalidBodyErr), err)
+		})
+	}
+}
+
+func TestStoredTemplate_Render(t *testing.T) {
+	tmpl, err := NewStoredTemplate("Shipped", CategoryMarketing, true, Bodies{
+		"email": {
+			Body:    `<p>Order #{{.orderId}} by {{.carrier}}</p>`,
+			Subject: "Order #{{.orderId}} shipped",
+			Headers: map[string]string{"X-Order": "{{.orderId}}"},
+		},
+		"telegram": {Body: `Order *{{.orderId}}*`, Format: "markdownV2"},
+	}, nil, nil)
+	assert.Nil(t, err)
+	params := types.JSON(`{"orderId":123,"carrier":"<Fast>"}`)
+
+	assert.Equal(t, CategoryMarketing, GetCategory(tmpl))
+	assert.True(t, IsTracked(tmpl))
+
+	message, err := Render(tmpl, channel.Email, params, Helpers{})
+	assert.Nil(t, err)
+	assert.Equal(t, &outbound.Message{
+		Text:    `<p>Order #123 by &lt;Fast&gt;</p>`,
+		Format:  outbound.FormatHTML,
+		Subject: "Order #123 shipped",
+		Headers: map[string]string{"X-Order": "123"},
+	}, message)
+
+	message, err = Render(tmpl, channel.Telegram, params, Helpers{})
+	assert.Nil(t, err)
+	assert.Equal(t, &outbound.Message{Text: `Order *123*`, Format: outbound.FormatMarkdownV2}, message)
+}
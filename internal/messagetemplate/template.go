@@ -5,20 +5,66 @@ import (
 	"errors"
 	"fmt"
 	"github.com/keweegen/notification/internal/channel"
+	"github.com/keweegen/notification/internal/channel/outbound"
 	"github.com/volatiletech/sqlboiler/v4/types"
+	"html"
 	"html/template"
+	"strings"
+	texttemplate "text/template"
 )
 
 var TemplateNotFoundErr = errors.New("template not found")
 
+// Template is a message template. Templates keep no state between renders, one template value
+// renders the messages of all the users concurrently: the params are decoded on every render.
+//
 //go:generate mockgen -source=template.go -destination=./mock/template.go
 type Template interface {
 	Name() string
-	SetParams(data types.JSON) error
+	// Data decodes the params into a new value the bodies are executed with.
+	Data(params types.JSON) (any, error)
 	EmailTemplate() *template.Template
 	TelegramTemplate() *template.Template
 }
 
+// MediaTemplate is implemented by templates attaching photos or documents to the message.
+// Media are declared by URL or generated from the data of the params at render time.
+type MediaTemplate interface {
+	Media(ch channel.Channel, data any) ([]outbound.Media, error)
+}
+
+// ActionTemplate is implemented by templates attaching buttons to the message.
+type ActionTemplate interface {
+	Actions(ch channel.Channel, data any) ([]outbound.Action, error)
+}
+
+// EmailHeadersTemplate is implemented by templates defining the email subject, the preheader shown
+// by mail clients next to the subject and extra headers such as Reply-To, List-Id or X-headers.
+// They are executed with the same params as the body, the preheader may be nil.
+type EmailHeadersTemplate interface {
+	EmailSubject() *texttemplate.Template
+	EmailPreheader() *texttemplate.Template
+	EmailHeaders() map[string]*texttemplate.Template
+}
+
+// PlainTextTemplate is implemented by templates providing the plain text alternative of the email,
+// it is generated from the HTML otherwise.
+type PlainTextTemplate interface {
+	EmailTextTemplate() *texttemplate.Template
+}
+
+// FormatTemplate is implemented by templates whose channel output is not HTML,
+// templates not implementing it are rendered as HTML.
+type FormatTemplate interface {
+	Format(ch channel.Channel) outbound.Format
+}
+
+// TrackingTemplate is implemented by templates switching the open and click tracking of their emails,
+// templates not implementing it are not tracked.
+type TrackingTemplate interface {
+	Tracking() bool
+}
+
 var templates = map[MessageTemplate]Template{
 	Receipt: new(ReceiptTemplate),
 }
@@ -31,20 +77,163 @@ func GetTemplate(name MessageTemplate) (Template, error) {
 	return tmpl, nil
 }
 
-func Parse(t Template, ch channel.Channel) (string, error) {
+// IsTracked reports whether the opens and clicks of the template emails are tracked.
+func IsTracked(t Template) bool {
+	tt, ok := t.(TrackingTemplate)
+	return ok && tt.Tracking()
+}
+
+// Parse executes the channel template with the params and the helpers of the message.
+func Parse(t Template, ch channel.Channel, params types.JSON, h Helpers) (string, error) {
+	data, err := t.Data(params)
+	if err != nil {
+		return "", fmt.Errorf("params: %s", err)
+	}
+	if h, err = h.withMessage(t); err != nil {
+		return "", err
+	}
+
+	return parse(t, ch, data, h)
+}
+
+// parse executes the channel template with the data. The template is cloned, so the parsed one
+// is never executed and the helpers of concurrent messages do not mix.
+func parse(t Template, ch channel.Channel, data any, h Helpers) (string, error) {
 	tmpl, err := getChannelTemplateByName(t, ch)
 	if err != nil {
 		return "", err
 	}
+	if tmpl == nil {
+		return "", fmt.Errorf("no template for channel '%s'", ch.String())
+	}
+
+	if tmpl, err = tmpl.Clone(); err != nil {
+		return "", fmt.Errorf("clone: %w", err)
+	}
+	tmpl.Funcs(h.funcs())
+	if h.strict {
+		tmpl.Option("missingkey=error")
+	}
 
 	var result bytes.Buffer
-	if err = tmpl.Execute(&result, t); err != nil {
+	if err = tmpl.Execute(&result, data); err != nil {
 		return "", fmt.Errorf("execute: %w", err)
 	}
 
 	return result.String(), nil
 }
 
+// Render builds the outbound message for the channel from the params: the text parsed from the channel
+// template in the template format plus the media and the actions declared by the template.
+// Email messages also get the subject, the preheader, the headers and the plain text,
+// messages with an unsubscribe link get the List-Unsubscribe headers (RFC 8058).
+// It is safe to render a template for several messages concurrently.
+func Render(t Template, ch channel.Channel, params types.JSON, h Helpers) (*outbound.Message, error) {
+	data, err := t.Data(params)
+	if err != nil {
+		return nil, fmt.Errorf("params: %s", err)
+	}
+	if h, err = h.withMessage(t); err != nil {
+		return nil, err
+	}
+
+	text, err := parse(t, ch, data, h)
+	if err != nil {
+		return nil, err
+	}
+
+	message := &outbound.Message{Text: text, Format: outbound.FormatHTML}
+
+	if ft, ok := t.(FormatTemplate); ok {
+		message.Format = ft.Format(ch)
+	}
+	if message.Format != outbound.FormatHTML {
+		// html/template escapes the params for HTML, other formats need them as is.
+		message.Text = html.UnescapeString(message.Text)
+	}
+
+	if mt, ok := t.(MediaTemplate); ok {
+		if message.Media, err = mt.Media(ch, data); err != nil {
+			return nil, fmt.Errorf("media: %w", err)
+		}
+	}
+
+	if at, ok := t.(ActionTemplate); ok {
+		if message.Actions, err = at.Actions(ch, data); err != nil {
+			return nil, fmt.Errorf("actions: %w", err)
+		}
+	}
+
+	if ht, ok := t.(EmailHeadersTemplate); ok && ch == channel.Email {
+		if err = renderEmailHeaders(ht, data, message, h); err != nil {
+			return nil, err
+		}
+	}
+
+	if pt, ok := t.(PlainTextTemplate); ok && ch == channel.Email {
+		if message.PlainText, err = executeText(pt.EmailTextTemplate(), data, h); err != nil {
+			return nil, fmt.Errorf("plain text: %w", err)
+		}
+	}
+
+	if h.UnsubscribeURL != "" && ch == channel.Email {
+		if message.Headers == nil {
+			message.Headers = make(map[string]string, 2)
+		}
+		message.Headers["List-Unsubscribe"] = "<" + h.UnsubscribeURL + ">"
+		message.Headers["List-Unsubscribe-Post"] = "List-Unsubscribe=One-Click"
+	}
+
+	return message, nil
+}
+
+func renderEmailHeaders(t EmailHeadersTemplate, data any, message *outbound.Message, h Helpers) error {
+	var err error
+
+	if message.Subject, err = executeText(t.EmailSubject(), data, h); err != nil {
+		return fmt.Errorf("subject: %w", err)
+	}
+	if message.Preheader, err = executeText(t.EmailPreheader(), data, h); err != nil {
+		return fmt.Errorf("preheader: %w", err)
+	}
+
+	headers := t.EmailHeaders()
+	if len(headers) == 0 {
+		return nil
+	}
+
+	message.Headers = make(map[string]string, len(headers))
+	for name, tmpl := range headers {
+		if message.Headers[name], err = executeText(tmpl, data, h); err != nil {
+			return fmt.Errorf("header '%s': %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func executeText(tmpl *texttemplate.Template, data any, h Helpers) (string, error) {
+	if tmpl == nil {
+		return "", nil
+	}
+
+	tmpl, err := tmpl.Clone()
+	if err != nil {
+		return "", fmt.Errorf("clone: %w", err)
+	}
+	tmpl.Funcs(h.textFuncs())
+	if h.strict {
+		tmpl.Option("missingkey=error")
+	}
+
+	var result bytes.Buffer
+	if err := tmpl.Execute(&result, data); err != nil {
+		return "", fmt.Errorf("execute: %w", err)
+	}
+
+	return strings.TrimSpace(result.String()), nil
+}
+
 func getChannelTemplateByName(t Template, ch channel.Channel) (*template.Template, error) {
 	switch ch {
 	case channel.Mock, channel.Telegram:
@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"github.com/golang/mock/gomock"
 	"github.com/keweegen/notification/internal/channel"
+	"github.com/keweegen/notification/internal/channel/outbound"
 	mock_messagetemplate "github.com/keweegen/notification/internal/messagetemplate/mock"
 	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/sqlboiler/v4/types"
 	"html/template"
+	"sync"
 	"testing"
 )
 
@@ -64,6 +67,7 @@ func TestParse(t *testing.T) {
 	defer controller.Finish()
 
 	tmpl := mock_messagetemplate.NewMockTemplate(controller)
+	tmpl.EXPECT().Data(nil).Return(nil, nil).AnyTimes()
 
 	cases := []testCase{
 		{
@@ -97,9 +101,152 @@ func TestParse(t *testing.T) {
 				tmpl.EXPECT().EmailTemplate().Return(tc.mockTemplate)
 			}
 
-			chTemplate, err := Parse(tmpl, tc.channel)
+			chTemplate, err := Parse(tmpl, tc.channel, nil, Helpers{})
 			assert.Equal(t, tc.expectedError, err)
 			assert.Equal(t, tc.expectedTemplateContent, chTemplate)
 		})
 	}
 }
+
+type mediaTemplate struct {
+	*mock_messagetemplate.MockTemplate
+	media   []outbound.Media
+	actions []outbound.Action
+}
+
+func (t *mediaTemplate) Media(_ channel.Channel, _ any) ([]outbound.Media, error) {
+	return t.media, nil
+}
+
+func (t *mediaTemplate) Actions(_ channel.Channel, _ any) ([]outbound.Action, error) {
+	return t.actions, nil
+}
+
+func TestRender(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	media := []outbound.Media{{Kind: outbound.Photo, URL: "https://example.com/promo.png"}}
+	actions := []outbound.Action{
+		{ID: "view", Label: "View order", URL: "https://example.com/orders/1"},
+		{ID: "confirm", Label: "Confirm delivery"},
+	}
+	tmpl := &mediaTemplate{MockTemplate: mock_messagetemplate.NewMockTemplate(controller), media: media, actions: actions}
+	tmpl.EXPECT().Data(nil).Return(nil, nil)
+	tmpl.EXPECT().TelegramTemplate().Return(template.Must(template.New("ns-test.telegram.media").Parse("Promo")))
+
+	message, err := Render(tmpl, channel.Telegram, nil, Helpers{})
+	assert.Nil(t, err)
+	assert.Equal(t, &outbound.Message{Text: "Promo", Format: outbound.FormatHTML, Media: media, Actions: actions}, message)
+}
+
+type markdownTemplate struct {
+	*mock_messagetemplate.MockTemplate
+}
+
+func (t *markdownTemplate) Format(_ channel.Channel) outbound.Format {
+	return outbound.FormatMarkdownV2
+}
+
+func TestRender_Format(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	tmpl := &markdownTemplate{MockTemplate: mock_messagetemplate.NewMockTemplate(controller)}
+	tmpl.EXPECT().Data(nil).Return(nil, nil)
+	tmpl.EXPECT().TelegramTemplate().Return(template.Must(template.New("ns-test.telegram.markdown").
+		Parse(`*Order* {{"<1 & 2>"}}`)))
+
+	message, err := Render(tmpl, channel.Telegram, nil, Helpers{})
+	assert.Nil(t, err)
+	assert.Equal(t, &outbound.Message{Text: "*Order* <1 & 2>", Format: outbound.FormatMarkdownV2}, message)
+}
+
+func TestRender_EmailHeaders(t *testing.T) {
+	tmpl := new(ReceiptTemplate)
+	params := types.JSON(`{"orderId": 123, "commissionAmount": 300, "totalAmount": 1300}`)
+
+	message, err := Render(tmpl, channel.Email, params, Helpers{})
+	assert.Nil(t, err)
+	assert.Equal(t, "Чек по заказу 123", message.Subject)
+	assert.Equal(t, "Заказ 123 успешно оплачен, сумма к списанию 1\u00a0300,00\u00a0KZT", message.Preheader)
+
+	message, err = Render(tmpl, channel.Telegram, params, Helpers{})
+	assert.Nil(t, err)
+	assert.Empty(t, message.Subject)
+}
+
+func TestRender_Unsubscribe(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	tmpl := mock_messagetemplate.NewMockTemplate(controller)
+	tmpl.EXPECT().Data(nil).Return(nil, nil).Times(2)
+	tmpl.EXPECT().EmailTemplate().Return(template.Must(template.New("ns-test.email.unsubscribe").Funcs(Funcs).
+		Parse(`<a href="{{unsubscribeURL}}">Unsubscribe</a>`))).Times(2)
+
+	unsubscribeURL := "https://ns.example.com/unsubscribe/token?a=1"
+	message, err := Render(tmpl, channel.Email, nil, Helpers{UnsubscribeURL: unsubscribeURL})
+	assert.Nil(t, err)
+	assert.Equal(t, `<a href="https://ns.example.com/unsubscribe/token?a=1">Unsubscribe</a>`, message.Text)
+	assert.Equal(t, map[string]string{
+		"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	}, message.Headers)
+
+	message, err = Render(tmpl, channel.Email, nil, Helpers{})
+	assert.Nil(t, err)
+	assert.Equal(t, `<a href="">Unsubscribe</a>`, message.Text)
+	assert.Empty(t, message.Headers)
+}
+
+func TestGetCategory(t *testing.T) {
+	assert.Equal(t, CategoryTransactional, GetCategory(new(ReceiptTemplate)))
+	assert.False(t, CategoryTransactional.CanUnsubscribe())
+	assert.True(t, CategoryMarketing.CanUnsubscribe())
+}
+
+// TestRender_Concurrent renders the templates for many messages at once, run it with -race.
+func TestRender_Concurrent(t *testing.T) {
+	stored, err := NewStoredTemplate("Shipped", CategoryMarketing, true, Bodies{
+		"email":    {Body: `<p>{{t "shipped" .orderId}}</p> <a href="{{unsubscribeURL}}">x</a>`, Subject: `{{t "shipped" .orderId}}`},
+		"telegram": {Body: `{{t "shipped" .orderId}}`, Format: "plain"},
+	}, &Catalog{Default: "en", Messages: map[string]map[string]Message{
+		"en": {"shipped": {Other: "Order %v shipped"}},
+		"ru": {"shipped": {Other: "Заказ %v отправлен"}},
+	}}, nil)
+	assert.Nil(t, err)
+
+	templates := []Template{new(ReceiptTemplate), stored}
+	locales := []string{"ru", "en"}
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+
+			for i := 0; i < 50; i++ {
+				orderID := fmt.Sprintf("%d%03d", worker+1, i)
+				params := types.JSON(fmt.Sprintf(
+					`{"orderId": %s, "commissionAmount": %s.5, "totalAmount": %s}`, orderID, orderID, orderID))
+				h := Helpers{UnsubscribeURL: "https://ns.example.com/unsubscribe/" + orderID, Locale: locales[i%2]}
+
+				for _, tmpl := range templates {
+					for _, ch := range channel.Channels {
+						message, err := Render(tmpl, ch, params, h)
+						if !assert.Nil(t, err) {
+							return
+						}
+						assert.Contains(t, message.Text, orderID, "%s %s", tmpl.Name(), ch)
+						if ch == channel.Email {
+							assert.Contains(t, message.Subject, orderID)
+							assert.Equal(t, "<"+h.UnsubscribeURL+">", message.Headers["List-Unsubscribe"])
+						}
+					}
+				}
+			}
+		}(worker)
+	}
+	wg.Wait()
+}
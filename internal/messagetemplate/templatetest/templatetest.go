@@ -0,0 +1,27 @@
+// Package templatetest checks message templates in tests.
+package templatetest
+
+import (
+	"github.com/keweegen/notification/internal/messagetemplate"
+	"testing"
+)
+
+// Lint fails the test with the problems messagetemplate.Lint finds in the compiled templates and,
+// unless the path is empty, in the ones of the template directory, which must load.
+func Lint(t testing.TB, path string) {
+	t.Helper()
+
+	var directory *messagetemplate.Directory
+	if path != "" {
+		directory = messagetemplate.NewDirectory(path)
+		if err := directory.Load(); err != nil {
+			t.Fatalf("load templates: %s", err)
+		}
+	}
+
+	for _, tmpl := range messagetemplate.LintTemplates(directory, nil) {
+		for _, err := range messagetemplate.Lint(tmpl) {
+			t.Error(err)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+package templatetest
+
+import "testing"
+
+func TestLint(t *testing.T) {
+	Lint(t, "")
+}
@@ -0,0 +1,378 @@
+// Package pdf writes simple PDF documents: pages of text, lines and rectangles set in
+// standard or embedded TrueType fonts.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// Size of an A4 page in points.
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+var InvalidColorErr = errors.New("pdf: invalid color")
+
+// Color is an RGB color.
+type Color struct {
+	R, G, B uint8
+}
+
+var (
+	Black = Color{}
+	White = Color{R: 0xff, G: 0xff, B: 0xff}
+)
+
+// ParseColor parses a color written as #rrggbb.
+func ParseColor(hex string) (Color, error) {
+	if len(hex) != 7 || hex[0] != '#' {
+		return Color{}, fmt.Errorf("%w: %s", InvalidColorErr, hex)
+	}
+	rgb, err := strconv.ParseUint(hex[1:], 16, 32)
+	if err != nil {
+		return Color{}, fmt.Errorf("%w: %s", InvalidColorErr, hex)
+	}
+	return Color{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb)}, nil
+}
+
+func (c Color) operands() string {
+	return number(float64(c.R)/255) + " " + number(float64(c.G)/255) + " " + number(float64(c.B)/255)
+}
+
+// Document is a PDF document of A4 pages. It is not safe for concurrent use.
+type Document struct {
+	Title     string
+	Author    string
+	CreatedAt time.Time
+
+	pages []*Page
+	fonts []*fontUsage
+}
+
+// Page is a page of a document, positions are in points from its top left corner.
+type Page struct {
+	doc     *Document
+	content bytes.Buffer
+}
+
+// fontUsage is a font used by a document with the glyphs it needs.
+type fontUsage struct {
+	font     *Font
+	resource string
+	runes    map[uint16]rune
+}
+
+func New() *Document {
+	return &Document{}
+}
+
+// AddPage adds an empty page to the end of the document.
+func (d *Document) AddPage() *Page {
+	p := &Page{doc: d}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// Text sets the text in the font of the size and the color, the baseline starting at the position.
+func (p *Page) Text(x, y float64, font *Font, size float64, color Color, text string) {
+	usage := p.doc.use(font)
+
+	var encoded strings.Builder
+	if font.trueType != nil {
+		encoded.WriteByte('<')
+		for _, r := range text {
+			glyph := font.glyph(r)
+			if _, ok := usage.runes[glyph]; !ok {
+				usage.runes[glyph] = r
+			}
+			fmt.Fprintf(&encoded, "%04X", glyph)
+		}
+		encoded.WriteByte('>')
+	} else {
+		encoded.WriteByte('(')
+		for _, r := range text {
+			c := font.code(r)
+			if c == '(' || c == ')' || c == '\\' {
+				encoded.WriteByte('\\')
+			}
+			encoded.WriteByte(c)
+		}
+		encoded.WriteByte(')')
+	}
+
+	fmt.Fprintf(&p.content, "BT /%s %s Tf %s rg %s %s Td %s Tj ET\n",
+		usage.resource, number(size), color.operands(), number(x), number(PageHeight-y), encoded.String())
+}
+
+// TextRight sets the text like Text does, ending at the position.
+func (p *Page) TextRight(x, y float64, font *Font, size float64, color Color, text string) {
+	p.Text(x-font.Width(text, size), y, font, size, color, text)
+}
+
+// Line draws a line of the width and the color between the positions.
+func (p *Page) Line(x1, y1, x2, y2, width float64, color Color) {
+	fmt.Fprintf(&p.content, "%s w %s RG %s %s m %s %s l S\n",
+		number(width), color.operands(), number(x1), number(PageHeight-y1), number(x2), number(PageHeight-y2))
+}
+
+// Rect fills a rectangle of the size with the top left corner at the position.
+func (p *Page) Rect(x, y, width, height float64, color Color) {
+	fmt.Fprintf(&p.content, "%s rg %s %s %s %s re f\n",
+		color.operands(), number(x), number(PageHeight-y-height), number(width), number(height))
+}
+
+func (d *Document) use(font *Font) *fontUsage {
+	for _, usage := range d.fonts {
+		if usage.font == font {
+			return usage
+		}
+	}
+
+	usage := &fontUsage{font: font, resource: "F" + strconv.Itoa(len(d.fonts)+1), runes: make(map[uint16]rune)}
+	d.fonts = append(d.fonts, usage)
+	return usage
+}
+
+// Bytes returns the document written as a PDF file.
+func (d *Document) Bytes() ([]byte, error) {
+	var b bytes.Buffer
+	if _, err := d.WriteTo(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// WriteTo writes the document as a PDF file.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	o := &objects{}
+	catalog, pages, info := o.reserve(), o.reserve(), o.reserve()
+
+	fonts := make([]string, 0, len(d.fonts))
+	for _, usage := range d.fonts {
+		font, err := o.font(usage)
+		if err != nil {
+			return 0, err
+		}
+		fonts = append(fonts, fmt.Sprintf("/%s %d 0 R", usage.resource, font))
+	}
+	resources := "<< /Font << " + strings.Join(fonts, " ") + " >> >>"
+
+	kids := make([]string, 0, len(d.pages))
+	for _, p := range d.pages {
+		content, err := o.stream("", p.content.Bytes())
+		if err != nil {
+			return 0, err
+		}
+		page := o.add(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources %s /Contents %d 0 R >>",
+			pages, number(PageWidth), number(PageHeight), resources, content))
+		kids = append(kids, fmt.Sprintf("%d 0 R", page))
+	}
+
+	o.set(catalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pages))
+	o.set(pages, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids)))
+
+	createdAt := d.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	entries := ""
+	if d.Title != "" {
+		entries += "/Title " + textString(d.Title) + " "
+	}
+	if d.Author != "" {
+		entries += "/Author " + textString(d.Author) + " "
+	}
+	o.set(info, fmt.Sprintf("<< %s/Producer (notification) /CreationDate (D:%s) >>",
+		entries, createdAt.UTC().Format("20060102150405Z")))
+
+	return o.writeTo(w, catalog, info)
+}
+
+// objects are the objects of a PDF file, numbered from one.
+type objects struct {
+	bodies [][]byte
+}
+
+func (o *objects) reserve() int {
+	o.bodies = append(o.bodies, nil)
+	return len(o.bodies)
+}
+
+func (o *objects) set(id int, body string) {
+	o.bodies[id-1] = []byte(body)
+}
+
+func (o *objects) add(body string) int {
+	id := o.reserve()
+	o.set(id, body)
+	return id
+}
+
+// stream adds a compressed stream with the entries of its dictionary.
+func (o *objects) stream(entries string, data []byte) (int, error) {
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	if _, err := zw.Write(data); err != nil {
+		return 0, err
+	}
+	if err := zw.Close(); err != nil {
+		return 0, err
+	}
+
+	id := o.reserve()
+	o.bodies[id-1] = append([]byte(fmt.Sprintf("<< %s/Length %d /Filter /FlateDecode >>\nstream\n", entries, compressed.Len())),
+		append(compressed.Bytes(), "\nendstream"...)...)
+	return id, nil
+}
+
+// font adds the objects of the font and returns the one its resource refers to.
+func (o *objects) font(usage *fontUsage) (int, error) {
+	f := usage.font
+	if f.trueType == nil {
+		return o.add(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", f.name)), nil
+	}
+
+	t := f.trueType
+	glyphs := make([]uint16, 0, len(usage.runes))
+	used := make(map[uint16]bool, len(usage.runes))
+	for glyph := range usage.runes {
+		glyphs = append(glyphs, glyph)
+		used[glyph] = true
+	}
+	sort.Slice(glyphs, func(i, j int) bool { return glyphs[i] < glyphs[j] })
+
+	name := subsetTag(glyphs) + "+" + f.name
+	scale := func(v int) string { return strconv.Itoa(v * 1000 / t.unitsPerEm) }
+
+	subset := t.subset(used)
+	file, err := o.stream(fmt.Sprintf("/Length1 %d ", len(subset)), subset)
+	if err != nil {
+		return 0, err
+	}
+	descriptor := o.add(fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%s %s %s %s] "+
+		"/ItalicAngle 0 /Ascent %s /Descent %s /CapHeight %s /StemV 80 /FontFile2 %d 0 R >>",
+		name, scale(t.bbox[0]), scale(t.bbox[1]), scale(t.bbox[2]), scale(t.bbox[3]),
+		scale(t.ascent), scale(t.descent), scale(t.ascent), file))
+
+	widths := make([]string, 0, len(glyphs))
+	for _, glyph := range glyphs {
+		widths = append(widths, fmt.Sprintf("%d [%s]", glyph, scale(t.advances[glyph])))
+	}
+	cidFont := o.add(fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s "+
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> "+
+		"/FontDescriptor %d 0 R /W [%s] /CIDToGIDMap /Identity >>", name, descriptor, strings.Join(widths, " ")))
+
+	toUnicode, err := o.stream("", toUnicodeCMap(glyphs, usage.runes))
+	if err != nil {
+		return 0, err
+	}
+
+	return o.add(fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H "+
+		"/DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>", name, cidFont, toUnicode)), nil
+}
+
+func (o *objects) writeTo(w io.Writer, root, info int) (int64, error) {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+
+	offsets := make([]int, len(o.bodies))
+	for i, body := range o.bodies {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n", i+1)
+		b.Write(body)
+		b.WriteString("\nendobj\n")
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(o.bodies)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(o.bodies)+1, root, info, xref)
+
+	return b.WriteTo(w)
+}
+
+// toUnicodeCMap maps the glyphs back to the characters, so the text can be copied and searched.
+func toUnicodeCMap(glyphs []uint16, runes map[uint16]rune) []byte {
+	var b bytes.Buffer
+	b.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+
+	// A block holds a hundred mappings at most.
+	for start := 0; start < len(glyphs); start += 100 {
+		end := start + 100
+		if end > len(glyphs) {
+			end = len(glyphs)
+		}
+		fmt.Fprintf(&b, "%d beginbfchar\n", end-start)
+		for _, glyph := range glyphs[start:end] {
+			fmt.Fprintf(&b, "<%04X> <", glyph)
+			for _, unit := range utf16.Encode([]rune{runes[glyph]}) {
+				fmt.Fprintf(&b, "%04X", unit)
+			}
+			b.WriteString(">\n")
+		}
+		b.WriteString("endbfchar\n")
+	}
+
+	b.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return b.Bytes()
+}
+
+// subsetTag returns the six uppercase letters prefixed to the name of a font subset, the same for
+// the same glyphs.
+func subsetTag(glyphs []uint16) string {
+	h := sha1.New()
+	for _, glyph := range glyphs {
+		h.Write([]byte{byte(glyph >> 8), byte(glyph)})
+	}
+	sum := h.Sum(nil)
+
+	tag := make([]byte, 6)
+	for i := range tag {
+		tag[i] = 'A' + sum[i]%26
+	}
+	return string(tag)
+}
+
+// textString writes the text as a PDF text string in UTF-16.
+func textString(text string) string {
+	var b strings.Builder
+	b.WriteString("<FEFF")
+	for _, unit := range utf16.Encode([]rune(text)) {
+		fmt.Fprintf(&b, "%04X", unit)
+	}
+	b.WriteByte('>')
+	return b.String()
+}
+
+// number writes the number with two decimals at most.
+func number(v float64) string {
+	return strconv.FormatFloat(float64(int64(v*100+0.5*sign(v)))/100, 'f', -1, 64)
+}
+
+func sign(v float64) float64 {
+	if v < 0 {
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,136 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// testFont is a TrueType font of the system the tests embed, they are skipped without one.
+const testFont = "/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf"
+
+func TestParseColor(t *testing.T) {
+	c, err := ParseColor("#1a73e8")
+	assert.Nil(t, err)
+	assert.Equal(t, Color{R: 0x1a, G: 0x73, B: 0xe8}, c)
+
+	for _, hex := range []string{"", "1a73e8", "#1a73e", "#1a73eg"} {
+		_, err := ParseColor(hex)
+		assert.ErrorIs(t, err, InvalidColorErr, hex)
+	}
+}
+
+func TestFont_Width(t *testing.T) {
+	assert.Equal(t, 22.23, Helvetica.Width("Total", 10))
+	assert.Equal(t, Helvetica.Width("a?", 10), Helvetica.Width("a€", 10))
+	assert.Equal(t, Helvetica.Width("1 000", 10), Helvetica.Width("1\u00a0000", 10))
+	assert.Greater(t, HelveticaBold.Width("Total", 10), Helvetica.Width("Total", 10))
+}
+
+func TestDocument_Bytes(t *testing.T) {
+	d := New()
+	d.Title = "Receipt (copy)"
+	d.CreatedAt = time.Date(2026, 10, 19, 12, 30, 0, 0, time.UTC)
+	p := d.AddPage()
+	p.Rect(0, 0, PageWidth, 80, Color{R: 0x1a, G: 0x73, B: 0xe8})
+	p.Text(40, 50, HelveticaBold, 20, White, "Shop (KZ)")
+	p.Line(40, 100, PageWidth-40, 100, 0.5, Black)
+	p.TextRight(PageWidth-40, 120, Helvetica, 10, Black, "Total: 1\u00a0300 KZT")
+
+	data, err := d.Bytes()
+	assert.Nil(t, err)
+	assertStructure(t, data)
+	assert.Contains(t, string(data), "/BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding")
+	assert.Contains(t, string(data), "/CreationDate (D:20261019123000Z)")
+	assert.Contains(t, string(data), "<< /Title <FEFF0052006500630065006900700074002000280063006F007000790029> /Producer")
+
+	content := inflate(t, data)
+	assert.Contains(t, content, "BT /F1 20 Tf 1 1 1 rg 40 791.89 Td (Shop \\(KZ\\)) Tj ET")
+	assert.Contains(t, content, "0.5 w 0 0 0 RG 40 741.89 m 555.28 741.89 l S")
+	assert.Contains(t, content, "(Total: 1 300 KZT) Tj ET")
+
+	again, err := d.Bytes()
+	assert.Nil(t, err)
+	assert.Equal(t, data, again)
+}
+
+func TestDocument_BytesTrueType(t *testing.T) {
+	data, err := os.ReadFile(testFont)
+	if err != nil {
+		t.Skip("no font: ", err)
+	}
+	font, err := ParseFont(data)
+	assert.Nil(t, err)
+	assert.Equal(t, "DejaVuSans", font.Name())
+	assert.Greater(t, font.Width("Итого", 10), 0.0)
+	assert.Equal(t, font.Width("1 000", 10), font.Width("1\u00a0000", 10))
+
+	d := New()
+	d.CreatedAt = time.Date(2026, 10, 19, 12, 30, 0, 0, time.UTC)
+	d.AddPage().Text(40, 40, font, 12, Black, "Итого: 1 300 ₸")
+	out, err := d.Bytes()
+	assert.Nil(t, err)
+	assertStructure(t, out)
+	assert.Regexp(t, `/BaseFont /[A-Z]{6}\+DejaVuSans /Encoding /Identity-H`, string(out))
+
+	glyph := font.glyph('И')
+	content := inflate(t, out)
+	assert.Contains(t, content, "<"+hex4(glyph))
+	assert.Contains(t, content, "<"+hex4(glyph)+"> <0418>")
+
+	subset := font.trueType.subset(map[uint16]bool{glyph: true})
+	assert.Equal(t, uint32(0xb1b0afba), checksum(subset))
+	tables, err := readTables(subset)
+	assert.Nil(t, err)
+	assert.NotContains(t, tables, "cmap")
+	assert.Len(t, tables["loca"], (len(font.trueType.advances)+1)*4)
+	assert.True(t, bytes.Contains(tables["glyf"], font.trueType.glyph(glyph)))
+	assert.False(t, bytes.Contains(tables["glyf"], font.trueType.glyph(font.glyph('W'))))
+
+	_, err = ParseFont([]byte("not a font"))
+	assert.ErrorIs(t, err, InvalidFontErr)
+}
+
+// assertStructure checks the cross-reference table points at the objects.
+func assertStructure(t *testing.T, data []byte) {
+	t.Helper()
+	assert.True(t, bytes.HasPrefix(data, []byte("%PDF-1.7\n")))
+	assert.True(t, bytes.HasSuffix(data, []byte("%%EOF\n")))
+
+	start := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(data)
+	assert.NotNil(t, start)
+	xref, _ := strconv.Atoi(string(start[1]))
+	assert.True(t, bytes.HasPrefix(data[xref:], []byte("xref\n")))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(data[xref:], -1)
+	assert.NotEmpty(t, entries)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		assert.True(t, bytes.HasPrefix(data[offset:], []byte(strconv.Itoa(i+1)+" 0 obj\n")), "object %d", i+1)
+	}
+}
+
+// inflate returns the streams of the document decompressed.
+func inflate(t *testing.T, data []byte) string {
+	t.Helper()
+	var content bytes.Buffer
+	for _, stream := range regexp.MustCompile(`(?s)/Length (\d+) /Filter /FlateDecode >>\nstream\n`).FindAllSubmatchIndex(data, -1) {
+		length, _ := strconv.Atoi(string(data[stream[2]:stream[3]]))
+		r, err := zlib.NewReader(bytes.NewReader(data[stream[1] : stream[1]+length]))
+		assert.Nil(t, err)
+		_, err = io.Copy(&content, r)
+		assert.Nil(t, err)
+	}
+	return content.String()
+}
+
+func hex4(glyph uint16) string {
+	return fmt.Sprintf("%04X", glyph)
+}
@@ -0,0 +1,112 @@
+package pdf
+
+import (
+	"fmt"
+	"os"
+)
+
+// Font is a font the text of a document is set in, either embedded from a TrueType font or
+// one of the standard fonts every PDF viewer has. The standard fonts cover ASCII only.
+// A font is immutable and shared by documents.
+type Font struct {
+	name     string
+	trueType *trueType
+	widths   []int
+}
+
+// Standard fonts.
+var (
+	Helvetica     = &Font{name: "Helvetica", widths: helveticaWidths}
+	HelveticaBold = &Font{name: "Helvetica-Bold", widths: helveticaBoldWidths}
+)
+
+// Widths of the ASCII characters from the space to the tilde, in thousandths of the font size.
+var (
+	helveticaWidths = []int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	helveticaBoldWidths = []int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+// ParseFont parses a TrueType font, the glyphs a document uses are embedded into it.
+func ParseFont(data []byte) (*Font, error) {
+	t, err := parseTrueType(data)
+	if err != nil {
+		return nil, err
+	}
+	return &Font{name: t.name, trueType: t}, nil
+}
+
+// LoadFont reads and parses the TrueType font file.
+func LoadFont(path string) (*Font, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("pdf: read font: %w", err)
+	}
+
+	f, err := ParseFont(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, path)
+	}
+	return f, nil
+}
+
+// Name returns the PostScript name of the font.
+func (f *Font) Name() string {
+	return f.name
+}
+
+// Width returns the width of the text set in the font of the size, in points.
+func (f *Font) Width(text string, size float64) float64 {
+	width := 0
+	for _, r := range text {
+		width += f.advance(r)
+	}
+	return float64(width) * size / 1000
+}
+
+// advance returns the width of the character in thousandths of the font size.
+func (f *Font) advance(r rune) int {
+	if f.trueType != nil {
+		t := f.trueType
+		return t.advances[f.glyph(r)] * 1000 / t.unitsPerEm
+	}
+	return f.widths[f.code(r)-' ']
+}
+
+// glyph returns the glyph of the character in the TrueType font, the one of the question mark
+// when the font has none.
+func (f *Font) glyph(r rune) uint16 {
+	if glyph, ok := f.trueType.glyphs[r]; ok {
+		return glyph
+	}
+	if r == '\u00a0' || r == '\u202f' {
+		return f.glyph(' ')
+	}
+	return f.trueType.glyphs['?']
+}
+
+// code returns the character code of the character in the standard font, spaces replace the
+// non-breaking ones and the question mark replaces the characters out of ASCII.
+func (f *Font) code(r rune) byte {
+	switch {
+	case r >= ' ' && r <= '~':
+		return byte(r)
+	case r == '\u00a0' || r == '\u202f':
+		return ' '
+	default:
+		return '?'
+	}
+}
@@ -0,0 +1,446 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var InvalidFontErr = errors.New("pdf: invalid font")
+
+// subsetTables are the tables of a TrueType font a PDF viewer needs, the hinting ones are kept when present.
+var subsetTables = []string{"cvt ", "fpgm", "glyf", "head", "hhea", "hmtx", "loca", "maxp", "prep"}
+
+// Composite glyph flags.
+const (
+	argsAreWords   = 0x0001
+	haveScale      = 0x0008
+	moreComponents = 0x0020
+	haveXYScale    = 0x0040
+	haveTwoByTwo   = 0x0080
+)
+
+// trueType is a parsed TrueType font, glyphs are addressed by their index.
+type trueType struct {
+	name       string
+	unitsPerEm int
+	bbox       [4]int
+	ascent     int
+	descent    int
+	advances   []int
+	glyphs     map[rune]uint16
+	tables     map[string][]byte
+	offsets    []int
+}
+
+func parseTrueType(data []byte) (*trueType, error) {
+	tables, err := readTables(data)
+	if err != nil {
+		return nil, err
+	}
+	t := &trueType{tables: tables}
+	for _, tag := range []string{"head", "hhea", "hmtx", "maxp", "loca", "glyf", "cmap"} {
+		if _, ok := t.tables[tag]; !ok {
+			return nil, fmt.Errorf("%w: no '%s' table", InvalidFontErr, tag)
+		}
+	}
+
+	if err := t.parseMetrics(); err != nil {
+		return nil, err
+	}
+	if err := t.parseLoca(); err != nil {
+		return nil, err
+	}
+	if err := t.parseCmap(); err != nil {
+		return nil, err
+	}
+	t.name = parseName(t.tables["name"])
+
+	return t, nil
+}
+
+// readTables returns the tables of the font file by their tags.
+func readTables(data []byte) (map[string][]byte, error) {
+	if len(data) < 12 {
+		return nil, fmt.Errorf("%w: too short", InvalidFontErr)
+	}
+	if version := binary.BigEndian.Uint32(data); version != 0x00010000 && version != 0x74727565 {
+		return nil, fmt.Errorf("%w: not a TrueType font", InvalidFontErr)
+	}
+
+	tables := make(map[string][]byte)
+	count := int(binary.BigEndian.Uint16(data[4:]))
+	for i := 0; i < count; i++ {
+		record := 12 + i*16
+		if record+16 > len(data) {
+			return nil, fmt.Errorf("%w: truncated table directory", InvalidFontErr)
+		}
+		tag := string(data[record : record+4])
+		offset := int(binary.BigEndian.Uint32(data[record+8:]))
+		length := int(binary.BigEndian.Uint32(data[record+12:]))
+		if offset+length > len(data) {
+			return nil, fmt.Errorf("%w: table '%s' out of bounds", InvalidFontErr, tag)
+		}
+		tables[tag] = data[offset : offset+length]
+	}
+
+	return tables, nil
+}
+
+func (t *trueType) parseMetrics() error {
+	head, hhea, hmtx := t.tables["head"], t.tables["hhea"], t.tables["hmtx"]
+	if len(head) < 54 || len(hhea) < 36 || len(t.tables["maxp"]) < 6 {
+		return fmt.Errorf("%w: truncated metrics", InvalidFontErr)
+	}
+
+	t.unitsPerEm = int(binary.BigEndian.Uint16(head[18:]))
+	if t.unitsPerEm == 0 {
+		return fmt.Errorf("%w: zero units per em", InvalidFontErr)
+	}
+	for i := range t.bbox {
+		t.bbox[i] = int(int16(binary.BigEndian.Uint16(head[36+i*2:])))
+	}
+	t.ascent = int(int16(binary.BigEndian.Uint16(hhea[4:])))
+	t.descent = int(int16(binary.BigEndian.Uint16(hhea[6:])))
+
+	glyphs := int(binary.BigEndian.Uint16(t.tables["maxp"][4:]))
+	metrics := int(binary.BigEndian.Uint16(hhea[34:]))
+	if metrics == 0 || metrics > glyphs || len(hmtx) < metrics*4 {
+		return fmt.Errorf("%w: invalid horizontal metrics", InvalidFontErr)
+	}
+
+	t.advances = make([]int, glyphs)
+	for i := range t.advances {
+		if i < metrics {
+			t.advances[i] = int(binary.BigEndian.Uint16(hmtx[i*4:]))
+		} else {
+			t.advances[i] = t.advances[metrics-1]
+		}
+	}
+
+	return nil
+}
+
+func (t *trueType) parseLoca() error {
+	loca := t.tables["loca"]
+	long := binary.BigEndian.Uint16(t.tables["head"][50:]) == 1
+
+	t.offsets = make([]int, len(t.advances)+1)
+	for i := range t.offsets {
+		switch {
+		case long && len(loca) >= i*4+4:
+			t.offsets[i] = int(binary.BigEndian.Uint32(loca[i*4:]))
+		case !long && len(loca) >= i*2+2:
+			t.offsets[i] = int(binary.BigEndian.Uint16(loca[i*2:])) * 2
+		default:
+			return fmt.Errorf("%w: truncated 'loca' table", InvalidFontErr)
+		}
+		if i > 0 && (t.offsets[i] < t.offsets[i-1] || t.offsets[i] > len(t.tables["glyf"])) {
+			return fmt.Errorf("%w: invalid glyph offsets", InvalidFontErr)
+		}
+	}
+
+	return nil
+}
+
+// parseCmap reads the Unicode mapping of the characters to the glyphs, formats 4 and 12 are supported.
+func (t *trueType) parseCmap() error {
+	cmap := t.tables["cmap"]
+	if len(cmap) < 4 {
+		return fmt.Errorf("%w: truncated 'cmap' table", InvalidFontErr)
+	}
+
+	var subtable []byte
+	best := 0
+	for i := 0; i < int(binary.BigEndian.Uint16(cmap[2:])); i++ {
+		record := 4 + i*8
+		if record+8 > len(cmap) {
+			break
+		}
+		platform := binary.BigEndian.Uint16(cmap[record:])
+		encoding := binary.BigEndian.Uint16(cmap[record+2:])
+		offset := int(binary.BigEndian.Uint32(cmap[record+4:]))
+		if offset+4 > len(cmap) {
+			continue
+		}
+
+		rank := 0
+		switch {
+		case platform == 3 && encoding == 10, platform == 0 && encoding >= 4:
+			rank = 2
+		case platform == 3 && encoding == 1, platform == 0:
+			rank = 1
+		}
+		if rank > best {
+			best, subtable = rank, cmap[offset:]
+		}
+	}
+	if subtable == nil {
+		return fmt.Errorf("%w: no Unicode 'cmap' subtable", InvalidFontErr)
+	}
+
+	t.glyphs = make(map[rune]uint16)
+	switch binary.BigEndian.Uint16(subtable) {
+	case 4:
+		return t.parseCmap4(subtable)
+	case 12:
+		return t.parseCmap12(subtable)
+	default:
+		return fmt.Errorf("%w: unsupported 'cmap' format %d", InvalidFontErr, binary.BigEndian.Uint16(subtable))
+	}
+}
+
+func (t *trueType) parseCmap4(s []byte) error {
+	if len(s) < 14 {
+		return fmt.Errorf("%w: truncated 'cmap' subtable", InvalidFontErr)
+	}
+	segments := int(binary.BigEndian.Uint16(s[6:])) / 2
+	ends, starts, deltas, ranges := 14, 16+segments*2, 16+segments*4, 16+segments*6
+	if len(s) < ranges+segments*2 {
+		return fmt.Errorf("%w: truncated 'cmap' subtable", InvalidFontErr)
+	}
+
+	for i := 0; i < segments; i++ {
+		end := int(binary.BigEndian.Uint16(s[ends+i*2:]))
+		start := int(binary.BigEndian.Uint16(s[starts+i*2:]))
+		delta := binary.BigEndian.Uint16(s[deltas+i*2:])
+		rangeOffset := int(binary.BigEndian.Uint16(s[ranges+i*2:]))
+
+		for c := start; c <= end && c != 0xffff; c++ {
+			glyph := uint16(c) + delta
+			if rangeOffset != 0 {
+				at := ranges + i*2 + rangeOffset + (c-start)*2
+				if at+2 > len(s) {
+					break
+				}
+				if glyph = binary.BigEndian.Uint16(s[at:]); glyph != 0 {
+					glyph += delta
+				}
+			}
+			if glyph != 0 && int(glyph) < len(t.advances) {
+				t.glyphs[rune(c)] = glyph
+			}
+		}
+	}
+
+	return nil
+}
+
+func (t *trueType) parseCmap12(s []byte) error {
+	if len(s) < 16 {
+		return fmt.Errorf("%w: truncated 'cmap' subtable", InvalidFontErr)
+	}
+	groups := int(binary.BigEndian.Uint32(s[12:]))
+	if len(s) < 16+groups*12 {
+		return fmt.Errorf("%w: truncated 'cmap' subtable", InvalidFontErr)
+	}
+
+	for i := 0; i < groups; i++ {
+		group := s[16+i*12:]
+		start, end := binary.BigEndian.Uint32(group), binary.BigEndian.Uint32(group[4:])
+		glyph := binary.BigEndian.Uint32(group[8:])
+		for c := start; c <= end && c <= 0x10ffff; c++ {
+			if g := glyph + c - start; g < uint32(len(t.advances)) {
+				t.glyphs[rune(c)] = uint16(g)
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseName returns the PostScript name of the font, "Font" when it has none.
+func parseName(name []byte) string {
+	if len(name) < 6 {
+		return "Font"
+	}
+	count := int(binary.BigEndian.Uint16(name[2:]))
+	storage := int(binary.BigEndian.Uint16(name[4:]))
+
+	for i := 0; i < count; i++ {
+		record := 6 + i*12
+		if record+12 > len(name) {
+			break
+		}
+		platform := binary.BigEndian.Uint16(name[record:])
+		id := binary.BigEndian.Uint16(name[record+6:])
+		length := int(binary.BigEndian.Uint16(name[record+8:]))
+		offset := storage + int(binary.BigEndian.Uint16(name[record+10:]))
+		if id != 6 || offset+length > len(name) {
+			continue
+		}
+
+		value := name[offset : offset+length]
+		if platform == 3 || platform == 0 {
+			ascii := make([]byte, 0, length/2)
+			for j := 1; j < len(value); j += 2 {
+				ascii = append(ascii, value[j])
+			}
+			value = ascii
+		}
+		if psName := postScriptName(value); psName != "" {
+			return psName
+		}
+	}
+
+	return "Font"
+}
+
+// postScriptName keeps the characters allowed in a PDF name.
+func postScriptName(value []byte) string {
+	var b bytes.Buffer
+	for _, c := range value {
+		if c > ' ' && c < 0x7f && !bytes.ContainsRune([]byte("()<>[]{}/%#"), rune(c)) {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// glyph returns the glyph data of the index.
+func (t *trueType) glyph(index uint16) []byte {
+	return t.tables["glyf"][t.offsets[index]:t.offsets[index+1]]
+}
+
+// subset returns the font keeping the outlines of the glyphs, the ones of the components of composite
+// glyphs and of the missing glyph. The glyph indexes are left as they are, so the text refers to them.
+func (t *trueType) subset(used map[uint16]bool) []byte {
+	keep := map[uint16]bool{0: true}
+	pending := make([]uint16, 0, len(used))
+	for glyph := range used {
+		pending = append(pending, glyph)
+	}
+	for len(pending) > 0 {
+		glyph := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if keep[glyph] {
+			continue
+		}
+		keep[glyph] = true
+		pending = append(pending, t.components(glyph)...)
+	}
+
+	var glyf bytes.Buffer
+	loca := make([]byte, (len(t.advances)+1)*4)
+	for i := range t.advances {
+		binary.BigEndian.PutUint32(loca[i*4:], uint32(glyf.Len()))
+		if keep[uint16(i)] {
+			glyf.Write(t.glyph(uint16(i)))
+			for glyf.Len()%4 != 0 {
+				glyf.WriteByte(0)
+			}
+		}
+	}
+	binary.BigEndian.PutUint32(loca[len(t.advances)*4:], uint32(glyf.Len()))
+
+	head := append([]byte(nil), t.tables["head"]...)
+	binary.BigEndian.PutUint32(head[8:], 0)
+	binary.BigEndian.PutUint16(head[50:], 1)
+
+	tables := map[string][]byte{"glyf": glyf.Bytes(), "loca": loca, "head": head}
+	for _, tag := range subsetTables {
+		if _, ok := tables[tag]; !ok && t.tables[tag] != nil {
+			tables[tag] = t.tables[tag]
+		}
+	}
+
+	return writeTrueType(tables)
+}
+
+// components returns the glyphs a composite glyph is made of.
+func (t *trueType) components(index uint16) []uint16 {
+	data := t.glyph(index)
+	if len(data) < 10 || int16(binary.BigEndian.Uint16(data)) >= 0 {
+		return nil
+	}
+
+	components := make([]uint16, 0, 2)
+	for at := 10; at+4 <= len(data); {
+		flags := binary.BigEndian.Uint16(data[at:])
+		glyph := binary.BigEndian.Uint16(data[at+2:])
+		if int(glyph) < len(t.advances) {
+			components = append(components, glyph)
+		}
+
+		at += 4
+		if flags&argsAreWords != 0 {
+			at += 4
+		} else {
+			at += 2
+		}
+		switch {
+		case flags&haveScale != 0:
+			at += 2
+		case flags&haveXYScale != 0:
+			at += 4
+		case flags&haveTwoByTwo != 0:
+			at += 8
+		}
+		if flags&moreComponents == 0 {
+			break
+		}
+	}
+
+	return components
+}
+
+// writeTrueType writes the tables as a font file with their checksums.
+func writeTrueType(tables map[string][]byte) []byte {
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	power, log := 1, 0
+	for power*2 <= len(tags) {
+		power, log = power*2, log+1
+	}
+
+	var b bytes.Buffer
+	header := make([]byte, 12)
+	binary.BigEndian.PutUint32(header, 0x00010000)
+	binary.BigEndian.PutUint16(header[4:], uint16(len(tags)))
+	binary.BigEndian.PutUint16(header[6:], uint16(power*16))
+	binary.BigEndian.PutUint16(header[8:], uint16(log))
+	binary.BigEndian.PutUint16(header[10:], uint16((len(tags)-power)*16))
+	b.Write(header)
+
+	offset := 12 + len(tags)*16
+	headOffset := 0
+	for _, tag := range tags {
+		record := make([]byte, 16)
+		copy(record, tag)
+		binary.BigEndian.PutUint32(record[4:], checksum(tables[tag]))
+		binary.BigEndian.PutUint32(record[8:], uint32(offset))
+		binary.BigEndian.PutUint32(record[12:], uint32(len(tables[tag])))
+		b.Write(record)
+
+		if tag == "head" {
+			headOffset = offset
+		}
+		offset += (len(tables[tag]) + 3) &^ 3
+	}
+	for _, tag := range tags {
+		b.Write(tables[tag])
+		for b.Len()%4 != 0 {
+			b.WriteByte(0)
+		}
+	}
+
+	font := b.Bytes()
+	binary.BigEndian.PutUint32(font[headOffset+8:], 0xb1b0afba-checksum(font))
+	return font
+}
+
+func checksum(data []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(data); i += 4 {
+		var word [4]byte
+		copy(word[:], data[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+	return sum
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/keweegen/notification/internal/entity"
+	"github.com/keweegen/notification/models"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+var FileNotFound = errors.New("file not found")
+
+//go:generate mockgen -source=file.go -destination=./mock/file.go
+type File interface {
+	Create(ctx context.Context, file *entity.File) error
+	Find(ctx context.Context, fileID string) (*entity.File, error)
+	Exists(ctx context.Context, fileID string) (bool, error)
+	FindByMessage(ctx context.Context, messageID string) (*entity.File, error)
+}
+
+type fileRepository struct {
+	db *sql.DB
+}
+
+func (r *fileRepository) init(db *sql.DB) File {
+	r.db = db
+	return r
+}
+
+func (r *fileRepository) Create(ctx context.Context, file *entity.File) error {
+	model := r.entityToSqlboiler(file)
+	if err := model.Insert(ctx, r.db, boil.Infer()); err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	file.CreatedAt = model.CreatedAt
+	return nil
+}
+
+func (r *fileRepository) Find(ctx context.Context, fileID string) (*entity.File, error) {
+	model, err := models.FindFile(ctx, r.db, fileID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, FileNotFound
+		}
+		return nil, fmt.Errorf("failed to find file: %w", err)
+	}
+	return r.sqlboilerToEntity(model), nil
+}
+
+func (r *fileRepository) Exists(ctx context.Context, fileID string) (bool, error) {
+	exist, err := models.FileExists(ctx, r.db, fileID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check exists file: %w", err)
+	}
+	return exist, nil
+}
+
+func (r *fileRepository) FindByMessage(ctx context.Context, messageID string) (*entity.File, error) {
+	model, err := models.Files(
+		models.FileWhere.MessageID.EQ(messageID),
+		qm.OrderBy(models.FileColumns.CreatedAt+" DESC"),
+	).One(ctx, r.db)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, FileNotFound
+		}
+		return nil, fmt.Errorf("failed to find message file: %w", err)
+	}
+	return r.sqlboilerToEntity(model), nil
+}
+
+func (r *fileRepository) entityToSqlboiler(data *entity.File) *models.File {
+	return &models.File{
+		ID:          data.ID,
+		Filename:    data.Filename,
+		ContentType: data.ContentType,
+		Size:        data.Size,
+		Data:        data.Data,
+		MessageID:   data.MessageID,
+	}
+}
+
+func (r *fileRepository) sqlboilerToEntity(data *models.File) *entity.File {
+	return &entity.File{
+		ID:          data.ID,
+		Filename:    data.Filename,
+		ContentType: data.ContentType,
+		Size:        data.Size,
+		Data:        data.Data,
+		MessageID:   data.MessageID,
+		CreatedAt:   data.CreatedAt,
+	}
+}
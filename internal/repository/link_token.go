@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-redis/redis/v9"
+	"time"
+)
+
+//go:generate mockgen -source=link_token.go -destination=./mock/link_token.go
+type LinkToken interface {
+	Consume(ctx context.Context, nonce string, ttl time.Duration) (bool, error)
+}
+
+type linkTokenRepository struct {
+	mb redis.UniversalClient
+}
+
+func (r *linkTokenRepository) init(mb redis.UniversalClient) LinkToken {
+	r.mb = mb
+	return r
+}
+
+// Consume marks the token nonce as used. It returns false when the nonce has already been used.
+func (r *linkTokenRepository) Consume(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	ok, err := r.mb.SetNX(ctx, r.key(nonce), time.Now().Unix(), ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to consume link token: %w", err)
+	}
+	return ok, nil
+}
+
+func (r *linkTokenRepository) key(nonce string) string {
+	return fmt.Sprintf("ns::link-token::%s", nonce)
+}
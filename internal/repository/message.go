@@ -7,11 +7,14 @@ import (
 	"fmt"
 	"github.com/go-redis/redis/v9"
 	"github.com/keweegen/notification/internal/channel"
+	"github.com/keweegen/notification/internal/channel/outbound"
 	"github.com/keweegen/notification/internal/entity"
 	"github.com/keweegen/notification/internal/messagetemplate"
 	"github.com/keweegen/notification/models"
 	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/sqlboiler/v4/types"
 	"time"
 )
 
@@ -19,11 +22,22 @@ import (
 type Message interface {
 	Create(ctx context.Context, message *entity.Message) error
 	CreateStatus(ctx context.Context, messageID, status, description string) error
+	UpdateParams(ctx context.Context, messageID string, params types.JSON) error
+	SetDelivery(ctx context.Context, messageID string, delivery outbound.Delivery) error
 	Find(ctx context.Context, messageID string) (*entity.Message, error)
+	FindByProviderMessageID(ctx context.Context, ch channel.Channel, recipient, providerMessageID string) (*entity.Message, error)
 	FindLastStatus(ctx context.Context, messageID string) (*entity.MessageStatus, error)
+	// CreateEvent records an event of a delivered message, it does not change the message status.
+	CreateEvent(ctx context.Context, messageID, eventType, description string) error
+	// FindEvents returns the events of the message in the order they happened.
+	FindEvents(ctx context.Context, messageID string) (entity.MessageEvents, error)
 	FindProcessMessages(ctx context.Context, dateFrom, dateTo time.Time) (entity.Messages, error)
+	// CountAttempts counts the delivery attempts of the message, the statuses created when a delivery starts.
+	CountAttempts(ctx context.Context, messageID string) (int64, error)
 	Exists(ctx context.Context, messageID string) (bool, error)
 	CheckForDuplicates(ctx context.Context, message *entity.Message) (string, error)
+	// FindEngagements aggregates the tracking events by template for the messages created in the period.
+	FindEngagements(ctx context.Context, ch channel.Channel, dateFrom, dateTo time.Time) (entity.Engagements, error)
 
 	Publish(ctx context.Context, key string, messageID string) error
 	Subscribe(ctx context.Context, keys ...string) *MessageSubscription
@@ -70,6 +84,32 @@ func (r *messageRepository) CreateStatus(ctx context.Context, messageID, status,
 	return nil
 }
 
+func (r *messageRepository) UpdateParams(ctx context.Context, messageID string, params types.JSON) error {
+	_, err := models.Messages(models.MessageWhere.ID.EQ(messageID)).
+		UpdateAll(ctx, r.db, models.M{models.MessageColumns.Params: params})
+	if err != nil {
+		return fmt.Errorf("failed to update message params: %w", err)
+	}
+	return nil
+}
+
+func (r *messageRepository) SetDelivery(ctx context.Context, messageID string, delivery outbound.Delivery) error {
+	parts := types.JSON("[]")
+	if len(delivery.Parts) > 0 {
+		_ = parts.Marshal(delivery.Parts)
+	}
+
+	_, err := models.Messages(models.MessageWhere.ID.EQ(messageID)).
+		UpdateAll(ctx, r.db, models.M{
+			models.MessageColumns.ProviderMessageID:    delivery.ID,
+			models.MessageColumns.ProviderMessageParts: parts,
+		})
+	if err != nil {
+		return fmt.Errorf("failed to set provider message id: %w", err)
+	}
+	return nil
+}
+
 func (r *messageRepository) Find(ctx context.Context, messageID string) (*entity.Message, error) {
 	model, err := models.FindMessage(ctx, r.db, messageID)
 	if err != nil {
@@ -78,6 +118,30 @@ func (r *messageRepository) Find(ctx context.Context, messageID string) (*entity
 	return r.sqlboilerToEntityMessage(model), nil
 }
 
+// FindByProviderMessageID finds the message delivered to the recipient under the id assigned by the provider.
+func (r *messageRepository) FindByProviderMessageID(
+	ctx context.Context,
+	ch channel.Channel,
+	recipient, providerMessageID string,
+) (*entity.Message, error) {
+	recipientQuery := fmt.Sprintf("%s IN (SELECT %s FROM %s WHERE %s=? AND %s=?)",
+		models.MessageColumns.UserID,
+		models.UserChannelColumns.UserID,
+		models.TableNames.UserChannel,
+		models.UserChannelColumns.Channel,
+		models.UserChannelColumns.Recipient)
+
+	model, err := models.Messages(
+		models.MessageWhere.Channel.EQ(int16(ch)),
+		models.MessageWhere.ProviderMessageID.EQ(providerMessageID),
+		qm.Where(recipientQuery, int16(ch), recipient)).
+		One(ctx, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find message by provider message id: %w", err)
+	}
+	return r.sqlboilerToEntityMessage(model), nil
+}
+
 func (r *messageRepository) FindLastStatus(ctx context.Context, messageID string) (*entity.MessageStatus, error) {
 	model, err := models.MessageStatuses(
 		models.MessageStatusWhere.MessageID.EQ(messageID),
@@ -90,8 +154,12 @@ func (r *messageRepository) FindLastStatus(ctx context.Context, messageID string
 }
 
 func (r *messageRepository) FindProcessMessages(ctx context.Context, dateFrom, dateTo time.Time) (entity.Messages, error) {
-	subQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s.%s IN (?, ?) AND %s.%s=? AND (%s.%s BETWEEN ? AND ?)",
+	subQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s.%s=%s.%s AND %s.%s IN (?, ?) AND %s.%s=? AND (%s.%s BETWEEN ? AND ?)",
+		models.TableNames.MessageStatus,
 		models.TableNames.MessageStatus,
+		models.MessageStatusColumns.MessageID,
+		models.TableNames.Message,
+		models.MessageColumns.ID,
 		models.TableNames.MessageStatus,
 		models.MessageStatusColumns.Status,
 		models.TableNames.MessageStatus,
@@ -117,6 +185,18 @@ func (r *messageRepository) FindProcessMessages(ctx context.Context, dateFrom, d
 	return r.sqlboilerToEntityMessages(messages), nil
 }
 
+func (r *messageRepository) CountAttempts(ctx context.Context, messageID string) (int64, error) {
+	count, err := models.MessageStatuses(
+		models.MessageStatusWhere.MessageID.EQ(messageID),
+		models.MessageStatusWhere.Status.EQ(entity.MessageStatusSending),
+		models.MessageStatusWhere.Description.EQ(entity.MessageAttemptDescription),
+	).Count(ctx, r.db)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count message attempts: %w", err)
+	}
+	return count, nil
+}
+
 func (r *messageRepository) Exists(ctx context.Context, messageID string) (bool, error) {
 	exist, err := models.MessageExists(ctx, r.db, messageID)
 	if err != nil {
@@ -151,26 +231,147 @@ func (r *messageRepository) Subscribe(ctx context.Context, keys ...string) *Mess
 }
 
 func (r *messageRepository) entityMessageToSqlboiler(data *entity.Message) *models.Message {
+	attachments := types.JSON("[]")
+	if len(data.Attachments) > 0 {
+		_ = attachments.Marshal(data.Attachments)
+	}
+	parts := types.JSON("[]")
+	if len(data.ProviderMessageParts) > 0 {
+		_ = parts.Marshal(data.ProviderMessageParts)
+	}
+
 	return &models.Message{
-		ID:         data.ID,
-		UserID:     data.UserID,
-		ExternalID: data.ExternalID,
-		Channel:    int16(data.Channel),
-		Template:   int16(data.MessageTemplate),
-		Timestamp:  time.UnixMilli(data.Timestamp),
-		Params:     data.Params,
+		ID:                   data.ID,
+		UserID:               data.UserID,
+		ExternalID:           data.ExternalID,
+		Channel:              int16(data.Channel),
+		Template:             int16(data.MessageTemplate),
+		TemplateVersion:      data.TemplateVersion,
+		Locale:               data.Locale,
+		TimeZone:             data.TimeZone,
+		Timestamp:            time.UnixMilli(data.Timestamp),
+		Params:               data.Params,
+		ProviderMessageID:    data.ProviderMessageID,
+		ProviderMessageParts: parts,
+		Attachments:          attachments,
+	}
+}
+
+const engagementsQuery = `SELECT m.template,
+       COUNT(*) FILTER (WHERE EXISTS(SELECT 1 FROM message_status s WHERE s.message_id = m.id AND s.status = $1)) AS sent,
+       COUNT(*) FILTER (WHERE e.opens > 0)  AS opened,
+       COUNT(*) FILTER (WHERE e.clicks > 0) AS clicked,
+       COALESCE(SUM(e.opens), 0)::bigint    AS opens,
+       COALESCE(SUM(e.clicks), 0)::bigint   AS clicks
+FROM message m
+         CROSS JOIN LATERAL (SELECT COUNT(*) FILTER (WHERE type = $2) AS opens,
+                                    COUNT(*) FILTER (WHERE type = $3) AS clicks
+                             FROM message_event
+                             WHERE message_id = m.id) e
+WHERE m.channel = $4
+  AND m.timestamp BETWEEN $5 AND $6
+GROUP BY m.template
+ORDER BY m.template`
+
+type engagementRow struct {
+	Template int16 `boil:"template"`
+	Sent     int64 `boil:"sent"`
+	Opened   int64 `boil:"opened"`
+	Clicked  int64 `boil:"clicked"`
+	Opens    int64 `boil:"opens"`
+	Clicks   int64 `boil:"clicks"`
+}
+
+func (r *messageRepository) FindEngagements(
+	ctx context.Context,
+	ch channel.Channel,
+	dateFrom, dateTo time.Time,
+) (entity.Engagements, error) {
+	var rows []*engagementRow
+
+	err := queries.Raw(engagementsQuery,
+		entity.MessageStatusSent,
+		entity.MessageEventOpened,
+		entity.MessageEventClicked,
+		int16(ch),
+		dateFrom,
+		dateTo).
+		Bind(ctx, r.db, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find engagements: %w", err)
 	}
+
+	engagements := make(entity.Engagements, 0, len(rows))
+	for _, row := range rows {
+		engagements = append(engagements, &entity.Engagement{
+			MessageTemplate: messagetemplate.MessageTemplate(row.Template),
+			Sent:            row.Sent,
+			Opened:          row.Opened,
+			Clicked:         row.Clicked,
+			Opens:           row.Opens,
+			Clicks:          row.Clicks,
+		})
+	}
+
+	return engagements, nil
+}
+
+func (r *messageRepository) CreateEvent(ctx context.Context, messageID, eventType, description string) error {
+	model := new(models.MessageEvent)
+	model.MessageID = messageID
+	model.Type = eventType
+	model.Description = description
+
+	if err := model.Insert(ctx, r.db, boil.Infer()); err != nil {
+		return fmt.Errorf("failed to create message event: %w", err)
+	}
+
+	return nil
+}
+
+func (r *messageRepository) FindEvents(ctx context.Context, messageID string) (entity.MessageEvents, error) {
+	items, err := models.MessageEvents(
+		models.MessageEventWhere.MessageID.EQ(messageID),
+		qm.OrderBy(fmt.Sprintf("%s, %s", models.MessageEventColumns.CreatedAt, models.MessageEventColumns.ID))).
+		All(ctx, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find message events: %w", err)
+	}
+
+	events := make(entity.MessageEvents, 0, len(items))
+	for _, m := range items {
+		events = append(events, &entity.MessageEvent{
+			ID:          m.ID,
+			MessageID:   m.MessageID,
+			Type:        m.Type,
+			Description: m.Description,
+			CreatedAt:   m.CreatedAt,
+		})
+	}
+
+	return events, nil
 }
 
 func (r *messageRepository) sqlboilerToEntityMessage(data *models.Message) *entity.Message {
+	var attachments []string
+	_ = data.Attachments.Unmarshal(&attachments)
+	var parts []outbound.Part
+	_ = data.ProviderMessageParts.Unmarshal(&parts)
+
 	return &entity.Message{
-		ID:              data.ID,
-		UserID:          data.UserID,
-		ExternalID:      data.ExternalID,
-		Channel:         channel.Channel(data.Channel),
-		MessageTemplate: messagetemplate.MessageTemplate(data.Template),
-		Timestamp:       data.Timestamp.UnixMilli(),
-		Params:          data.Params,
+		ID:                   data.ID,
+		UserID:               data.UserID,
+		ExternalID:           data.ExternalID,
+		Channel:              channel.Channel(data.Channel),
+		MessageTemplate:      messagetemplate.MessageTemplate(data.Template),
+		TemplateVersion:      data.TemplateVersion,
+		Locale:               data.Locale,
+		TimeZone:             data.TimeZone,
+		Timestamp:            data.Timestamp.UnixMilli(),
+		Params:               data.Params,
+		ProviderMessageID:    data.ProviderMessageID,
+		ProviderMessageParts: parts,
+		Attachments:          attachments,
 	}
 }
 
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: link_token.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockLinkToken is a mock of LinkToken interface.
+type MockLinkToken struct {
+	ctrl     *gomock.Controller
+	recorder *MockLinkTokenMockRecorder
+}
+
+// MockLinkTokenMockRecorder is the mock recorder for MockLinkToken.
+type MockLinkTokenMockRecorder struct {
+	mock *MockLinkToken
+}
+
+// NewMockLinkToken creates a new mock instance.
+func NewMockLinkToken(ctrl *gomock.Controller) *MockLinkToken {
+	mock := &MockLinkToken{ctrl: ctrl}
+	mock.recorder = &MockLinkTokenMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLinkToken) EXPECT() *MockLinkTokenMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockLinkToken) Consume(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", ctx, nonce, ttl)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockLinkTokenMockRecorder) Consume(ctx, nonce, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockLinkToken)(nil).Consume), ctx, nonce, ttl)
+}
@@ -10,8 +10,11 @@ import (
 	time "time"
 
 	gomock "github.com/golang/mock/gomock"
+	channel "github.com/keweegen/notification/internal/channel"
+	outbound "github.com/keweegen/notification/internal/channel/outbound"
 	entity "github.com/keweegen/notification/internal/entity"
 	repository "github.com/keweegen/notification/internal/repository"
+	types "github.com/volatiletech/sqlboiler/v4/types"
 )
 
 // MockMessage is a mock of Message interface.
@@ -52,6 +55,21 @@ func (mr *MockMessageMockRecorder) CheckForDuplicates(ctx, message interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckForDuplicates", reflect.TypeOf((*MockMessage)(nil).CheckForDuplicates), ctx, message)
 }
 
+// CountAttempts mocks base method.
+func (m *MockMessage) CountAttempts(ctx context.Context, messageID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAttempts", ctx, messageID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAttempts indicates an expected call of CountAttempts.
+func (mr *MockMessageMockRecorder) CountAttempts(ctx, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAttempts", reflect.TypeOf((*MockMessage)(nil).CountAttempts), ctx, messageID)
+}
+
 // Create mocks base method.
 func (m *MockMessage) Create(ctx context.Context, message *entity.Message) error {
 	m.ctrl.T.Helper()
@@ -66,6 +84,20 @@ func (mr *MockMessageMockRecorder) Create(ctx, message interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockMessage)(nil).Create), ctx, message)
 }
 
+// CreateEvent mocks base method.
+func (m *MockMessage) CreateEvent(ctx context.Context, messageID, eventType, description string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEvent", ctx, messageID, eventType, description)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateEvent indicates an expected call of CreateEvent.
+func (mr *MockMessageMockRecorder) CreateEvent(ctx, messageID, eventType, description interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEvent", reflect.TypeOf((*MockMessage)(nil).CreateEvent), ctx, messageID, eventType, description)
+}
+
 // CreateStatus mocks base method.
 func (m *MockMessage) CreateStatus(ctx context.Context, messageID, status, description string) error {
 	m.ctrl.T.Helper()
@@ -110,6 +142,51 @@ func (mr *MockMessageMockRecorder) Find(ctx, messageID interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockMessage)(nil).Find), ctx, messageID)
 }
 
+// FindByProviderMessageID mocks base method.
+func (m *MockMessage) FindByProviderMessageID(ctx context.Context, ch channel.Channel, recipient, providerMessageID string) (*entity.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByProviderMessageID", ctx, ch, recipient, providerMessageID)
+	ret0, _ := ret[0].(*entity.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByProviderMessageID indicates an expected call of FindByProviderMessageID.
+func (mr *MockMessageMockRecorder) FindByProviderMessageID(ctx, ch, recipient, providerMessageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByProviderMessageID", reflect.TypeOf((*MockMessage)(nil).FindByProviderMessageID), ctx, ch, recipient, providerMessageID)
+}
+
+// FindEngagements mocks base method.
+func (m *MockMessage) FindEngagements(ctx context.Context, ch channel.Channel, dateFrom, dateTo time.Time) (entity.Engagements, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindEngagements", ctx, ch, dateFrom, dateTo)
+	ret0, _ := ret[0].(entity.Engagements)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindEngagements indicates an expected call of FindEngagements.
+func (mr *MockMessageMockRecorder) FindEngagements(ctx, ch, dateFrom, dateTo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindEngagements", reflect.TypeOf((*MockMessage)(nil).FindEngagements), ctx, ch, dateFrom, dateTo)
+}
+
+// FindEvents mocks base method.
+func (m *MockMessage) FindEvents(ctx context.Context, messageID string) (entity.MessageEvents, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindEvents", ctx, messageID)
+	ret0, _ := ret[0].(entity.MessageEvents)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindEvents indicates an expected call of FindEvents.
+func (mr *MockMessageMockRecorder) FindEvents(ctx, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindEvents", reflect.TypeOf((*MockMessage)(nil).FindEvents), ctx, messageID)
+}
+
 // FindLastStatus mocks base method.
 func (m *MockMessage) FindLastStatus(ctx context.Context, messageID string) (*entity.MessageStatus, error) {
 	m.ctrl.T.Helper()
@@ -154,6 +231,20 @@ func (mr *MockMessageMockRecorder) Publish(ctx, key, messageID interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockMessage)(nil).Publish), ctx, key, messageID)
 }
 
+// SetDelivery mocks base method.
+func (m *MockMessage) SetDelivery(ctx context.Context, messageID string, delivery outbound.Delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDelivery", ctx, messageID, delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDelivery indicates an expected call of SetDelivery.
+func (mr *MockMessageMockRecorder) SetDelivery(ctx, messageID, delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDelivery", reflect.TypeOf((*MockMessage)(nil).SetDelivery), ctx, messageID, delivery)
+}
+
 // Subscribe mocks base method.
 func (m *MockMessage) Subscribe(ctx context.Context, keys ...string) *repository.MessageSubscription {
 	m.ctrl.T.Helper()
@@ -172,3 +263,17 @@ func (mr *MockMessageMockRecorder) Subscribe(ctx interface{}, keys ...interface{
 	varargs := append([]interface{}{ctx}, keys...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockMessage)(nil).Subscribe), varargs...)
 }
+
+// UpdateParams mocks base method.
+func (m *MockMessage) UpdateParams(ctx context.Context, messageID string, params types.JSON) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateParams", ctx, messageID, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateParams indicates an expected call of UpdateParams.
+func (mr *MockMessageMockRecorder) UpdateParams(ctx, messageID, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateParams", reflect.TypeOf((*MockMessage)(nil).UpdateParams), ctx, messageID, params)
+}
@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: preference.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	channel "github.com/keweegen/notification/internal/channel"
+	entity "github.com/keweegen/notification/internal/entity"
+	messagetemplate "github.com/keweegen/notification/internal/messagetemplate"
+)
+
+// MockPreference is a mock of Preference interface.
+type MockPreference struct {
+	ctrl     *gomock.Controller
+	recorder *MockPreferenceMockRecorder
+}
+
+// MockPreferenceMockRecorder is the mock recorder for MockPreference.
+type MockPreferenceMockRecorder struct {
+	mock *MockPreference
+}
+
+// NewMockPreference creates a new mock instance.
+func NewMockPreference(ctrl *gomock.Controller) *MockPreference {
+	mock := &MockPreference{ctrl: ctrl}
+	mock.recorder = &MockPreferenceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPreference) EXPECT() *MockPreferenceMockRecorder {
+	return m.recorder
+}
+
+// IsSubscribed mocks base method.
+func (m *MockPreference) IsSubscribed(ctx context.Context, userID int64, ch channel.Channel, category messagetemplate.Category) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsSubscribed", ctx, userID, ch, category)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsSubscribed indicates an expected call of IsSubscribed.
+func (mr *MockPreferenceMockRecorder) IsSubscribed(ctx, userID, ch, category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSubscribed", reflect.TypeOf((*MockPreference)(nil).IsSubscribed), ctx, userID, ch, category)
+}
+
+// Save mocks base method.
+func (m *MockPreference) Save(ctx context.Context, preference *entity.UserPreference) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, preference)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockPreferenceMockRecorder) Save(ctx, preference interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockPreference)(nil).Save), ctx, preference)
+}
@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: suppression.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	channel "github.com/keweegen/notification/internal/channel"
+	entity "github.com/keweegen/notification/internal/entity"
+)
+
+// MockSuppression is a mock of Suppression interface.
+type MockSuppression struct {
+	ctrl     *gomock.Controller
+	recorder *MockSuppressionMockRecorder
+}
+
+// MockSuppressionMockRecorder is the mock recorder for MockSuppression.
+type MockSuppressionMockRecorder struct {
+	mock *MockSuppression
+}
+
+// NewMockSuppression creates a new mock instance.
+func NewMockSuppression(ctrl *gomock.Controller) *MockSuppression {
+	mock := &MockSuppression{ctrl: ctrl}
+	mock.recorder = &MockSuppressionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSuppression) EXPECT() *MockSuppressionMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSuppression) Create(ctx context.Context, suppression *entity.Suppression) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, suppression)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSuppressionMockRecorder) Create(ctx, suppression interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSuppression)(nil).Create), ctx, suppression)
+}
+
+// Delete mocks base method.
+func (m *MockSuppression) Delete(ctx context.Context, ch channel.Channel, recipient string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, ch, recipient)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSuppressionMockRecorder) Delete(ctx, ch, recipient interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSuppression)(nil).Delete), ctx, ch, recipient)
+}
+
+// Exists mocks base method.
+func (m *MockSuppression) Exists(ctx context.Context, ch channel.Channel, recipient string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exists", ctx, ch, recipient)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockSuppressionMockRecorder) Exists(ctx, ch, recipient interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockSuppression)(nil).Exists), ctx, ch, recipient)
+}
+
+// Find mocks base method.
+func (m *MockSuppression) Find(ctx context.Context, ch channel.Channel, recipient string) (*entity.Suppression, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, ch, recipient)
+	ret0, _ := ret[0].(*entity.Suppression)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockSuppressionMockRecorder) Find(ctx, ch, recipient interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockSuppression)(nil).Find), ctx, ch, recipient)
+}
+
+// FindAll mocks base method.
+func (m *MockSuppression) FindAll(ctx context.Context, ch channel.Channel, limit, offset int) (entity.Suppressions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx, ch, limit, offset)
+	ret0, _ := ret[0].(entity.Suppressions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockSuppressionMockRecorder) FindAll(ctx, ch, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockSuppression)(nil).FindAll), ctx, ch, limit, offset)
+}
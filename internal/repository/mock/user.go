@@ -64,6 +64,21 @@ func (mr *MockUserMockRecorder) DestroyChannel(ctx, channelID interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DestroyChannel", reflect.TypeOf((*MockUser)(nil).DestroyChannel), ctx, channelID)
 }
 
+// DisableChannel mocks base method.
+func (m *MockUser) DisableChannel(ctx context.Context, channelID int64, reason string) (*entity.UserChannel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableChannel", ctx, channelID, reason)
+	ret0, _ := ret[0].(*entity.UserChannel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableChannel indicates an expected call of DisableChannel.
+func (mr *MockUserMockRecorder) DisableChannel(ctx, channelID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableChannel", reflect.TypeOf((*MockUser)(nil).DisableChannel), ctx, channelID, reason)
+}
+
 // Exists mocks base method.
 func (m *MockUser) Exists(ctx context.Context, userID int64) (bool, error) {
 	m.ctrl.T.Helper()
@@ -124,6 +139,35 @@ func (mr *MockUserMockRecorder) FindChannelsByUser(ctx, userID interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindChannelsByUser", reflect.TypeOf((*MockUser)(nil).FindChannelsByUser), ctx, userID)
 }
 
+// FindLocale mocks base method.
+func (m *MockUser) FindLocale(ctx context.Context, userID int64) (*entity.UserLocale, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLocale", ctx, userID)
+	ret0, _ := ret[0].(*entity.UserLocale)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLocale indicates an expected call of FindLocale.
+func (mr *MockUserMockRecorder) FindLocale(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLocale", reflect.TypeOf((*MockUser)(nil).FindLocale), ctx, userID)
+}
+
+// SaveLocale mocks base method.
+func (m *MockUser) SaveLocale(ctx context.Context, locale *entity.UserLocale) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveLocale", ctx, locale)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveLocale indicates an expected call of SaveLocale.
+func (mr *MockUserMockRecorder) SaveLocale(ctx, locale interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLocale", reflect.TypeOf((*MockUser)(nil).SaveLocale), ctx, locale)
+}
+
 // UpdateChannel mocks base method.
 func (m *MockUser) UpdateChannel(ctx context.Context, channel *entity.UserChannel) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/keweegen/notification/internal/channel"
+	"github.com/keweegen/notification/internal/entity"
+	"github.com/keweegen/notification/internal/messagetemplate"
+	"github.com/keweegen/notification/models"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+)
+
+//go:generate mockgen -source=preference.go -destination=./mock/preference.go
+type Preference interface {
+	// Save creates the preference or updates the subscription of the existing one.
+	Save(ctx context.Context, preference *entity.UserPreference) error
+	// IsSubscribed reports whether the user receives the category in the channel, users are subscribed by default.
+	IsSubscribed(ctx context.Context, userID int64, ch channel.Channel, category messagetemplate.Category) (bool, error)
+}
+
+type preferenceRepository struct {
+	db *sql.DB
+}
+
+func (r *preferenceRepository) init(db *sql.DB) Preference {
+	r.db = db
+	return r
+}
+
+func (r *preferenceRepository) Save(ctx context.Context, preference *entity.UserPreference) error {
+	model := r.entityToSqlboiler(preference)
+	conflict := []string{
+		models.UserPreferenceColumns.UserID,
+		models.UserPreferenceColumns.Channel,
+		models.UserPreferenceColumns.Category,
+	}
+	update := boil.Whitelist(models.UserPreferenceColumns.Subscribed, models.UserPreferenceColumns.UpdatedAt)
+
+	if err := model.Upsert(ctx, r.db, true, conflict, update, boil.Infer()); err != nil {
+		return fmt.Errorf("failed to save user preference: %w", err)
+	}
+	return nil
+}
+
+func (r *preferenceRepository) IsSubscribed(
+	ctx context.Context,
+	userID int64,
+	ch channel.Channel,
+	category messagetemplate.Category,
+) (bool, error) {
+	model, err := models.UserPreferences(
+		models.UserPreferenceWhere.UserID.EQ(userID),
+		models.UserPreferenceWhere.Channel.EQ(int16(ch)),
+		models.UserPreferenceWhere.Category.EQ(string(category)),
+	).One(ctx, r.db)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return true, nil
+		}
+		return false, fmt.Errorf("failed to find user preference: %w", err)
+	}
+	return model.Subscribed, nil
+}
+
+func (r *preferenceRepository) entityToSqlboiler(data *entity.UserPreference) *models.UserPreference {
+	return &models.UserPreference{
+		ID:         data.ID,
+		UserID:     data.UserID,
+		Channel:    int16(data.Channel),
+		Category:   string(data.Category),
+		Subscribed: data.Subscribed,
+	}
+}
@@ -6,13 +6,23 @@ import (
 )
 
 type Store struct {
-    Message Message
-    User    User
+    Message     Message
+    User        User
+    LinkToken   LinkToken
+    File        File
+    Suppression Suppression
+    Preference  Preference
+    Template    Template
 }
 
 func NewStore(db *sql.DB, mb redis.UniversalClient) *Store {
     return &Store{
-        Message: new(messageRepository).init(db, mb),
-        User:    new(userRepository).init(db),
+        Message:     new(messageRepository).init(db, mb),
+        User:        new(userRepository).init(db),
+        LinkToken:   new(linkTokenRepository).init(mb),
+        File:        new(fileRepository).init(db),
+        Suppression: new(suppressionRepository).init(db),
+        Preference:  new(preferenceRepository).init(db),
+        Template:    new(templateRepository).init(db),
     }
 }
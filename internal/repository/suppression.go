@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/keweegen/notification/internal/channel"
+	"github.com/keweegen/notification/internal/entity"
+	"github.com/keweegen/notification/models"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"strings"
+)
+
+var SuppressionNotFound = errors.New("suppression not found")
+
+//go:generate mockgen -source=suppression.go -destination=./mock/suppression.go
+type Suppression interface {
+	// Create adds the recipient to the list, an existing suppression keeps its reason.
+	Create(ctx context.Context, suppression *entity.Suppression) error
+	Find(ctx context.Context, ch channel.Channel, recipient string) (*entity.Suppression, error)
+	FindAll(ctx context.Context, ch channel.Channel, limit, offset int) (entity.Suppressions, error)
+	Exists(ctx context.Context, ch channel.Channel, recipient string) (bool, error)
+	Delete(ctx context.Context, ch channel.Channel, recipient string) error
+}
+
+type suppressionRepository struct {
+	db *sql.DB
+}
+
+func (r *suppressionRepository) init(db *sql.DB) Suppression {
+	r.db = db
+	return r
+}
+
+func (r *suppressionRepository) Create(ctx context.Context, suppression *entity.Suppression) error {
+	model := r.entityToSqlboiler(suppression)
+	conflict := []string{models.SuppressionColumns.Channel, models.SuppressionColumns.Recipient}
+
+	if err := model.Upsert(ctx, r.db, false, conflict, boil.None(), boil.Infer()); err != nil {
+		return fmt.Errorf("failed to create suppression: %w", err)
+	}
+	return nil
+}
+
+func (r *suppressionRepository) Find(ctx context.Context, ch channel.Channel, recipient string) (*entity.Suppression, error) {
+	model, err := models.Suppressions(r.byRecipient(ch, recipient)...).One(ctx, r.db)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, SuppressionNotFound
+		}
+		return nil, fmt.Errorf("failed to find suppression: %w", err)
+	}
+	return r.sqlboilerToEntity(model), nil
+}
+
+func (r *suppressionRepository) FindAll(ctx context.Context, ch channel.Channel, limit, offset int) (entity.Suppressions, error) {
+	items, err := models.Suppressions(
+		models.SuppressionWhere.Channel.EQ(int16(ch)),
+		qm.OrderBy(models.SuppressionColumns.ID+" DESC"),
+		qm.Limit(limit),
+		qm.Offset(offset),
+	).All(ctx, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find suppressions: %w", err)
+	}
+
+	suppressions := make(entity.Suppressions, 0, len(items))
+	for _, item := range items {
+		suppressions = append(suppressions, r.sqlboilerToEntity(item))
+	}
+	return suppressions, nil
+}
+
+func (r *suppressionRepository) Exists(ctx context.Context, ch channel.Channel, recipient string) (bool, error) {
+	exists, err := models.Suppressions(r.byRecipient(ch, recipient)...).Exists(ctx, r.db)
+	if err != nil {
+		return false, fmt.Errorf("failed to check exists suppression: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *suppressionRepository) Delete(ctx context.Context, ch channel.Channel, recipient string) error {
+	deleted, err := models.Suppressions(r.byRecipient(ch, recipient)...).DeleteAll(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to delete suppression: %w", err)
+	}
+	if deleted == 0 {
+		return SuppressionNotFound
+	}
+	return nil
+}
+
+// byRecipient matches the recipient case-insensitively, email addresses are stored lowercased.
+func (r *suppressionRepository) byRecipient(ch channel.Channel, recipient string) []qm.QueryMod {
+	return []qm.QueryMod{
+		models.SuppressionWhere.Channel.EQ(int16(ch)),
+		models.SuppressionWhere.Recipient.EQ(strings.ToLower(recipient)),
+	}
+}
+
+func (r *suppressionRepository) entityToSqlboiler(data *entity.Suppression) *models.Suppression {
+	return &models.Suppression{
+		ID:          data.ID,
+		Channel:     int16(data.Channel),
+		Recipient:   strings.ToLower(data.Recipient),
+		Reason:      data.Reason,
+		Description: data.Description,
+		MessageID:   data.MessageID,
+	}
+}
+
+func (r *suppressionRepository) sqlboilerToEntity(data *models.Suppression) *entity.Suppression {
+	return &entity.Suppression{
+		ID:          data.ID,
+		Channel:     channel.Channel(data.Channel),
+		Recipient:   data.Recipient,
+		Reason:      data.Reason,
+		Description: data.Description,
+		MessageID:   data.MessageID,
+		CreatedAt:   data.CreatedAt,
+	}
+}
@@ -0,0 +1,302 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/keweegen/notification/internal/entity"
+	"github.com/keweegen/notification/internal/messagetemplate"
+	"github.com/keweegen/notification/models"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/sqlboiler/v4/types"
+	"time"
+)
+
+var (
+	TemplateNotFound        = errors.New("template not found")
+	TemplateVersionNotFound = errors.New("template version not found")
+)
+
+//go:generate mockgen -source=template.go -destination=./mock/template.go
+type Template interface {
+	Create(ctx context.Context, template *entity.Template) error
+	Update(ctx context.Context, template *entity.Template) error
+	Find(ctx context.Context, id messagetemplate.MessageTemplate) (*entity.Template, error)
+	// FindByName matches the name case-insensitively.
+	FindByName(ctx context.Context, name string) (*entity.Template, error)
+	FindAll(ctx context.Context) (entity.Templates, error)
+	Exists(ctx context.Context, id messagetemplate.MessageTemplate) (bool, error)
+	Delete(ctx context.Context, id messagetemplate.MessageTemplate) error
+
+	// CreateVersion numbers the version after the current one of the template and makes it current.
+	CreateVersion(ctx context.Context, version *entity.TemplateVersion) error
+	FindVersion(ctx context.Context, id messagetemplate.MessageTemplate, version int) (*entity.TemplateVersion, error)
+	FindVersions(ctx context.Context, id messagetemplate.MessageTemplate) (entity.TemplateVersions, error)
+}
+
+type templateRepository struct {
+	db *sql.DB
+}
+
+func (r *templateRepository) init(db *sql.DB) Template {
+	r.db = db
+	return r
+}
+
+func (r *templateRepository) Create(ctx context.Context, template *entity.Template) error {
+	model := r.entityToSqlboiler(template)
+	if err := model.Insert(ctx, r.db, boil.Infer()); err != nil {
+		return fmt.Errorf("failed to create template: %w", err)
+	}
+
+	template.ID = messagetemplate.MessageTemplate(model.ID)
+	template.CreatedAt = model.CreatedAt
+	template.UpdatedAt = model.UpdatedAt
+	return nil
+}
+
+func (r *templateRepository) Update(ctx context.Context, template *entity.Template) error {
+	updated, err := models.Templates(models.TemplateWhere.ID.EQ(int16(template.ID))).
+		UpdateAll(ctx, r.db, models.M{
+			models.TemplateColumns.Name:      template.Name,
+			models.TemplateColumns.Category:  string(template.Category),
+			models.TemplateColumns.Tracking:  template.Tracking,
+			models.TemplateColumns.UpdatedAt: time.Now(),
+		})
+	if err != nil {
+		return fmt.Errorf("failed to update template: %w", err)
+	}
+	if updated == 0 {
+		return TemplateNotFound
+	}
+	return nil
+}
+
+func (r *templateRepository) Find(ctx context.Context, id messagetemplate.MessageTemplate) (*entity.Template, error) {
+	model, err := models.FindTemplate(ctx, r.db, int16(id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, TemplateNotFound
+		}
+		return nil, fmt.Errorf("failed to find template: %w", err)
+	}
+	return r.sqlboilerToEntity(model), nil
+}
+
+func (r *templateRepository) FindByName(ctx context.Context, name string) (*entity.Template, error) {
+	model, err := models.Templates(qm.Where("lower("+models.TemplateColumns.Name+") = lower(?)", name)).One(ctx, r.db)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, TemplateNotFound
+		}
+		return nil, fmt.Errorf("failed to find template by name: %w", err)
+	}
+	return r.sqlboilerToEntity(model), nil
+}
+
+func (r *templateRepository) FindAll(ctx context.Context) (entity.Templates, error) {
+	items, err := models.Templates(qm.OrderBy(models.TemplateColumns.ID)).All(ctx, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find templates: %w", err)
+	}
+
+	templates := make(entity.Templates, 0, len(items))
+	for _, item := range items {
+		templates = append(templates, r.sqlboilerToEntity(item))
+	}
+	return templates, nil
+}
+
+func (r *templateRepository) Exists(ctx context.Context, id messagetemplate.MessageTemplate) (bool, error) {
+	exists, err := models.TemplateExists(ctx, r.db, int16(id))
+	if err != nil {
+		return false, fmt.Errorf("failed to check exists template: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *templateRepository) Delete(ctx context.Context, id messagetemplate.MessageTemplate) error {
+	deleted, err := models.Templates(models.TemplateWhere.ID.EQ(int16(id))).DeleteAll(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+	if deleted == 0 {
+		return TemplateNotFound
+	}
+	return nil
+}
+
+func (r *templateRepository) CreateVersion(ctx context.Context, version *entity.TemplateVersion) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	template, err := models.Templates(
+		models.TemplateWhere.ID.EQ(int16(version.TemplateID)),
+		qm.For("UPDATE")).
+		One(ctx, tx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TemplateNotFound
+		}
+		return fmt.Errorf("failed to lock template: %w", err)
+	}
+
+	model, err := r.versionEntityToSqlboiler(version)
+	if err != nil {
+		return err
+	}
+	model.Version = template.CurrentVersion + 1
+
+	if err = model.Insert(ctx, tx, boil.Infer()); err != nil {
+		return fmt.Errorf("failed to create template version: %w", err)
+	}
+
+	template.CurrentVersion = model.Version
+	update := boil.Whitelist(models.TemplateColumns.CurrentVersion, models.TemplateColumns.UpdatedAt)
+	if _, err = template.Update(ctx, tx, update); err != nil {
+		return fmt.Errorf("failed to update current template version: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit template version: %w", err)
+	}
+
+	version.ID = model.ID
+	version.Version = model.Version
+	version.CreatedAt = model.CreatedAt
+	return nil
+}
+
+func (r *templateRepository) FindVersion(
+	ctx context.Context,
+	id messagetemplate.MessageTemplate,
+	version int,
+) (*entity.TemplateVersion, error) {
+	model, err := models.TemplateVersions(
+		models.TemplateVersionWhere.TemplateID.EQ(int16(id)),
+		models.TemplateVersionWhere.Version.EQ(version)).
+		One(ctx, r.db)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, TemplateVersionNotFound
+		}
+		return nil, fmt.Errorf("failed to find template version: %w", err)
+	}
+	return r.versionSqlboilerToEntity(model)
+}
+
+func (r *templateRepository) FindVersions(
+	ctx context.Context,
+	id messagetemplate.MessageTemplate,
+) (entity.TemplateVersions, error) {
+	items, err := models.TemplateVersions(
+		models.TemplateVersionWhere.TemplateID.EQ(int16(id)),
+		qm.OrderBy(models.TemplateVersionColumns.Version+" DESC")).
+		All(ctx, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find template versions: %w", err)
+	}
+
+	versions := make(entity.TemplateVersions, 0, len(items))
+	for _, item := range items {
+		version, err := r.versionSqlboilerToEntity(item)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+func (r *templateRepository) entityToSqlboiler(data *entity.Template) *models.Template {
+	return &models.Template{
+		ID:             int16(data.ID),
+		Name:           data.Name,
+		Category:       string(data.Category),
+		Tracking:       data.Tracking,
+		CurrentVersion: data.CurrentVersion,
+	}
+}
+
+func (r *templateRepository) sqlboilerToEntity(data *models.Template) *entity.Template {
+	return &entity.Template{
+		ID:             messagetemplate.MessageTemplate(data.ID),
+		Name:           data.Name,
+		Category:       messagetemplate.Category(data.Category),
+		Tracking:       data.Tracking,
+		CurrentVersion: data.CurrentVersion,
+		CreatedAt:      data.CreatedAt,
+		UpdatedAt:      data.UpdatedAt,
+	}
+}
+
+func (r *templateRepository) versionEntityToSqlboiler(data *entity.TemplateVersion) (*models.TemplateVersion, error) {
+	bodies := types.JSON("{}")
+	if err := bodies.Marshal(data.Bodies); err != nil {
+		return nil, fmt.Errorf("failed to marshal template bodies: %w", err)
+	}
+
+	paramsSchema := data.ParamsSchema
+	if len(paramsSchema) == 0 {
+		paramsSchema = types.JSON("{}")
+	}
+
+	catalog := types.JSON("{}")
+	if data.Catalog != nil {
+		if err := catalog.Marshal(data.Catalog); err != nil {
+			return nil, fmt.Errorf("failed to marshal template catalog: %w", err)
+		}
+	}
+
+	var exampleParams null.JSON
+	if len(data.ExampleParams) > 0 {
+		exampleParams = null.JSONFrom(data.ExampleParams)
+	}
+
+	return &models.TemplateVersion{
+		ID:            data.ID,
+		TemplateID:    int16(data.TemplateID),
+		Version:       data.Version,
+		ParamsSchema:  paramsSchema,
+		Bodies:        bodies,
+		Catalog:       catalog,
+		ExampleParams: exampleParams,
+	}, nil
+}
+
+func (r *templateRepository) versionSqlboilerToEntity(data *models.TemplateVersion) (*entity.TemplateVersion, error) {
+	var bodies messagetemplate.Bodies
+	if err := data.Bodies.Unmarshal(&bodies); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal template bodies: %w", err)
+	}
+
+	var catalog *messagetemplate.Catalog
+	if len(data.Catalog) > 0 && string(data.Catalog) != "{}" {
+		catalog = new(messagetemplate.Catalog)
+		if err := data.Catalog.Unmarshal(catalog); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal template catalog: %w", err)
+		}
+	}
+
+	var exampleParams types.JSON
+	if data.ExampleParams.Valid {
+		exampleParams = types.JSON(data.ExampleParams.JSON)
+	}
+
+	return &entity.TemplateVersion{
+		ID:            data.ID,
+		TemplateID:    messagetemplate.MessageTemplate(data.TemplateID),
+		Version:       data.Version,
+		ParamsSchema:  data.ParamsSchema,
+		Bodies:        bodies,
+		Catalog:       catalog,
+		ExampleParams: exampleParams,
+		CreatedAt:     data.CreatedAt,
+	}, nil
+}
@@ -8,7 +8,9 @@ import (
 	"github.com/keweegen/notification/internal/channel"
 	"github.com/keweegen/notification/internal/entity"
 	"github.com/keweegen/notification/models"
+	"github.com/volatiletech/null/v8"
 	"github.com/volatiletech/sqlboiler/v4/boil"
+	"time"
 )
 
 var UserChannelNotFound = errors.New("user channel not found")
@@ -18,10 +20,15 @@ type User interface {
 	CreateChannel(ctx context.Context, channel *entity.UserChannel) error
 	FindChannel(ctx context.Context, channelID int64) (*entity.UserChannel, error)
 	UpdateChannel(ctx context.Context, channel *entity.UserChannel) error
+	DisableChannel(ctx context.Context, channelID int64, reason string) (*entity.UserChannel, error)
 	DestroyChannel(ctx context.Context, channelID int64) error
 	FindChannelsByUser(ctx context.Context, userID int64) (entity.UserChannels, error)
 	FindByChannel(ctx context.Context, userID int64, channel channel.Channel) (*entity.UserChannel, error)
 	Exists(ctx context.Context, userID int64) (bool, error)
+
+	SaveLocale(ctx context.Context, locale *entity.UserLocale) error
+	// FindLocale returns an empty locale when the user has none.
+	FindLocale(ctx context.Context, userID int64) (*entity.UserLocale, error)
 }
 
 type userRepository struct {
@@ -62,6 +69,26 @@ func (r *userRepository) UpdateChannel(ctx context.Context, channel *entity.User
 	return nil
 }
 
+func (r *userRepository) DisableChannel(ctx context.Context, channelID int64, reason string) (*entity.UserChannel, error) {
+	model, err := models.FindUserChannel(ctx, r.db, channelID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, UserChannelNotFound
+		}
+		return nil, fmt.Errorf("failed to find user channel: %w", err)
+	}
+
+	model.CanNotify = false
+	model.DisabledReason = reason
+	model.DisabledAt = null.TimeFrom(time.Now())
+
+	if _, err = model.Update(ctx, r.db, boil.Infer()); err != nil {
+		return nil, fmt.Errorf("failed to disable user channel: %w", err)
+	}
+
+	return r.sqlboilerToEntity(model), nil
+}
+
 func (r *userRepository) DestroyChannel(ctx context.Context, channelID int64) error {
 	if _, err := models.UserChannels(models.UserChannelWhere.ID.EQ(channelID)).DeleteAll(ctx, r.db); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -107,13 +134,45 @@ func (r *userRepository) Exists(ctx context.Context, userID int64) (bool, error)
 	return exist, nil
 }
 
+func (r *userRepository) SaveLocale(ctx context.Context, locale *entity.UserLocale) error {
+	model := &models.UserLocale{
+		UserID:    locale.UserID,
+		Locale:    locale.Locale,
+		TimeZone:  locale.TimeZone,
+		UpdatedAt: time.Now(),
+	}
+	update := boil.Whitelist(
+		models.UserLocaleColumns.Locale,
+		models.UserLocaleColumns.TimeZone,
+		models.UserLocaleColumns.UpdatedAt)
+
+	if err := model.Upsert(ctx, r.db, true, []string{models.UserLocaleColumns.UserID}, update, boil.Infer()); err != nil {
+		return fmt.Errorf("failed to save user locale: %w", err)
+	}
+	return nil
+}
+
+func (r *userRepository) FindLocale(ctx context.Context, userID int64) (*entity.UserLocale, error) {
+	model, err := models.FindUserLocale(ctx, r.db, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &entity.UserLocale{UserID: userID}, nil
+		}
+		return nil, fmt.Errorf("failed to find user locale: %w", err)
+	}
+	return &entity.UserLocale{UserID: model.UserID, Locale: model.Locale, TimeZone: model.TimeZone}, nil
+}
+
 func (r *userRepository) sqlboilerToEntity(data *models.UserChannel) *entity.UserChannel {
 	return &entity.UserChannel{
-		ID:        data.ID,
-		UserID:    data.UserID,
-		Channel:   channel.Channel(data.Channel),
-		Recipient: data.Recipient,
-		CanNotify: data.CanNotify,
+		ID:             data.ID,
+		UserID:         data.UserID,
+		Channel:        channel.Channel(data.Channel),
+		Recipient:      data.Recipient,
+		CanNotify:      data.CanNotify,
+		DisabledReason: data.DisabledReason,
+		DisabledAt:     data.DisabledAt.Ptr(),
+		TrackingOptOut: data.TrackingOptOut,
 	}
 }
 
@@ -129,10 +188,13 @@ func (r *userRepository) sqlboilerToEntities(data models.UserChannelSlice) entit
 
 func (r *userRepository) entityToSqlboiler(data *entity.UserChannel) *models.UserChannel {
 	return &models.UserChannel{
-		ID:        data.ID,
-		UserID:    data.UserID,
-		Channel:   int16(data.Channel),
-		Recipient: data.Recipient,
-		CanNotify: data.CanNotify,
+		ID:             data.ID,
+		UserID:         data.UserID,
+		Channel:        int16(data.Channel),
+		Recipient:      data.Recipient,
+		CanNotify:      data.CanNotify,
+		DisabledReason: data.DisabledReason,
+		DisabledAt:     null.TimeFromPtr(data.DisabledAt),
+		TrackingOptOut: data.TrackingOptOut,
 	}
 }
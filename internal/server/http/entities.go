@@ -1,6 +1,7 @@
 package http
 
 import (
+	"github.com/keweegen/notification/internal/messagetemplate"
 	"github.com/volatiletech/sqlboiler/v4/types"
 	"time"
 )
@@ -18,6 +19,15 @@ type generateMessageIDResponse struct {
 }
 
 type sendMessageRequest struct {
+	Params      types.JSON `json:"params"`
+	Attachments []string   `json:"attachments"`
+	// Locale overrides the locale of the user.
+	Locale string `json:"locale"`
+	// DryRun renders the message as it would be sent without storing and sending it.
+	DryRun bool `json:"dryRun"`
+}
+
+type editMessageRequest struct {
 	Params types.JSON `json:"params"`
 }
 
@@ -28,28 +38,169 @@ type messageResponse struct {
 	StatusTime        time.Time `json:"statusTime"`
 }
 
+type messageEventResponse struct {
+	Type        string    `json:"type"`
+	Description string    `json:"description"`
+	Time        time.Time `json:"time"`
+}
+
 type operationStatus struct {
 	Status            bool   `json:"status"`
 	StatusDescription string `json:"statusDescription"`
 }
 
+type renderTemplateRequest struct {
+	Channel string     `json:"channel"`
+	Params  types.JSON `json:"params"`
+	Locale  string     `json:"locale"`
+}
+
+type previewResponse struct {
+	Channel   string                       `json:"channel"`
+	Locale    string                       `json:"locale"`
+	Format    string                       `json:"format"`
+	Subject   string                       `json:"subject,omitempty"`
+	Preheader string                       `json:"preheader,omitempty"`
+	Headers   map[string]string            `json:"headers,omitempty"`
+	Text      string                       `json:"text"`
+	PlainText string                       `json:"plainText,omitempty"`
+	Media     []previewMediaResponse       `json:"media,omitempty"`
+	Actions   []previewActionResponse      `json:"actions,omitempty"`
+	Warnings  []messagetemplate.FieldError `json:"warnings"`
+}
+
+type previewMediaResponse struct {
+	Kind        string `json:"kind"`
+	URL         string `json:"url,omitempty"`
+	Filename    string `json:"filename,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	Size        int    `json:"size,omitempty"`
+}
+
+type previewActionResponse struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+	URL   string `json:"url,omitempty"`
+}
+
+type paramsErrorResponse struct {
+	Status            bool                         `json:"status"`
+	StatusDescription string                       `json:"statusDescription"`
+	Errors            []messagetemplate.FieldError `json:"errors"`
+}
+
 type userChannelRequest struct {
-	ID        int64  `json:"id"`
-	UserID    int64  `json:"userId"`
-	Channel   string `json:"channel"`
-	Recipient string `json:"recipient"`
-	CanNotify bool   `json:"canNotify"`
+	ID             int64  `json:"id"`
+	UserID         int64  `json:"userId"`
+	Channel        string `json:"channel"`
+	Recipient      string `json:"recipient"`
+	CanNotify      bool   `json:"canNotify"`
+	TrackingOptOut bool   `json:"trackingOptOut"`
 }
 
 type userChannelUpdateRequest struct {
-	Recipient string `json:"recipient"`
-	CanNotify bool   `json:"canNotify"`
+	Recipient      string `json:"recipient"`
+	CanNotify      bool   `json:"canNotify"`
+	TrackingOptOut *bool  `json:"trackingOptOut"`
 }
 
 type userChannelResponse struct {
-	ID        int64  `json:"id"`
-	UserID    int64  `json:"userId"`
-	Channel   string `json:"channel"`
-	Recipient string `json:"recipient"`
-	CanNotify bool   `json:"canNotify"`
+	ID             int64      `json:"id"`
+	UserID         int64      `json:"userId"`
+	Channel        string     `json:"channel"`
+	Recipient      string     `json:"recipient"`
+	CanNotify      bool       `json:"canNotify"`
+	DisabledReason string     `json:"disabledReason,omitempty"`
+	DisabledAt     *time.Time `json:"disabledAt,omitempty"`
+	TrackingOptOut bool       `json:"trackingOptOut"`
+}
+
+type userLocaleRequest struct {
+	Locale   string `json:"locale"`
+	TimeZone string `json:"timeZone"`
+}
+
+type userLocaleResponse struct {
+	UserID   int64  `json:"userId"`
+	Locale   string `json:"locale"`
+	TimeZone string `json:"timeZone"`
+}
+
+type telegramLinkResponse struct {
+	Link      string    `json:"link"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type fileResponse struct {
+	ID          string    `json:"id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type emailEventsRequest struct {
+	Events []emailEvent `json:"events"`
+}
+
+type emailEvent struct {
+	Type        string `json:"type"`
+	Recipient   string `json:"recipient"`
+	MessageID   string `json:"messageId"`
+	Description string `json:"description"`
+	Permanent   bool   `json:"permanent"`
+}
+
+type suppressionResponse struct {
+	Channel     string    `json:"channel"`
+	Recipient   string    `json:"recipient"`
+	Reason      string    `json:"reason"`
+	Description string    `json:"description"`
+	MessageID   string    `json:"messageId,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type engagementResponse struct {
+	MessageTemplate string  `json:"messageTemplate"`
+	Sent            int64   `json:"sent"`
+	Opened          int64   `json:"opened"`
+	Clicked         int64   `json:"clicked"`
+	Opens           int64   `json:"opens"`
+	Clicks          int64   `json:"clicks"`
+	OpenRate        float64 `json:"openRate"`
+	ClickRate       float64 `json:"clickRate"`
+}
+
+type templateRequest struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Tracking bool   `json:"tracking"`
+}
+
+type templateResponse struct {
+	ID             int       `json:"id"`
+	Name           string    `json:"name"`
+	Category       string    `json:"category"`
+	Tracking       bool      `json:"tracking"`
+	CurrentVersion int       `json:"currentVersion"`
+	BuiltIn        bool      `json:"builtIn"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+type templateVersionRequest struct {
+	ParamsSchema  types.JSON               `json:"paramsSchema"`
+	Bodies        messagetemplate.Bodies   `json:"bodies"`
+	Catalog       *messagetemplate.Catalog `json:"catalog"`
+	ExampleParams types.JSON               `json:"exampleParams"`
+}
+
+type templateVersionResponse struct {
+	TemplateID    int                      `json:"templateId"`
+	Version       int                      `json:"version"`
+	ParamsSchema  types.JSON               `json:"paramsSchema"`
+	Bodies        messagetemplate.Bodies   `json:"bodies"`
+	Catalog       *messagetemplate.Catalog `json:"catalog,omitempty"`
+	ExampleParams types.JSON               `json:"exampleParams,omitempty"`
+	CreatedAt     time.Time                `json:"createdAt"`
 }
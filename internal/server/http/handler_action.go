@@ -0,0 +1,88 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/keweegen/notification/internal/service"
+	"html/template"
+)
+
+// actionPage is shown to the user following a tracked button, the form posts back to the same address.
+// Recording the press on GET is avoided because mail scanners and link previews open the links
+// of incoming messages, and scanners running scripts would submit the form, so it takes a click.
+var actionPage = template.Must(template.New("action").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Action</title></head>
+<body>
+{{if .Confirm}}
+<form method="post">
+    <button type="submit">Continue</button>
+</form>
+{{else}}
+<p>{{.Text}}</p>
+{{end}}
+</body>
+</html>`))
+
+type actionPageData struct {
+	Confirm bool
+	Text    string
+}
+
+type actionHandler struct {
+	services *service.Store
+}
+
+func (h *actionHandler) init(services *service.Store) *actionHandler {
+	h.services = services
+	return h
+}
+
+// Confirm shows the confirmation page of a tracked button.
+func (h *actionHandler) Confirm(c *fiber.Ctx) error {
+	redirectURL, err := h.services.Message.ActionURL(c.Params("token"))
+	if err != nil {
+		return h.sendError(c, err, redirectURL)
+	}
+
+	return h.sendPage(c, fiber.StatusOK, actionPageData{Confirm: true})
+}
+
+// Click records the press of a tracked button and redirects to the action URL.
+func (h *actionHandler) Click(c *fiber.Ctx) error {
+	redirectURL, err := h.services.Message.HandleClick(c.Context(), c.Params("token"))
+	if err != nil {
+		return h.sendError(c, err, redirectURL)
+	}
+
+	if redirectURL == "" {
+		return h.sendPage(c, fiber.StatusOK, actionPageData{Text: "Thank you, your answer has been recorded."})
+	}
+
+	return c.Redirect(redirectURL, fiber.StatusSeeOther)
+}
+
+// sendError maps the errors of the token, expired links of URL actions still redirect without being recorded.
+func (h *actionHandler) sendError(c *fiber.Ctx, err error, redirectURL string) error {
+	switch {
+	case errors.Is(err, service.ExpiredActionTokenErr) && redirectURL != "":
+		return c.Redirect(redirectURL, fiber.StatusFound)
+	case errors.Is(err, service.ExpiredActionTokenErr):
+		return h.sendPage(c, fiber.StatusGone, actionPageData{Text: "The link has expired."})
+	case errors.Is(err, service.InvalidActionTokenErr), errors.Is(err, service.MessageNotFoundErr):
+		return h.sendPage(c, fiber.StatusNotFound, actionPageData{Text: "The link is invalid."})
+	default:
+		return sendError(c, err)
+	}
+}
+
+func (h *actionHandler) sendPage(c *fiber.Ctx, statusCode int, data actionPageData) error {
+	var page bytes.Buffer
+	if err := actionPage.Execute(&page, data); err != nil {
+		return sendServerError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.Status(statusCode).Send(page.Bytes())
+}
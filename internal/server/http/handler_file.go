@@ -0,0 +1,59 @@
+package http
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/keweegen/notification/internal/service"
+	"io"
+)
+
+const uploadFormField = "file"
+
+type fileHandler struct {
+	services *service.Store
+}
+
+func (h *fileHandler) init(services *service.Store) *fileHandler {
+	h.services = services
+	return h
+}
+
+func (h *fileHandler) Upload(c *fiber.Ctx) error {
+	header, err := c.FormFile(uploadFormField)
+	if err != nil {
+		return sendBadRequest(c, err)
+	}
+	if header.Size > h.services.File.MaxSize() {
+		return sendError(c, service.FileTooLargeErr, fiber.StatusRequestEntityTooLarge)
+	}
+
+	f, err := header.Open()
+	if err != nil {
+		return sendServerError(c, err)
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, h.services.File.MaxSize()+1))
+	if err != nil {
+		return sendServerError(c, err)
+	}
+
+	file, err := h.services.File.Upload(c.Context(), header.Filename, data)
+	if err != nil {
+		if errors.Is(err, service.FileTooLargeErr) {
+			return sendError(c, err, fiber.StatusRequestEntityTooLarge)
+		}
+		if errors.Is(err, service.EmptyFileErr) {
+			return sendBadRequest(c, err)
+		}
+		return sendError(c, err)
+	}
+
+	return sendSuccess(c, fileResponse{
+		ID:          file.ID,
+		Filename:    file.Filename,
+		ContentType: file.ContentType,
+		Size:        file.Size,
+		CreatedAt:   file.CreatedAt,
+	})
+}
@@ -1,9 +1,11 @@
 package http
 
 import (
+    "errors"
     "github.com/gofiber/fiber/v2"
     "github.com/keweegen/notification/internal/channel"
     "github.com/keweegen/notification/internal/messagetemplate"
+    "github.com/keweegen/notification/internal/repository"
     "github.com/keweegen/notification/internal/service"
 )
 
@@ -28,9 +30,9 @@ func (h *messageHandler) GenerateID(c *fiber.Ctx) error {
         return sendError(c, service.InvalidChannelErr)
     }
 
-    mt, ok := messagetemplate.GetMessageTemplateTypeFromString(requestData.MessageTemplate)
-    if !ok {
-        return sendError(c, service.InvalidMessageTemplateErr)
+    mt, err := h.services.Template.FindID(c.Context(), requestData.MessageTemplate)
+    if err != nil {
+        return sendError(c, err)
     }
 
     id := h.services.Message.GenerateID(
@@ -51,9 +53,19 @@ func (h *messageHandler) Send(c *fiber.Ctx) error {
         return sendBadRequest(c, err)
     }
 
-    messageID, err := h.services.Message.Send(c.Context(), messageID, requestData.Params)
+    if requestData.DryRun {
+        preview, err := h.services.Message.DryRun(
+            c.Context(), messageID, requestData.Params, requestData.Attachments, requestData.Locale)
+        if err != nil {
+            return h.sendSendError(c, err)
+        }
+        return sendPreview(c, preview)
+    }
+
+    messageID, err := h.services.Message.Send(
+        c.Context(), messageID, requestData.Params, requestData.Attachments, requestData.Locale)
     if err != nil {
-        return sendError(c, err)
+        return h.sendSendError(c, err)
     }
 
     status, err := h.services.Message.GetStatus(c.Context(), messageID)
@@ -82,3 +94,88 @@ func (h *messageHandler) GetStatus(c *fiber.Ctx) error {
         StatusTime:        status.CreatedAt,
     })
 }
+
+func (h *messageHandler) GetEvents(c *fiber.Ctx) error {
+    events, err := h.services.Message.GetEvents(c.Context(), c.Params("messageId"))
+    if err != nil {
+        if errors.Is(err, service.MessageNotFoundErr) {
+            return sendError(c, err, fiber.StatusNotFound)
+        }
+        return sendError(c, err)
+    }
+
+    response := make([]messageEventResponse, 0, len(events))
+    for _, e := range events {
+        response = append(response, messageEventResponse{
+            Type:        e.Type,
+            Description: e.Description,
+            Time:        e.CreatedAt,
+        })
+    }
+
+    return sendSuccess(c, response)
+}
+
+// Document responds with the document generated for the message, such as the PDF copy of a receipt.
+func (h *messageHandler) Document(c *fiber.Ctx) error {
+    file, err := h.services.Document.Find(c.Context(), c.Params("messageId"))
+    if err != nil {
+        if errors.Is(err, service.DocumentNotFoundErr) {
+            return sendError(c, err, fiber.StatusNotFound)
+        }
+        return sendServerError(c, err)
+    }
+
+    c.Attachment(file.Filename)
+    c.Set(fiber.HeaderContentType, file.ContentType)
+    return c.Status(fiber.StatusOK).Send(file.Data)
+}
+
+func (h *messageHandler) Edit(c *fiber.Ctx) error {
+    messageID := c.Params("messageId")
+    requestData := new(editMessageRequest)
+
+    if err := c.BodyParser(&requestData); err != nil {
+        return sendBadRequest(c, err)
+    }
+
+    if err := h.services.Message.Edit(c.Context(), messageID, requestData.Params); err != nil {
+        return h.sendEditError(c, err)
+    }
+
+    return h.GetStatus(c)
+}
+
+func (h *messageHandler) Delete(c *fiber.Ctx) error {
+    if err := h.services.Message.Delete(c.Context(), c.Params("messageId")); err != nil {
+        return h.sendEditError(c, err)
+    }
+
+    return h.GetStatus(c)
+}
+
+// sendSendError maps the errors of sending a message, the recipient errors come from dry runs only.
+func (h *messageHandler) sendSendError(c *fiber.Ctx, err error) error {
+    switch {
+    case errors.Is(err, service.AttachmentNotFoundErr), errors.Is(err, service.InvalidLocaleErr):
+        return sendBadRequest(c, err)
+    case errors.Is(err, messagetemplate.InvalidParamsErr):
+        return sendParamsError(c, err)
+    case errors.Is(err, service.RenderFailedErr), errors.Is(err, service.TemplateWithoutVersionErr):
+        return sendError(c, err, fiber.StatusUnprocessableEntity)
+    case errors.Is(err, repository.UserChannelNotFound), errors.Is(err, service.CannotNotifyErr),
+        errors.Is(err, service.RecipientSuppressedErr), errors.Is(err, service.RecipientUnsubscribedErr):
+        return sendError(c, err, fiber.StatusConflict)
+    }
+    return sendError(c, err)
+}
+
+func (h *messageHandler) sendEditError(c *fiber.Ctx, err error) error {
+    if errors.Is(err, channel.EditUnsupportedErr) || errors.Is(err, service.MessageNotDeliveredErr) {
+        return sendBadRequest(c, err)
+    }
+    if errors.Is(err, messagetemplate.InvalidParamsErr) {
+        return sendParamsError(c, err)
+    }
+    return sendError(c, err)
+}
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/keweegen/notification/internal/channel"
+	"github.com/keweegen/notification/internal/channel/email"
+	"github.com/keweegen/notification/internal/entity"
+	"github.com/keweegen/notification/internal/repository"
+	"github.com/keweegen/notification/internal/service"
+	"net/url"
+	"strconv"
+)
+
+const webhookSecretHeader = "X-Webhook-Secret"
+
+type suppressionHandler struct {
+	services *service.Store
+}
+
+func (h *suppressionHandler) init(services *service.Store) *suppressionHandler {
+	h.services = services
+	return h
+}
+
+// EmailEvents receives bounce and complaint events from the mail provider.
+func (h *suppressionHandler) EmailEvents(c *fiber.Ctx) error {
+	if !h.services.Suppression.ValidateWebhookSecret(c.Get(webhookSecretHeader)) {
+		return sendError(c, fiber.ErrForbidden, fiber.StatusForbidden)
+	}
+
+	requestData := new(emailEventsRequest)
+	if err := c.BodyParser(requestData); err != nil {
+		return sendBadRequest(c, err)
+	}
+
+	for _, event := range requestData.Events {
+		feedback := email.Feedback{
+			Type:        event.Type,
+			Recipient:   event.Recipient,
+			MessageID:   event.MessageID,
+			Description: event.Description,
+			Permanent:   event.Permanent || event.Type == email.FeedbackComplaint,
+		}
+		if err := h.services.Suppression.Record(c.Context(), channel.Email, feedback); err != nil {
+			if errors.Is(err, service.InvalidFeedbackErr) {
+				return sendBadRequest(c, err)
+			}
+			return sendError(c, err)
+		}
+	}
+
+	return sendSuccess(c, operationStatus{
+		Status:            true,
+		StatusDescription: "Events recorded successfully",
+	})
+}
+
+func (h *suppressionHandler) All(c *fiber.Ctx) error {
+	ch, ok := channel.GetChannelTypeFromString(c.Params("channel"))
+	if !ok {
+		return sendBadRequest(c, service.InvalidChannelErr)
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	offset, _ := strconv.Atoi(c.Query("offset"))
+
+	suppressions, err := h.services.Suppression.FindAll(c.Context(), ch, limit, offset)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	response := make([]*suppressionResponse, 0, len(suppressions))
+	for _, s := range suppressions {
+		response = append(response, h.suppressionToResponse(s))
+	}
+
+	return sendSuccess(c, response)
+}
+
+func (h *suppressionHandler) Read(c *fiber.Ctx) error {
+	ch, recipient, err := h.params(c)
+	if err != nil {
+		return sendBadRequest(c, err)
+	}
+
+	suppression, err := h.services.Suppression.Find(c.Context(), ch, recipient)
+	if err != nil {
+		if errors.Is(err, repository.SuppressionNotFound) {
+			return sendError(c, err, fiber.StatusNotFound)
+		}
+		return sendError(c, err)
+	}
+
+	return sendSuccess(c, h.suppressionToResponse(suppression))
+}
+
+func (h *suppressionHandler) Lift(c *fiber.Ctx) error {
+	ch, recipient, err := h.params(c)
+	if err != nil {
+		return sendBadRequest(c, err)
+	}
+
+	if err = h.services.Suppression.Lift(c.Context(), ch, recipient); err != nil {
+		if errors.Is(err, repository.SuppressionNotFound) {
+			return sendError(c, err, fiber.StatusNotFound)
+		}
+		return sendError(c, err)
+	}
+
+	return sendSuccess(c, operationStatus{
+		Status:            true,
+		StatusDescription: "Suppression lifted successfully",
+	})
+}
+
+// -- Helpers
+
+func (h *suppressionHandler) params(c *fiber.Ctx) (channel.Channel, string, error) {
+	ch, ok := channel.GetChannelTypeFromString(c.Params("channel"))
+	if !ok {
+		return 0, "", service.InvalidChannelErr
+	}
+
+	recipient, err := url.PathUnescape(c.Params("recipient"))
+	if err != nil {
+		return 0, "", err
+	}
+
+	return ch, recipient, nil
+}
+
+func (h *suppressionHandler) suppressionToResponse(s *entity.Suppression) *suppressionResponse {
+	return &suppressionResponse{
+		Channel:     s.Channel.String(),
+		Recipient:   s.Recipient,
+		Reason:      s.Reason,
+		Description: s.Description,
+		MessageID:   s.MessageID,
+		CreatedAt:   s.CreatedAt,
+	}
+}
@@ -0,0 +1,47 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/keweegen/notification/internal/channel/telegram"
+	"github.com/keweegen/notification/internal/service"
+)
+
+const telegramSecretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+type telegramHandler struct {
+	services *service.Store
+}
+
+func (h *telegramHandler) init(services *service.Store) *telegramHandler {
+	h.services = services
+	return h
+}
+
+func (h *telegramHandler) IssueLink(c *fiber.Ctx) error {
+	userID, err := c.ParamsInt("userId")
+	if err != nil {
+		return sendBadRequest(c, err)
+	}
+
+	link, expiresAt, err := h.services.Telegram.IssueLink(c.Context(), int64(userID))
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	return sendSuccess(c, telegramLinkResponse{Link: link, ExpiresAt: expiresAt})
+}
+
+func (h *telegramHandler) Webhook(c *fiber.Ctx) error {
+	if !h.services.Telegram.ValidateWebhookSecret(c.Get(telegramSecretTokenHeader)) {
+		return sendError(c, fiber.ErrForbidden, fiber.StatusForbidden)
+	}
+
+	update := new(telegram.Update)
+	if err := c.BodyParser(update); err != nil {
+		return sendBadRequest(c, err)
+	}
+
+	h.services.Telegram.HandleUpdate(c.Context(), *update)
+
+	return c.SendStatus(fiber.StatusOK)
+}
@@ -0,0 +1,275 @@
+package http
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/keweegen/notification/internal/channel"
+	"github.com/keweegen/notification/internal/entity"
+	"github.com/keweegen/notification/internal/messagetemplate"
+	"github.com/keweegen/notification/internal/repository"
+	"github.com/keweegen/notification/internal/service"
+	"github.com/volatiletech/sqlboiler/v4/types"
+)
+
+type templateHandler struct {
+	services *service.Store
+}
+
+func (h *templateHandler) init(services *service.Store) *templateHandler {
+	h.services = services
+	return h
+}
+
+func (h *templateHandler) All(c *fiber.Ctx) error {
+	templates, err := h.services.Template.FindAll(c.Context())
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	response := make([]*templateResponse, 0, len(templates))
+	for _, t := range templates {
+		response = append(response, h.templateToResponse(t))
+	}
+
+	return sendSuccess(c, response)
+}
+
+func (h *templateHandler) Create(c *fiber.Ctx) error {
+	requestData := new(templateRequest)
+	if err := c.BodyParser(requestData); err != nil {
+		return sendBadRequest(c, err)
+	}
+
+	template := h.templateRequestToEntity(requestData)
+	if err := h.services.Template.Create(c.Context(), template); err != nil {
+		return h.sendError(c, err)
+	}
+
+	return sendSuccess(c, h.templateToResponse(template))
+}
+
+func (h *templateHandler) Read(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("templateId")
+	if err != nil {
+		return sendBadRequest(c, err)
+	}
+
+	template, err := h.services.Template.Find(c.Context(), messagetemplate.MessageTemplate(id))
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return sendSuccess(c, h.templateToResponse(template))
+}
+
+func (h *templateHandler) Update(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("templateId")
+	if err != nil {
+		return sendBadRequest(c, err)
+	}
+
+	requestData := new(templateRequest)
+	if err = c.BodyParser(requestData); err != nil {
+		return sendBadRequest(c, err)
+	}
+
+	template := h.templateRequestToEntity(requestData)
+	template.ID = messagetemplate.MessageTemplate(id)
+	if err = h.services.Template.Update(c.Context(), template); err != nil {
+		return h.sendError(c, err)
+	}
+
+	return sendSuccess(c, operationStatus{
+		Status:            true,
+		StatusDescription: "Template updated successfully",
+	})
+}
+
+func (h *templateHandler) Destroy(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("templateId")
+	if err != nil {
+		return sendBadRequest(c, err)
+	}
+
+	if err = h.services.Template.Delete(c.Context(), messagetemplate.MessageTemplate(id)); err != nil {
+		return h.sendError(c, err)
+	}
+
+	return sendSuccess(c, operationStatus{
+		Status:            true,
+		StatusDescription: "Template deleted successfully",
+	})
+}
+
+func (h *templateHandler) AllVersions(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("templateId")
+	if err != nil {
+		return sendBadRequest(c, err)
+	}
+
+	versions, err := h.services.Template.FindVersions(c.Context(), messagetemplate.MessageTemplate(id))
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	response := make([]*templateVersionResponse, 0, len(versions))
+	for _, v := range versions {
+		response = append(response, h.versionToResponse(v))
+	}
+
+	return sendSuccess(c, response)
+}
+
+// CreateVersion publishes new bodies of the template, messages sent from now on are rendered with them.
+func (h *templateHandler) CreateVersion(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("templateId")
+	if err != nil {
+		return sendBadRequest(c, err)
+	}
+
+	requestData := new(templateVersionRequest)
+	if err = c.BodyParser(requestData); err != nil {
+		return sendBadRequest(c, err)
+	}
+
+	version := &entity.TemplateVersion{
+		TemplateID:    messagetemplate.MessageTemplate(id),
+		ParamsSchema:  requestData.ParamsSchema,
+		Bodies:        requestData.Bodies,
+		Catalog:       requestData.Catalog,
+		ExampleParams: requestData.ExampleParams,
+	}
+	if err = h.services.Template.CreateVersion(c.Context(), version); err != nil {
+		return h.sendError(c, err)
+	}
+
+	return sendSuccess(c, h.versionToResponse(version))
+}
+
+func (h *templateHandler) ReadVersion(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("templateId")
+	if err != nil {
+		return sendBadRequest(c, err)
+	}
+	number, err := c.ParamsInt("version")
+	if err != nil {
+		return sendBadRequest(c, err)
+	}
+
+	version, err := h.services.Template.FindVersion(c.Context(), messagetemplate.MessageTemplate(id), number)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return sendSuccess(c, h.versionToResponse(version))
+}
+
+// Schema returns the JSON Schema the params of the messages sent with the template are validated with,
+// an empty schema when the template accepts any params.
+func (h *templateHandler) Schema(c *fiber.Ctx) error {
+	id, err := h.services.Template.FindID(c.Context(), c.Params("name"))
+	if err != nil {
+		if errors.Is(err, service.InvalidMessageTemplateErr) {
+			return sendError(c, err, fiber.StatusNotFound)
+		}
+		return sendError(c, err)
+	}
+
+	version, err := h.services.Template.CurrentVersion(c.Context(), id)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+	schema, err := h.services.Template.ParamsSchema(c.Context(), id, version)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+	if len(schema) == 0 {
+		schema = types.JSON(`{}`)
+	}
+
+	return sendSuccess(c, schema)
+}
+
+// Render previews the current version of the template for a channel, params not matching the params
+// schema are reported as warnings.
+func (h *templateHandler) Render(c *fiber.Ctx) error {
+	requestData := new(renderTemplateRequest)
+	if err := c.BodyParser(requestData); err != nil {
+		return sendBadRequest(c, err)
+	}
+
+	ch, ok := channel.GetChannelTypeFromString(requestData.Channel)
+	if !ok {
+		return sendBadRequest(c, service.InvalidChannelErr)
+	}
+
+	id, err := h.services.Template.FindID(c.Context(), c.Params("name"))
+	if err != nil {
+		if errors.Is(err, service.InvalidMessageTemplateErr) {
+			return sendError(c, err, fiber.StatusNotFound)
+		}
+		return sendError(c, err)
+	}
+
+	preview, err := h.services.Message.Preview(c.Context(), id, ch, requestData.Params, requestData.Locale)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.InvalidLocaleErr), errors.Is(err, service.InvalidChannelErr):
+			return sendBadRequest(c, err)
+		case errors.Is(err, service.RenderFailedErr):
+			return sendError(c, err, fiber.StatusUnprocessableEntity)
+		}
+		return h.sendError(c, err)
+	}
+
+	return sendPreview(c, preview)
+}
+
+// -- Helpers
+
+func (h *templateHandler) sendError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, repository.TemplateNotFound), errors.Is(err, repository.TemplateVersionNotFound):
+		return sendError(c, err, fiber.StatusNotFound)
+	case errors.Is(err, service.InvalidTemplateErr):
+		return sendBadRequest(c, err)
+	case errors.Is(err, service.BuiltInTemplateErr), errors.Is(err, service.TemplateConflictErr):
+		return sendError(c, err, fiber.StatusConflict)
+	case errors.Is(err, service.TemplateWithoutVersionErr):
+		return sendError(c, err, fiber.StatusUnprocessableEntity)
+	}
+	return sendError(c, err)
+}
+
+func (h *templateHandler) templateRequestToEntity(data *templateRequest) *entity.Template {
+	return &entity.Template{
+		Name:     data.Name,
+		Category: messagetemplate.Category(data.Category),
+		Tracking: data.Tracking,
+	}
+}
+
+func (h *templateHandler) templateToResponse(t *entity.Template) *templateResponse {
+	return &templateResponse{
+		ID:             int(t.ID),
+		Name:           t.Name,
+		Category:       string(t.Category),
+		Tracking:       t.Tracking,
+		CurrentVersion: t.CurrentVersion,
+		BuiltIn:        t.ID.IsValid(),
+		CreatedAt:      t.CreatedAt,
+		UpdatedAt:      t.UpdatedAt,
+	}
+}
+
+func (h *templateHandler) versionToResponse(v *entity.TemplateVersion) *templateVersionResponse {
+	return &templateVersionResponse{
+		TemplateID:    int(v.TemplateID),
+		Version:       v.Version,
+		ParamsSchema:  v.ParamsSchema,
+		Bodies:        v.Bodies,
+		Catalog:       v.Catalog,
+		ExampleParams: v.ExampleParams,
+		CreatedAt:     v.CreatedAt,
+	}
+}
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/keweegen/notification/internal/entity"
+	"github.com/keweegen/notification/internal/service"
+	"time"
+)
+
+const (
+	reportDateLayout    = "2006-01-02"
+	defaultReportPeriod = 30 * 24 * time.Hour
+)
+
+// trackingPixel is a transparent 1x1 GIF.
+var trackingPixel = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+type trackingHandler struct {
+	services *service.Store
+}
+
+func (h *trackingHandler) init(services *service.Store) *trackingHandler {
+	h.services = services
+	return h
+}
+
+// Open records the open of a tracked email and responds with the tracking pixel.
+func (h *trackingHandler) Open(c *fiber.Ctx) error {
+	err := h.services.Message.HandleOpen(c.Context(), c.Params("messageId"), c.Query("s"))
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "image/gif")
+	c.Set(fiber.HeaderCacheControl, "no-store, no-cache, must-revalidate")
+	return c.Status(fiber.StatusOK).Send(trackingPixel)
+}
+
+// Click records the click of a tracked link and redirects to the link.
+func (h *trackingHandler) Click(c *fiber.Ctx) error {
+	link := c.Query("u")
+
+	err := h.services.Message.HandleTrackedClick(c.Context(), c.Params("messageId"), link, c.Query("s"))
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return c.Redirect(link, fiber.StatusFound)
+}
+
+// Engagements reports the opens and clicks by template for the emails created
+// between the dateFrom and dateTo days, the last 30 days by default.
+func (h *trackingHandler) Engagements(c *fiber.Ctx) error {
+	dateTo := time.Now()
+	dateFrom := dateTo.Add(-defaultReportPeriod)
+
+	var err error
+	if value := c.Query("dateFrom"); value != "" {
+		if dateFrom, err = time.Parse(reportDateLayout, value); err != nil {
+			return sendBadRequest(c, fmt.Errorf("dateFrom: %w", err))
+		}
+	}
+	if value := c.Query("dateTo"); value != "" {
+		if dateTo, err = time.Parse(reportDateLayout, value); err != nil {
+			return sendBadRequest(c, fmt.Errorf("dateTo: %w", err))
+		}
+		dateTo = dateTo.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+
+	engagements, err := h.services.Message.Engagements(c.Context(), dateFrom, dateTo)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	response := make([]*engagementResponse, 0, len(engagements))
+	for _, e := range engagements {
+		response = append(response, h.engagementToResponse(e))
+	}
+
+	return sendSuccess(c, response)
+}
+
+// -- Helpers
+
+func (h *trackingHandler) sendError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, service.InvalidTrackingSignatureErr) || errors.Is(err, service.MessageNotFoundErr) {
+		return sendError(c, err, fiber.StatusNotFound)
+	}
+	return sendError(c, err)
+}
+
+func (h *trackingHandler) engagementToResponse(e *entity.Engagement) *engagementResponse {
+	response := &engagementResponse{
+		MessageTemplate: e.MessageTemplate.String(),
+		Sent:            e.Sent,
+		Opened:          e.Opened,
+		Clicked:         e.Clicked,
+		Opens:           e.Opens,
+		Clicks:          e.Clicks,
+	}
+	if e.Sent > 0 {
+		response.OpenRate = float64(e.Opened) / float64(e.Sent)
+		response.ClickRate = float64(e.Clicked) / float64(e.Sent)
+	}
+	return response
+}
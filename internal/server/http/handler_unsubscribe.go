@@ -0,0 +1,79 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/keweegen/notification/internal/service"
+	"html/template"
+)
+
+// unsubscribePage is shown to the user following the link, the form posts back to the same address.
+// Unsubscribing on GET is avoided because mail scanners open the links of incoming messages.
+var unsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Unsubscribe</title></head>
+<body>
+{{if .Confirm}}
+<form method="post">
+    <p>Stop receiving these messages?</p>
+    <button type="submit">Unsubscribe</button>
+</form>
+{{else}}
+<p>{{.Text}}</p>
+{{end}}
+</body>
+</html>`))
+
+type unsubscribePageData struct {
+	Confirm bool
+	Text    string
+}
+
+type unsubscribeHandler struct {
+	services *service.Store
+}
+
+func (h *unsubscribeHandler) init(services *service.Store) *unsubscribeHandler {
+	h.services = services
+	return h
+}
+
+// Confirm shows the unsubscribe confirmation page.
+func (h *unsubscribeHandler) Confirm(c *fiber.Ctx) error {
+	if err := h.services.Preference.ValidateUnsubscribeToken(c.Params("token")); err != nil {
+		return h.sendError(c, err)
+	}
+
+	return h.sendPage(c, fiber.StatusOK, unsubscribePageData{Confirm: true})
+}
+
+// Unsubscribe handles the confirmation form and the one-click requests of mail clients (RFC 8058).
+func (h *unsubscribeHandler) Unsubscribe(c *fiber.Ctx) error {
+	if err := h.services.Preference.Unsubscribe(c.Context(), c.Params("token")); err != nil {
+		return h.sendError(c, err)
+	}
+
+	return h.sendPage(c, fiber.StatusOK, unsubscribePageData{Text: "You have been unsubscribed."})
+}
+
+func (h *unsubscribeHandler) sendError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.InvalidUnsubscribeTokenErr):
+		return h.sendPage(c, fiber.StatusNotFound, unsubscribePageData{Text: "The unsubscribe link is invalid."})
+	case errors.Is(err, service.ExpiredUnsubscribeTokenErr):
+		return h.sendPage(c, fiber.StatusGone, unsubscribePageData{Text: "The unsubscribe link has expired."})
+	default:
+		return sendError(c, err)
+	}
+}
+
+func (h *unsubscribeHandler) sendPage(c *fiber.Ctx, statusCode int, data unsubscribePageData) error {
+	var page bytes.Buffer
+	if err := unsubscribePage.Execute(&page, data); err != nil {
+		return sendServerError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.Status(statusCode).Send(page.Bytes())
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"github.com/gofiber/fiber/v2"
 	"github.com/keweegen/notification/internal/channel"
 	"github.com/keweegen/notification/internal/entity"
@@ -63,7 +64,8 @@ func (h *userHandler) UpdateChannel(c *fiber.Ctx) error {
 
 	err = h.services.
 		User.
-		UpdateNotificationChannel(c.Context(), int64(channelID), requestData.Recipient, requestData.CanNotify)
+		UpdateNotificationChannel(c.Context(), int64(channelID),
+			requestData.Recipient, requestData.CanNotify, requestData.TrackingOptOut)
 	if err != nil {
 		return sendError(c, err)
 	}
@@ -103,15 +105,54 @@ func (h *userHandler) AllChannelsByUser(c *fiber.Ctx) error {
 	return sendSuccess(c, h.userChannelsToResponse(channels))
 }
 
+func (h *userHandler) ReadLocale(c *fiber.Ctx) error {
+	userID, err := c.ParamsInt("userId")
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	locale, err := h.services.User.FindLocale(c.Context(), int64(userID))
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	return sendSuccess(c, h.userLocaleToResponse(locale))
+}
+
+func (h *userHandler) UpdateLocale(c *fiber.Ctx) error {
+	userID, err := c.ParamsInt("userId")
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	requestData := new(userLocaleRequest)
+	if err = c.BodyParser(requestData); err != nil {
+		return sendBadRequest(c, err)
+	}
+
+	locale, err := h.services.User.SetLocale(c.Context(), int64(userID), requestData.Locale, requestData.TimeZone)
+	if err != nil {
+		if errors.Is(err, service.InvalidLocaleErr) || errors.Is(err, service.InvalidTimeZoneErr) {
+			return sendBadRequest(c, err)
+		}
+		return sendError(c, err)
+	}
+
+	return sendSuccess(c, h.userLocaleToResponse(locale))
+}
+
 // -- Helpers
 
 func (h *userHandler) userChannelToResponse(channel *entity.UserChannel) *userChannelResponse {
 	return &userChannelResponse{
-		ID:        channel.ID,
-		UserID:    channel.UserID,
-		Channel:   channel.Channel.String(),
-		Recipient: channel.Recipient,
-		CanNotify: channel.CanNotify,
+		ID:             channel.ID,
+		UserID:         channel.UserID,
+		Channel:        channel.Channel.String(),
+		Recipient:      channel.Recipient,
+		CanNotify:      channel.CanNotify,
+		DisabledReason: channel.DisabledReason,
+		DisabledAt:     channel.DisabledAt,
+		TrackingOptOut: channel.TrackingOptOut,
 	}
 }
 
@@ -122,11 +163,12 @@ func (h *userHandler) userChannelRequestToEntity(channelRequest *userChannelRequ
 	}
 
 	return &entity.UserChannel{
-		ID:        channelRequest.ID,
-		UserID:    channelRequest.UserID,
-		Channel:   ch,
-		Recipient: channelRequest.Recipient,
-		CanNotify: channelRequest.CanNotify,
+		ID:             channelRequest.ID,
+		UserID:         channelRequest.UserID,
+		Channel:        ch,
+		Recipient:      channelRequest.Recipient,
+		CanNotify:      channelRequest.CanNotify,
+		TrackingOptOut: channelRequest.TrackingOptOut,
 	}, nil
 }
 
@@ -139,3 +181,11 @@ func (h *userHandler) userChannelsToResponse(channels entity.UserChannels) []*us
 
 	return response
 }
+
+func (h *userHandler) userLocaleToResponse(locale *entity.UserLocale) *userLocaleResponse {
+	return &userLocaleResponse{
+		UserID:   locale.UserID,
+		Locale:   locale.Locale,
+		TimeZone: locale.TimeZone,
+	}
+}
@@ -1,6 +1,13 @@
 package http
 
-import "github.com/gofiber/fiber/v2"
+import (
+    "errors"
+    "github.com/gofiber/fiber/v2"
+    "github.com/keweegen/notification/internal/channel/outbound"
+    "github.com/keweegen/notification/internal/entity"
+    "github.com/keweegen/notification/internal/messagetemplate"
+    "strings"
+)
 
 func sendSuccess(c *fiber.Ctx, data any) error {
     return c.Status(fiber.StatusOK).JSON(data)
@@ -10,6 +17,71 @@ func sendBadRequest(c *fiber.Ctx, err error) error {
     return sendError(c, err, fiber.StatusBadRequest)
 }
 
+// sendParamsError responds with the fields of the params not matching the params schema of the template.
+func sendParamsError(c *fiber.Ctx, err error) error {
+    response := paramsErrorResponse{StatusDescription: err.Error()}
+
+    var paramsErr *messagetemplate.ParamsError
+    if errors.As(err, &paramsErr) {
+        response.Errors = paramsErr.Errors
+    }
+
+    return c.Status(fiber.StatusUnprocessableEntity).JSON(response)
+}
+
+// sendPreview responds with the content of a message rendered without being sent.
+func sendPreview(c *fiber.Ctx, preview *entity.Preview) error {
+    content := preview.Content
+    response := previewResponse{
+        Channel:   strings.ToLower(preview.Channel.String()),
+        Locale:    preview.Locale,
+        Format:    formatName(content.Format),
+        Subject:   content.Subject,
+        Preheader: content.Preheader,
+        Headers:   content.Headers,
+        Text:      content.Text,
+        PlainText: content.PlainText,
+        Warnings:  preview.Warnings,
+    }
+    if response.Warnings == nil {
+        response.Warnings = make([]messagetemplate.FieldError, 0)
+    }
+
+    for _, media := range content.Media {
+        kind := "photo"
+        if media.Kind == outbound.Document {
+            kind = "document"
+        }
+        response.Media = append(response.Media, previewMediaResponse{
+            Kind:        kind,
+            URL:         media.URL,
+            Filename:    media.Filename,
+            ContentType: media.ContentType,
+            Size:        len(media.Data),
+        })
+    }
+    for _, action := range content.Actions {
+        response.Actions = append(response.Actions, previewActionResponse{
+            ID:    action.ID,
+            Label: action.Label,
+            URL:   action.URL,
+        })
+    }
+
+    return sendSuccess(c, response)
+}
+
+func formatName(format outbound.Format) string {
+    switch format {
+    case outbound.FormatPlain:
+        return "plain"
+    case outbound.FormatMarkdownV2:
+        return "markdownV2"
+    default:
+        return "html"
+    }
+}
+
 func sendServerError(c *fiber.Ctx, err error) error {
     return sendError(c, err, fiber.StatusInternalServerError)
 }
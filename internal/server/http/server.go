@@ -6,6 +6,9 @@ import (
 	"github.com/keweegen/notification/internal/service"
 )
 
+// multipartOverhead is the room left in the body limit for the multipart encoding of an uploaded file.
+const multipartOverhead = 64 << 10
+
 type Server struct {
 	base *fiber.App
 }
@@ -15,6 +18,7 @@ func NewServer(services *service.Store) *Server {
 	server.base = fiber.New(fiber.Config{
 		AppName:           "Notification Service",
 		EnablePrintRoutes: true,
+		BodyLimit:         int(services.File.MaxSize()) + multipartOverhead,
 	})
 
 	server.base.Use(logger.New())
@@ -29,6 +33,37 @@ func (s *Server) setRoutes(services *service.Store) {
 	messageGroup.Post("generate-id", messageHandlers.GenerateID).Name("Generate message id")
 	messageGroup.Post(":messageId/send", messageHandlers.Send).Name("Send message by generated id")
 	messageGroup.Get(":messageId/status", messageHandlers.GetStatus).Name("Get message status by generated id")
+	messageGroup.Get(":messageId/events", messageHandlers.GetEvents).Name("Get message events by generated id")
+	messageGroup.Get(":messageId/document", messageHandlers.Document).Name("Download message document")
+	messageGroup.Patch(":messageId", messageHandlers.Edit).Name("Edit delivered message")
+	messageGroup.Delete(":messageId", messageHandlers.Delete).Name("Delete delivered message")
+
+	actionHandlers := new(actionHandler).init(services)
+	s.base.Get("action/:token", actionHandlers.Confirm).Name("Show tracked button confirmation")
+	s.base.Post("action/:token", actionHandlers.Click).Name("Record tracked button click")
+
+	trackingHandlers := new(trackingHandler).init(services)
+	trackGroup := s.base.Group("track")
+	trackGroup.Get("open/:messageId", trackingHandlers.Open).Name("Record email open")
+	trackGroup.Get("click/:messageId", trackingHandlers.Click).Name("Record email link click")
+	s.base.Get("report/engagement", trackingHandlers.Engagements).Name("Get email engagement by template")
+
+	unsubscribeHandlers := new(unsubscribeHandler).init(services)
+	s.base.Get("unsubscribe/:token", unsubscribeHandlers.Confirm).Name("Show unsubscribe confirmation")
+	s.base.Post("unsubscribe/:token", unsubscribeHandlers.Unsubscribe).Name("Unsubscribe from message category")
+
+	templateGroup := s.base.Group("template")
+	templateHandlers := new(templateHandler).init(services)
+	templateGroup.Get("", templateHandlers.All).Name("Get all templates")
+	templateGroup.Post("", templateHandlers.Create).Name("Create template")
+	templateGroup.Get(":templateId", templateHandlers.Read).Name("Get template")
+	templateGroup.Patch(":templateId", templateHandlers.Update).Name("Update template")
+	templateGroup.Delete(":templateId", templateHandlers.Destroy).Name("Delete template")
+	templateGroup.Get(":templateId/version", templateHandlers.AllVersions).Name("Get all template versions")
+	templateGroup.Post(":templateId/version", templateHandlers.CreateVersion).Name("Create template version")
+	templateGroup.Get(":templateId/version/:version", templateHandlers.ReadVersion).Name("Get template version")
+	templateGroup.Get(":name/schema", templateHandlers.Schema).Name("Get template params schema")
+	templateGroup.Post(":name/render", templateHandlers.Render).Name("Render template preview")
 
 	userGroup := s.base.Group("user")
 	userHandlers := new(userHandler).init(services)
@@ -37,6 +72,27 @@ func (s *Server) setRoutes(services *service.Store) {
 	userGroup.Post("channel", userHandlers.CreateChannel).Name("Create user notification channel")
 	userGroup.Patch("channel/:userChannelId", userHandlers.UpdateChannel).Name("Update user notification channel")
 	userGroup.Delete("channel/:userChannelId", userHandlers.DestroyChannel).Name("Destroy user notification channel")
+	userGroup.Get(":userId/locale", userHandlers.ReadLocale).Name("Get user locale")
+	userGroup.Put(":userId/locale", userHandlers.UpdateLocale).Name("Set user locale")
+
+	telegramHandlers := new(telegramHandler).init(services)
+	userGroup.Post(":userId/telegram/link", telegramHandlers.IssueLink).Name("Issue telegram account link")
+
+	fileHandlers := new(fileHandler).init(services)
+	s.base.Post("file", fileHandlers.Upload).Name("Upload file for attachments")
+
+	suppressionHandlers := new(suppressionHandler).init(services)
+	s.base.Post("email/events", suppressionHandlers.EmailEvents).Name("Receive email bounce and complaint events")
+
+	suppressionGroup := s.base.Group("suppression")
+	suppressionGroup.Get(":channel", suppressionHandlers.All).Name("Get suppressed recipients of channel")
+	suppressionGroup.Get(":channel/:recipient", suppressionHandlers.Read).Name("Get suppressed recipient")
+	suppressionGroup.Delete(":channel/:recipient", suppressionHandlers.Lift).Name("Lift recipient suppression")
+
+	if services.Telegram.WebhookEnabled() {
+		telegramGroup := s.base.Group("telegram")
+		telegramGroup.Post("webhook", telegramHandlers.Webhook).Name("Receive telegram bot updates")
+	}
 }
 
 func (s *Server) Listen(addr string) error {
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/keweegen/notification/internal/channel/outbound"
+	"github.com/keweegen/notification/internal/entity"
+	"github.com/keweegen/notification/internal/webhook"
+	"strings"
+	"time"
+)
+
+const (
+	defaultActionTokenTTL = 90 * 24 * time.Hour
+	actionTokenMACSize    = 16
+)
+
+var (
+	InvalidActionTokenErr = errors.New("action token: invalid")
+	ExpiredActionTokenErr = errors.New("action token: expired")
+)
+
+type actionToken struct {
+	MessageID string `json:"m"`
+	ActionID  string `json:"a"`
+	URL       string `json:"u,omitempty"`
+	ExpiresAt int64  `json:"e"`
+}
+
+// ActionURL checks the token of a tracked button without recording the press, for the confirmation page,
+// and returns the URL of the action, empty for callback actions. The URL is also returned with
+// ExpiredActionTokenErr, the link keeps working after the press can no longer be recorded.
+func (m *Message) ActionURL(token string) (string, error) {
+	t, err := m.parseActionToken(token)
+	if t == nil {
+		return "", err
+	}
+	return t.URL, err
+}
+
+// HandleClick records the press of a tracked button and returns the URL to redirect to,
+// empty for callback actions. Expired tokens are not recorded, see ActionURL.
+func (m *Message) HandleClick(ctx context.Context, token string) (string, error) {
+	t, err := m.parseActionToken(token)
+	if err != nil {
+		if t == nil {
+			return "", err
+		}
+		return t.URL, err
+	}
+
+	message, err := m.repoStore.Message.Find(ctx, t.MessageID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", MessageNotFoundErr
+		}
+		return "", err
+	}
+
+	if err = m.RecordAction(ctx, message, t.ActionID); err != nil {
+		return "", err
+	}
+
+	return t.URL, nil
+}
+
+// RecordAction records the action event of the message and forwards the action to the producer.
+func (m *Message) RecordAction(ctx context.Context, message *entity.Message, actionID string) error {
+	description := fmt.Sprintf("Action '%s'", actionID)
+	if err := m.repoStore.Message.CreateEvent(ctx, message.ID, entity.MessageEventAction, description); err != nil {
+		return err
+	}
+
+	payload := actionPayload{
+		MessageID:   message.ID,
+		ActionID:    actionID,
+		UserID:      message.UserID,
+		Channel:     message.Channel.String(),
+		TriggeredAt: time.Now(),
+	}
+	if err := m.webhooks.Send(ctx, webhook.ActionTriggered, payload); err != nil {
+		m.logger.Error("send action webhook", "messageId", message.ID, "actionId", actionID, "error", err)
+	}
+
+	return nil
+}
+
+// trackActions turns the buttons into links to the service, so that pressing them is recorded.
+// Without a configured public address callback buttons stay untracked and are dropped by the drivers.
+func (m *Message) trackActions(messageID string, actions []outbound.Action) []outbound.Action {
+	if m.actions.BaseURL == "" || m.actions.Secret == "" {
+		return actions
+	}
+
+	expiresAt := time.Now().Add(m.actionTokenTTL()).Unix()
+
+	tracked := make([]outbound.Action, 0, len(actions))
+	for _, action := range actions {
+		token := m.makeActionToken(actionToken{MessageID: messageID, ActionID: action.ID, URL: action.URL, ExpiresAt: expiresAt})
+		action.URL = strings.TrimRight(m.actions.BaseURL, "/") + "/action/" + token
+		tracked = append(tracked, action)
+	}
+
+	return tracked
+}
+
+func (m *Message) actionTokenTTL() time.Duration {
+	if m.actions.TTL <= 0 {
+		return defaultActionTokenTTL
+	}
+	return m.actions.TTL
+}
+
+func (m *Message) makeActionToken(t actionToken) string {
+	payload, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(m.signActionToken(payload))
+}
+
+func (m *Message) parseActionToken(token string) (*actionToken, error) {
+	encodedPayload, encodedMAC, ok := strings.Cut(token, ".")
+	if !ok || m.actions.Secret == "" {
+		return nil, InvalidActionTokenErr
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, InvalidActionTokenErr
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(encodedMAC)
+	if err != nil || !hmac.Equal(mac, m.signActionToken(payload)) {
+		return nil, InvalidActionTokenErr
+	}
+
+	t := new(actionToken)
+	if err = json.Unmarshal(payload, t); err != nil || t.MessageID == "" || t.ActionID == "" {
+		return nil, InvalidActionTokenErr
+	}
+	// Tokens issued before they had an expiry are treated as expired.
+	if time.Now().After(time.Unix(t.ExpiresAt, 0)) {
+		return t, ExpiredActionTokenErr
+	}
+
+	return t, nil
+}
+
+func (m *Message) signActionToken(payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(m.actions.Secret))
+	mac.Write(payload)
+	return mac.Sum(nil)[:actionTokenMACSize]
+}
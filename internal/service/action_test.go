@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/keweegen/notification/internal/channel/outbound"
+	"github.com/keweegen/notification/internal/entity"
+	"github.com/keweegen/notification/internal/webhook"
+	"github.com/keweegen/notification/utils"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMessage_trackActions(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	message := mocked.FakeMessage()
+
+	tracked := services.Message.trackActions(message.ID, []outbound.Action{
+		{ID: "view", Label: "View order", URL: "https://example.com/orders/1"},
+		{ID: "confirm", Label: "Confirm delivery"},
+	})
+	assert.Len(t, tracked, 2)
+
+	for i, expected := range []actionToken{
+		{MessageID: message.ID, ActionID: "view", URL: "https://example.com/orders/1"},
+		{MessageID: message.ID, ActionID: "confirm"},
+	} {
+		assert.True(t, strings.HasPrefix(tracked[i].URL, "https://ns.example.com/action/"))
+
+		token, err := services.Message.parseActionToken(strings.TrimPrefix(tracked[i].URL, "https://ns.example.com/action/"))
+		assert.Nil(t, err)
+		assert.InDelta(t, time.Now().Add(defaultActionTokenTTL).Unix(), token.ExpiresAt, 5)
+		expected.ExpiresAt = token.ExpiresAt
+		assert.Equal(t, &expected, token)
+	}
+
+	_, err := services.Message.parseActionToken(strings.TrimPrefix(tracked[0].URL, "https://ns.example.com/action/") + "A")
+	assert.Equal(t, InvalidActionTokenErr, err)
+}
+
+func TestMessage_HandleClick(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+	message := mocked.FakeMessage()
+
+	token := services.Message.makeActionToken(actionToken{
+		MessageID: message.ID,
+		ActionID:  "view",
+		URL:       "https://example.com/orders/1",
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	})
+
+	mocked.RepositoryMessage.EXPECT().Find(ctx, message.ID).Return(message, nil)
+	mocked.RepositoryMessage.EXPECT().CreateEvent(ctx, message.ID, entity.MessageEventAction, "Action 'view'").Return(nil)
+	mocked.Webhook.EXPECT().Send(ctx, webhook.ActionTriggered, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ webhook.Event, payload any) error {
+			p := payload.(actionPayload)
+			assert.Equal(t, message.ID, p.MessageID)
+			assert.Equal(t, "view", p.ActionID)
+			assert.Equal(t, message.Channel.String(), p.Channel)
+			return nil
+		})
+
+	redirectURL, err := services.Message.HandleClick(ctx, token)
+	assert.Nil(t, err)
+	assert.Equal(t, "https://example.com/orders/1", redirectURL)
+}
+
+func TestMessage_HandleClick_Expired(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+	message := mocked.FakeMessage()
+
+	expired := time.Now().Add(-time.Hour).Unix()
+	link := services.Message.makeActionToken(actionToken{MessageID: message.ID, ActionID: "view", URL: "https://example.com/orders/1", ExpiresAt: expired})
+	callback := services.Message.makeActionToken(actionToken{MessageID: message.ID, ActionID: "confirm", ExpiresAt: expired})
+	legacy := services.Message.makeActionToken(actionToken{MessageID: message.ID, ActionID: "confirm"})
+
+	redirectURL, err := services.Message.HandleClick(ctx, link)
+	assert.ErrorIs(t, err, ExpiredActionTokenErr)
+	assert.Equal(t, "https://example.com/orders/1", redirectURL)
+
+	for _, token := range []string{callback, legacy} {
+		redirectURL, err = services.Message.ActionURL(token)
+		assert.ErrorIs(t, err, ExpiredActionTokenErr)
+		assert.Empty(t, redirectURL)
+	}
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/keweegen/notification/config"
+	"github.com/keweegen/notification/internal/channel"
+	"github.com/keweegen/notification/internal/entity"
+	"github.com/keweegen/notification/internal/messagetemplate"
+	"github.com/keweegen/notification/internal/pdf"
+	"github.com/keweegen/notification/internal/repository"
+	"time"
+)
+
+var DocumentNotFoundErr = errors.New("document not found")
+
+// defaultDocumentColor is the header color of the documents without one in the config.
+var defaultDocumentColor = pdf.Color{R: 0x1a, G: 0x73, B: 0xe8}
+
+// Document generates the PDF documents of the messages, such as the copy of a receipt, and stores them
+// as files of the message so support can download them again.
+type Document struct {
+	cfg     config.Documents
+	repo    repository.File
+	options messagetemplate.DocumentOptions
+}
+
+func NewDocument(cfg config.Documents, repo repository.File) *Document {
+	return &Document{
+		cfg:     cfg,
+		repo:    repo,
+		options: messagetemplate.DocumentOptions{Brand: cfg.Brand, Color: defaultDocumentColor},
+	}
+}
+
+// Load parses the color and loads the fonts of the config, it fails when any of them is invalid or missing.
+// It is called once before the messages are handled.
+func (d *Document) Load() error {
+	if d.cfg.Color != "" {
+		color, err := pdf.ParseColor(d.cfg.Color)
+		if err != nil {
+			return err
+		}
+		d.options.Color = color
+	}
+
+	if d.cfg.Font != "" {
+		font, err := pdf.LoadFont(d.cfg.Font)
+		if err != nil {
+			return err
+		}
+		d.options.Font = font
+	}
+	if d.cfg.BoldFont != "" {
+		font, err := pdf.LoadFont(d.cfg.BoldFont)
+		if err != nil {
+			return err
+		}
+		d.options.BoldFont = font
+	}
+
+	return nil
+}
+
+// HasDocument reports whether the messages of the channel get the documents of their templates,
+// the email channel attaches them and the Telegram channel sends them.
+func (d *Document) HasDocument(ch channel.Channel) bool {
+	return ch == channel.Email || ch == channel.Telegram
+}
+
+// Generate returns the document of the message, nil when its template has none. The document is generated
+// and stored on the first call, the retries of the message send the stored one.
+func (d *Document) Generate(
+	ctx context.Context,
+	tmpl messagetemplate.Template,
+	message *entity.Message,
+	h messagetemplate.Helpers,
+) (*entity.File, error) {
+	if !messagetemplate.HasDocument(tmpl) {
+		return nil, nil
+	}
+
+	file, err := d.repo.FindByMessage(ctx, message.ID)
+	if err == nil {
+		return file, nil
+	}
+	if !errors.Is(err, repository.FileNotFound) {
+		return nil, err
+	}
+
+	options := d.options
+	options.IssuedAt = time.Now()
+	media, err := messagetemplate.RenderDocument(tmpl, message.Params, h, options)
+	if err != nil {
+		return nil, err
+	}
+
+	file = &entity.File{
+		ID:          uuid.NewString(),
+		Filename:    media.Filename,
+		ContentType: media.ContentType,
+		Size:        int64(len(media.Data)),
+		Data:        media.Data,
+		MessageID:   message.ID,
+	}
+	if err = d.repo.Create(ctx, file); err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// Find returns the document generated for the message.
+func (d *Document) Find(ctx context.Context, messageID string) (*entity.File, error) {
+	file, err := d.repo.FindByMessage(ctx, messageID)
+	if errors.Is(err, repository.FileNotFound) {
+		return nil, DocumentNotFoundErr
+	}
+	return file, err
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/keweegen/notification/config"
+	"github.com/keweegen/notification/internal/channel"
+	"github.com/keweegen/notification/internal/entity"
+	"github.com/keweegen/notification/internal/messagetemplate"
+	"github.com/keweegen/notification/internal/pdf"
+	"github.com/keweegen/notification/internal/repository"
+	"github.com/keweegen/notification/utils"
+	"github.com/stretchr/testify/assert"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDocument_Generate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+
+	receipt, _ := messagetemplate.GetTemplate(messagetemplate.Receipt)
+	stored, err := messagetemplate.NewStoredTemplate("Shipped", messagetemplate.CategoryTransactional, false,
+		messagetemplate.Bodies{"telegram": {Body: "Order {{.orderId}}"}}, nil, nil)
+	assert.Nil(t, err)
+
+	message := mocked.FakeMessage()
+	message.Channel = channel.Email
+	storedFile := &entity.File{ID: "1", Filename: "receipt-123.pdf", MessageID: message.ID}
+	dbErr := errors.New("connection refused")
+
+	cases := []struct {
+		name          string
+		template      messagetemplate.Template
+		mock          func()
+		expectedFile  *entity.File
+		expectedError error
+	}{
+		{
+			name:     "template without document",
+			template: stored,
+			mock:     func() {},
+		},
+		{
+			name:     "stored",
+			template: receipt,
+			mock: func() {
+				mocked.RepositoryFile.EXPECT().FindByMessage(ctx, message.ID).Return(storedFile, nil)
+			},
+			expectedFile: storedFile,
+		},
+		{
+			name:     "generated",
+			template: receipt,
+			mock: func() {
+				mocked.RepositoryFile.EXPECT().FindByMessage(ctx, message.ID).Return(nil, repository.FileNotFound)
+				mocked.RepositoryFile.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, file *entity.File) error {
+					assert.NotEmpty(t, file.ID)
+					assert.Equal(t, "receipt-123.pdf", file.Filename)
+					assert.Equal(t, "application/pdf", file.ContentType)
+					assert.Equal(t, message.ID, file.MessageID)
+					assert.Equal(t, int64(len(file.Data)), file.Size)
+					assert.True(t, bytes.HasPrefix(file.Data, []byte("%PDF-")))
+					return nil
+				})
+			},
+		},
+		{
+			name:     "database error",
+			template: receipt,
+			mock: func() {
+				mocked.RepositoryFile.EXPECT().FindByMessage(ctx, message.ID).Return(nil, dbErr)
+			},
+			expectedError: dbErr,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+
+			file, err := services.Document.Generate(ctx, tc.template, message, messagetemplate.Helpers{})
+			assert.ErrorIs(t, err, tc.expectedError)
+			if tc.expectedFile != nil {
+				assert.Equal(t, tc.expectedFile, file)
+			}
+		})
+	}
+}
+
+func TestDocument_Find(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+
+	mocked.RepositoryFile.EXPECT().FindByMessage(ctx, "NS-1").Return(nil, repository.FileNotFound)
+	_, err := services.Document.Find(ctx, "NS-1")
+	assert.ErrorIs(t, err, DocumentNotFoundErr)
+}
+
+func TestDocument_Load(t *testing.T) {
+	d := NewDocument(config.Documents{Color: "#ff0000"}, nil)
+	assert.Nil(t, d.Load())
+	assert.Equal(t, pdf.Color{R: 0xff}, d.options.Color)
+
+	assert.ErrorIs(t, NewDocument(config.Documents{Color: "red"}, nil).Load(), pdf.InvalidColorErr)
+	assert.ErrorIs(t, NewDocument(config.Documents{Font: "/nonexistent.ttf"}, nil).Load(), fs.ErrNotExist)
+	assert.ErrorIs(t, NewDocument(config.Documents{BoldFont: "/nonexistent.ttf"}, nil).Load(), fs.ErrNotExist)
+
+	font := filepath.Join(t.TempDir(), "invalid.ttf")
+	assert.Nil(t, os.WriteFile(font, []byte("not a font"), 0o644))
+	assert.NotNil(t, NewDocument(config.Documents{Font: font}, nil).Load())
+}
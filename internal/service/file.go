@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/keweegen/notification/config"
+	"github.com/keweegen/notification/internal/entity"
+	"github.com/keweegen/notification/internal/repository"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+const defaultMaxFileSize = 10 << 20
+
+var (
+	EmptyFileErr          = errors.New("file: empty")
+	FileTooLargeErr       = errors.New("file: too large")
+	AttachmentNotFoundErr = errors.New("attachment not found")
+)
+
+type File struct {
+	cfg  config.Files
+	repo repository.File
+}
+
+func NewFile(cfg config.Files, repo repository.File) *File {
+	return &File{cfg: cfg, repo: repo}
+}
+
+// MaxSize returns the maximum size of an uploaded file in bytes.
+func (f *File) MaxSize() int64 {
+	if f.cfg.MaxSize <= 0 {
+		return defaultMaxFileSize
+	}
+	return f.cfg.MaxSize
+}
+
+// Upload stores the file to be referenced as message attachment.
+// The content type is detected from the content, falling back to the file extension.
+func (f *File) Upload(ctx context.Context, filename string, data []byte) (*entity.File, error) {
+	if len(data) == 0 {
+		return nil, EmptyFileErr
+	}
+	if int64(len(data)) > f.MaxSize() {
+		return nil, FileTooLargeErr
+	}
+
+	file := &entity.File{
+		ID:          uuid.NewString(),
+		Filename:    filepath.Base(filename),
+		ContentType: detectContentType(filename, data),
+		Size:        int64(len(data)),
+		Data:        data,
+	}
+	if err := f.repo.Create(ctx, file); err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// detectContentType sniffs the content type, generic results are refined by the file extension.
+func detectContentType(filename string, data []byte) string {
+	contentType := http.DetectContentType(data)
+	if contentType != "application/octet-stream" && !strings.HasPrefix(contentType, "text/plain") {
+		return contentType
+	}
+
+	if byExtension := mime.TypeByExtension(strings.ToLower(filepath.Ext(filename))); byExtension != "" {
+		return byExtension
+	}
+	return contentType
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/keweegen/notification/utils"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestFile_Upload(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+
+	cases := []struct {
+		name                string
+		filename            string
+		data                []byte
+		expectedContentType string
+		expectedError       error
+	}{
+		{
+			name:                "pdf",
+			filename:            "receipt.pdf",
+			data:                []byte("%PDF-1.4\n"),
+			expectedContentType: "application/pdf",
+		},
+		{
+			name:                "png without extension",
+			filename:            "../logo",
+			data:                []byte("\x89PNG\r\n\x1a\n"),
+			expectedContentType: "image/png",
+		},
+		{
+			name:                "csv by extension",
+			filename:            "orders.csv",
+			data:                []byte("id,amount\n1,100\n"),
+			expectedContentType: "text/csv; charset=utf-8",
+		},
+		{
+			name:          "empty",
+			filename:      "empty.txt",
+			expectedError: EmptyFileErr,
+		},
+		{
+			name:          "too large",
+			filename:      "large.txt",
+			data:          []byte(strings.Repeat("a", defaultMaxFileSize+1)),
+			expectedError: FileTooLargeErr,
+		},
+		{
+			name:          "database error",
+			filename:      "receipt.pdf",
+			data:          []byte("%PDF-1.4\n"),
+			expectedError: utils.FakeDatabaseError,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if len(c.data) > 0 && len(c.data) <= defaultMaxFileSize {
+				mocked.RepositoryFile.EXPECT().Create(ctx, gomock.Any()).Return(c.expectedError)
+			}
+
+			file, err := services.File.Upload(ctx, c.filename, c.data)
+			assert.Equal(t, c.expectedError, err)
+			if c.expectedError != nil {
+				assert.Nil(t, file)
+				return
+			}
+
+			assert.NotEmpty(t, file.ID)
+			assert.NotContains(t, file.Filename, "/")
+			assert.Equal(t, c.expectedContentType, file.ContentType)
+			assert.Equal(t, int64(len(c.data)), file.Size)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/keweegen/notification/config"
+	"github.com/keweegen/notification/internal/channel"
+	"github.com/keweegen/notification/internal/channel/email"
+	"github.com/keweegen/notification/logger"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const defaultMailboxInterval = time.Minute
+
+// Mailbox reads the bounce and complaint reports delivered to a local Maildir. Read messages are
+// moved from "new" to "cur" with the seen flag, the ones failed to be recorded are retried.
+type Mailbox struct {
+	logger       logger.Logger
+	cfg          config.Bounces
+	suppressions *Suppression
+}
+
+func NewMailbox(l logger.Logger, cfg config.Bounces, suppressions *Suppression) *Mailbox {
+	return &Mailbox{
+		logger:       l.With("service", "mailbox"),
+		cfg:          cfg,
+		suppressions: suppressions,
+	}
+}
+
+func (mb *Mailbox) Do(ctx context.Context, quit <-chan struct{}) {
+	if mb.cfg.Maildir == "" {
+		return
+	}
+
+	interval := mb.cfg.Interval
+	if interval <= 0 {
+		interval = defaultMailboxInterval
+	}
+
+	mb.check(ctx)
+
+	for {
+		select {
+		case <-quit:
+			return
+		case <-time.After(interval):
+			mb.check(ctx)
+		}
+	}
+}
+
+func (mb *Mailbox) check(ctx context.Context) {
+	entries, err := os.ReadDir(filepath.Join(mb.cfg.Maildir, "new"))
+	if err != nil {
+		mb.logger.Error("failed to read mailbox", "error", err)
+		return
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		l := mb.logger.With("file", entry.Name())
+		if err = mb.process(ctx, entry.Name()); err != nil {
+			l.Error("failed to process report", "error", err)
+			continue
+		}
+
+		if err = os.Rename(
+			filepath.Join(mb.cfg.Maildir, "new", entry.Name()),
+			filepath.Join(mb.cfg.Maildir, "cur", entry.Name()+":2,S"),
+		); err != nil {
+			l.Error("failed to mark report as read", "error", err)
+		}
+	}
+}
+
+func (mb *Mailbox) process(ctx context.Context, name string) error {
+	f, err := os.Open(filepath.Join(mb.cfg.Maildir, "new", name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	reports, err := email.ParseReport(f)
+	if errors.Is(err, email.NotReportErr) {
+		mb.logger.Debug("skip message without report", "file", name)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, feedback := range reports {
+		if err = mb.suppressions.Record(ctx, channel.Email, feedback); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -5,10 +5,13 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"github.com/keweegen/notification/config"
 	"github.com/keweegen/notification/internal/channel"
+	"github.com/keweegen/notification/internal/channel/outbound"
 	"github.com/keweegen/notification/internal/entity"
 	"github.com/keweegen/notification/internal/messagetemplate"
 	"github.com/keweegen/notification/internal/repository"
+	"github.com/keweegen/notification/internal/webhook"
 	"github.com/keweegen/notification/logger"
 	"github.com/volatiletech/sqlboiler/v4/types"
 	"strconv"
@@ -23,11 +26,14 @@ const (
 
 var (
 	MessageNotFoundErr        = errors.New("message not found")
+	MessageNotDeliveredErr    = errors.New("message has not been delivered yet")
 	InvalidMessageIDErr       = errors.New("messageId: invalid")
 	InvalidChannelErr         = errors.New("messageId: invalid channel")
 	InvalidMessageTemplateErr = errors.New("messageId: invalid message template")
 	InvalidUserErr            = errors.New("messageId: invalid user")
 	InvalidTimestamp          = errors.New("messageId: invalid timestamp")
+	CannotNotifyErr           = errors.New("CanNotify is false")
+	RenderFailedErr           = errors.New("template: render failed")
 
 	minTime = time.Now().AddDate(-3, 0, 0)
 	maxTime = time.Now().AddDate(1, 0, 0)
@@ -35,14 +41,30 @@ var (
 
 type Message struct {
 	logger       logger.Logger
+	actions      config.Actions
+	locales      config.Locales
 	repoStore    *repository.Store
 	channelStore *channel.Store
+	webhooks     webhook.Sender
+	preferences  *Preference
+	templates    *Template
+	documents    *Document
 
 	chQueueChannels map[channel.Channel]chan string
 	mx              *sync.Mutex
 }
 
-func NewMessage(l logger.Logger, repo *repository.Store, channelStore *channel.Store) *Message {
+func NewMessage(
+	l logger.Logger,
+	cfg config.Actions,
+	locales config.Locales,
+	repo *repository.Store,
+	channelStore *channel.Store,
+	webhooks webhook.Sender,
+	preferences *Preference,
+	templates *Template,
+	documents *Document,
+) *Message {
 	channels := make(map[channel.Channel]chan string)
 
 	for _, ch := range channel.Channels {
@@ -51,8 +73,14 @@ func NewMessage(l logger.Logger, repo *repository.Store, channelStore *channel.S
 
 	return &Message{
 		logger:          l.With("service", "message"),
+		actions:         cfg,
+		locales:         locales,
 		repoStore:       repo,
 		channelStore:    channelStore,
+		webhooks:        webhooks,
+		preferences:     preferences,
+		templates:       templates,
+		documents:       documents,
 		chQueueChannels: channels,
 		mx:              new(sync.Mutex),
 	}
@@ -100,7 +128,11 @@ func (m *Message) parseID(ctx context.Context, id string) (*entity.Message, erro
 	}
 
 	mt := messagetemplate.MessageTemplate(messageTemplateID)
-	if !mt.IsValid() {
+	templateExists, err := m.templates.Exists(ctx, mt)
+	if err != nil {
+		return nil, err
+	}
+	if !templateExists {
 		return nil, InvalidMessageTemplateErr
 	}
 
@@ -142,13 +174,36 @@ func (m *Message) GetStatus(ctx context.Context, id string) (*entity.MessageStat
 	return nil, err
 }
 
-func (m *Message) Send(ctx context.Context, id string, params types.JSON) (string, error) {
-	message, err := m.parseID(ctx, id)
+// GetEvents returns the events of the delivered message, such as opens, clicks, pressed buttons and bounces.
+func (m *Message) GetEvents(ctx context.Context, id string) (entity.MessageEvents, error) {
+	if err := m.ValidateID(ctx, id); err != nil {
+		return nil, fmt.Errorf("get events: %w", err)
+	}
+
+	exists, err := m.repoStore.Message.Exists(ctx, id)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	if !exists {
+		return nil, MessageNotFoundErr
 	}
 
-	message.Params = params
+	return m.repoStore.Message.FindEvents(ctx, id)
+}
+
+// Send queues the message, attachments reference uploaded files. The message is rendered in the locale
+// given or, when it is empty, in the locale of the user.
+func (m *Message) Send(
+	ctx context.Context,
+	id string,
+	params types.JSON,
+	attachments []string,
+	locale string,
+) (string, error) {
+	message, err := m.newMessage(ctx, id, params, attachments, locale)
+	if err != nil {
+		return "", err
+	}
 
 	messageId, err := m.repoStore.Message.CheckForDuplicates(ctx, message)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
@@ -158,6 +213,10 @@ func (m *Message) Send(ctx context.Context, id string, params types.JSON) (strin
 		return messageId, nil
 	}
 
+	if err = m.checkAttachments(ctx, attachments); err != nil {
+		return "", err
+	}
+
 	if err = m.repoStore.Message.Create(ctx, message); err != nil {
 		return "", err
 	}
@@ -168,6 +227,167 @@ func (m *Message) Send(ctx context.Context, id string, params types.JSON) (strin
 	return message.ID, m.repoStore.Message.Publish(ctx, m.pubSubKey(message.Channel), message.ID)
 }
 
+// DryRun goes through the checks of Send and of the delivery and renders the message as it would be sent,
+// the message is neither stored nor sent. The recipient checks fail with the errors of the delivery.
+// Its links and buttons are not tracked, the tokens of a message not sent yet are not handed out.
+func (m *Message) DryRun(
+	ctx context.Context,
+	id string,
+	params types.JSON,
+	attachments []string,
+	locale string,
+) (*entity.Preview, error) {
+	message, err := m.newMessage(ctx, id, params, attachments, locale)
+	if err != nil {
+		return nil, err
+	}
+	if err = m.checkAttachments(ctx, attachments); err != nil {
+		return nil, err
+	}
+
+	userChannel, err := m.findRecipient(ctx, message)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := m.getContentFromTemplate(ctx, message, userChannel, contentDryRun)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", RenderFailedErr, err)
+	}
+
+	return &entity.Preview{
+		Channel:  message.Channel,
+		Locale:   message.Locale,
+		Content:  content,
+		Warnings: localeWarnings(locale, message.Locale),
+	}, nil
+}
+
+// Preview renders the current version of the template for the channel without a message. Params not matching
+// the params schema are reported as warnings, the locale falls back as for the messages, to the default
+// locale when it is empty.
+func (m *Message) Preview(
+	ctx context.Context,
+	mt messagetemplate.MessageTemplate,
+	ch channel.Channel,
+	params types.JSON,
+	locale string,
+) (*entity.Preview, error) {
+	if !ch.IsValid() {
+		return nil, InvalidChannelErr
+	}
+
+	message := &entity.Message{Channel: ch, MessageTemplate: mt, Params: params}
+
+	var err error
+	if message.TemplateVersion, err = m.templates.CurrentVersion(ctx, mt); err != nil {
+		return nil, err
+	}
+	if message.Locale, err = m.templateLocale(ctx, message, locale); err != nil {
+		return nil, err
+	}
+
+	preview := &entity.Preview{Channel: ch, Locale: message.Locale}
+
+	var paramsErr *messagetemplate.ParamsError
+	err = m.templates.ValidateParams(ctx, mt, message.TemplateVersion, params)
+	if err != nil && !errors.As(err, &paramsErr) {
+		return nil, err
+	}
+	if paramsErr != nil {
+		preview.Warnings = paramsErr.Errors
+	}
+	preview.Warnings = append(preview.Warnings, localeWarnings(locale, message.Locale)...)
+
+	tmpl, err := m.templates.Get(ctx, mt, message.TemplateVersion)
+	if err != nil {
+		return nil, err
+	}
+	helpers := messagetemplate.Helpers{
+		Locale:    message.Locale,
+		Fallbacks: m.fallbacks(message.Locale),
+		TimeZone:  m.timeZone(message),
+	}
+	preview.Content, err = messagetemplate.Render(tmpl, ch, params, helpers)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", RenderFailedErr, err)
+	}
+
+	return preview, nil
+}
+
+// Edit re-renders a delivered message with new params and replaces its content at the provider.
+func (m *Message) Edit(ctx context.Context, id string, params types.JSON) error {
+	message, userChannel, editor, err := m.findDeliveredMessage(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err = m.templates.ValidateParams(ctx, message.MessageTemplate, message.TemplateVersion, params); err != nil {
+		return err
+	}
+	message.Params = params
+
+	content, err := m.getContentFromTemplate(ctx, message, userChannel, contentEdit)
+	if err != nil {
+		return fmt.Errorf("get content from template: %w", err)
+	}
+	if err = editor.Edit(userChannel.Recipient, message.Delivery(), content); err != nil {
+		return fmt.Errorf("edit message with channel driver: %w", err)
+	}
+	if err = m.repoStore.Message.UpdateParams(ctx, message.ID, params); err != nil {
+		return err
+	}
+
+	return m.repoStore.Message.CreateStatus(ctx, message.ID, entity.MessageStatusEdited, "Message edited")
+}
+
+// Delete removes a delivered message from the recipient's chat.
+func (m *Message) Delete(ctx context.Context, id string) error {
+	message, userChannel, editor, err := m.findDeliveredMessage(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err = editor.Delete(userChannel.Recipient, message.Delivery()); err != nil {
+		return fmt.Errorf("delete message with channel driver: %w", err)
+	}
+
+	return m.repoStore.Message.CreateStatus(ctx, message.ID, entity.MessageStatusDeleted, "Message deleted")
+}
+
+func (m *Message) findDeliveredMessage(
+	ctx context.Context,
+	id string,
+) (*entity.Message, *entity.UserChannel, channel.Editor, error) {
+	if err := m.ValidateID(ctx, id); err != nil {
+		return nil, nil, nil, err
+	}
+
+	message, err := m.repoStore.Message.Find(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, nil, MessageNotFoundErr
+		}
+		return nil, nil, nil, err
+	}
+
+	editor, err := m.channelStore.GetEditor(message.Channel)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if message.ProviderMessageID == "" {
+		return nil, nil, nil, MessageNotDeliveredErr
+	}
+
+	userChannel, err := m.repoStore.User.FindByChannel(ctx, message.UserID, message.Channel)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("find user notification channel: %w", err)
+	}
+
+	return message, userChannel, editor, nil
+}
+
 func (m *Message) HandleMessages(ctx context.Context, quit <-chan struct{}) {
 	subscriptionKeys := make([]string, 0, len(channel.Channels))
 
@@ -267,7 +487,7 @@ func (m *Message) handleChannelMessages(ctx context.Context, quit <-chan struct{
 
 			if err = m.sendMessage(ctx, message); err != nil {
 				l.Error("failed send message", "error", err)
-				m.makeStatus(ctx, message.ID, entity.MessageStatusFailed, err.Error())
+				m.makeFailedStatus(ctx, message.ID, err)
 			}
 		}
 	}
@@ -275,35 +495,148 @@ func (m *Message) handleChannelMessages(ctx context.Context, quit <-chan struct{
 
 func (m *Message) sendMessage(ctx context.Context, message *entity.Message) error {
 	m.logger.Debug("sending message")
-	m.makeStatus(ctx, message.ID, entity.MessageStatusSending, "Sending a message")
+	m.makeStatus(ctx, message.ID, entity.MessageStatusSending, entity.MessageAttemptDescription)
 
 	channelDriver, err := m.channelStore.Get(message.Channel)
 	if err != nil {
 		return fmt.Errorf("get channel driver by name: %w", err)
 	}
 
-	userChannelSettings, err := m.repoStore.User.FindByChannel(ctx, message.UserID, message.Channel)
+	userChannelSettings, err := m.findRecipient(ctx, message)
 	if err != nil {
-		return fmt.Errorf("find user notification channel: %w", err)
-	}
-	if !userChannelSettings.CanNotify {
-		return errors.New("CanNotify is false")
+		return err
 	}
 
-	content, err := m.getContentFromTemplate(message)
+	content, err := m.getContentFromTemplate(ctx, message, userChannelSettings, contentSend)
 	if err != nil {
-		return fmt.Errorf("get content from template")
+		return fmt.Errorf("get content from template: %w", err)
 	}
-	if err = channelDriver.Send(userChannelSettings.Recipient, content); err != nil {
+	delivery, err := channelDriver.Send(userChannelSettings.Recipient, content)
+	if err != nil {
+		if len(delivery.Parts) > 0 {
+			if saveErr := m.repoStore.Message.SetDelivery(ctx, message.ID, delivery); saveErr != nil {
+				m.logger.Error("save partial delivery", "messageId", message.ID, "error", saveErr)
+			}
+		}
+		if channel.IsRecipientUnreachable(err) {
+			m.disableUserChannel(ctx, userChannelSettings, err.Error())
+		}
 		return fmt.Errorf("send message with channel driver: %w", err)
 	}
 
+	if err = m.repoStore.Message.SetDelivery(ctx, message.ID, delivery); err != nil {
+		m.logger.Error("save provider message id", "messageId", message.ID, "error", err)
+	}
+
 	m.makeStatus(ctx, message.ID, entity.MessageStatusSent, "Message sent")
 	m.logger.Debug("message sent", "channel", message.Channel, "content", content)
 
 	return nil
 }
 
+// disableUserChannel turns off notifications for a channel whose recipient permanently
+// refuses messages and reports it, so the product can ask the user to reconnect.
+func (m *Message) disableUserChannel(ctx context.Context, userChannel *entity.UserChannel, reason string) {
+	l := m.logger.With("userChannelId", userChannel.ID, "reason", reason)
+
+	disabled, err := m.repoStore.User.DisableChannel(ctx, userChannel.ID, reason)
+	if err != nil {
+		l.Error("disable user channel", "error", err)
+		return
+	}
+
+	l.Info("user channel disabled")
+
+	payload := channelDisabledPayload{
+		UserChannelID: disabled.ID,
+		UserID:        disabled.UserID,
+		Channel:       disabled.Channel.String(),
+		Recipient:     disabled.Recipient,
+		Reason:        disabled.DisabledReason,
+		DisabledAt:    disabled.DisabledAt,
+	}
+	if err = m.webhooks.Send(ctx, webhook.ChannelDisabled, payload); err != nil {
+		l.Error("send channel disabled webhook", "error", err)
+	}
+}
+
+// newMessage parses the message id and validates the params of a message to send,
+// the message gets the current version of the template and its locale.
+func (m *Message) newMessage(
+	ctx context.Context,
+	id string,
+	params types.JSON,
+	attachments []string,
+	locale string,
+) (*entity.Message, error) {
+	message, err := m.parseID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	message.Params = params
+	message.Attachments = attachments
+
+	if message.TemplateVersion, err = m.templates.CurrentVersion(ctx, message.MessageTemplate); err != nil {
+		return nil, err
+	}
+	if err = m.templates.ValidateParams(ctx, message.MessageTemplate, message.TemplateVersion, params); err != nil {
+		return nil, err
+	}
+	if err = m.resolveLocale(ctx, message, locale); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+func (m *Message) checkAttachments(ctx context.Context, attachments []string) error {
+	for _, fileID := range attachments {
+		exists, err := m.repoStore.File.Exists(ctx, fileID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("%w: '%s'", AttachmentNotFoundErr, fileID)
+		}
+	}
+	return nil
+}
+
+// findRecipient returns the user channel the message is delivered to, it fails when the user turned
+// the channel off, the recipient is suppressed or unsubscribed from the category of the message.
+func (m *Message) findRecipient(ctx context.Context, message *entity.Message) (*entity.UserChannel, error) {
+	userChannel, err := m.repoStore.User.FindByChannel(ctx, message.UserID, message.Channel)
+	if err != nil {
+		return nil, fmt.Errorf("find user notification channel: %w", err)
+	}
+	if !userChannel.CanNotify {
+		return nil, CannotNotifyErr
+	}
+
+	suppressed, err := m.repoStore.Suppression.Exists(ctx, message.Channel, userChannel.Recipient)
+	if err != nil {
+		return nil, fmt.Errorf("check suppression: %w", err)
+	}
+	if suppressed {
+		return nil, fmt.Errorf("%w: %s", RecipientSuppressedErr, userChannel.Recipient)
+	}
+
+	category, err := m.getCategory(ctx, message)
+	if err != nil {
+		return nil, err
+	}
+	subscribed, err := m.preferences.IsSubscribed(ctx, message.UserID, message.Channel, category)
+	if err != nil {
+		return nil, fmt.Errorf("check subscription: %w", err)
+	}
+	if !subscribed {
+		return nil, fmt.Errorf("%w: %s", RecipientUnsubscribedErr, category)
+	}
+
+	return userChannel, nil
+}
+
 func (m *Message) makeStatus(ctx context.Context, messageID, status, description string) {
 	if err := m.repoStore.Message.CreateStatus(ctx, messageID, status, description); err != nil {
 		m.logger.Error("create message status",
@@ -314,23 +647,167 @@ func (m *Message) makeStatus(ctx context.Context, messageID, status, description
 	}
 }
 
+// makeFailedStatus marks the message failed, after a temporary failure it stays
+// in the sending status to be retried by the message checker.
+func (m *Message) makeFailedStatus(ctx context.Context, messageID string, err error) {
+	if channel.IsTemporary(err) {
+		m.makeStatus(ctx, messageID, entity.MessageStatusSending, "Temporary failure, will be retried: "+err.Error())
+		return
+	}
+
+	m.makeStatus(ctx, messageID, entity.MessageStatusFailed, err.Error())
+}
+
 func (m *Message) pubSubKey(channel channel.Channel) string {
 	return fmt.Sprintf("ns::%d", channel)
 }
 
-func (m *Message) getContentFromTemplate(message *entity.Message) (string, error) {
-	tmpl, err := messagetemplate.GetTemplate(message.MessageTemplate)
+// resolveLocale sets the locale of the fallback chain of the requested or the user locale the message
+// template is translated to, and the time zone of the user, the default one when the user has none.
+func (m *Message) resolveLocale(ctx context.Context, message *entity.Message, locale string) error {
+	userLocale, err := m.repoStore.User.FindLocale(ctx, message.UserID)
+	if err != nil {
+		return err
+	}
+	if locale == "" {
+		locale = userLocale.Locale
+	}
+	message.TimeZone = userLocale.TimeZone
+
+	message.Locale, err = m.templateLocale(ctx, message, locale)
+	return err
+}
+
+// timeZone returns the time zone the dates of the message are formatted in.
+func (m *Message) timeZone(message *entity.Message) string {
+	if message.TimeZone == "" {
+		return m.locales.TimeZone
+	}
+	return message.TimeZone
+}
+
+// fallbacks returns the fallback chain of the locale of a message, the catalog messages missing
+// from the locale are looked up in it.
+func (m *Message) fallbacks(locale string) []string {
+	if locale == "" {
+		return nil
+	}
+	return messagetemplate.FallbackChain(locale, m.locales.Fallbacks, m.locales.Default)
+}
+
+// templateLocale returns the locale of the fallback chain of the locale the message template is translated to.
+func (m *Message) templateLocale(ctx context.Context, message *entity.Message, locale string) (string, error) {
+	if locale != "" {
+		normalized, ok := messagetemplate.NormalizeLocale(locale)
+		if !ok {
+			return "", InvalidLocaleErr
+		}
+		locale = normalized
+	}
+
+	tmpl, err := m.templates.Get(ctx, message.MessageTemplate, message.TemplateVersion)
 	if err != nil {
 		return "", fmt.Errorf("failed to get message template: %w", err)
 	}
 
-	if err = tmpl.SetParams(message.Params); err != nil {
-		return "", fmt.Errorf("failed to set params message template: %s", err)
+	chain := messagetemplate.FallbackChain(locale, m.locales.Fallbacks, m.locales.Default)
+	return messagetemplate.ResolveLocale(tmpl, chain), nil
+}
+
+// localeWarnings reports the requested locale the message is not rendered in.
+func localeWarnings(requested, resolved string) []messagetemplate.FieldError {
+	if requested == "" || resolved == "" {
+		return nil
 	}
+	if normalized, _ := messagetemplate.NormalizeLocale(requested); strings.EqualFold(normalized, resolved) {
+		return nil
+	}
+	return []messagetemplate.FieldError{{
+		Field:   "locale",
+		Message: fmt.Sprintf("is not translated to %s, rendered in %s", requested, resolved),
+	}}
+}
 
-	data, err := messagetemplate.Parse(tmpl, message.Channel)
+func (m *Message) getCategory(ctx context.Context, message *entity.Message) (messagetemplate.Category, error) {
+	tmpl, err := m.templates.Get(ctx, message.MessageTemplate, message.TemplateVersion)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse message template: %w", err)
+		return "", fmt.Errorf("failed to get message template: %w", err)
+	}
+	return messagetemplate.GetCategory(tmpl), nil
+}
+
+// contentPurpose is what the content of a message is rendered for.
+type contentPurpose int
+
+const (
+	// contentSend is the content delivered to the recipient, with tracked links and buttons.
+	contentSend contentPurpose = iota
+	// contentEdit is the new content of a delivered message, the document sent with it is kept.
+	contentEdit
+	// contentDryRun is the content shown to the producer, the tracking and action tokens
+	// valid for the message are not minted for it and the document is not generated.
+	contentDryRun
+)
+
+func (m *Message) getContentFromTemplate(
+	ctx context.Context,
+	message *entity.Message,
+	userChannel *entity.UserChannel,
+	purpose contentPurpose,
+) (*outbound.Message, error) {
+	tmpl, err := m.templates.Get(ctx, message.MessageTemplate, message.TemplateVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message template: %w", err)
+	}
+
+	helpers := messagetemplate.Helpers{
+		UnsubscribeURL: m.preferences.UnsubscribeURL(message.UserID, message.Channel, messagetemplate.GetCategory(tmpl)),
+		Locale:         message.Locale,
+		Fallbacks:      m.fallbacks(message.Locale),
+		TimeZone:       m.timeZone(message),
+	}
+
+	data, err := messagetemplate.Render(tmpl, message.Channel, message.Params, helpers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse message template: %w", err)
+	}
+
+	if purpose != contentDryRun && message.Channel == channel.Email && data.Format == outbound.FormatHTML &&
+		messagetemplate.IsTracked(tmpl) && !userChannel.TrackingOptOut {
+		data.Text = m.trackEmail(message.ID, data.Text)
+	}
+
+	if purpose != contentDryRun && len(data.Actions) > 0 && !message.Channel.HasCallbacks() {
+		data.Actions = m.trackActions(message.ID, data.Actions)
+	}
+
+	if purpose == contentSend && m.documents.HasDocument(message.Channel) {
+		document, err := m.documents.Generate(ctx, tmpl, message, helpers)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate document: %w", err)
+		}
+		if document != nil {
+			data.Media = append(data.Media, outbound.Media{
+				Kind:        outbound.Document,
+				Filename:    document.Filename,
+				ContentType: document.ContentType,
+				Data:        document.Data,
+			})
+		}
+	}
+
+	for _, fileID := range message.Attachments {
+		file, err := m.repoStore.File.Find(ctx, fileID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find attachment '%s': %w", fileID, err)
+		}
+
+		data.Media = append(data.Media, outbound.Media{
+			Kind:        outbound.Document,
+			Filename:    file.Filename,
+			ContentType: file.ContentType,
+			Data:        file.Data,
+		})
 	}
 
 	return data, nil
@@ -11,6 +11,8 @@ import (
 
 const (
 	retryTimeoutChecker = 10 * time.Minute
+	// maxSendAttempts is the number of delivery attempts after which a message is marked failed.
+	maxSendAttempts = 5
 )
 
 type MessageChecker struct {
@@ -56,13 +58,31 @@ func (mc *MessageChecker) resendMessages(ctx context.Context, messages entity.Me
 	for _, message := range messages {
 		message := message
 
-		go func() {
-			if err := mc.messageService.sendMessage(ctx, message); err != nil {
-				mc.logger.Error("failed to send message",
-					"messageId", message.ID,
-					"error", err)
-			}
-		}()
+		go mc.resendMessage(ctx, message)
+	}
+}
+
+// resendMessage retries the delivery of the message, a message failing every attempt is marked failed
+// once it runs out of attempts.
+func (mc *MessageChecker) resendMessage(ctx context.Context, message *entity.Message) {
+	attempts, err := mc.repo.Message.CountAttempts(ctx, message.ID)
+	if err != nil {
+		mc.logger.Error("failed to count message attempts",
+			"messageId", message.ID,
+			"error", err)
+		return
+	}
+	if attempts >= maxSendAttempts {
+		mc.messageService.makeStatus(ctx, message.ID, entity.MessageStatusFailed,
+			fmt.Sprintf("Not delivered after %d attempts", attempts))
+		return
+	}
+
+	if err = mc.messageService.sendMessage(ctx, message); err != nil {
+		mc.logger.Error("failed to send message",
+			"messageId", message.ID,
+			"error", err)
+		mc.messageService.makeFailedStatus(ctx, message.ID, err)
 	}
 }
 
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/golang/mock/gomock"
+	"github.com/keweegen/notification/internal/channel/outbound"
 	"github.com/keweegen/notification/internal/entity"
 	"github.com/keweegen/notification/utils"
 	"testing"
@@ -29,9 +30,12 @@ func TestMessageChecker_Do_OK(t *testing.T) {
 	mocked.Logger.EXPECT().Debug("message sent", "channel", message.Channel, "content", gomock.Any())
 
 	mocked.RepositoryMessage.EXPECT().FindProcessMessages(ctx, gomock.Any(), gomock.Any()).Return(messages, nil)
+	mocked.RepositoryMessage.EXPECT().CountAttempts(ctx, message.ID).Return(int64(1), nil)
 	mocked.RepositoryMessage.EXPECT().CreateStatus(ctx, message.ID, entity.MessageStatusSending, "Sending a message").Return(nil)
 	mocked.RepositoryUser.EXPECT().FindByChannel(ctx, message.UserID, message.Channel).Return(userChannel, nil)
-	mocked.ChannelDriver.EXPECT().Send(userChannel.Recipient, gomock.Any()).Return(nil)
+	mocked.RepositorySuppression.EXPECT().Exists(ctx, message.Channel, userChannel.Recipient).Return(false, nil)
+	mocked.ChannelDriver.EXPECT().Send(userChannel.Recipient, gomock.Any()).Return(outbound.Delivery{ID: "1"}, nil)
+	mocked.RepositoryMessage.EXPECT().SetDelivery(ctx, message.ID, outbound.Delivery{ID: "1"}).Return(nil)
 	mocked.RepositoryMessage.EXPECT().CreateStatus(ctx, message.ID, entity.MessageStatusSent, "Message sent").Return(nil)
 
 	go services.MessageChecker.Do(ctx, mocked.QuitCh)
@@ -81,8 +85,34 @@ func TestMessageChecker_Do_ErrorResendMessages(t *testing.T) {
 		"error", fmt.Errorf("find user notification channel: %w", expectedError))
 
 	mocked.RepositoryMessage.EXPECT().FindProcessMessages(ctx, gomock.Any(), gomock.Any()).Return(messages, nil)
+	mocked.RepositoryMessage.EXPECT().CountAttempts(ctx, message.ID).Return(int64(1), nil)
 	mocked.RepositoryMessage.EXPECT().CreateStatus(ctx, message.ID, entity.MessageStatusSending, "Sending a message").Return(nil)
 	mocked.RepositoryUser.EXPECT().FindByChannel(ctx, message.UserID, message.Channel).Return(nil, expectedError)
+	mocked.RepositoryMessage.EXPECT().CreateStatus(ctx, message.ID, entity.MessageStatusFailed,
+		fmt.Errorf("find user notification channel: %w", expectedError).Error()).Return(nil)
+
+	go services.MessageChecker.Do(ctx, mocked.QuitCh)
+
+	mocked.WriteQuitChannel()
+}
+
+func TestMessageChecker_Do_AttemptsExhausted(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+
+	message := mocked.FakeMessage()
+	messages := entity.Messages{message}
+
+	mocked.Logger.EXPECT().Debug("get process messages")
+	mocked.RepositoryMessage.EXPECT().FindProcessMessages(ctx, gomock.Any(), gomock.Any()).Return(messages, nil)
+	mocked.RepositoryMessage.EXPECT().CountAttempts(ctx, message.ID).Return(int64(maxSendAttempts), nil)
+	mocked.RepositoryMessage.EXPECT().CreateStatus(ctx, message.ID, entity.MessageStatusFailed, "Not delivered after 5 attempts").Return(nil)
 
 	go services.MessageChecker.Do(ctx, mocked.QuitCh)
 
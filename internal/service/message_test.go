@@ -7,11 +7,15 @@ import (
 	"fmt"
 	"github.com/golang/mock/gomock"
 	"github.com/keweegen/notification/internal/channel"
+	"github.com/keweegen/notification/internal/channel/outbound"
+	"github.com/keweegen/notification/internal/channel/telegram"
 	"github.com/keweegen/notification/internal/entity"
 	"github.com/keweegen/notification/internal/messagetemplate"
 	"github.com/keweegen/notification/internal/repository"
+	"github.com/keweegen/notification/internal/webhook"
 	"github.com/keweegen/notification/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/sqlboiler/v4/types"
 	"testing"
 	"time"
 )
@@ -256,6 +260,7 @@ func TestMessage_Send(t *testing.T) {
 	mocked.ExpectLoggerWithServices()
 
 	services := mock(t, mocked)
+	params := types.JSON(`{"orderId": 123, "commissionAmount": 2, "totalAmount": 1002}`)
 
 	cases := []struct {
 		name                        string
@@ -278,6 +283,8 @@ func TestMessage_Send(t *testing.T) {
 				Channel:         channel.Telegram,
 				UserID:          1234567890,
 				MessageTemplate: messagetemplate.Receipt,
+				Params:          params,
+				Locale:          "ru",
 				Timestamp:       1666115824000,
 				ExternalID:      1234567890,
 			},
@@ -292,6 +299,8 @@ func TestMessage_Send(t *testing.T) {
 				Channel:         channel.Telegram,
 				UserID:          1234567890,
 				MessageTemplate: messagetemplate.Receipt,
+				Params:          params,
+				Locale:          "ru",
 				Timestamp:       1666115824000,
 				ExternalID:      1234567890,
 			},
@@ -311,6 +320,8 @@ func TestMessage_Send(t *testing.T) {
 				Channel:         channel.Telegram,
 				UserID:          1234567890,
 				MessageTemplate: messagetemplate.Receipt,
+				Params:          params,
+				Locale:          "ru",
 				Timestamp:       1666115824000,
 				ExternalID:      1234567890,
 			},
@@ -325,6 +336,8 @@ func TestMessage_Send(t *testing.T) {
 				Channel:         channel.Telegram,
 				UserID:          1234567890,
 				MessageTemplate: messagetemplate.Receipt,
+				Params:          params,
+				Locale:          "ru",
 				Timestamp:       1666115824000,
 				ExternalID:      1234567890,
 			},
@@ -339,6 +352,8 @@ func TestMessage_Send(t *testing.T) {
 				Channel:         channel.Telegram,
 				UserID:          1234567890,
 				MessageTemplate: messagetemplate.Receipt,
+				Params:          params,
+				Locale:          "ru",
 				Timestamp:       1666115824000,
 				ExternalID:      1234567890,
 			},
@@ -353,6 +368,8 @@ func TestMessage_Send(t *testing.T) {
 				Channel:         channel.Telegram,
 				UserID:          1234567890,
 				MessageTemplate: messagetemplate.Receipt,
+				Params:          params,
+				Locale:          "ru",
 				Timestamp:       1666115824000,
 				ExternalID:      1234567890,
 			},
@@ -367,7 +384,7 @@ func TestMessage_Send(t *testing.T) {
 			var testErr error
 
 			defer func() {
-				id, err := services.Message.Send(ctx, tc.input, nil)
+				id, err := services.Message.Send(ctx, tc.input, params, nil, "")
 				assert.Equal(t, testErr, err)
 				assert.Equal(t, tc.expectedId, id)
 			}()
@@ -378,6 +395,8 @@ func TestMessage_Send(t *testing.T) {
 			}
 
 			mocked.RepositoryUser.EXPECT().Exists(ctx, tc.userID).Return(true, nil)
+			mocked.RepositoryTemplate.EXPECT().Find(ctx, messagetemplate.Receipt).Return(nil, repository.TemplateNotFound)
+			mocked.RepositoryUser.EXPECT().FindLocale(ctx, tc.userID).Return(&entity.UserLocale{UserID: tc.userID}, nil)
 
 			if tc.expectedErrorOnDuplicate != nil {
 				mocked.RepositoryMessage.EXPECT().CheckForDuplicates(ctx, tc.message).Return("", tc.expectedErrorOnDuplicate)
@@ -514,10 +533,12 @@ func TestMessage_getContentFromTemplate(t *testing.T) {
 	mocked.ExpectLoggerWithServices()
 
 	services := mock(t, mocked)
+	ctx := context.Background()
 
 	cases := []struct {
 		name            string
 		input           *entity.Message
+		document        *entity.File
 		expectedError   error
 		expectedContent string
 	}{
@@ -526,7 +547,7 @@ func TestMessage_getContentFromTemplate(t *testing.T) {
 			input: &entity.Message{
 				Channel:         channel.Telegram,
 				MessageTemplate: messagetemplate.MessageTemplate(0),
-				Params:          []byte(`{"orderId": 123, "commissionAmount": "1 KZT", "totalAmount": "1001 KZT"}`),
+				Params:          []byte(`{"orderId": 123, "commissionAmount": 1, "totalAmount": 1001}`),
 			},
 			expectedError: fmt.Errorf("failed to get message template: %w", messagetemplate.TemplateNotFoundErr),
 		},
@@ -537,14 +558,14 @@ func TestMessage_getContentFromTemplate(t *testing.T) {
 				MessageTemplate: messagetemplate.Receipt,
 				Params:          nil,
 			},
-			expectedError: fmt.Errorf("failed to set params message template: %s", errors.New("unexpected end of JSON input")),
+			expectedError: fmt.Errorf("failed to parse message template: %w", errors.New("params: unexpected end of JSON input")),
 		},
 		{
 			name: "invalid channel",
 			input: &entity.Message{
 				Channel:         channel.Channel(0),
 				MessageTemplate: messagetemplate.Receipt,
-				Params:          []byte(`{"orderId": 123, "commissionAmount": "1 KZT", "totalAmount": "1001 KZT"}`),
+				Params:          []byte(`{"orderId": 123, "commissionAmount": 1, "totalAmount": 1001}`),
 			},
 			expectedError: fmt.Errorf("failed to parse message template: %w", errors.New("unknown channel 'Channel(0)'")),
 		},
@@ -553,29 +574,67 @@ func TestMessage_getContentFromTemplate(t *testing.T) {
 			input: &entity.Message{
 				Channel:         channel.Telegram,
 				MessageTemplate: messagetemplate.Receipt,
-				Params:          []byte(`{"orderId": 123, "commissionAmount": "1 KZT", "totalAmount": "1001 KZT"}`),
+				Params:          []byte(`{"orderId": 123, "commissionAmount": 1, "totalAmount": 1001}`),
 			},
+			document:      &entity.File{Filename: "receipt-123.pdf", ContentType: "application/pdf", Data: []byte("%PDF-1.7")},
 			expectedError: nil,
-			expectedContent: `<b>Чек</b>
-
-Заказ <code>123</code> успешно оплачен
-
-Комиссия: 1 KZT
-Сумма к списанию: 1001 KZT
-
-Спасибо за покупку`,
+			expectedContent: "<b>Чек</b>\n\n" +
+				"Заказ <b>123</b> успешно оплачен\n\n" +
+				"Комиссия: 1,00\u00a0KZT\n" +
+				"Сумма к списанию: 1\u00a0001,00\u00a0KZT\n\n" +
+				"Спасибо за покупку!",
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			data, err := services.Message.getContentFromTemplate(tc.input)
+			if tc.document != nil {
+				mocked.RepositoryFile.EXPECT().FindByMessage(ctx, tc.input.ID).Return(tc.document, nil)
+			}
+
+			data, err := services.Message.getContentFromTemplate(ctx, tc.input, mocked.FakeUserChannel(), contentSend)
 			assert.Equal(t, tc.expectedError, err)
-			assert.Equal(t, tc.expectedContent, data)
+			if tc.expectedError == nil {
+				assert.Equal(t, &outbound.Message{
+					Text:   tc.expectedContent,
+					Format: outbound.FormatHTML,
+					Media: []outbound.Media{{
+						Kind:        outbound.Document,
+						Filename:    tc.document.Filename,
+						ContentType: tc.document.ContentType,
+						Data:        tc.document.Data,
+					}},
+				}, data)
+			} else {
+				assert.Nil(t, data)
+			}
 		})
 	}
 }
 
+func TestMessage_getContentFromTemplate_WithoutDocument(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+
+	message := &entity.Message{
+		Channel:         channel.Telegram,
+		MessageTemplate: messagetemplate.Receipt,
+		Params:          []byte(`{"orderId": 123, "commissionAmount": 1, "totalAmount": 1001}`),
+	}
+
+	for _, purpose := range []contentPurpose{contentEdit, contentDryRun} {
+		data, err := services.Message.getContentFromTemplate(ctx, message, mocked.FakeUserChannel(), purpose)
+		assert.Nil(t, err)
+		assert.Empty(t, data.Media)
+	}
+}
+
 func TestMessage_appendQueueChannelMessage(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
@@ -629,8 +688,355 @@ func TestMessage_appendQueueChannelMessage(t *testing.T) {
 func mock(t *testing.T, mocked *utils.MockedInstances) *Store {
 	t.Helper()
 
-	repo := &repository.Store{Message: mocked.RepositoryMessage, User: mocked.RepositoryUser}
-	channels := &channel.Store{Drivers: map[channel.Channel]channel.Driver{channel.Mock: mocked.ChannelDriver}}
+	repo := &repository.Store{
+		Message:     mocked.RepositoryMessage,
+		User:        mocked.RepositoryUser,
+		LinkToken:   mocked.RepositoryLinkToken,
+		File:        mocked.RepositoryFile,
+		Suppression: mocked.RepositorySuppression,
+		Preference:  mocked.RepositoryPreference,
+		Template:    mocked.RepositoryTemplate,
+	}
+	channels := &channel.Store{Drivers: map[channel.Channel]channel.Driver{
+		channel.Mock:     mocked.ChannelDriver,
+		channel.Telegram: mocked.TelegramBot,
+	}}
+
+	return NewStore(mocked.Logger, mocked.FakeConfig(), repo, channels, mocked.Webhook)
+}
+
+func TestMessage_sendMessage_RecipientUnreachable(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+
+	message := mocked.FakeMessage()
+	userChannel := mocked.FakeUserChannel()
+	userChannel.ID = 1
+	driverErr := &telegram.Error{Code: 403, Description: "Forbidden: bot was blocked by the user"}
+
+	disabledAt := time.Now()
+	disabledChannel := *userChannel
+	disabledChannel.CanNotify = false
+	disabledChannel.DisabledReason = driverErr.Error()
+	disabledChannel.DisabledAt = &disabledAt
+
+	mocked.Logger.EXPECT().Debug("sending message")
+	mocked.Logger.EXPECT().With("userChannelId", userChannel.ID, "reason", driverErr.Error()).Return(mocked.Logger)
+	mocked.Logger.EXPECT().Info("user channel disabled")
+
+	mocked.RepositoryMessage.EXPECT().CreateStatus(ctx, message.ID, entity.MessageStatusSending, "Sending a message").Return(nil)
+	mocked.RepositoryUser.EXPECT().FindByChannel(ctx, message.UserID, message.Channel).Return(userChannel, nil)
+	mocked.RepositorySuppression.EXPECT().Exists(ctx, message.Channel, userChannel.Recipient).Return(false, nil)
+	mocked.ChannelDriver.EXPECT().Send(userChannel.Recipient, gomock.Any()).Return(outbound.Delivery{}, driverErr)
+	mocked.RepositoryUser.EXPECT().DisableChannel(ctx, userChannel.ID, driverErr.Error()).Return(&disabledChannel, nil)
+	mocked.Webhook.EXPECT().Send(ctx, webhook.ChannelDisabled, channelDisabledPayload{
+		UserChannelID: disabledChannel.ID,
+		UserID:        disabledChannel.UserID,
+		Channel:       disabledChannel.Channel.String(),
+		Recipient:     disabledChannel.Recipient,
+		Reason:        disabledChannel.DisabledReason,
+		DisabledAt:    disabledChannel.DisabledAt,
+	}).Return(nil)
+
+	err := services.Message.sendMessage(ctx, message)
+	assert.ErrorIs(t, err, driverErr)
+}
+
+func TestMessage_sendMessage_PartialDelivery(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+
+	message := mocked.FakeMessage()
+	userChannel := mocked.FakeUserChannel()
+	driverErr := &telegram.Error{Code: 429, Description: "Too Many Requests: retry after 5"}
+	delivery := outbound.Delivery{ID: "1", Parts: []outbound.Part{{ID: "1", Kind: outbound.PartText}}}
+
+	mocked.Logger.EXPECT().Debug("sending message")
+	mocked.RepositoryMessage.EXPECT().CreateStatus(ctx, message.ID, entity.MessageStatusSending, "Sending a message").Return(nil)
+	mocked.RepositoryUser.EXPECT().FindByChannel(ctx, message.UserID, message.Channel).Return(userChannel, nil)
+	mocked.RepositorySuppression.EXPECT().Exists(ctx, message.Channel, userChannel.Recipient).Return(false, nil)
+	mocked.ChannelDriver.EXPECT().Send(userChannel.Recipient, gomock.Any()).Return(delivery, driverErr)
+	mocked.RepositoryMessage.EXPECT().SetDelivery(ctx, message.ID, delivery).Return(nil)
+
+	err := services.Message.sendMessage(ctx, message)
+	assert.ErrorIs(t, err, driverErr)
+}
+
+func TestMessage_sendMessage_RecipientSuppressed(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+
+	message := mocked.FakeMessage()
+	userChannel := mocked.FakeUserChannel()
+
+	mocked.Logger.EXPECT().Debug("sending message")
+	mocked.RepositoryMessage.EXPECT().CreateStatus(ctx, message.ID, entity.MessageStatusSending, "Sending a message").Return(nil)
+	mocked.RepositoryUser.EXPECT().FindByChannel(ctx, message.UserID, message.Channel).Return(userChannel, nil)
+	mocked.RepositorySuppression.EXPECT().Exists(ctx, message.Channel, userChannel.Recipient).Return(true, nil)
+
+	err := services.Message.sendMessage(ctx, message)
+	assert.ErrorIs(t, err, RecipientSuppressedErr)
+}
+
+func TestMessage_DryRun(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+
+	message := mocked.FakeMessage()
+	message.ID = services.Message.GenerateID(message.Channel, message.MessageTemplate, message.UserID, message.Timestamp, message.ExternalID)
+	userChannel := mocked.FakeUserChannel()
+
+	mocked.RepositoryUser.EXPECT().Exists(ctx, message.UserID).Return(true, nil).Times(2)
+	mocked.RepositoryTemplate.EXPECT().Find(ctx, messagetemplate.Receipt).Return(nil, repository.TemplateNotFound).Times(2)
+	mocked.RepositoryUser.EXPECT().FindByChannel(ctx, message.UserID, message.Channel).Return(userChannel, nil)
+	mocked.RepositorySuppression.EXPECT().Exists(ctx, message.Channel, userChannel.Recipient).Return(false, nil)
+	mocked.RepositoryUser.EXPECT().FindLocale(ctx, message.UserID).
+		Return(&entity.UserLocale{UserID: message.UserID, Locale: "kk", TimeZone: "Asia/Almaty"}, nil)
+
+	preview, err := services.Message.DryRun(ctx, message.ID, message.Params, nil, "en-US")
+	assert.Nil(t, err)
+	assert.Equal(t, "en", preview.Locale)
+	assert.Equal(t, []messagetemplate.FieldError{{Field: "locale", Message: "is not translated to en-US, rendered in en"}},
+		preview.Warnings)
+	assert.Contains(t, preview.Content.Text, "Order <b>123</b> has been paid")
+
+	_, err = services.Message.DryRun(ctx, message.ID, types.JSON(`{"orderId": 123}`), nil, "")
+	assert.ErrorIs(t, err, messagetemplate.InvalidParamsErr)
+}
+
+func TestMessage_DryRun_Untracked(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+
+	message := mocked.FakeMessage()
+	message.Channel = channel.Email
+	message.MessageTemplate = 2
+	message.ID = services.Message.GenerateID(message.Channel, message.MessageTemplate, message.UserID, message.Timestamp, message.ExternalID)
+	userChannel := mocked.FakeUserChannel()
+	userChannel.Channel = channel.Email
+
+	mocked.RepositoryUser.EXPECT().Exists(ctx, message.UserID).Return(true, nil)
+	mocked.RepositoryTemplate.EXPECT().Exists(ctx, message.MessageTemplate).Return(true, nil)
+	mocked.RepositoryTemplate.EXPECT().Find(ctx, message.MessageTemplate).
+		Return(&entity.Template{
+			ID:             2,
+			Name:           "Shipped",
+			Category:       messagetemplate.CategoryTransactional,
+			Tracking:       true,
+			CurrentVersion: 1,
+		}, nil).AnyTimes()
+	mocked.RepositoryTemplate.EXPECT().FindVersion(ctx, message.MessageTemplate, 1).
+		Return(&entity.TemplateVersion{
+			TemplateID: 2,
+			Version:    1,
+			Bodies: messagetemplate.Bodies{"email": {
+				Body:    `<a href="https://shop.kz/orders/{{.orderId}}">Order</a>`,
+				Subject: "Shipped",
+			}},
+		}, nil)
+	mocked.RepositoryUser.EXPECT().FindByChannel(ctx, message.UserID, message.Channel).Return(userChannel, nil)
+	mocked.RepositorySuppression.EXPECT().Exists(ctx, message.Channel, userChannel.Recipient).Return(false, nil)
+	mocked.RepositoryUser.EXPECT().FindLocale(ctx, message.UserID).Return(&entity.UserLocale{UserID: message.UserID}, nil)
+
+	preview, err := services.Message.DryRun(ctx, message.ID, types.JSON(`{"orderId": 123}`), nil, "")
+	assert.Nil(t, err)
+	assert.Equal(t, `<a href="https://shop.kz/orders/123">Order</a>`, preview.Content.Text)
+}
+
+func TestMessage_Preview(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+
+	mocked.RepositoryTemplate.EXPECT().Find(ctx, messagetemplate.Receipt).Return(nil, repository.TemplateNotFound).Times(3)
+
+	preview, err := services.Message.Preview(ctx, messagetemplate.Receipt, channel.Email,
+		types.JSON(`{"orderId": 123, "totalAmount": 1001}`), "kk")
+	assert.Nil(t, err)
+	assert.Equal(t, "ru", preview.Locale)
+	assert.Equal(t, []messagetemplate.FieldError{
+		{Field: "commissionAmount", Message: "is required"},
+		{Field: "locale", Message: "is not translated to kk, rendered in ru"},
+	}, preview.Warnings)
+	assert.Equal(t, "Чек по заказу 123", preview.Content.Subject)
+
+	_, err = services.Message.Preview(ctx, messagetemplate.Receipt, channel.Email, types.JSON(`{"orderId": "x"}`), "")
+	assert.ErrorIs(t, err, RenderFailedErr)
+
+	_, err = services.Message.Preview(ctx, messagetemplate.Receipt, channel.Email, nil, "not a locale")
+	assert.ErrorIs(t, err, InvalidLocaleErr)
+
+	_, err = services.Message.Preview(ctx, messagetemplate.Receipt, channel.Channel(0), nil, "")
+	assert.ErrorIs(t, err, InvalidChannelErr)
+}
+
+func TestMessage_Edit(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+
+	userChannel := mocked.FakeUserChannel()
+	userChannel.Channel = channel.Telegram
+	params := []byte(`{"orderId": 123, "commissionAmount": 2, "totalAmount": 1002}`)
+
+	cases := []struct {
+		name          string
+		channel       channel.Channel
+		providerID    string
+		editErr       error
+		expectedError error
+	}{
+		{
+			name:       "ok",
+			channel:    channel.Telegram,
+			providerID: "42",
+		},
+		{
+			name:          "not delivered",
+			channel:       channel.Telegram,
+			expectedError: MessageNotDeliveredErr,
+		},
+		{
+			name:          "unsupported channel",
+			channel:       channel.Mock,
+			providerID:    "42",
+			expectedError: channel.EditUnsupportedErr,
+		},
+		{
+			name:          "driver error",
+			channel:       channel.Telegram,
+			providerID:    "42",
+			editErr:       &telegram.Error{Code: 400, Description: "Bad Request: message to edit not found"},
+			expectedError: &telegram.Error{Code: 400, Description: "Bad Request: message to edit not found"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			message := mocked.FakeMessage()
+			message.Channel = tc.channel
+			message.ProviderMessageID = tc.providerID
+			message.ID = services.Message.GenerateID(message.Channel, message.MessageTemplate, message.UserID, message.Timestamp, message.ExternalID)
+
+			mocked.RepositoryUser.EXPECT().Exists(ctx, message.UserID).Return(true, nil)
+			mocked.RepositoryMessage.EXPECT().Find(ctx, message.ID).Return(message, nil)
+
+			if tc.channel == channel.Telegram && tc.providerID != "" {
+				mocked.RepositoryUser.EXPECT().FindByChannel(ctx, message.UserID, message.Channel).Return(userChannel, nil)
+				mocked.TelegramBot.EXPECT().Edit(userChannel.Recipient, message.Delivery(), gomock.Any()).
+					DoAndReturn(func(_ string, _ outbound.Delivery, content *outbound.Message) error {
+						assert.Empty(t, content.Media)
+						return tc.editErr
+					})
+
+				if tc.editErr == nil {
+					mocked.RepositoryMessage.EXPECT().UpdateParams(ctx, message.ID, gomock.Any()).Return(nil)
+					mocked.RepositoryMessage.EXPECT().CreateStatus(ctx, message.ID, entity.MessageStatusEdited, "Message edited").Return(nil)
+				}
+			}
+
+			err := services.Message.Edit(ctx, message.ID, params)
+			if tc.expectedError == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.expectedError.Error())
+			}
+		})
+	}
+}
+
+func TestMessage_Delete(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+
+	userChannel := mocked.FakeUserChannel()
+	userChannel.Channel = channel.Telegram
+
+	message := mocked.FakeMessage()
+	message.Channel = channel.Telegram
+	message.ProviderMessageID = "42"
+	message.ProviderMessageParts = []outbound.Part{{ID: "41", Kind: outbound.PartText}, {ID: "42", Kind: outbound.PartText}}
+	message.ID = services.Message.GenerateID(message.Channel, message.MessageTemplate, message.UserID, message.Timestamp, message.ExternalID)
+
+	mocked.RepositoryUser.EXPECT().Exists(ctx, message.UserID).Return(true, nil)
+	mocked.RepositoryMessage.EXPECT().Find(ctx, message.ID).Return(message, nil)
+	mocked.RepositoryUser.EXPECT().FindByChannel(ctx, message.UserID, message.Channel).Return(userChannel, nil)
+	mocked.TelegramBot.EXPECT().Delete(userChannel.Recipient, outbound.Delivery{ID: "42", Parts: message.ProviderMessageParts}).Return(nil)
+	mocked.RepositoryMessage.EXPECT().CreateStatus(ctx, message.ID, entity.MessageStatusDeleted, "Message deleted").Return(nil)
+
+	assert.Nil(t, services.Message.Delete(ctx, message.ID))
+}
+
+func TestMessage_GetEvents(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+
+	message := mocked.FakeMessage()
+	message.ID = services.Message.GenerateID(message.Channel, message.MessageTemplate, message.UserID, message.Timestamp, message.ExternalID)
+	events := entity.MessageEvents{{MessageID: message.ID, Type: entity.MessageEventOpened, Description: "Message opened"}}
+
+	mocked.RepositoryUser.EXPECT().Exists(ctx, message.UserID).Return(true, nil).Times(2)
+	mocked.RepositoryMessage.EXPECT().Exists(ctx, message.ID).Return(true, nil)
+	mocked.RepositoryMessage.EXPECT().FindEvents(ctx, message.ID).Return(events, nil)
+
+	found, err := services.Message.GetEvents(ctx, message.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, events, found)
+
+	mocked.RepositoryMessage.EXPECT().Exists(ctx, message.ID).Return(false, nil)
 
-	return NewStore(mocked.Logger, repo, channels)
+	_, err = services.Message.GetEvents(ctx, message.ID)
+	assert.ErrorIs(t, err, MessageNotFoundErr)
 }
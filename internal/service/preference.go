@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/keweegen/notification/config"
+	"github.com/keweegen/notification/internal/channel"
+	"github.com/keweegen/notification/internal/entity"
+	"github.com/keweegen/notification/internal/messagetemplate"
+	"github.com/keweegen/notification/internal/repository"
+	"github.com/keweegen/notification/logger"
+	"strings"
+	"time"
+)
+
+const (
+	defaultUnsubscribeTokenTTL = 90 * 24 * time.Hour
+	unsubscribeTokenMACSize    = 16
+)
+
+var (
+	InvalidUnsubscribeTokenErr = errors.New("unsubscribe token: invalid")
+	ExpiredUnsubscribeTokenErr = errors.New("unsubscribe token: expired")
+	RecipientUnsubscribedErr   = errors.New("recipient unsubscribed from the message category")
+)
+
+type unsubscribeToken struct {
+	UserID    int64                    `json:"u"`
+	Channel   channel.Channel          `json:"c"`
+	Category  messagetemplate.Category `json:"k"`
+	ExpiresAt int64                    `json:"e"`
+}
+
+type Preference struct {
+	logger  logger.Logger
+	cfg     config.Unsubscribe
+	baseURL string
+	repo    repository.Preference
+}
+
+func NewPreference(l logger.Logger, cfg config.Unsubscribe, baseURL string, repo repository.Preference) *Preference {
+	return &Preference{
+		logger:  l.With("service", "preference"),
+		cfg:     cfg,
+		baseURL: baseURL,
+		repo:    repo,
+	}
+}
+
+// IsSubscribed reports whether the user receives messages of the category in the channel,
+// transactional messages are always received.
+func (p *Preference) IsSubscribed(
+	ctx context.Context,
+	userID int64,
+	ch channel.Channel,
+	category messagetemplate.Category,
+) (bool, error) {
+	if !category.CanUnsubscribe() {
+		return true, nil
+	}
+	return p.repo.IsSubscribed(ctx, userID, ch, category)
+}
+
+// UnsubscribeURL returns the one-click unsubscribe link of the category. It is empty for transactional
+// categories and while the public address or the secret are not configured.
+func (p *Preference) UnsubscribeURL(userID int64, ch channel.Channel, category messagetemplate.Category) string {
+	if !category.CanUnsubscribe() || p.baseURL == "" || p.cfg.Secret == "" {
+		return ""
+	}
+
+	token := p.makeUnsubscribeToken(unsubscribeToken{
+		UserID:    userID,
+		Channel:   ch,
+		Category:  category,
+		ExpiresAt: time.Now().Add(p.unsubscribeTokenTTL()).Unix(),
+	})
+
+	return strings.TrimRight(p.baseURL, "/") + "/unsubscribe/" + token
+}
+
+// ValidateUnsubscribeToken checks the token without unsubscribing, for the confirmation page.
+func (p *Preference) ValidateUnsubscribeToken(token string) error {
+	_, err := p.parseUnsubscribeToken(token)
+	return err
+}
+
+// Unsubscribe records in the user's preferences that the category is no longer sent to the channel.
+// Repeated requests with the same token succeed.
+func (p *Preference) Unsubscribe(ctx context.Context, token string) error {
+	t, err := p.parseUnsubscribeToken(token)
+	if err != nil {
+		return err
+	}
+
+	preference := &entity.UserPreference{
+		UserID:     t.UserID,
+		Channel:    t.Channel,
+		Category:   t.Category,
+		Subscribed: false,
+	}
+	if err = p.repo.Save(ctx, preference); err != nil {
+		return err
+	}
+
+	p.logger.Info("user unsubscribed", "userId", t.UserID, "channel", t.Channel, "category", t.Category)
+
+	return nil
+}
+
+func (p *Preference) unsubscribeTokenTTL() time.Duration {
+	if p.cfg.TTL <= 0 {
+		return defaultUnsubscribeTokenTTL
+	}
+	return p.cfg.TTL
+}
+
+func (p *Preference) makeUnsubscribeToken(t unsubscribeToken) string {
+	payload, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(p.signUnsubscribeToken(payload))
+}
+
+func (p *Preference) parseUnsubscribeToken(token string) (*unsubscribeToken, error) {
+	encodedPayload, encodedMAC, ok := strings.Cut(token, ".")
+	if !ok || p.cfg.Secret == "" {
+		return nil, InvalidUnsubscribeTokenErr
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, InvalidUnsubscribeTokenErr
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(encodedMAC)
+	if err != nil || !hmac.Equal(mac, p.signUnsubscribeToken(payload)) {
+		return nil, InvalidUnsubscribeTokenErr
+	}
+
+	t := new(unsubscribeToken)
+	if err = json.Unmarshal(payload, t); err != nil || !t.Channel.IsValid() ||
+		!t.Category.IsValid() || !t.Category.CanUnsubscribe() {
+		return nil, InvalidUnsubscribeTokenErr
+	}
+	if time.Now().After(time.Unix(t.ExpiresAt, 0)) {
+		return nil, ExpiredUnsubscribeTokenErr
+	}
+
+	return t, nil
+}
+
+func (p *Preference) signUnsubscribeToken(payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(p.cfg.Secret))
+	mac.Write(payload)
+	return mac.Sum(nil)[:unsubscribeTokenMACSize]
+}
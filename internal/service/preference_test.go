@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/keweegen/notification/internal/channel"
+	"github.com/keweegen/notification/internal/entity"
+	"github.com/keweegen/notification/internal/messagetemplate"
+	"github.com/keweegen/notification/utils"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPreference_Unsubscribe(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+
+	assert.Empty(t, services.Preference.UnsubscribeURL(1, channel.Email, messagetemplate.CategoryTransactional))
+
+	unsubscribeURL := services.Preference.UnsubscribeURL(1, channel.Email, messagetemplate.CategoryMarketing)
+	assert.True(t, strings.HasPrefix(unsubscribeURL, "https://ns.example.com/unsubscribe/"))
+	token := strings.TrimPrefix(unsubscribeURL, "https://ns.example.com/unsubscribe/")
+
+	assert.Nil(t, services.Preference.ValidateUnsubscribeToken(token))
+
+	mocked.RepositoryPreference.EXPECT().Save(ctx, &entity.UserPreference{
+		UserID:   1,
+		Channel:  channel.Email,
+		Category: messagetemplate.CategoryMarketing,
+	}).Return(nil)
+	mocked.Logger.EXPECT().Info("user unsubscribed",
+		"userId", int64(1), "channel", channel.Email, "category", messagetemplate.CategoryMarketing)
+	assert.Nil(t, services.Preference.Unsubscribe(ctx, token))
+
+	assert.ErrorIs(t, services.Preference.Unsubscribe(ctx, token+"x"), InvalidUnsubscribeTokenErr)
+	assert.ErrorIs(t, services.Preference.Unsubscribe(ctx, "payload"), InvalidUnsubscribeTokenErr)
+
+	expired := services.Preference.makeUnsubscribeToken(unsubscribeToken{
+		UserID:    1,
+		Channel:   channel.Email,
+		Category:  messagetemplate.CategoryMarketing,
+		ExpiresAt: time.Now().Add(-time.Minute).Unix(),
+	})
+	assert.ErrorIs(t, services.Preference.Unsubscribe(ctx, expired), ExpiredUnsubscribeTokenErr)
+
+	transactional := services.Preference.makeUnsubscribeToken(unsubscribeToken{
+		UserID:    1,
+		Channel:   channel.Email,
+		Category:  messagetemplate.CategoryTransactional,
+		ExpiresAt: time.Now().Add(time.Minute).Unix(),
+	})
+	assert.ErrorIs(t, services.Preference.Unsubscribe(ctx, transactional), InvalidUnsubscribeTokenErr)
+}
+
+func TestPreference_IsSubscribed(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+
+	subscribed, err := services.Preference.IsSubscribed(ctx, 1, channel.Email, messagetemplate.CategoryTransactional)
+	assert.Nil(t, err)
+	assert.True(t, subscribed)
+
+	mocked.RepositoryPreference.EXPECT().IsSubscribed(ctx, int64(1), channel.Email, messagetemplate.CategoryMarketing).
+		Return(false, nil)
+	subscribed, err = services.Preference.IsSubscribed(ctx, 1, channel.Email, messagetemplate.CategoryMarketing)
+	assert.Nil(t, err)
+	assert.False(t, subscribed)
+}
@@ -1,8 +1,10 @@
 package service
 
 import (
+	"github.com/keweegen/notification/config"
 	"github.com/keweegen/notification/internal/channel"
 	"github.com/keweegen/notification/internal/repository"
+	"github.com/keweegen/notification/internal/webhook"
 	"github.com/keweegen/notification/logger"
 )
 
@@ -10,14 +12,38 @@ type Store struct {
 	Message        *Message
 	MessageChecker *MessageChecker
 	User           *User
+	Telegram       *Telegram
+	File           *File
+	Suppression    *Suppression
+	Mailbox        *Mailbox
+	Preference     *Preference
+	Template       *Template
+	Document       *Document
 }
 
-func NewStore(l logger.Logger, repo *repository.Store, channels *channel.Store) *Store {
-	m := NewMessage(l, repo, channels)
+func NewStore(
+	l logger.Logger,
+	cfg *config.Config,
+	repo *repository.Store,
+	channels *channel.Store,
+	webhooks webhook.Sender,
+) *Store {
+	preference := NewPreference(l, cfg.Unsubscribe, cfg.Actions.BaseURL, repo.Preference)
+	templates := NewTemplate(l, cfg.Templates, repo.Template)
+	documents := NewDocument(cfg.Documents, repo.File)
+	m := NewMessage(l, cfg.Actions, cfg.Locales, repo, channels, webhooks, preference, templates, documents)
+	suppression := NewSuppression(l, cfg.Bounces, repo)
 
 	return &Store{
 		Message:        m,
 		MessageChecker: NewMessageChecker(l, repo, m),
 		User:           NewUser(repo.User),
+		Telegram:       NewTelegram(l, cfg.NotificationChannels.Telegram, repo, channels, m),
+		File:           NewFile(cfg.Files, repo.File),
+		Suppression:    suppression,
+		Mailbox:        NewMailbox(l, cfg.Bounces, suppression),
+		Preference:     preference,
+		Template:       templates,
+		Document:       documents,
 	}
 }
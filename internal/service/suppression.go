@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/keweegen/notification/config"
+	"github.com/keweegen/notification/internal/channel"
+	"github.com/keweegen/notification/internal/channel/email"
+	"github.com/keweegen/notification/internal/entity"
+	"github.com/keweegen/notification/internal/repository"
+	"github.com/keweegen/notification/logger"
+	"strings"
+)
+
+const (
+	defaultSuppressionsLimit = 100
+
+	// suppressionDescriptionLimit is the size of the suppression.description column.
+	suppressionDescriptionLimit = 1024
+)
+
+var (
+	RecipientSuppressedErr = errors.New("recipient is suppressed")
+	InvalidFeedbackErr     = errors.New("invalid feedback")
+)
+
+type Suppression struct {
+	logger logger.Logger
+	cfg    config.Bounces
+	repo   *repository.Store
+}
+
+func NewSuppression(l logger.Logger, cfg config.Bounces, repo *repository.Store) *Suppression {
+	return &Suppression{
+		logger: l.With("service", "suppression"),
+		cfg:    cfg,
+		repo:   repo,
+	}
+}
+
+// ValidateWebhookSecret checks the secret sent by the provider with bounce and complaint events.
+// Events are refused while no secret is configured.
+func (s *Suppression) ValidateWebhookSecret(secret string) bool {
+	return s.cfg.Secret != "" && hmac.Equal([]byte(secret), []byte(s.cfg.Secret))
+}
+
+// Record handles a bounce or a complaint: the bounced or complained event is recorded for the originating
+// message and the recipient is suppressed. Soft bounces are only logged.
+func (s *Suppression) Record(ctx context.Context, ch channel.Channel, feedback email.Feedback) error {
+	l := s.logger.With("type", feedback.Type, "recipient", feedback.Recipient, "providerMessageId", feedback.MessageID)
+
+	event, reason := entity.MessageEventBounced, entity.SuppressionReasonBounce
+	switch feedback.Type {
+	case email.FeedbackBounce:
+	case email.FeedbackComplaint:
+		event, reason = entity.MessageEventComplained, entity.SuppressionReasonComplaint
+	default:
+		return fmt.Errorf("%w: unknown type '%s'", InvalidFeedbackErr, feedback.Type)
+	}
+	if feedback.Recipient == "" {
+		return fmt.Errorf("%w: recipient is empty", InvalidFeedbackErr)
+	}
+
+	if feedback.Type == email.FeedbackBounce && !feedback.Permanent {
+		l.Info("soft bounce", "description", feedback.Description)
+		return nil
+	}
+
+	suppression := &entity.Suppression{
+		Channel:     ch,
+		Recipient:   feedback.Recipient,
+		Reason:      reason,
+		Description: cutDescription(feedback.Description, suppressionDescriptionLimit),
+	}
+
+	if feedback.MessageID != "" {
+		message, err := s.repo.Message.FindByProviderMessageID(ctx, ch, feedback.Recipient, feedback.MessageID)
+		switch {
+		case err == nil:
+			suppression.MessageID = message.ID
+			if err = s.repo.Message.CreateEvent(ctx, message.ID, event,
+				cutDescription(feedback.Description, eventDescriptionLimit)); err != nil {
+				return err
+			}
+		case errors.Is(err, sql.ErrNoRows):
+			l.Info("originating message not found")
+		default:
+			return err
+		}
+	}
+
+	if err := s.repo.Suppression.Create(ctx, suppression); err != nil {
+		return err
+	}
+
+	l.Info("recipient suppressed")
+
+	return nil
+}
+
+func (s *Suppression) Find(ctx context.Context, ch channel.Channel, recipient string) (*entity.Suppression, error) {
+	return s.repo.Suppression.Find(ctx, ch, recipient)
+}
+
+// FindAll returns the suppressions of the channel, the most recent first.
+func (s *Suppression) FindAll(ctx context.Context, ch channel.Channel, limit, offset int) (entity.Suppressions, error) {
+	if limit <= 0 || limit > defaultSuppressionsLimit {
+		limit = defaultSuppressionsLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.repo.Suppression.FindAll(ctx, ch, limit, offset)
+}
+
+// Lift removes the recipient from the suppression list, messages are sent to it again.
+func (s *Suppression) Lift(ctx context.Context, ch channel.Channel, recipient string) error {
+	if err := s.repo.Suppression.Delete(ctx, ch, recipient); err != nil {
+		return err
+	}
+
+	s.logger.Info("suppression lifted", "channel", ch, "recipient", strings.ToLower(recipient))
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/keweegen/notification/internal/channel"
+	"github.com/keweegen/notification/internal/channel/email"
+	"github.com/keweegen/notification/internal/entity"
+	"github.com/keweegen/notification/internal/repository"
+	"github.com/keweegen/notification/utils"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestSuppression_Record(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+	message := mocked.FakeMessage()
+
+	cases := []struct {
+		name                     string
+		feedback                 email.Feedback
+		messageErr               error
+		expectedEvent            string
+		expectedEventDescription string
+		expectedReason           string
+		expectedSuppression      bool
+		expectedDescription      string
+		expectedError            error
+	}{
+		{
+			name: "hard bounce",
+			feedback: email.Feedback{
+				Type: email.FeedbackBounce, Recipient: "user@example.com", MessageID: "<1@example.com>",
+				Description: "550 5.1.1 User unknown", Permanent: true,
+			},
+			expectedEvent:       entity.MessageEventBounced,
+			expectedReason:      entity.SuppressionReasonBounce,
+			expectedSuppression: true,
+		},
+		{
+			name: "long diagnostic",
+			feedback: email.Feedback{
+				Type: email.FeedbackBounce, Recipient: "user@example.com", MessageID: "<1@example.com>",
+				Description: "550 " + strings.Repeat("я", 1100), Permanent: true,
+			},
+			expectedEvent:            entity.MessageEventBounced,
+			expectedEventDescription: "550 " + strings.Repeat("я", 251),
+			expectedReason:           entity.SuppressionReasonBounce,
+			expectedSuppression:      true,
+			expectedDescription:      "550 " + strings.Repeat("я", 1020),
+		},
+		{
+			name: "complaint",
+			feedback: email.Feedback{
+				Type: email.FeedbackComplaint, Recipient: "user@example.com", MessageID: "<1@example.com>",
+				Description: "Feedback-Type: abuse", Permanent: true,
+			},
+			expectedEvent:       entity.MessageEventComplained,
+			expectedReason:      entity.SuppressionReasonComplaint,
+			expectedSuppression: true,
+		},
+		{
+			name: "unknown message",
+			feedback: email.Feedback{
+				Type: email.FeedbackBounce, Recipient: "user@example.com", MessageID: "<2@example.com>",
+				Description: "550 5.1.1 User unknown", Permanent: true,
+			},
+			messageErr:          fmt.Errorf("failed to find message by provider message id: %w", sql.ErrNoRows),
+			expectedReason:      entity.SuppressionReasonBounce,
+			expectedSuppression: true,
+		},
+		{
+			name: "soft bounce",
+			feedback: email.Feedback{
+				Type: email.FeedbackBounce, Recipient: "user@example.com", MessageID: "<1@example.com>",
+				Description: "452 4.2.2 Mailbox full",
+			},
+		},
+		{
+			name:          "unknown type",
+			feedback:      email.Feedback{Type: "delivery", Recipient: "user@example.com"},
+			expectedError: InvalidFeedbackErr,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := tc.feedback
+			if tc.expectedEventDescription == "" {
+				tc.expectedEventDescription = f.Description
+			}
+			if tc.expectedDescription == "" {
+				tc.expectedDescription = f.Description
+			}
+			mocked.Logger.EXPECT().With("type", f.Type, "recipient", f.Recipient, "providerMessageId", f.MessageID).Return(mocked.Logger)
+
+			if tc.expectedError == nil && !tc.expectedSuppression {
+				mocked.Logger.EXPECT().Info("soft bounce", "description", f.Description)
+			}
+			if tc.expectedSuppression {
+				found := message
+				if tc.messageErr != nil {
+					found = nil
+					mocked.Logger.EXPECT().Info("originating message not found")
+				}
+				mocked.RepositoryMessage.EXPECT().FindByProviderMessageID(ctx, channel.Email, f.Recipient, f.MessageID).Return(found, tc.messageErr)
+
+				messageID := ""
+				if tc.expectedEvent != "" {
+					messageID = message.ID
+					mocked.RepositoryMessage.EXPECT().CreateEvent(ctx, message.ID, tc.expectedEvent, tc.expectedEventDescription).Return(nil)
+				}
+				mocked.RepositorySuppression.EXPECT().Create(ctx, &entity.Suppression{
+					Channel:     channel.Email,
+					Recipient:   f.Recipient,
+					Reason:      tc.expectedReason,
+					Description: tc.expectedDescription,
+					MessageID:   messageID,
+				}).Return(nil)
+				mocked.Logger.EXPECT().Info("recipient suppressed")
+			}
+
+			err := services.Suppression.Record(ctx, channel.Email, f)
+			assert.ErrorIs(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestSuppression_Lift(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+
+	mocked.RepositorySuppression.EXPECT().Delete(ctx, channel.Email, "User@Example.com").Return(nil)
+	mocked.Logger.EXPECT().Info("suppression lifted", "channel", channel.Email, "recipient", "user@example.com")
+	assert.Nil(t, services.Suppression.Lift(ctx, channel.Email, "User@Example.com"))
+
+	mocked.RepositorySuppression.EXPECT().Delete(ctx, channel.Email, "user@example.com").Return(repository.SuppressionNotFound)
+	assert.ErrorIs(t, services.Suppression.Lift(ctx, channel.Email, "user@example.com"), repository.SuppressionNotFound)
+}
@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/keweegen/notification/config"
+	"github.com/keweegen/notification/internal/channel"
+	"github.com/keweegen/notification/internal/channel/outbound"
+	"github.com/keweegen/notification/internal/channel/telegram"
+	"github.com/keweegen/notification/internal/entity"
+	"github.com/keweegen/notification/internal/repository"
+	"github.com/keweegen/notification/logger"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultLinkTokenTTL = 15 * time.Minute
+	pollingTimeout      = 30 * time.Second
+
+	linkTokenNonceSize = 6
+	linkTokenMACSize   = 12
+	linkTokenSize      = 8 + 4 + linkTokenNonceSize + linkTokenMACSize
+
+	linkedReply      = "Уведомления подключены. Теперь вы будете получать их в этом чате."
+	invalidLinkReply = "Ссылка недействительна или устарела. Запросите новую ссылку в приложении."
+)
+
+var (
+	TelegramBotNotConfiguredErr = errors.New("telegram bot is not configured")
+	InvalidLinkTokenErr         = errors.New("link token: invalid")
+	ExpiredLinkTokenErr         = errors.New("link token: expired")
+	UsedLinkTokenErr            = errors.New("link token: already used")
+	InvalidCallbackQueryErr     = errors.New("callback query: no message or data")
+	WebhookSecretMissingErr     = errors.New("telegram webhook: no secret token configured")
+)
+
+type Telegram struct {
+	logger    logger.Logger
+	cfg       config.Telegram
+	repoStore *repository.Store
+	bot       telegram.Bot
+	messages  *Message
+}
+
+func NewTelegram(
+	l logger.Logger,
+	cfg config.Telegram,
+	repo *repository.Store,
+	channelStore *channel.Store,
+	messages *Message,
+) *Telegram {
+	bot, _ := channelStore.TelegramBot()
+
+	return &Telegram{
+		logger:    l.With("service", "telegram"),
+		cfg:       cfg,
+		repoStore: repo,
+		bot:       bot,
+		messages:  messages,
+	}
+}
+
+// IssueLink returns a one-time deep link, opening it starts the bot with a token bound to the user.
+func (t *Telegram) IssueLink(ctx context.Context, userID int64) (string, time.Time, error) {
+	if t.cfg.BotName == "" || t.cfg.Link.Secret == "" {
+		return "", time.Time{}, TelegramBotNotConfiguredErr
+	}
+
+	expiresAt := time.Now().Add(t.linkTokenTTL()).Truncate(time.Second)
+
+	token, err := t.makeLinkToken(userID, expiresAt)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	link := url.URL{
+		Scheme:   "https",
+		Host:     "t.me",
+		Path:     t.cfg.BotName,
+		RawQuery: url.Values{"start": []string{token}}.Encode(),
+	}
+
+	return link.String(), expiresAt, nil
+}
+
+// PollUpdates receives updates with long polling until quit is closed.
+func (t *Telegram) PollUpdates(ctx context.Context, quit <-chan struct{}) {
+	if t.bot == nil {
+		t.logger.Error("poll updates", "error", TelegramBotNotConfiguredErr)
+		return
+	}
+
+	if err := t.bot.DeleteWebhook(); err != nil {
+		t.logger.Error("delete webhook before polling", "error", err)
+	}
+
+	var offset int64
+
+	for {
+		select {
+		case <-quit:
+			return
+		default:
+			updates, err := t.bot.GetUpdates(ctx, offset, pollingTimeout)
+			if err != nil {
+				t.logger.Error("get updates", "error", err)
+				time.Sleep(retryTimeout)
+				continue
+			}
+
+			for _, update := range updates {
+				offset = update.ID + 1
+				t.HandleUpdate(ctx, update)
+			}
+		}
+	}
+}
+
+// SetWebhook registers the URL the Bot API delivers updates to, the updates are authenticated
+// with the secret token so it must be configured.
+func (t *Telegram) SetWebhook() error {
+	if t.bot == nil {
+		return TelegramBotNotConfiguredErr
+	}
+	if t.cfg.Updates.SecretToken == "" {
+		return WebhookSecretMissingErr
+	}
+	return t.bot.SetWebhook(t.cfg.Updates.WebhookURL, t.cfg.Updates.SecretToken)
+}
+
+// WebhookEnabled reports whether the updates are received by webhook, the webhook route is not served otherwise.
+func (t *Telegram) WebhookEnabled() bool {
+	return t.cfg.Updates.Mode == "webhook"
+}
+
+// ValidateWebhookSecret checks the secret token header sent by the Bot API with webhook updates,
+// no request is valid while the secret token is not configured.
+func (t *Telegram) ValidateWebhookSecret(secretToken string) bool {
+	if t.cfg.Updates.SecretToken == "" {
+		return false
+	}
+	return hmac.Equal([]byte(secretToken), []byte(t.cfg.Updates.SecretToken))
+}
+
+func (t *Telegram) HandleUpdate(ctx context.Context, update telegram.Update) {
+	if update.CallbackQuery != nil {
+		t.handleCallbackQuery(ctx, update.CallbackQuery)
+		return
+	}
+	if update.Message == nil {
+		return
+	}
+
+	command, argument, _ := strings.Cut(strings.TrimSpace(update.Message.Text), " ")
+	if command != "/start" {
+		return
+	}
+
+	chatID := strconv.FormatInt(update.Message.Chat.ID, 10)
+	l := t.logger.With("updateId", update.ID, "chatId", chatID)
+
+	reply := linkedReply
+	if err := t.linkChat(ctx, chatID, strings.TrimSpace(argument)); err != nil {
+		l.Error("link telegram chat", "error", err)
+		reply = invalidLinkReply
+	}
+
+	if _, err := t.bot.Send(chatID, &outbound.Message{Text: reply}); err != nil {
+		l.Error("send reply", "error", err)
+	}
+}
+
+// handleCallbackQuery records the pressed button of the message it is attached to.
+// The query is always answered, otherwise the button keeps showing a loading indicator.
+func (t *Telegram) handleCallbackQuery(ctx context.Context, query *telegram.CallbackQuery) {
+	l := t.logger.With("callbackQueryId", query.ID)
+
+	if err := t.recordAction(ctx, query); err != nil {
+		l.Error("record telegram action", "error", err)
+	}
+
+	if err := t.bot.AnswerCallbackQuery(query.ID, ""); err != nil {
+		l.Error("answer callback query", "error", err)
+	}
+}
+
+func (t *Telegram) recordAction(ctx context.Context, query *telegram.CallbackQuery) error {
+	if query.Message == nil || query.Data == "" {
+		return InvalidCallbackQueryErr
+	}
+
+	message, err := t.repoStore.Message.FindByProviderMessageID(ctx,
+		channel.Telegram,
+		strconv.FormatInt(query.Message.Chat.ID, 10),
+		strconv.FormatInt(query.Message.ID, 10))
+	if err != nil {
+		return fmt.Errorf("find message: %w", err)
+	}
+
+	return t.messages.RecordAction(ctx, message, query.Data)
+}
+
+func (t *Telegram) linkChat(ctx context.Context, chatID, token string) error {
+	userID, nonce, expiresAt, err := t.parseLinkToken(token)
+	if err != nil {
+		return err
+	}
+
+	ok, err := t.repoStore.LinkToken.Consume(ctx, nonce, time.Until(expiresAt))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return UsedLinkTokenErr
+	}
+
+	userChannel, err := t.repoStore.User.FindByChannel(ctx, userID, channel.Telegram)
+	if errors.Is(err, repository.UserChannelNotFound) {
+		return t.repoStore.User.CreateChannel(ctx, &entity.UserChannel{
+			UserID:    userID,
+			Channel:   channel.Telegram,
+			Recipient: chatID,
+			CanNotify: true,
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	userChannel.Recipient = chatID
+	userChannel.CanNotify = true
+	userChannel.DisabledReason = ""
+	userChannel.DisabledAt = nil
+
+	return t.repoStore.User.UpdateChannel(ctx, userChannel)
+}
+
+func (t *Telegram) linkTokenTTL() time.Duration {
+	if t.cfg.Link.TTL <= 0 {
+		return defaultLinkTokenTTL
+	}
+	return t.cfg.Link.TTL
+}
+
+// makeLinkToken encodes the user id, the expiration time and a random nonce, signed with HMAC-SHA256.
+// The result fits the 64 characters allowed for the /start parameter.
+func (t *Telegram) makeLinkToken(userID int64, expiresAt time.Time) (string, error) {
+	payload := make([]byte, 0, linkTokenSize)
+	payload = binary.BigEndian.AppendUint64(payload, uint64(userID))
+	payload = binary.BigEndian.AppendUint32(payload, uint32(expiresAt.Unix()))
+
+	nonce := make([]byte, linkTokenNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generate link token nonce: %w", err)
+	}
+	payload = append(payload, nonce...)
+	payload = append(payload, t.signLinkToken(payload)...)
+
+	return base64.RawURLEncoding.EncodeToString(payload), nil
+}
+
+func (t *Telegram) parseLinkToken(token string) (int64, string, time.Time, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(data) != linkTokenSize {
+		return 0, "", time.Time{}, InvalidLinkTokenErr
+	}
+
+	payload, mac := data[:linkTokenSize-linkTokenMACSize], data[linkTokenSize-linkTokenMACSize:]
+	if !hmac.Equal(mac, t.signLinkToken(payload)) {
+		return 0, "", time.Time{}, InvalidLinkTokenErr
+	}
+
+	userID := int64(binary.BigEndian.Uint64(payload[:8]))
+	expiresAt := time.Unix(int64(binary.BigEndian.Uint32(payload[8:12])), 0)
+	nonce := hex.EncodeToString(payload[12:])
+
+	if time.Now().After(expiresAt) {
+		return 0, "", time.Time{}, ExpiredLinkTokenErr
+	}
+
+	return userID, nonce, expiresAt, nil
+}
+
+func (t *Telegram) signLinkToken(payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(t.cfg.Link.Secret))
+	mac.Write(payload)
+	return mac.Sum(nil)[:linkTokenMACSize]
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/keweegen/notification/config"
+	"github.com/keweegen/notification/internal/channel"
+	"github.com/keweegen/notification/internal/channel/outbound"
+	"github.com/keweegen/notification/internal/channel/telegram"
+	"github.com/keweegen/notification/internal/entity"
+	"github.com/keweegen/notification/internal/repository"
+	"github.com/keweegen/notification/internal/webhook"
+	"github.com/keweegen/notification/utils"
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestTelegram_IssueLink(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+
+	link, expiresAt, err := services.Telegram.IssueLink(ctx, 1234567890)
+	assert.Nil(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), expiresAt, time.Second)
+
+	u, err := url.Parse(link)
+	assert.Nil(t, err)
+	assert.Equal(t, "t.me", u.Host)
+	assert.Equal(t, "/ns_test_bot", u.Path)
+
+	token := u.Query().Get("start")
+	assert.LessOrEqual(t, len(token), 64)
+
+	userID, nonce, tokenExpiresAt, err := services.Telegram.parseLinkToken(token)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1234567890), userID)
+	assert.NotEmpty(t, nonce)
+	assert.Equal(t, expiresAt.Unix(), tokenExpiresAt.Unix())
+
+	_, _, _, err = services.Telegram.parseLinkToken(token[:len(token)-2] + "AA")
+	assert.Equal(t, InvalidLinkTokenErr, err)
+
+	expired, err := services.Telegram.makeLinkToken(1234567890, time.Now().Add(-time.Second))
+	assert.Nil(t, err)
+	_, _, _, err = services.Telegram.parseLinkToken(expired)
+	assert.Equal(t, ExpiredLinkTokenErr, err)
+}
+
+func TestTelegram_HandleUpdate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+	userID := int64(1234567890)
+	chatID := int64(408354752)
+
+	cases := []struct {
+		name                string
+		text                string
+		tokenUsed           bool
+		existingUserChannel *entity.UserChannel
+		expectedReply       string
+	}{
+		{
+			name:          "create channel",
+			text:          "/start {token}",
+			expectedReply: linkedReply,
+		},
+		{
+			name: "update disabled channel",
+			text: "/start {token}",
+			existingUserChannel: &entity.UserChannel{
+				ID:             1,
+				UserID:         userID,
+				Channel:        channel.Telegram,
+				Recipient:      "1",
+				DisabledReason: "Forbidden: bot was blocked by the user",
+				DisabledAt:     new(time.Time),
+			},
+			expectedReply: linkedReply,
+		},
+		{
+			name:          "used token",
+			text:          "/start {token}",
+			tokenUsed:     true,
+			expectedReply: invalidLinkReply,
+		},
+		{
+			name:          "invalid token",
+			text:          "/start qwerty",
+			expectedReply: invalidLinkReply,
+		},
+		{
+			name: "not a start command",
+			text: "hello",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			link, _, err := services.Telegram.IssueLink(ctx, userID)
+			assert.Nil(t, err)
+			u, _ := url.Parse(link)
+
+			update := telegram.Update{ID: 1, Message: &telegram.Message{
+				Chat: telegram.Chat{ID: chatID},
+				Text: tc.text,
+			}}
+			if tc.text == "/start {token}" {
+				update.Message.Text = "/start " + u.Query().Get("start")
+				mocked.RepositoryLinkToken.EXPECT().Consume(ctx, gomock.Any(), gomock.Any()).Return(!tc.tokenUsed, nil)
+			}
+
+			if tc.expectedReply == "" {
+				services.Telegram.HandleUpdate(ctx, update)
+				return
+			}
+
+			mocked.Logger.EXPECT().With("updateId", update.ID, "chatId", "408354752").Return(mocked.Logger)
+
+			switch {
+			case tc.expectedReply == invalidLinkReply:
+				mocked.Logger.EXPECT().Error("link telegram chat", "error", gomock.Any())
+			case tc.existingUserChannel == nil:
+				mocked.RepositoryUser.EXPECT().FindByChannel(ctx, userID, channel.Telegram).Return(nil, repository.UserChannelNotFound)
+				mocked.RepositoryUser.EXPECT().CreateChannel(ctx, &entity.UserChannel{
+					UserID:    userID,
+					Channel:   channel.Telegram,
+					Recipient: "408354752",
+					CanNotify: true,
+				}).Return(nil)
+			default:
+				mocked.RepositoryUser.EXPECT().FindByChannel(ctx, userID, channel.Telegram).Return(tc.existingUserChannel, nil)
+				mocked.RepositoryUser.EXPECT().UpdateChannel(ctx, &entity.UserChannel{
+					ID:        tc.existingUserChannel.ID,
+					UserID:    userID,
+					Channel:   channel.Telegram,
+					Recipient: "408354752",
+					CanNotify: true,
+				}).Return(nil)
+			}
+
+			mocked.TelegramBot.EXPECT().Send("408354752", &outbound.Message{Text: tc.expectedReply}).Return(outbound.Delivery{ID: "1"}, nil)
+
+			services.Telegram.HandleUpdate(ctx, update)
+		})
+	}
+}
+
+func TestTelegram_HandleUpdate_CallbackQuery(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+
+	message := mocked.FakeMessage()
+	message.Channel = channel.Telegram
+	message.ProviderMessageID = "42"
+
+	update := telegram.Update{ID: 1, CallbackQuery: &telegram.CallbackQuery{
+		ID:      "777",
+		Message: &telegram.Message{ID: 42, Chat: telegram.Chat{ID: 408354752}},
+		Data:    "confirm",
+	}}
+
+	mocked.Logger.EXPECT().With("callbackQueryId", "777").Return(mocked.Logger)
+	mocked.RepositoryMessage.EXPECT().FindByProviderMessageID(ctx, channel.Telegram, "408354752", "42").Return(message, nil)
+	mocked.RepositoryMessage.EXPECT().CreateEvent(ctx, message.ID, entity.MessageEventAction, "Action 'confirm'").Return(nil)
+	mocked.Webhook.EXPECT().Send(ctx, webhook.ActionTriggered, gomock.Any()).Return(nil)
+	mocked.TelegramBot.EXPECT().AnswerCallbackQuery("777", "").Return(nil)
+
+	services.Telegram.HandleUpdate(ctx, update)
+}
+
+func TestTelegram_ValidateWebhookSecret(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+
+	assert.False(t, services.Telegram.ValidateWebhookSecret(""))
+	assert.False(t, services.Telegram.WebhookEnabled())
+	assert.ErrorIs(t, services.Telegram.SetWebhook(), WebhookSecretMissingErr)
+
+	services.Telegram.cfg.Updates = config.TelegramUpdates{Mode: "webhook", SecretToken: "secret"}
+	assert.True(t, services.Telegram.WebhookEnabled())
+	assert.True(t, services.Telegram.ValidateWebhookSecret("secret"))
+	assert.False(t, services.Telegram.ValidateWebhookSecret("wrong"))
+	assert.False(t, services.Telegram.ValidateWebhookSecret(""))
+}
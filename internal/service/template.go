@@ -0,0 +1,574 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/fsnotify/fsnotify"
+	"github.com/keweegen/notification/config"
+	"github.com/keweegen/notification/internal/entity"
+	"github.com/keweegen/notification/internal/messagetemplate"
+	"github.com/keweegen/notification/internal/repository"
+	"github.com/keweegen/notification/logger"
+	"github.com/volatiletech/sqlboiler/v4/types"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	// reloadDelay gathers the file events of one change, editors write a file in several steps.
+	reloadDelay = 200 * time.Millisecond
+	// versionCacheTTL bounds how long a compiled version is reused, another instance of the service
+	// may have changed the template meanwhile.
+	versionCacheTTL = time.Minute
+)
+
+var (
+	InvalidTemplateErr          = errors.New("template: invalid")
+	BuiltInTemplateErr          = errors.New("template: compiled into the service")
+	InvalidTemplateNameErr      = fmt.Errorf("%w: name is required", InvalidTemplateErr)
+	InvalidTemplateCategoryErr  = fmt.Errorf("%w: unknown category", InvalidTemplateErr)
+	InvalidTemplateParamsSchema = fmt.Errorf("%w: params schema", InvalidTemplateErr)
+	TemplateWithoutVersionErr   = errors.New("template: has no version to render with")
+	TemplateConflictErr         = errors.New("template: conflicts with a template of the directory")
+)
+
+// Template manages the templates messages are rendered with. A template of the directory takes
+// precedence over a compiled one with the same id, the database templates are versioned.
+type Template struct {
+	logger    logger.Logger
+	repo      repository.Template
+	directory *messagetemplate.Directory
+
+	mx       sync.RWMutex
+	versions map[versionKey]*compiledVersion
+}
+
+type versionKey struct {
+	id      messagetemplate.MessageTemplate
+	version int
+}
+
+// compiledVersion is a stored version compiled with the template it belongs to.
+type compiledVersion struct {
+	template      messagetemplate.Template
+	schema        types.JSON
+	exampleParams types.JSON
+	expiresAt     time.Time
+}
+
+func NewTemplate(l logger.Logger, cfg config.Templates, repo repository.Template) *Template {
+	t := &Template{
+		logger:   l.With("service", "template"),
+		repo:     repo,
+		versions: make(map[versionKey]*compiledVersion),
+	}
+	if cfg.Dir != "" {
+		t.directory = messagetemplate.NewDirectory(cfg.Dir)
+		t.directory.CheckConflicts(t.checkDirectoryConflict)
+	}
+	return t
+}
+
+// LoadDirectory loads the templates of the directory, it fails when any of them is invalid.
+func (t *Template) LoadDirectory() error {
+	if t.directory == nil {
+		return nil
+	}
+	return t.directory.Load()
+}
+
+// WatchDirectory reloads the templates of the directory when its files change or on SIGHUP.
+func (t *Template) WatchDirectory(quit <-chan struct{}) {
+	if t.directory == nil {
+		return
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.logger.Error("failed to watch template directory", "error", err)
+		return
+	}
+	defer func() { _ = watcher.Close() }()
+	t.watchDirectory(watcher)
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var reload <-chan time.Time
+	for {
+		select {
+		case <-quit:
+			return
+		case <-hup:
+			t.reloadDirectory(watcher)
+		case <-reload:
+			reload = nil
+			t.reloadDirectory(watcher)
+		case _, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			reload = time.After(reloadDelay)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			t.logger.Error("template directory watcher", "error", err)
+		}
+	}
+}
+
+// Create stores the template, the ids taken by the templates of the directory are skipped.
+func (t *Template) Create(ctx context.Context, template *entity.Template) error {
+	template.ID = 0
+	template.CurrentVersion = 0
+	if err := t.validate(template); err != nil {
+		return err
+	}
+	if err := t.checkStoredConflict(template); err != nil {
+		return err
+	}
+
+	for {
+		if err := t.repo.Create(ctx, template); err != nil {
+			return err
+		}
+		if !t.inDirectory(template.ID) {
+			return nil
+		}
+		if err := t.repo.Delete(ctx, template.ID); err != nil {
+			return err
+		}
+		template.ID = 0
+	}
+}
+
+// Update changes the name, the category and the tracking of the template,
+// templates compiled into the service keep their names.
+func (t *Template) Update(ctx context.Context, template *entity.Template) error {
+	if err := t.validate(template); err != nil {
+		return err
+	}
+
+	if template.ID.IsValid() {
+		current, err := t.repo.Find(ctx, template.ID)
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(current.Name, template.Name) {
+			return fmt.Errorf("%w: the name cannot be changed", BuiltInTemplateErr)
+		}
+	} else if err := t.checkStoredConflict(template); err != nil {
+		return err
+	}
+
+	if err := t.repo.Update(ctx, template); err != nil {
+		return err
+	}
+	t.forgetVersions(template.ID)
+
+	return nil
+}
+
+func (t *Template) Find(ctx context.Context, id messagetemplate.MessageTemplate) (*entity.Template, error) {
+	return t.repo.Find(ctx, id)
+}
+
+func (t *Template) FindAll(ctx context.Context) (entity.Templates, error) {
+	return t.repo.FindAll(ctx)
+}
+
+// Delete removes the template with its versions, templates compiled into the service cannot be deleted.
+func (t *Template) Delete(ctx context.Context, id messagetemplate.MessageTemplate) error {
+	if id.IsValid() {
+		return fmt.Errorf("%w: it cannot be deleted", BuiltInTemplateErr)
+	}
+	if err := t.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	t.forgetVersions(id)
+
+	return nil
+}
+
+// CreateVersion compiles the bodies and stores them as the new current version of the template.
+func (t *Template) CreateVersion(ctx context.Context, version *entity.TemplateVersion) error {
+	template, err := t.repo.Find(ctx, version.TemplateID)
+	if err != nil {
+		return err
+	}
+
+	if _, err = messagetemplate.ParseSchema(version.ParamsSchema); err != nil {
+		return fmt.Errorf("%w: %s", InvalidTemplateParamsSchema, err)
+	}
+	_, err = messagetemplate.NewStoredTemplate(
+		template.Name, template.Category, template.Tracking, version.Bodies, version.Catalog, t.layouts())
+	if err != nil {
+		return fmt.Errorf("%w: %s", InvalidTemplateErr, err)
+	}
+
+	return t.repo.CreateVersion(ctx, version)
+}
+
+func (t *Template) FindVersion(
+	ctx context.Context,
+	id messagetemplate.MessageTemplate,
+	version int,
+) (*entity.TemplateVersion, error) {
+	return t.repo.FindVersion(ctx, id, version)
+}
+
+func (t *Template) FindVersions(ctx context.Context, id messagetemplate.MessageTemplate) (entity.TemplateVersions, error) {
+	return t.repo.FindVersions(ctx, id)
+}
+
+// FindID returns the id of the template by its name.
+func (t *Template) FindID(ctx context.Context, name string) (messagetemplate.MessageTemplate, error) {
+	if t.directory != nil {
+		if mt, ok := t.directory.FindID(name); ok {
+			return mt, nil
+		}
+	}
+	if mt, ok := messagetemplate.GetMessageTemplateTypeFromString(name); ok {
+		return mt, nil
+	}
+
+	template, err := t.repo.FindByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, repository.TemplateNotFound) {
+			return 0, InvalidMessageTemplateErr
+		}
+		return 0, err
+	}
+
+	return template.ID, nil
+}
+
+func (t *Template) Exists(ctx context.Context, id messagetemplate.MessageTemplate) (bool, error) {
+	if id.IsValid() || t.inDirectory(id) {
+		return true, nil
+	}
+	if id <= 0 {
+		return false, nil
+	}
+	return t.repo.Exists(ctx, id)
+}
+
+// CurrentVersion returns the version new messages of the template are rendered with,
+// 0 for the directory templates and while a template compiled into the service has no stored versions.
+// Messages of a database template cannot be rendered before its first version is created.
+func (t *Template) CurrentVersion(ctx context.Context, id messagetemplate.MessageTemplate) (int, error) {
+	if t.inDirectory(id) {
+		return 0, nil
+	}
+
+	template, err := t.repo.Find(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.TemplateNotFound) && id.IsValid() {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if template.CurrentVersion == 0 && !id.IsValid() {
+		return 0, TemplateWithoutVersionErr
+	}
+
+	return template.CurrentVersion, nil
+}
+
+// Get returns the template to render a message with: the directory or the compiled one for version 0,
+// the stored version otherwise. Stored versions are compiled once and reused.
+func (t *Template) Get(
+	ctx context.Context,
+	id messagetemplate.MessageTemplate,
+	version int,
+) (messagetemplate.Template, error) {
+	if version == 0 {
+		if t.directory != nil {
+			if tmpl, ok := t.directory.Get(id); ok {
+				return tmpl, nil
+			}
+		}
+		return messagetemplate.GetTemplate(id)
+	}
+
+	compiled, err := t.compiledVersion(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+	return compiled.template, nil
+}
+
+// ParamsSchema returns the JSON Schema of the params of the template version, nil when it has none.
+func (t *Template) ParamsSchema(
+	ctx context.Context,
+	id messagetemplate.MessageTemplate,
+	version int,
+) (types.JSON, error) {
+	if version > 0 {
+		compiled, err := t.compiledVersion(ctx, id, version)
+		if err != nil {
+			return nil, err
+		}
+		return compiled.schema, nil
+	}
+
+	if t.inDirectory(id) {
+		return t.directory.ParamsSchema(id), nil
+	}
+	if tmpl, err := messagetemplate.GetTemplate(id); err == nil {
+		return messagetemplate.GetParamsSchema(tmpl), nil
+	}
+	return nil, nil
+}
+
+// LintTemplates reloads the directory and returns the templates to lint: the compiled ones, those of
+// the directory and the current versions of the stored ones. The templates of the directory failing
+// to load and the stored versions failing to load or compile are returned as errors by name.
+func (t *Template) LintTemplates(ctx context.Context) ([]messagetemplate.LintTemplate, map[string]error, error) {
+	errs := make(map[string]error)
+	if t.directory != nil {
+		directoryErrs, err := t.directory.Reload()
+		if err != nil {
+			return nil, nil, err
+		}
+		for name, err := range directoryErrs {
+			errs[name] = err
+		}
+	}
+
+	templates, err := t.repo.FindAll(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stored := make([]messagetemplate.LintTemplate, 0, len(templates))
+	for _, template := range templates {
+		if template.CurrentVersion == 0 || t.inDirectory(template.ID) {
+			continue
+		}
+
+		compiled, err := t.compiledVersion(ctx, template.ID, template.CurrentVersion)
+		if err != nil {
+			errs[template.Name] = fmt.Errorf("version %d: %w", template.CurrentVersion, err)
+			continue
+		}
+
+		stored = append(stored, messagetemplate.LintTemplate{
+			ID:            template.ID,
+			Name:          template.Name,
+			Template:      compiled.template,
+			ExampleParams: compiled.exampleParams,
+			ParamsSchema:  compiled.schema,
+		})
+	}
+
+	return messagetemplate.LintTemplates(t.directory, stored), errs, nil
+}
+
+// ValidateParams validates the message params against the params schema of the template version,
+// the error wraps messagetemplate.InvalidParamsErr and lists the invalid fields.
+func (t *Template) ValidateParams(
+	ctx context.Context,
+	id messagetemplate.MessageTemplate,
+	version int,
+	params types.JSON,
+) error {
+	schema, err := t.ParamsSchema(ctx, id, version)
+	if err != nil {
+		return err
+	}
+	return messagetemplate.ValidateParams(schema, params)
+}
+
+func (t *Template) validate(template *entity.Template) error {
+	template.Name = strings.TrimSpace(template.Name)
+	if template.Name == "" {
+		return InvalidTemplateNameErr
+	}
+
+	if template.Category == "" {
+		template.Category = messagetemplate.CategoryTransactional
+	}
+	if !template.Category.IsValid() {
+		return InvalidTemplateCategoryErr
+	}
+
+	return nil
+}
+
+// checkStoredConflict refuses a database template taking the name of a template of the directory,
+// the directory template would be found by the name instead.
+func (t *Template) checkStoredConflict(template *entity.Template) error {
+	if t.directory == nil {
+		return nil
+	}
+	if id, ok := t.directory.FindID(template.Name); ok && id != template.ID {
+		return fmt.Errorf("%w: name '%s' is taken by template %d", TemplateConflictErr, template.Name, id)
+	}
+	return nil
+}
+
+// checkDirectoryConflict refuses a directory template taking the id or the name of a database template,
+// it would shadow the stored versions. Templates compiled into the service may be overridden.
+func (t *Template) checkDirectoryConflict(id messagetemplate.MessageTemplate, name string) error {
+	ctx := context.Background()
+
+	if !id.IsValid() {
+		stored, err := t.repo.Find(ctx, id)
+		switch {
+		case err == nil:
+			return fmt.Errorf("%w: id %d is taken by the stored template '%s'", messagetemplate.InvalidManifestErr, id, stored.Name)
+		case !errors.Is(err, repository.TemplateNotFound):
+			return err
+		}
+	}
+
+	stored, err := t.repo.FindByName(ctx, name)
+	switch {
+	case err == nil && stored.ID != id && !stored.ID.IsValid():
+		return fmt.Errorf("%w: name '%s' is taken by the stored template %d", messagetemplate.InvalidManifestErr, name, stored.ID)
+	case err != nil && !errors.Is(err, repository.TemplateNotFound):
+		return err
+	}
+
+	return nil
+}
+
+// compiledVersion returns the stored version compiled, from the cache while it has not expired.
+func (t *Template) compiledVersion(
+	ctx context.Context,
+	id messagetemplate.MessageTemplate,
+	version int,
+) (*compiledVersion, error) {
+	key := versionKey{id: id, version: version}
+
+	t.mx.RLock()
+	compiled, ok := t.versions[key]
+	t.mx.RUnlock()
+	if ok && time.Now().Before(compiled.expiresAt) {
+		return compiled, nil
+	}
+
+	template, err := t.repo.Find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := t.repo.FindVersion(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := messagetemplate.NewStoredTemplate(
+		template.Name, template.Category, template.Tracking, stored.Bodies, stored.Catalog, t.layouts())
+	if err != nil {
+		return nil, err
+	}
+
+	compiled = &compiledVersion{
+		template:      tmpl,
+		schema:        stored.ParamsSchema,
+		exampleParams: stored.ExampleParams,
+		expiresAt:     time.Now().Add(versionCacheTTL),
+	}
+
+	t.mx.Lock()
+	t.versions[key] = compiled
+	t.mx.Unlock()
+
+	return compiled, nil
+}
+
+// forgetVersions drops the compiled versions of the template, all of them when id is 0.
+func (t *Template) forgetVersions(id messagetemplate.MessageTemplate) {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	for key := range t.versions {
+		if id == 0 || key.id == id {
+			delete(t.versions, key)
+		}
+	}
+}
+
+// layouts returns the layouts shared by the templates of the directory, the stored versions use them too.
+func (t *Template) layouts() messagetemplate.Layouts {
+	if t.directory == nil {
+		return nil
+	}
+	return t.directory.Layouts()
+}
+
+func (t *Template) inDirectory(id messagetemplate.MessageTemplate) bool {
+	if t.directory == nil {
+		return false
+	}
+	return t.directory.Has(id)
+}
+
+// watchDirectory watches the directory and its template directories, new ones are added on reload.
+func (t *Template) watchDirectory(watcher *fsnotify.Watcher) {
+	path := t.directory.Path()
+	if err := watcher.Add(path); err != nil {
+		t.logger.Error("failed to watch template directory", "path", path, "error", err)
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		t.logger.Error("failed to read template directory", "path", path, "error", err)
+		return
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(path, entry.Name())
+		if err = watcher.Add(dir); err != nil {
+			t.logger.Error("failed to watch template directory", "path", dir, "error", err)
+		}
+		if entry.Name() == messagetemplate.SharedDir {
+			t.watchSharedDirectory(watcher, dir)
+		}
+	}
+}
+
+// watchSharedDirectory watches the channel directories of the shared layouts.
+func (t *Template) watchSharedDirectory(watcher *fsnotify.Watcher, path string) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		t.logger.Error("failed to read template directory", "path", path, "error", err)
+		return
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(path, entry.Name())
+		if err = watcher.Add(dir); err != nil {
+			t.logger.Error("failed to watch template directory", "path", dir, "error", err)
+		}
+	}
+}
+
+func (t *Template) reloadDirectory(watcher *fsnotify.Watcher) {
+	t.watchDirectory(watcher)
+
+	errs, err := t.directory.Reload()
+	if err != nil {
+		t.logger.Error("failed to reload templates", "error", err)
+		return
+	}
+	for dir, err := range errs {
+		t.logger.Error("template rejected, the previous version is kept", "template", dir, "error", err)
+	}
+	// The stored versions are compiled with the shared layouts of the directory.
+	t.forgetVersions(0)
+
+	t.logger.Info("templates reloaded", "rejected", len(errs))
+}
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/keweegen/notification/config"
+	"github.com/keweegen/notification/internal/channel"
+	"github.com/keweegen/notification/internal/entity"
+	"github.com/keweegen/notification/internal/messagetemplate"
+	"github.com/keweegen/notification/internal/repository"
+	"github.com/keweegen/notification/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/sqlboiler/v4/types"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTemplate_CreateVersion(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+	template := &entity.Template{ID: 2, Name: "Shipped", Category: messagetemplate.CategoryTransactional}
+
+	cases := []struct {
+		name          string
+		version       *entity.TemplateVersion
+		expectedError error
+	}{
+		{
+			name: "valid",
+			version: &entity.TemplateVersion{
+				TemplateID:   2,
+				ParamsSchema: types.JSON(`{"type":"object"}`),
+				Bodies:       messagetemplate.Bodies{"email": {Body: "Order #{{.orderId}}"}},
+			},
+		},
+		{
+			name: "invalid body",
+			version: &entity.TemplateVersion{
+				TemplateID: 2,
+				Bodies:     messagetemplate.Bodies{"email": {Body: "Order #{{.orderId"}},
+			},
+			expectedError: InvalidTemplateErr,
+		},
+		{
+			name: "invalid params schema",
+			version: &entity.TemplateVersion{
+				TemplateID:   2,
+				ParamsSchema: types.JSON(`[]`),
+				Bodies:       messagetemplate.Bodies{"email": {Body: "Order #{{.orderId}}"}},
+			},
+			expectedError: InvalidTemplateParamsSchema,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mocked.RepositoryTemplate.EXPECT().Find(ctx, messagetemplate.MessageTemplate(2)).Return(template, nil)
+			if tc.expectedError == nil {
+				mocked.RepositoryTemplate.EXPECT().CreateVersion(ctx, tc.version).Return(nil)
+			}
+
+			err := services.Template.CreateVersion(ctx, tc.version)
+			assert.True(t, errors.Is(err, tc.expectedError), err)
+		})
+	}
+}
+
+func TestTemplate_Get(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+
+	tmpl, err := services.Template.Get(ctx, messagetemplate.Receipt, 0)
+	assert.Nil(t, err)
+	assert.IsType(t, new(messagetemplate.ReceiptTemplate), tmpl)
+
+	mocked.RepositoryTemplate.EXPECT().Find(ctx, messagetemplate.Receipt).
+		Return(&entity.Template{ID: messagetemplate.Receipt, Name: "Receipt", CurrentVersion: 2}, nil)
+	mocked.RepositoryTemplate.EXPECT().FindVersion(ctx, messagetemplate.Receipt, 1).
+		Return(&entity.TemplateVersion{
+			TemplateID: messagetemplate.Receipt,
+			Version:    1,
+			Bodies:     messagetemplate.Bodies{"telegram": {Body: "Order #{{.orderId}}", Format: "plain"}},
+		}, nil)
+
+	tmpl, err = services.Template.Get(ctx, messagetemplate.Receipt, 1)
+	assert.Nil(t, err)
+	params := types.JSON(`{"orderId":123}`)
+	text, err := messagetemplate.Parse(tmpl, channel.Telegram, params, messagetemplate.Helpers{})
+	assert.Nil(t, err)
+	assert.Equal(t, "Order #123", text)
+
+	cached, err := services.Template.Get(ctx, messagetemplate.Receipt, 1)
+	assert.Nil(t, err)
+	assert.Same(t, tmpl, cached)
+
+	mocked.RepositoryTemplate.EXPECT().Find(ctx, messagetemplate.MessageTemplate(7)).Return(nil, repository.TemplateNotFound)
+	_, err = services.Template.Get(ctx, 7, 1)
+	assert.Equal(t, repository.TemplateNotFound, err)
+}
+
+func TestTemplate_CurrentVersion(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+
+	mocked.RepositoryTemplate.EXPECT().Find(ctx, messagetemplate.Receipt).Return(nil, repository.TemplateNotFound)
+	version, err := services.Template.CurrentVersion(ctx, messagetemplate.Receipt)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, version)
+
+	mocked.RepositoryTemplate.EXPECT().Find(ctx, messagetemplate.MessageTemplate(7)).
+		Return(&entity.Template{ID: 7, Name: "Shipped"}, nil)
+	_, err = services.Template.CurrentVersion(ctx, 7)
+	assert.ErrorIs(t, err, TemplateWithoutVersionErr)
+
+	mocked.RepositoryTemplate.EXPECT().Find(ctx, messagetemplate.MessageTemplate(7)).
+		Return(&entity.Template{ID: 7, Name: "Shipped", CurrentVersion: 3}, nil)
+	version, err = services.Template.CurrentVersion(ctx, 7)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, version)
+}
+
+func TestTemplate_Delete(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+
+	err := services.Template.Delete(ctx, messagetemplate.Receipt)
+	assert.True(t, errors.Is(err, BuiltInTemplateErr), err)
+
+	mocked.RepositoryTemplate.EXPECT().Delete(ctx, messagetemplate.MessageTemplate(2)).Return(nil)
+	assert.Nil(t, services.Template.Delete(ctx, 2))
+}
+
+func TestTemplate_ValidateParams(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+
+	err := services.Template.ValidateParams(ctx, messagetemplate.Receipt, 0, types.JSON(`{"orderId": 123}`))
+	assert.True(t, errors.Is(err, messagetemplate.InvalidParamsErr), err)
+
+	mocked.RepositoryTemplate.EXPECT().Find(ctx, messagetemplate.MessageTemplate(2)).
+		Return(&entity.Template{ID: 2, Name: "Shipped", CurrentVersion: 1}, nil)
+	mocked.RepositoryTemplate.EXPECT().FindVersion(ctx, messagetemplate.MessageTemplate(2), 1).
+		Return(&entity.TemplateVersion{
+			TemplateID:   2,
+			Version:      1,
+			Bodies:       messagetemplate.Bodies{"telegram": {Body: "Shipped by {{.carrier}}"}},
+			ParamsSchema: types.JSON(`{"type":"object","required":["carrier"]}`),
+		}, nil)
+
+	assert.Nil(t, services.Template.ValidateParams(ctx, 2, 1, types.JSON(`{"carrier":"dhl"}`)))
+	assert.EqualError(t, services.Template.ValidateParams(ctx, 2, 1, nil),
+		"template: invalid params: carrier is required")
+}
+
+func TestTemplate_DirectoryConflicts(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.Logger.EXPECT().With("service", "template").Return(mocked.Logger)
+
+	ctx := context.Background()
+	path := t.TempDir()
+	assert.Nil(t, os.MkdirAll(filepath.Join(path, "shipped"), 0o755))
+	assert.Nil(t, os.WriteFile(filepath.Join(path, "shipped", messagetemplate.ManifestFile),
+		[]byte("id: 100\nname: OrderShipped\n"), 0o644))
+	assert.Nil(t, os.WriteFile(filepath.Join(path, "shipped", "telegram.md"), []byte("Order shipped"), 0o644))
+
+	templates := NewTemplate(mocked.Logger, config.Templates{Dir: path}, mocked.RepositoryTemplate)
+
+	mocked.RepositoryTemplate.EXPECT().Find(ctx, messagetemplate.MessageTemplate(100)).
+		Return(&entity.Template{ID: 100, Name: "Shipped"}, nil)
+	err := templates.LoadDirectory()
+	assert.ErrorIs(t, err, messagetemplate.InvalidManifestErr)
+	assert.ErrorContains(t, err, "id 100 is taken by the stored template 'Shipped'")
+
+	mocked.RepositoryTemplate.EXPECT().Find(ctx, messagetemplate.MessageTemplate(100)).Return(nil, repository.TemplateNotFound)
+	mocked.RepositoryTemplate.EXPECT().FindByName(ctx, "OrderShipped").Return(&entity.Template{ID: 7, Name: "OrderShipped"}, nil)
+	err = templates.LoadDirectory()
+	assert.ErrorContains(t, err, "name 'OrderShipped' is taken by the stored template 7")
+
+	mocked.RepositoryTemplate.EXPECT().Find(ctx, messagetemplate.MessageTemplate(100)).Return(nil, repository.TemplateNotFound)
+	mocked.RepositoryTemplate.EXPECT().FindByName(ctx, "OrderShipped").Return(nil, repository.TemplateNotFound)
+	assert.Nil(t, templates.LoadDirectory())
+
+	err = templates.Create(ctx, &entity.Template{Name: "orderShipped"})
+	assert.ErrorIs(t, err, TemplateConflictErr)
+
+	nextID := messagetemplate.MessageTemplate(100)
+	mocked.RepositoryTemplate.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, template *entity.Template) error {
+		template.ID = nextID
+		nextID++
+		return nil
+	}).Times(2)
+	mocked.RepositoryTemplate.EXPECT().Delete(ctx, messagetemplate.MessageTemplate(100)).Return(nil)
+
+	template := &entity.Template{Name: "Delivered"}
+	assert.Nil(t, templates.Create(ctx, template))
+	assert.Equal(t, messagetemplate.MessageTemplate(101), template.ID)
+}
+
+func TestTemplate_LintTemplates(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+
+	shipped := &entity.Template{ID: 2, Name: "Shipped", Category: messagetemplate.CategoryTransactional, CurrentVersion: 1}
+	broken := &entity.Template{ID: 4, Name: "Broken", Category: messagetemplate.CategoryTransactional, CurrentVersion: 3}
+	mocked.RepositoryTemplate.EXPECT().FindAll(ctx).Return(entity.Templates{
+		{ID: messagetemplate.Receipt, Name: "Receipt"},
+		shipped,
+		{ID: 3, Name: "Draft"},
+		broken,
+	}, nil)
+	mocked.RepositoryTemplate.EXPECT().Find(ctx, shipped.ID).Return(shipped, nil)
+	mocked.RepositoryTemplate.EXPECT().FindVersion(ctx, shipped.ID, 1).Return(&entity.TemplateVersion{
+		TemplateID:    shipped.ID,
+		Version:       1,
+		Bodies:        messagetemplate.Bodies{"telegram": {Body: "Shipped by {{.carrier}}"}},
+		ExampleParams: types.JSON(`{"carrier":"dhl"}`),
+	}, nil)
+	mocked.RepositoryTemplate.EXPECT().Find(ctx, broken.ID).Return(broken, nil)
+	mocked.RepositoryTemplate.EXPECT().FindVersion(ctx, broken.ID, 3).Return(nil, repository.TemplateVersionNotFound)
+
+	templates, errs, err := services.Template.LintTemplates(ctx)
+	assert.Nil(t, err)
+	assert.Len(t, errs, 1)
+	assert.ErrorIs(t, errs["Broken"], repository.TemplateVersionNotFound)
+
+	assert.Len(t, templates, 2)
+	assert.Equal(t, "Receipt", templates[0].Name)
+	assert.Equal(t, messagetemplate.MessageTemplate(2), templates[1].ID)
+	assert.Equal(t, types.JSON(`{"carrier":"dhl"}`), templates[1].ExampleParams)
+	assert.Empty(t, messagetemplate.Lint(templates[1]))
+}
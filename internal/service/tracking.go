@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"github.com/keweegen/notification/internal/channel"
+	"github.com/keweegen/notification/internal/entity"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	trackingSignatureSize = 16
+	trackingOpen          = "open"
+	trackingClick         = "click"
+
+	// eventDescriptionLimit is the size of the message_event.description column.
+	eventDescriptionLimit = 255
+)
+
+var InvalidTrackingSignatureErr = errors.New("tracking: invalid signature")
+
+var (
+	trackedLinkPattern = regexp.MustCompile(`(?is)(<a\s[^>]*?href\s*=\s*)("https?://[^"]*"|'https?://[^']*')`)
+	bodyEndPattern     = regexp.MustCompile(`(?i)</body\s*>`)
+)
+
+// HandleOpen records the open of a tracked email, reported by the tracking pixel.
+func (m *Message) HandleOpen(ctx context.Context, messageID, signature string) error {
+	if !m.checkTrackingSignature(signature, trackingOpen, messageID) {
+		return InvalidTrackingSignatureErr
+	}
+
+	return m.recordTracking(ctx, messageID, entity.MessageEventOpened, "Message opened")
+}
+
+// HandleTrackedClick checks the signature of a rewritten link and records the click. Recording
+// failures are only logged, so that the user always gets to the link.
+func (m *Message) HandleTrackedClick(ctx context.Context, messageID, link, signature string) error {
+	if !m.checkTrackingSignature(signature, trackingClick, messageID, link) {
+		return InvalidTrackingSignatureErr
+	}
+
+	description := cutDescription("Link clicked: "+link, eventDescriptionLimit)
+	if err := m.recordTracking(ctx, messageID, entity.MessageEventClicked, description); err != nil {
+		m.logger.Error("record click", "messageId", messageID, "error", err)
+	}
+
+	return nil
+}
+
+// Engagements reports the opens and clicks of the emails created in the period by template.
+func (m *Message) Engagements(ctx context.Context, dateFrom, dateTo time.Time) (entity.Engagements, error) {
+	return m.repoStore.Message.FindEngagements(ctx, channel.Email, dateFrom, dateTo)
+}
+
+func (m *Message) recordTracking(ctx context.Context, messageID, eventType, description string) error {
+	exists, err := m.repoStore.Message.Exists(ctx, messageID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return MessageNotFoundErr
+	}
+
+	return m.repoStore.Message.CreateEvent(ctx, messageID, eventType, description)
+}
+
+// trackEmail rewrites the links of the HTML text to the click redirect and appends the open pixel.
+// Links to the service itself, such as tracked buttons and unsubscribe links, are kept.
+// Without a configured public address the text is not changed.
+func (m *Message) trackEmail(messageID, text string) string {
+	if m.actions.BaseURL == "" || m.actions.Secret == "" {
+		return text
+	}
+	baseURL := strings.TrimRight(m.actions.BaseURL, "/")
+
+	text = trackedLinkPattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := trackedLinkPattern.FindStringSubmatch(match)
+		quoted := parts[2]
+		link := html.UnescapeString(quoted[1 : len(quoted)-1])
+		if strings.HasPrefix(link, baseURL+"/") {
+			return match
+		}
+
+		tracked := baseURL + "/track/click/" + url.PathEscape(messageID) + "?" + url.Values{
+			"u": []string{link},
+			"s": []string{m.signTracking(trackingClick, messageID, link)},
+		}.Encode()
+		return parts[1] + `"` + html.EscapeString(tracked) + `"`
+	})
+
+	pixelURL := baseURL + "/track/open/" + url.PathEscape(messageID) + "?" + url.Values{
+		"s": []string{m.signTracking(trackingOpen, messageID)},
+	}.Encode()
+	pixel := `<img src="` + html.EscapeString(pixelURL) + `" width="1" height="1" alt="" ` +
+		`style="display:block;width:1px;height:1px;border:0">`
+
+	if loc := bodyEndPattern.FindAllStringIndex(text, -1); len(loc) > 0 {
+		end := loc[len(loc)-1][0]
+		return text[:end] + pixel + text[end:]
+	}
+
+	return text + pixel
+}
+
+func (m *Message) checkTrackingSignature(signature string, kind string, values ...string) bool {
+	if m.actions.Secret == "" {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(m.signTracking(kind, values...)))
+}
+
+// signTracking signs the kind of the event with its parameters, the kind keeps the signature
+// of a pixel from being valid for a link and the other way round.
+func (m *Message) signTracking(kind string, values ...string) string {
+	mac := hmac.New(sha256.New, []byte(m.actions.Secret))
+	mac.Write([]byte(kind))
+	for _, value := range values {
+		mac.Write([]byte{'\n'})
+		mac.Write([]byte(value))
+	}
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:trackingSignatureSize])
+}
+
+// cutDescription cuts the description to the limit of characters of its column.
+func cutDescription(description string, limit int) string {
+	if utf8.RuneCountInString(description) <= limit {
+		return description
+	}
+	return string([]rune(description)[:limit])
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/keweegen/notification/internal/entity"
+	"github.com/keweegen/notification/utils"
+	"github.com/stretchr/testify/assert"
+	"html"
+	"net/url"
+	"regexp"
+	"testing"
+)
+
+func TestMessage_trackEmail(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+	messageID := mocked.FakeMessage().ID
+
+	text := `<html><body><p><a href="https://example.com/orders/1?a=1&amp;b=2">Order</a>
+<a href='https://ns.example.com/unsubscribe/token'>Unsubscribe</a> <a href="mailto:help@example.com">Help</a></p></body></html>`
+	tracked := services.Message.trackEmail(messageID, text)
+
+	hrefs := regexp.MustCompile(`href=["']([^"']*)["']`).FindAllStringSubmatch(tracked, -1)
+	assert.Len(t, hrefs, 3)
+	assert.Equal(t, "https://ns.example.com/unsubscribe/token", hrefs[1][1])
+	assert.Equal(t, "mailto:help@example.com", hrefs[2][1])
+
+	click, err := url.Parse(html.UnescapeString(hrefs[0][1]))
+	assert.Nil(t, err)
+	assert.Equal(t, "/track/click/"+messageID, click.Path)
+	assert.Equal(t, "https://example.com/orders/1?a=1&b=2", click.Query().Get("u"))
+
+	pixel := regexp.MustCompile(`<img src="([^"]*)"[^>]*></body>`).FindStringSubmatch(tracked)
+	assert.Len(t, pixel, 2)
+	open, err := url.Parse(html.UnescapeString(pixel[1]))
+	assert.Nil(t, err)
+	assert.Equal(t, "/track/open/"+messageID, open.Path)
+
+	mocked.RepositoryMessage.EXPECT().Exists(ctx, messageID).Return(true, nil).Times(2)
+	mocked.RepositoryMessage.EXPECT().CreateEvent(ctx, messageID, entity.MessageEventClicked,
+		"Link clicked: https://example.com/orders/1?a=1&b=2").Return(nil)
+	mocked.RepositoryMessage.EXPECT().CreateEvent(ctx, messageID, entity.MessageEventOpened, "Message opened").Return(nil)
+
+	assert.Nil(t, services.Message.HandleTrackedClick(ctx, messageID, click.Query().Get("u"), click.Query().Get("s")))
+	assert.Nil(t, services.Message.HandleOpen(ctx, messageID, open.Query().Get("s")))
+
+	assert.ErrorIs(t, services.Message.HandleTrackedClick(ctx, messageID, "https://evil.example.com", click.Query().Get("s")),
+		InvalidTrackingSignatureErr)
+	assert.ErrorIs(t, services.Message.HandleOpen(ctx, messageID, click.Query().Get("s")), InvalidTrackingSignatureErr)
+}
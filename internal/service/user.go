@@ -2,10 +2,17 @@ package service
 
 import (
 	"context"
+	"errors"
 	"github.com/keweegen/notification/internal/entity"
+	"github.com/keweegen/notification/internal/messagetemplate"
 	"github.com/keweegen/notification/internal/repository"
 )
 
+var (
+	InvalidLocaleErr   = errors.New("locale: invalid")
+	InvalidTimeZoneErr = errors.New("timeZone: invalid")
+)
+
 type User struct {
 	repo repository.User
 }
@@ -27,7 +34,14 @@ func (u *User) FindNotificationChannel(ctx context.Context, userChannelID int64)
 	return u.repo.FindChannel(ctx, userChannelID)
 }
 
-func (u *User) UpdateNotificationChannel(ctx context.Context, userChannelID int64, recipient string, canNotify bool) error {
+// UpdateNotificationChannel replaces the recipient and the notify flag, the tracking opt-out is kept when nil.
+func (u *User) UpdateNotificationChannel(
+	ctx context.Context,
+	userChannelID int64,
+	recipient string,
+	canNotify bool,
+	trackingOptOut *bool,
+) error {
 	model, err := u.FindNotificationChannel(ctx, userChannelID)
 	if err != nil {
 		return err
@@ -35,6 +49,14 @@ func (u *User) UpdateNotificationChannel(ctx context.Context, userChannelID int6
 
 	model.Recipient = recipient
 	model.CanNotify = canNotify
+	if trackingOptOut != nil {
+		model.TrackingOptOut = *trackingOptOut
+	}
+
+	if canNotify {
+		model.DisabledReason = ""
+		model.DisabledAt = nil
+	}
 
 	return u.repo.UpdateChannel(ctx, model)
 }
@@ -43,6 +65,26 @@ func (u *User) DestroyNotificationChannel(ctx context.Context, userChannelID int
 	return u.repo.DestroyChannel(ctx, userChannelID)
 }
 
+// SetLocale saves the locale and the IANA time zone messages are rendered in for the user,
+// e.g. kk-KZ and Asia/Almaty. The time zone may be empty, the default one is used then.
+func (u *User) SetLocale(ctx context.Context, userID int64, locale, timeZone string) (*entity.UserLocale, error) {
+	normalized, ok := messagetemplate.NormalizeLocale(locale)
+	if !ok {
+		return nil, InvalidLocaleErr
+	}
+	if _, err := messagetemplate.LoadTimeZone(timeZone); err != nil {
+		return nil, InvalidTimeZoneErr
+	}
+
+	userLocale := &entity.UserLocale{UserID: userID, Locale: normalized, TimeZone: timeZone}
+	return userLocale, u.repo.SaveLocale(ctx, userLocale)
+}
+
+// FindLocale returns the locale of the user, the locale and the time zone are empty when they are not set.
+func (u *User) FindLocale(ctx context.Context, userID int64) (*entity.UserLocale, error) {
+	return u.repo.FindLocale(ctx, userID)
+}
+
 func (u *User) FindNotificationChannels(ctx context.Context, userID int64) (entity.UserChannels, error) {
 	return u.repo.FindChannelsByUser(ctx, userID)
 }
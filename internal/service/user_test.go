@@ -184,7 +184,7 @@ func TestUser_UpdateChannel(t *testing.T) {
 				mocked.RepositoryUser.EXPECT().UpdateChannel(ctx, c.expectedFindUserChannel).Return(c.expectedError)
 			}
 
-			err := services.User.UpdateNotificationChannel(ctx, userChannelID, c.recipient, c.canNotify)
+			err := services.User.UpdateNotificationChannel(ctx, userChannelID, c.recipient, c.canNotify, nil)
 			assert.Equal(t, c.expectedError, err)
 
 			if c.expectedFindUserChannel != nil {
@@ -277,3 +277,57 @@ func TestUser_FindNotificationChannels(t *testing.T) {
 		})
 	}
 }
+
+func TestUser_SetLocale(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mocked := utils.NewMockedInstances(controller)
+	mocked.ExpectLoggerWithServices()
+
+	services := mock(t, mocked)
+	ctx := context.Background()
+
+	cases := []struct {
+		name           string
+		locale         string
+		timeZone       string
+		expectedLocale *entity.UserLocale
+		expectedError  error
+	}{
+		{
+			name:           "ok",
+			locale:         "kk_kz",
+			timeZone:       "Asia/Almaty",
+			expectedLocale: &entity.UserLocale{UserID: 1, Locale: "kk-KZ", TimeZone: "Asia/Almaty"},
+		},
+		{
+			name:           "default time zone",
+			locale:         "ru",
+			expectedLocale: &entity.UserLocale{UserID: 1, Locale: "ru"},
+		},
+		{
+			name:          "invalid locale",
+			locale:        "not a locale",
+			expectedError: InvalidLocaleErr,
+		},
+		{
+			name:          "invalid time zone",
+			locale:        "ru",
+			timeZone:      "Asia/Nowhere",
+			expectedError: InvalidTimeZoneErr,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.expectedLocale != nil {
+				mocked.RepositoryUser.EXPECT().SaveLocale(ctx, tc.expectedLocale).Return(nil)
+			}
+
+			locale, err := services.User.SetLocale(ctx, 1, tc.locale, tc.timeZone)
+			assert.Equal(t, tc.expectedError, err)
+			assert.Equal(t, tc.expectedLocale, locale)
+		})
+	}
+}
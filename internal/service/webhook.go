@@ -0,0 +1,20 @@
+package service
+
+import "time"
+
+type channelDisabledPayload struct {
+	UserChannelID int64      `json:"userChannelId"`
+	UserID        int64      `json:"userId"`
+	Channel       string     `json:"channel"`
+	Recipient     string     `json:"recipient"`
+	Reason        string     `json:"reason"`
+	DisabledAt    *time.Time `json:"disabledAt"`
+}
+
+type actionPayload struct {
+	MessageID   string    `json:"messageId"`
+	ActionID    string    `json:"actionId"`
+	UserID      int64     `json:"userId"`
+	Channel     string    `json:"channel"`
+	TriggeredAt time.Time `json:"triggeredAt"`
+}
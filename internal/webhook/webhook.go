@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/keweegen/notification/config"
+	"net/http"
+	"time"
+)
+
+type Event string
+
+const (
+	ChannelDisabled Event = "channelDisabled"
+	ActionTriggered Event = "action"
+)
+
+//go:generate mockgen -source=webhook.go -destination=./mock/webhook.go
+type Sender interface {
+	Send(ctx context.Context, event Event, payload any) error
+}
+
+type sender struct {
+	urls       map[Event]string
+	httpClient *http.Client
+}
+
+func New(cfg config.Webhooks) Sender {
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &sender{
+		urls: map[Event]string{
+			ChannelDisabled: cfg.ChannelDisabled,
+			ActionTriggered: cfg.Action,
+		},
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Send posts the payload as JSON to the URL configured for the event.
+// Events without a configured URL are silently skipped.
+func (s *sender) Send(ctx context.Context, event Event, payload any) error {
+	url := s.urls[event]
+	if url == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(struct {
+		Event   Event `json:"event"`
+		Payload any   `json:"payload"`
+	}{Event: event, Payload: payload})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to make webhook request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := s.httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("webhook '%s' responded with status %d", event, response.StatusCode)
+	}
+
+	return nil
+}
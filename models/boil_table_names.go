@@ -4,11 +4,25 @@
 package models
 
 var TableNames = struct {
-	Message       string
-	MessageStatus string
-	UserChannel   string
+	File            string
+	Message         string
+	MessageEvent    string
+	MessageStatus   string
+	Suppression     string
+	Template        string
+	TemplateVersion string
+	UserChannel     string
+	UserLocale      string
+	UserPreference  string
 }{
-	Message:       "message",
-	MessageStatus: "message_status",
-	UserChannel:   "user_channel",
+	File:            "file",
+	Message:         "message",
+	MessageEvent:    "message_event",
+	MessageStatus:   "message_status",
+	Suppression:     "suppression",
+	Template:        "template",
+	TemplateVersion: "template_version",
+	UserChannel:     "user_channel",
+	UserLocale:      "user_locale",
+	UserPreference:  "user_preference",
 }
@@ -24,102 +24,86 @@ import (
 
 // Message is an object representing the database table.
 type Message struct {
-	ID         string     `boil:"id" json:"id" toml:"id" yaml:"id"`
-	UserID     int64      `boil:"user_id" json:"user_id" toml:"user_id" yaml:"user_id"`
-	ExternalID int64      `boil:"external_id" json:"external_id" toml:"external_id" yaml:"external_id"`
-	Channel    int16      `boil:"channel" json:"channel" toml:"channel" yaml:"channel"`
-	Template   int16      `boil:"template" json:"template" toml:"template" yaml:"template"`
-	Params     types.JSON `boil:"params" json:"params" toml:"params" yaml:"params"`
-	Timestamp  time.Time  `boil:"timestamp" json:"timestamp" toml:"timestamp" yaml:"timestamp"`
+	ID                   string     `boil:"id" json:"id" toml:"id" yaml:"id"`
+	UserID               int64      `boil:"user_id" json:"user_id" toml:"user_id" yaml:"user_id"`
+	ExternalID           int64      `boil:"external_id" json:"external_id" toml:"external_id" yaml:"external_id"`
+	Channel              int16      `boil:"channel" json:"channel" toml:"channel" yaml:"channel"`
+	Template             int16      `boil:"template" json:"template" toml:"template" yaml:"template"`
+	Params               types.JSON `boil:"params" json:"params" toml:"params" yaml:"params"`
+	Timestamp            time.Time  `boil:"timestamp" json:"timestamp" toml:"timestamp" yaml:"timestamp"`
+	ProviderMessageID    string     `boil:"provider_message_id" json:"provider_message_id" toml:"provider_message_id" yaml:"provider_message_id"`
+	Attachments          types.JSON `boil:"attachments" json:"attachments" toml:"attachments" yaml:"attachments"`
+	TemplateVersion      int        `boil:"template_version" json:"template_version" toml:"template_version" yaml:"template_version"`
+	Locale               string     `boil:"locale" json:"locale" toml:"locale" yaml:"locale"`
+	TimeZone             string     `boil:"time_zone" json:"time_zone" toml:"time_zone" yaml:"time_zone"`
+	ProviderMessageParts types.JSON `boil:"provider_message_parts" json:"provider_message_parts" toml:"provider_message_parts" yaml:"provider_message_parts"`
 
 	R *messageR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L messageL  `boil:"-" json:"-" toml:"-" yaml:"-"`
 }
 
 var MessageColumns = struct {
-	ID         string
-	UserID     string
-	ExternalID string
-	Channel    string
-	Template   string
-	Params     string
-	Timestamp  string
+	ID                   string
+	UserID               string
+	ExternalID           string
+	Channel              string
+	Template             string
+	Params               string
+	Timestamp            string
+	ProviderMessageID    string
+	Attachments          string
+	TemplateVersion      string
+	Locale               string
+	TimeZone             string
+	ProviderMessageParts string
 }{
-	ID:         "id",
-	UserID:     "user_id",
-	ExternalID: "external_id",
-	Channel:    "channel",
-	Template:   "template",
-	Params:     "params",
-	Timestamp:  "timestamp",
+	ID:                   "id",
+	UserID:               "user_id",
+	ExternalID:           "external_id",
+	Channel:              "channel",
+	Template:             "template",
+	Params:               "params",
+	Timestamp:            "timestamp",
+	ProviderMessageID:    "provider_message_id",
+	Attachments:          "attachments",
+	TemplateVersion:      "template_version",
+	Locale:               "locale",
+	TimeZone:             "time_zone",
+	ProviderMessageParts: "provider_message_parts",
 }
 
 var MessageTableColumns = struct {
-	ID         string
-	UserID     string
-	ExternalID string
-	Channel    string
-	Template   string
-	Params     string
-	Timestamp  string
+	ID                   string
+	UserID               string
+	ExternalID           string
+	Channel              string
+	Template             string
+	Params               string
+	Timestamp            string
+	ProviderMessageID    string
+	Attachments          string
+	TemplateVersion      string
+	Locale               string
+	TimeZone             string
+	ProviderMessageParts string
 }{
-	ID:         "message.id",
-	UserID:     "message.user_id",
-	ExternalID: "message.external_id",
-	Channel:    "message.channel",
-	Template:   "message.template",
-	Params:     "message.params",
-	Timestamp:  "message.timestamp",
+	ID:                   "message.id",
+	UserID:               "message.user_id",
+	ExternalID:           "message.external_id",
+	Channel:              "message.channel",
+	Template:             "message.template",
+	Params:               "message.params",
+	Timestamp:            "message.timestamp",
+	ProviderMessageID:    "message.provider_message_id",
+	Attachments:          "message.attachments",
+	TemplateVersion:      "message.template_version",
+	Locale:               "message.locale",
+	TimeZone:             "message.time_zone",
+	ProviderMessageParts: "message.provider_message_parts",
 }
 
 // Generated where
 
-type whereHelperstring struct{ field string }
-
-func (w whereHelperstring) EQ(x string) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.EQ, x) }
-func (w whereHelperstring) NEQ(x string) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.NEQ, x) }
-func (w whereHelperstring) LT(x string) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.LT, x) }
-func (w whereHelperstring) LTE(x string) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.LTE, x) }
-func (w whereHelperstring) GT(x string) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.GT, x) }
-func (w whereHelperstring) GTE(x string) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.GTE, x) }
-func (w whereHelperstring) IN(slice []string) qm.QueryMod {
-	values := make([]interface{}, 0, len(slice))
-	for _, value := range slice {
-		values = append(values, value)
-	}
-	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
-}
-func (w whereHelperstring) NIN(slice []string) qm.QueryMod {
-	values := make([]interface{}, 0, len(slice))
-	for _, value := range slice {
-		values = append(values, value)
-	}
-	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
-}
-
-type whereHelperint64 struct{ field string }
-
-func (w whereHelperint64) EQ(x int64) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.EQ, x) }
-func (w whereHelperint64) NEQ(x int64) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.NEQ, x) }
-func (w whereHelperint64) LT(x int64) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.LT, x) }
-func (w whereHelperint64) LTE(x int64) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.LTE, x) }
-func (w whereHelperint64) GT(x int64) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.GT, x) }
-func (w whereHelperint64) GTE(x int64) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.GTE, x) }
-func (w whereHelperint64) IN(slice []int64) qm.QueryMod {
-	values := make([]interface{}, 0, len(slice))
-	for _, value := range slice {
-		values = append(values, value)
-	}
-	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
-}
-func (w whereHelperint64) NIN(slice []int64) qm.QueryMod {
-	values := make([]interface{}, 0, len(slice))
-	for _, value := range slice {
-		values = append(values, value)
-	}
-	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
-}
-
 type whereHelperint16 struct{ field string }
 
 func (w whereHelperint16) EQ(x int16) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.EQ, x) }
@@ -164,54 +148,71 @@ func (w whereHelpertypes_JSON) GTE(x types.JSON) qm.QueryMod {
 	return qmhelper.Where(w.field, qmhelper.GTE, x)
 }
 
-type whereHelpertime_Time struct{ field string }
+type whereHelperint struct{ field string }
 
-func (w whereHelpertime_Time) EQ(x time.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.EQ, x)
-}
-func (w whereHelpertime_Time) NEQ(x time.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.NEQ, x)
-}
-func (w whereHelpertime_Time) LT(x time.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.LT, x)
-}
-func (w whereHelpertime_Time) LTE(x time.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.LTE, x)
-}
-func (w whereHelpertime_Time) GT(x time.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.GT, x)
+func (w whereHelperint) EQ(x int) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.EQ, x) }
+func (w whereHelperint) NEQ(x int) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.NEQ, x) }
+func (w whereHelperint) LT(x int) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.LT, x) }
+func (w whereHelperint) LTE(x int) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.LTE, x) }
+func (w whereHelperint) GT(x int) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.GT, x) }
+func (w whereHelperint) GTE(x int) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.GTE, x) }
+func (w whereHelperint) IN(slice []int) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
 }
-func (w whereHelpertime_Time) GTE(x time.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.GTE, x)
+func (w whereHelperint) NIN(slice []int) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
 }
 
 var MessageWhere = struct {
-	ID         whereHelperstring
-	UserID     whereHelperint64
-	ExternalID whereHelperint64
-	Channel    whereHelperint16
-	Template   whereHelperint16
-	Params     whereHelpertypes_JSON
-	Timestamp  whereHelpertime_Time
+	ID                   whereHelperstring
+	UserID               whereHelperint64
+	ExternalID           whereHelperint64
+	Channel              whereHelperint16
+	Template             whereHelperint16
+	Params               whereHelpertypes_JSON
+	Timestamp            whereHelpertime_Time
+	ProviderMessageID    whereHelperstring
+	Attachments          whereHelpertypes_JSON
+	TemplateVersion      whereHelperint
+	Locale               whereHelperstring
+	TimeZone             whereHelperstring
+	ProviderMessageParts whereHelpertypes_JSON
 }{
-	ID:         whereHelperstring{field: "\"message\".\"id\""},
-	UserID:     whereHelperint64{field: "\"message\".\"user_id\""},
-	ExternalID: whereHelperint64{field: "\"message\".\"external_id\""},
-	Channel:    whereHelperint16{field: "\"message\".\"channel\""},
-	Template:   whereHelperint16{field: "\"message\".\"template\""},
-	Params:     whereHelpertypes_JSON{field: "\"message\".\"params\""},
-	Timestamp:  whereHelpertime_Time{field: "\"message\".\"timestamp\""},
+	ID:                   whereHelperstring{field: "\"message\".\"id\""},
+	UserID:               whereHelperint64{field: "\"message\".\"user_id\""},
+	ExternalID:           whereHelperint64{field: "\"message\".\"external_id\""},
+	Channel:              whereHelperint16{field: "\"message\".\"channel\""},
+	Template:             whereHelperint16{field: "\"message\".\"template\""},
+	Params:               whereHelpertypes_JSON{field: "\"message\".\"params\""},
+	Timestamp:            whereHelpertime_Time{field: "\"message\".\"timestamp\""},
+	ProviderMessageID:    whereHelperstring{field: "\"message\".\"provider_message_id\""},
+	Attachments:          whereHelpertypes_JSON{field: "\"message\".\"attachments\""},
+	TemplateVersion:      whereHelperint{field: "\"message\".\"template_version\""},
+	Locale:               whereHelperstring{field: "\"message\".\"locale\""},
+	TimeZone:             whereHelperstring{field: "\"message\".\"time_zone\""},
+	ProviderMessageParts: whereHelpertypes_JSON{field: "\"message\".\"provider_message_parts\""},
 }
 
 // MessageRels is where relationship names are stored.
 var MessageRels = struct {
+	MessageEvents   string
 	MessageStatuses string
 }{
+	MessageEvents:   "MessageEvents",
 	MessageStatuses: "MessageStatuses",
 }
 
 // messageR is where relationships are stored.
 type messageR struct {
+	MessageEvents   MessageEventSlice  `boil:"MessageEvents" json:"MessageEvents" toml:"MessageEvents" yaml:"MessageEvents"`
 	MessageStatuses MessageStatusSlice `boil:"MessageStatuses" json:"MessageStatuses" toml:"MessageStatuses" yaml:"MessageStatuses"`
 }
 
@@ -220,6 +221,13 @@ func (*messageR) NewStruct() *messageR {
 	return &messageR{}
 }
 
+func (r *messageR) GetMessageEvents() MessageEventSlice {
+	if r == nil {
+		return nil
+	}
+	return r.MessageEvents
+}
+
 func (r *messageR) GetMessageStatuses() MessageStatusSlice {
 	if r == nil {
 		return nil
@@ -231,9 +239,9 @@ func (r *messageR) GetMessageStatuses() MessageStatusSlice {
 type messageL struct{}
 
 var (
-	messageAllColumns            = []string{"id", "user_id", "external_id", "channel", "template", "params", "timestamp"}
+	messageAllColumns            = []string{"id", "user_id", "external_id", "channel", "template", "params", "timestamp", "provider_message_id", "attachments", "template_version", "locale", "time_zone", "provider_message_parts"}
 	messageColumnsWithoutDefault = []string{"id", "user_id", "external_id", "channel", "template", "timestamp"}
-	messageColumnsWithDefault    = []string{"params"}
+	messageColumnsWithDefault    = []string{"params", "provider_message_id", "attachments", "template_version", "locale", "time_zone", "provider_message_parts"}
 	messagePrimaryKeyColumns     = []string{"id"}
 	messageGeneratedColumns      = []string{}
 )
@@ -516,6 +524,20 @@ func (q messageQuery) Exists(ctx context.Context, exec boil.ContextExecutor) (bo
 	return count > 0, nil
 }
 
+// MessageEvents retrieves all the message_event's MessageEvents with an executor.
+func (o *Message) MessageEvents(mods ...qm.QueryMod) messageEventQuery {
+	var queryMods []qm.QueryMod
+	if len(mods) != 0 {
+		queryMods = append(queryMods, mods...)
+	}
+
+	queryMods = append(queryMods,
+		qm.Where("\"message_event\".\"message_id\"=?", o.ID),
+	)
+
+	return MessageEvents(queryMods...)
+}
+
 // MessageStatuses retrieves all the message_status's MessageStatuses with an executor.
 func (o *Message) MessageStatuses(mods ...qm.QueryMod) messageStatusQuery {
 	var queryMods []qm.QueryMod
@@ -530,6 +552,120 @@ func (o *Message) MessageStatuses(mods ...qm.QueryMod) messageStatusQuery {
 	return MessageStatuses(queryMods...)
 }
 
+// LoadMessageEvents allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-M or N-M relationship.
+func (messageL) LoadMessageEvents(ctx context.Context, e boil.ContextExecutor, singular bool, maybeMessage interface{}, mods queries.Applicator) error {
+	var slice []*Message
+	var object *Message
+
+	if singular {
+		var ok bool
+		object, ok = maybeMessage.(*Message)
+		if !ok {
+			object = new(Message)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybeMessage)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybeMessage))
+			}
+		}
+	} else {
+		s, ok := maybeMessage.(*[]*Message)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybeMessage)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybeMessage))
+			}
+		}
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &messageR{}
+		}
+		args = append(args, object.ID)
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &messageR{}
+			}
+
+			for _, a := range args {
+				if a == obj.ID {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.ID)
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`message_event`),
+		qm.WhereIn(`message_event.message_id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load message_event")
+	}
+
+	var resultSlice []*MessageEvent
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice message_event")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results in eager load on message_event")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for message_event")
+	}
+
+	if len(messageEventAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+	if singular {
+		object.R.MessageEvents = resultSlice
+		for _, foreign := range resultSlice {
+			if foreign.R == nil {
+				foreign.R = &messageEventR{}
+			}
+			foreign.R.Message = object
+		}
+		return nil
+	}
+
+	for _, foreign := range resultSlice {
+		for _, local := range slice {
+			if local.ID == foreign.MessageID {
+				local.R.MessageEvents = append(local.R.MessageEvents, foreign)
+				if foreign.R == nil {
+					foreign.R = &messageEventR{}
+				}
+				foreign.R.Message = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
 // LoadMessageStatuses allows an eager lookup of values, cached into the
 // loaded structs of the objects. This is for a 1-M or N-M relationship.
 func (messageL) LoadMessageStatuses(ctx context.Context, e boil.ContextExecutor, singular bool, maybeMessage interface{}, mods queries.Applicator) error {
@@ -644,6 +780,59 @@ func (messageL) LoadMessageStatuses(ctx context.Context, e boil.ContextExecutor,
 	return nil
 }
 
+// AddMessageEvents adds the given related objects to the existing relationships
+// of the message, optionally inserting them as new records.
+// Appends related to o.R.MessageEvents.
+// Sets related.R.Message appropriately.
+func (o *Message) AddMessageEvents(ctx context.Context, exec boil.ContextExecutor, insert bool, related ...*MessageEvent) error {
+	var err error
+	for _, rel := range related {
+		if insert {
+			rel.MessageID = o.ID
+			if err = rel.Insert(ctx, exec, boil.Infer()); err != nil {
+				return errors.Wrap(err, "failed to insert into foreign table")
+			}
+		} else {
+			updateQuery := fmt.Sprintf(
+				"UPDATE \"message_event\" SET %s WHERE %s",
+				strmangle.SetParamNames("\"", "\"", 1, []string{"message_id"}),
+				strmangle.WhereClause("\"", "\"", 2, messageEventPrimaryKeyColumns),
+			)
+			values := []interface{}{o.ID, rel.ID}
+
+			if boil.IsDebug(ctx) {
+				writer := boil.DebugWriterFrom(ctx)
+				fmt.Fprintln(writer, updateQuery)
+				fmt.Fprintln(writer, values)
+			}
+			if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+				return errors.Wrap(err, "failed to update foreign table")
+			}
+
+			rel.MessageID = o.ID
+		}
+	}
+
+	if o.R == nil {
+		o.R = &messageR{
+			MessageEvents: related,
+		}
+	} else {
+		o.R.MessageEvents = append(o.R.MessageEvents, related...)
+	}
+
+	for _, rel := range related {
+		if rel.R == nil {
+			rel.R = &messageEventR{
+				Message: o,
+			}
+		} else {
+			rel.R.Message = o
+		}
+	}
+	return nil
+}
+
 // AddMessageStatuses adds the given related objects to the existing relationships
 // of the message, optionally inserting them as new records.
 // Appends related to o.R.MessageStatuses.
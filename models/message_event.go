@@ -0,0 +1,1114 @@
+// Code generated by SQLBoiler 4.13.0 (https://github.com/volatiletech/sqlboiler). DO NOT EDIT.
+// This file is meant to be re-generated in place and/or deleted at any time.
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/sqlboiler/v4/queries/qmhelper"
+	"github.com/volatiletech/strmangle"
+)
+
+// MessageEvent is an object representing the database table.
+type MessageEvent struct {
+	ID          int64     `boil:"id" json:"id" toml:"id" yaml:"id"`
+	MessageID   string    `boil:"message_id" json:"message_id" toml:"message_id" yaml:"message_id"`
+	Type        string    `boil:"type" json:"type" toml:"type" yaml:"type"`
+	Description string    `boil:"description" json:"description" toml:"description" yaml:"description"`
+	CreatedAt   time.Time `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+
+	R *messageEventR `boil:"-" json:"-" toml:"-" yaml:"-"`
+	L messageEventL  `boil:"-" json:"-" toml:"-" yaml:"-"`
+}
+
+var MessageEventColumns = struct {
+	ID          string
+	MessageID   string
+	Type        string
+	Description string
+	CreatedAt   string
+}{
+	ID:          "id",
+	MessageID:   "message_id",
+	Type:        "type",
+	Description: "description",
+	CreatedAt:   "created_at",
+}
+
+var MessageEventTableColumns = struct {
+	ID          string
+	MessageID   string
+	Type        string
+	Description string
+	CreatedAt   string
+}{
+	ID:          "message_event.id",
+	MessageID:   "message_event.message_id",
+	Type:        "message_event.type",
+	Description: "message_event.description",
+	CreatedAt:   "message_event.created_at",
+}
+
+// Generated where
+
+var MessageEventWhere = struct {
+	ID          whereHelperint64
+	MessageID   whereHelperstring
+	Type        whereHelperstring
+	Description whereHelperstring
+	CreatedAt   whereHelpertime_Time
+}{
+	ID:          whereHelperint64{field: "\"message_event\".\"id\""},
+	MessageID:   whereHelperstring{field: "\"message_event\".\"message_id\""},
+	Type:        whereHelperstring{field: "\"message_event\".\"type\""},
+	Description: whereHelperstring{field: "\"message_event\".\"description\""},
+	CreatedAt:   whereHelpertime_Time{field: "\"message_event\".\"created_at\""},
+}
+
+// MessageEventRels is where relationship names are stored.
+var MessageEventRels = struct {
+	Message string
+}{
+	Message: "Message",
+}
+
+// messageEventR is where relationships are stored.
+type messageEventR struct {
+	Message *Message `boil:"Message" json:"Message" toml:"Message" yaml:"Message"`
+}
+
+// NewStruct creates a new relationship struct
+func (*messageEventR) NewStruct() *messageEventR {
+	return &messageEventR{}
+}
+
+func (r *messageEventR) GetMessage() *Message {
+	if r == nil {
+		return nil
+	}
+	return r.Message
+}
+
+// messageEventL is where Load methods for each relationship are stored.
+type messageEventL struct{}
+
+var (
+	messageEventAllColumns            = []string{"id", "message_id", "type", "description", "created_at"}
+	messageEventColumnsWithoutDefault = []string{"message_id", "type", "description"}
+	messageEventColumnsWithDefault    = []string{"id", "created_at"}
+	messageEventPrimaryKeyColumns     = []string{"id"}
+	messageEventGeneratedColumns      = []string{}
+)
+
+type (
+	// MessageEventSlice is an alias for a slice of pointers to MessageEvent.
+	// This should almost always be used instead of []MessageEvent.
+	MessageEventSlice []*MessageEvent
+	// MessageEventHook is the signature for custom MessageEvent hook methods
+	MessageEventHook func(context.Context, boil.ContextExecutor, *MessageEvent) error
+
+	messageEventQuery struct {
+		*queries.Query
+	}
+)
+
+// Cache for insert, update and upsert
+var (
+	messageEventType                 = reflect.TypeOf(&MessageEvent{})
+	messageEventMapping              = queries.MakeStructMapping(messageEventType)
+	messageEventPrimaryKeyMapping, _ = queries.BindMapping(messageEventType, messageEventMapping, messageEventPrimaryKeyColumns)
+	messageEventInsertCacheMut       sync.RWMutex
+	messageEventInsertCache          = make(map[string]insertCache)
+	messageEventUpdateCacheMut       sync.RWMutex
+	messageEventUpdateCache          = make(map[string]updateCache)
+	messageEventUpsertCacheMut       sync.RWMutex
+	messageEventUpsertCache          = make(map[string]insertCache)
+)
+
+var (
+	// Force time package dependency for automated UpdatedAt/CreatedAt.
+	_ = time.Second
+	// Force qmhelper dependency for where clause generation (which doesn't
+	// always happen)
+	_ = qmhelper.Where
+)
+
+var messageEventAfterSelectHooks []MessageEventHook
+
+var messageEventBeforeInsertHooks []MessageEventHook
+var messageEventAfterInsertHooks []MessageEventHook
+
+var messageEventBeforeUpdateHooks []MessageEventHook
+var messageEventAfterUpdateHooks []MessageEventHook
+
+var messageEventBeforeDeleteHooks []MessageEventHook
+var messageEventAfterDeleteHooks []MessageEventHook
+
+var messageEventBeforeUpsertHooks []MessageEventHook
+var messageEventAfterUpsertHooks []MessageEventHook
+
+// doAfterSelectHooks executes all "after Select" hooks.
+func (o *MessageEvent) doAfterSelectHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range messageEventAfterSelectHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeInsertHooks executes all "before insert" hooks.
+func (o *MessageEvent) doBeforeInsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range messageEventBeforeInsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterInsertHooks executes all "after Insert" hooks.
+func (o *MessageEvent) doAfterInsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range messageEventAfterInsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpdateHooks executes all "before Update" hooks.
+func (o *MessageEvent) doBeforeUpdateHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range messageEventBeforeUpdateHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpdateHooks executes all "after Update" hooks.
+func (o *MessageEvent) doAfterUpdateHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range messageEventAfterUpdateHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeDeleteHooks executes all "before Delete" hooks.
+func (o *MessageEvent) doBeforeDeleteHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range messageEventBeforeDeleteHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterDeleteHooks executes all "after Delete" hooks.
+func (o *MessageEvent) doAfterDeleteHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range messageEventAfterDeleteHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpsertHooks executes all "before Upsert" hooks.
+func (o *MessageEvent) doBeforeUpsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range messageEventBeforeUpsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpsertHooks executes all "after Upsert" hooks.
+func (o *MessageEvent) doAfterUpsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range messageEventAfterUpsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddMessageEventHook registers your hook function for all future operations.
+func AddMessageEventHook(hookPoint boil.HookPoint, messageEventHook MessageEventHook) {
+	switch hookPoint {
+	case boil.AfterSelectHook:
+		messageEventAfterSelectHooks = append(messageEventAfterSelectHooks, messageEventHook)
+	case boil.BeforeInsertHook:
+		messageEventBeforeInsertHooks = append(messageEventBeforeInsertHooks, messageEventHook)
+	case boil.AfterInsertHook:
+		messageEventAfterInsertHooks = append(messageEventAfterInsertHooks, messageEventHook)
+	case boil.BeforeUpdateHook:
+		messageEventBeforeUpdateHooks = append(messageEventBeforeUpdateHooks, messageEventHook)
+	case boil.AfterUpdateHook:
+		messageEventAfterUpdateHooks = append(messageEventAfterUpdateHooks, messageEventHook)
+	case boil.BeforeDeleteHook:
+		messageEventBeforeDeleteHooks = append(messageEventBeforeDeleteHooks, messageEventHook)
+	case boil.AfterDeleteHook:
+		messageEventAfterDeleteHooks = append(messageEventAfterDeleteHooks, messageEventHook)
+	case boil.BeforeUpsertHook:
+		messageEventBeforeUpsertHooks = append(messageEventBeforeUpsertHooks, messageEventHook)
+	case boil.AfterUpsertHook:
+		messageEventAfterUpsertHooks = append(messageEventAfterUpsertHooks, messageEventHook)
+	}
+}
+
+// One returns a single messageEvent record from the query.
+func (q messageEventQuery) One(ctx context.Context, exec boil.ContextExecutor) (*MessageEvent, error) {
+	o := &MessageEvent{}
+
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Bind(ctx, exec, o)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "models: failed to execute a one query for message_event")
+	}
+
+	if err := o.doAfterSelectHooks(ctx, exec); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// All returns all MessageEvent records from the query.
+func (q messageEventQuery) All(ctx context.Context, exec boil.ContextExecutor) (MessageEventSlice, error) {
+	var o []*MessageEvent
+
+	err := q.Bind(ctx, exec, &o)
+	if err != nil {
+		return nil, errors.Wrap(err, "models: failed to assign all query results to MessageEvent slice")
+	}
+
+	if len(messageEventAfterSelectHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterSelectHooks(ctx, exec); err != nil {
+				return o, err
+			}
+		}
+	}
+
+	return o, nil
+}
+
+// Count returns the count of all MessageEvent records in the query.
+func (q messageEventQuery) Count(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+
+	err := q.Query.QueryRowContext(ctx, exec).Scan(&count)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to count message_event rows")
+	}
+
+	return count, nil
+}
+
+// Exists checks if the row exists in the table.
+func (q messageEventQuery) Exists(ctx context.Context, exec boil.ContextExecutor) (bool, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Query.QueryRowContext(ctx, exec).Scan(&count)
+	if err != nil {
+		return false, errors.Wrap(err, "models: failed to check if message_event exists")
+	}
+
+	return count > 0, nil
+}
+
+// Message pointed to by the foreign key.
+func (o *MessageEvent) Message(mods ...qm.QueryMod) messageQuery {
+	queryMods := []qm.QueryMod{
+		qm.Where("\"id\" = ?", o.MessageID),
+	}
+
+	queryMods = append(queryMods, mods...)
+
+	return Messages(queryMods...)
+}
+
+// LoadMessage allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for an N-1 relationship.
+func (messageEventL) LoadMessage(ctx context.Context, e boil.ContextExecutor, singular bool, maybeMessageEvent interface{}, mods queries.Applicator) error {
+	var slice []*MessageEvent
+	var object *MessageEvent
+
+	if singular {
+		var ok bool
+		object, ok = maybeMessageEvent.(*MessageEvent)
+		if !ok {
+			object = new(MessageEvent)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybeMessageEvent)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybeMessageEvent))
+			}
+		}
+	} else {
+		s, ok := maybeMessageEvent.(*[]*MessageEvent)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybeMessageEvent)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybeMessageEvent))
+			}
+		}
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &messageEventR{}
+		}
+		args = append(args, object.MessageID)
+
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &messageEventR{}
+			}
+
+			for _, a := range args {
+				if a == obj.MessageID {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.MessageID)
+
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`message`),
+		qm.WhereIn(`message.id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load Message")
+	}
+
+	var resultSlice []*Message
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice Message")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results of eager load for message")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for message")
+	}
+
+	if len(messageEventAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(resultSlice) == 0 {
+		return nil
+	}
+
+	if singular {
+		foreign := resultSlice[0]
+		object.R.Message = foreign
+		if foreign.R == nil {
+			foreign.R = &messageR{}
+		}
+		foreign.R.MessageEvents = append(foreign.R.MessageEvents, object)
+		return nil
+	}
+
+	for _, local := range slice {
+		for _, foreign := range resultSlice {
+			if local.MessageID == foreign.ID {
+				local.R.Message = foreign
+				if foreign.R == nil {
+					foreign.R = &messageR{}
+				}
+				foreign.R.MessageEvents = append(foreign.R.MessageEvents, local)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetMessage of the messageEvent to the related item.
+// Sets o.R.Message to related.
+// Adds o to related.R.MessageEvents.
+func (o *MessageEvent) SetMessage(ctx context.Context, exec boil.ContextExecutor, insert bool, related *Message) error {
+	var err error
+	if insert {
+		if err = related.Insert(ctx, exec, boil.Infer()); err != nil {
+			return errors.Wrap(err, "failed to insert into foreign table")
+		}
+	}
+
+	updateQuery := fmt.Sprintf(
+		"UPDATE \"message_event\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, []string{"message_id"}),
+		strmangle.WhereClause("\"", "\"", 2, messageEventPrimaryKeyColumns),
+	)
+	values := []interface{}{related.ID, o.ID}
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, updateQuery)
+		fmt.Fprintln(writer, values)
+	}
+	if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	o.MessageID = related.ID
+	if o.R == nil {
+		o.R = &messageEventR{
+			Message: related,
+		}
+	} else {
+		o.R.Message = related
+	}
+
+	if related.R == nil {
+		related.R = &messageR{
+			MessageEvents: MessageEventSlice{o},
+		}
+	} else {
+		related.R.MessageEvents = append(related.R.MessageEvents, o)
+	}
+
+	return nil
+}
+
+// MessageEvents retrieves all the records using an executor.
+func MessageEvents(mods ...qm.QueryMod) messageEventQuery {
+	mods = append(mods, qm.From("\"message_event\""))
+	q := NewQuery(mods...)
+	if len(queries.GetSelect(q)) == 0 {
+		queries.SetSelect(q, []string{"\"message_event\".*"})
+	}
+
+	return messageEventQuery{q}
+}
+
+// FindMessageEvent retrieves a single record by ID with an executor.
+// If selectCols is empty Find will return all columns.
+func FindMessageEvent(ctx context.Context, exec boil.ContextExecutor, iD int64, selectCols ...string) (*MessageEvent, error) {
+	messageEventObj := &MessageEvent{}
+
+	sel := "*"
+	if len(selectCols) > 0 {
+		sel = strings.Join(strmangle.IdentQuoteSlice(dialect.LQ, dialect.RQ, selectCols), ",")
+	}
+	query := fmt.Sprintf(
+		"select %s from \"message_event\" where \"id\"=$1", sel,
+	)
+
+	q := queries.Raw(query, iD)
+
+	err := q.Bind(ctx, exec, messageEventObj)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "models: unable to select from message_event")
+	}
+
+	if err = messageEventObj.doAfterSelectHooks(ctx, exec); err != nil {
+		return messageEventObj, err
+	}
+
+	return messageEventObj, nil
+}
+
+// Insert a single record using an executor.
+// See boil.Columns.InsertColumnSet documentation to understand column list inference for inserts.
+func (o *MessageEvent) Insert(ctx context.Context, exec boil.ContextExecutor, columns boil.Columns) error {
+	if o == nil {
+		return errors.New("models: no message_event provided for insertion")
+	}
+
+	var err error
+	if !boil.TimestampsAreSkipped(ctx) {
+		currTime := time.Now().In(boil.GetLocation())
+
+		if o.CreatedAt.IsZero() {
+			o.CreatedAt = currTime
+		}
+	}
+
+	if err := o.doBeforeInsertHooks(ctx, exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(messageEventColumnsWithDefault, o)
+
+	key := makeCacheKey(columns, nzDefaults)
+	messageEventInsertCacheMut.RLock()
+	cache, cached := messageEventInsertCache[key]
+	messageEventInsertCacheMut.RUnlock()
+
+	if !cached {
+		wl, returnColumns := columns.InsertColumnSet(
+			messageEventAllColumns,
+			messageEventColumnsWithDefault,
+			messageEventColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		cache.valueMapping, err = queries.BindMapping(messageEventType, messageEventMapping, wl)
+		if err != nil {
+			return err
+		}
+		cache.retMapping, err = queries.BindMapping(messageEventType, messageEventMapping, returnColumns)
+		if err != nil {
+			return err
+		}
+		if len(wl) != 0 {
+			cache.query = fmt.Sprintf("INSERT INTO \"message_event\" (\"%s\") %%sVALUES (%s)%%s", strings.Join(wl, "\",\""), strmangle.Placeholders(dialect.UseIndexPlaceholders, len(wl), 1, 1))
+		} else {
+			cache.query = "INSERT INTO \"message_event\" %sDEFAULT VALUES%s"
+		}
+
+		var queryOutput, queryReturning string
+
+		if len(cache.retMapping) != 0 {
+			queryReturning = fmt.Sprintf(" RETURNING \"%s\"", strings.Join(returnColumns, "\",\""))
+		}
+
+		cache.query = fmt.Sprintf(cache.query, queryOutput, queryReturning)
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, vals)
+	}
+
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRowContext(ctx, cache.query, vals...).Scan(queries.PtrsFromMapping(value, cache.retMapping)...)
+	} else {
+		_, err = exec.ExecContext(ctx, cache.query, vals...)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "models: unable to insert into message_event")
+	}
+
+	if !cached {
+		messageEventInsertCacheMut.Lock()
+		messageEventInsertCache[key] = cache
+		messageEventInsertCacheMut.Unlock()
+	}
+
+	return o.doAfterInsertHooks(ctx, exec)
+}
+
+// Update uses an executor to update the MessageEvent.
+// See boil.Columns.UpdateColumnSet documentation to understand column list inference for updates.
+// Update does not automatically update the record in case of default values. Use .Reload() to refresh the records.
+func (o *MessageEvent) Update(ctx context.Context, exec boil.ContextExecutor, columns boil.Columns) (int64, error) {
+	var err error
+	if err = o.doBeforeUpdateHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+	key := makeCacheKey(columns, nil)
+	messageEventUpdateCacheMut.RLock()
+	cache, cached := messageEventUpdateCache[key]
+	messageEventUpdateCacheMut.RUnlock()
+
+	if !cached {
+		wl := columns.UpdateColumnSet(
+			messageEventAllColumns,
+			messageEventPrimaryKeyColumns,
+		)
+
+		if !columns.IsWhitelist() {
+			wl = strmangle.SetComplement(wl, []string{"created_at"})
+		}
+		if len(wl) == 0 {
+			return 0, errors.New("models: unable to update message_event, could not build whitelist")
+		}
+
+		cache.query = fmt.Sprintf("UPDATE \"message_event\" SET %s WHERE %s",
+			strmangle.SetParamNames("\"", "\"", 1, wl),
+			strmangle.WhereClause("\"", "\"", len(wl)+1, messageEventPrimaryKeyColumns),
+		)
+		cache.valueMapping, err = queries.BindMapping(messageEventType, messageEventMapping, append(wl, messageEventPrimaryKeyColumns...))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	values := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), cache.valueMapping)
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, values)
+	}
+	var result sql.Result
+	result, err = exec.ExecContext(ctx, cache.query, values...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update message_event row")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by update for message_event")
+	}
+
+	if !cached {
+		messageEventUpdateCacheMut.Lock()
+		messageEventUpdateCache[key] = cache
+		messageEventUpdateCacheMut.Unlock()
+	}
+
+	return rowsAff, o.doAfterUpdateHooks(ctx, exec)
+}
+
+// UpdateAll updates all rows with the specified column values.
+func (q messageEventQuery) UpdateAll(ctx context.Context, exec boil.ContextExecutor, cols M) (int64, error) {
+	queries.SetUpdate(q.Query, cols)
+
+	result, err := q.Query.ExecContext(ctx, exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update all for message_event")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to retrieve rows affected for message_event")
+	}
+
+	return rowsAff, nil
+}
+
+// UpdateAll updates all rows with the specified column values, using an executor.
+func (o MessageEventSlice) UpdateAll(ctx context.Context, exec boil.ContextExecutor, cols M) (int64, error) {
+	ln := int64(len(o))
+	if ln == 0 {
+		return 0, nil
+	}
+
+	if len(cols) == 0 {
+		return 0, errors.New("models: update all requires at least one column argument")
+	}
+
+	colNames := make([]string, len(cols))
+	args := make([]interface{}, len(cols))
+
+	i := 0
+	for name, value := range cols {
+		colNames[i] = name
+		args[i] = value
+		i++
+	}
+
+	// Append all of the primary key values for each column
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), messageEventPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := fmt.Sprintf("UPDATE \"message_event\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, colNames),
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), len(colNames)+1, messageEventPrimaryKeyColumns, len(o)))
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args...)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update all in messageEvent slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to retrieve rows affected all in update all messageEvent")
+	}
+	return rowsAff, nil
+}
+
+// Upsert attempts an insert using an executor, and does an update or ignore on conflict.
+// See boil.Columns documentation for how to properly use updateColumns and insertColumns.
+func (o *MessageEvent) Upsert(ctx context.Context, exec boil.ContextExecutor, updateOnConflict bool, conflictColumns []string, updateColumns, insertColumns boil.Columns) error {
+	if o == nil {
+		return errors.New("models: no message_event provided for upsert")
+	}
+	if !boil.TimestampsAreSkipped(ctx) {
+		currTime := time.Now().In(boil.GetLocation())
+
+		if o.CreatedAt.IsZero() {
+			o.CreatedAt = currTime
+		}
+	}
+
+	if err := o.doBeforeUpsertHooks(ctx, exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(messageEventColumnsWithDefault, o)
+
+	// Build cache key in-line uglily - mysql vs psql problems
+	buf := strmangle.GetBuffer()
+	if updateOnConflict {
+		buf.WriteByte('t')
+	} else {
+		buf.WriteByte('f')
+	}
+	buf.WriteByte('.')
+	for _, c := range conflictColumns {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(updateColumns.Kind))
+	for _, c := range updateColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(insertColumns.Kind))
+	for _, c := range insertColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	for _, c := range nzDefaults {
+		buf.WriteString(c)
+	}
+	key := buf.String()
+	strmangle.PutBuffer(buf)
+
+	messageEventUpsertCacheMut.RLock()
+	cache, cached := messageEventUpsertCache[key]
+	messageEventUpsertCacheMut.RUnlock()
+
+	var err error
+
+	if !cached {
+		insert, ret := insertColumns.InsertColumnSet(
+			messageEventAllColumns,
+			messageEventColumnsWithDefault,
+			messageEventColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		update := updateColumns.UpdateColumnSet(
+			messageEventAllColumns,
+			messageEventPrimaryKeyColumns,
+		)
+
+		if updateOnConflict && len(update) == 0 {
+			return errors.New("models: unable to upsert message_event, could not build update column list")
+		}
+
+		conflict := conflictColumns
+		if len(conflict) == 0 {
+			conflict = make([]string, len(messageEventPrimaryKeyColumns))
+			copy(conflict, messageEventPrimaryKeyColumns)
+		}
+		cache.query = buildUpsertQueryPostgres(dialect, "\"message_event\"", updateOnConflict, ret, update, conflict, insert)
+
+		cache.valueMapping, err = queries.BindMapping(messageEventType, messageEventMapping, insert)
+		if err != nil {
+			return err
+		}
+		if len(ret) != 0 {
+			cache.retMapping, err = queries.BindMapping(messageEventType, messageEventMapping, ret)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+	var returns []interface{}
+	if len(cache.retMapping) != 0 {
+		returns = queries.PtrsFromMapping(value, cache.retMapping)
+	}
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, vals)
+	}
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRowContext(ctx, cache.query, vals...).Scan(returns...)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = nil // Postgres doesn't return anything when there's no update
+		}
+	} else {
+		_, err = exec.ExecContext(ctx, cache.query, vals...)
+	}
+	if err != nil {
+		return errors.Wrap(err, "models: unable to upsert message_event")
+	}
+
+	if !cached {
+		messageEventUpsertCacheMut.Lock()
+		messageEventUpsertCache[key] = cache
+		messageEventUpsertCacheMut.Unlock()
+	}
+
+	return o.doAfterUpsertHooks(ctx, exec)
+}
+
+// Delete deletes a single MessageEvent record with an executor.
+// Delete will match against the primary key column to find the record to delete.
+func (o *MessageEvent) Delete(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if o == nil {
+		return 0, errors.New("models: no MessageEvent provided for delete")
+	}
+
+	if err := o.doBeforeDeleteHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+
+	args := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), messageEventPrimaryKeyMapping)
+	sql := "DELETE FROM \"message_event\" WHERE \"id\"=$1"
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args...)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete from message_event")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by delete for message_event")
+	}
+
+	if err := o.doAfterDeleteHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAll deletes all matching rows.
+func (q messageEventQuery) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if q.Query == nil {
+		return 0, errors.New("models: no messageEventQuery provided for delete all")
+	}
+
+	queries.SetDelete(q.Query)
+
+	result, err := q.Query.ExecContext(ctx, exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete all from message_event")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by deleteall for message_event")
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAll deletes all rows in the slice, using an executor.
+func (o MessageEventSlice) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if len(o) == 0 {
+		return 0, nil
+	}
+
+	if len(messageEventBeforeDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doBeforeDeleteHooks(ctx, exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	var args []interface{}
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), messageEventPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "DELETE FROM \"message_event\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, messageEventPrimaryKeyColumns, len(o))
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete all from messageEvent slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by deleteall for message_event")
+	}
+
+	if len(messageEventAfterDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterDeleteHooks(ctx, exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return rowsAff, nil
+}
+
+// Reload refetches the object from the database
+// using the primary keys with an executor.
+func (o *MessageEvent) Reload(ctx context.Context, exec boil.ContextExecutor) error {
+	ret, err := FindMessageEvent(ctx, exec, o.ID)
+	if err != nil {
+		return err
+	}
+
+	*o = *ret
+	return nil
+}
+
+// ReloadAll refetches every row with matching primary key column values
+// and overwrites the original object slice with the newly updated slice.
+func (o *MessageEventSlice) ReloadAll(ctx context.Context, exec boil.ContextExecutor) error {
+	if o == nil || len(*o) == 0 {
+		return nil
+	}
+
+	slice := MessageEventSlice{}
+	var args []interface{}
+	for _, obj := range *o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), messageEventPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "SELECT \"message_event\".* FROM \"message_event\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, messageEventPrimaryKeyColumns, len(*o))
+
+	q := queries.Raw(sql, args...)
+
+	err := q.Bind(ctx, exec, &slice)
+	if err != nil {
+		return errors.Wrap(err, "models: unable to reload all in MessageEventSlice")
+	}
+
+	*o = slice
+
+	return nil
+}
+
+// MessageEventExists checks if the MessageEvent row exists.
+func MessageEventExists(ctx context.Context, exec boil.ContextExecutor, iD int64) (bool, error) {
+	var exists bool
+	sql := "select exists(select 1 from \"message_event\" where \"id\"=$1 limit 1)"
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, iD)
+	}
+	row := exec.QueryRowContext(ctx, sql, iD)
+
+	err := row.Scan(&exists)
+	if err != nil {
+		return false, errors.Wrap(err, "models: unable to check if message_event exists")
+	}
+
+	return exists, nil
+}
@@ -0,0 +1,939 @@
+// Code generated by SQLBoiler 4.13.0 (https://github.com/volatiletech/sqlboiler). DO NOT EDIT.
+// This file is meant to be re-generated in place and/or deleted at any time.
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/sqlboiler/v4/queries/qmhelper"
+	"github.com/volatiletech/strmangle"
+)
+
+// Suppression is an object representing the database table.
+type Suppression struct {
+	ID          int64     `boil:"id" json:"id" toml:"id" yaml:"id"`
+	Channel     int16     `boil:"channel" json:"channel" toml:"channel" yaml:"channel"`
+	Recipient   string    `boil:"recipient" json:"recipient" toml:"recipient" yaml:"recipient"`
+	Reason      string    `boil:"reason" json:"reason" toml:"reason" yaml:"reason"`
+	Description string    `boil:"description" json:"description" toml:"description" yaml:"description"`
+	MessageID   string    `boil:"message_id" json:"message_id" toml:"message_id" yaml:"message_id"`
+	CreatedAt   time.Time `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+
+	R *suppressionR `boil:"-" json:"-" toml:"-" yaml:"-"`
+	L suppressionL  `boil:"-" json:"-" toml:"-" yaml:"-"`
+}
+
+var SuppressionColumns = struct {
+	ID          string
+	Channel     string
+	Recipient   string
+	Reason      string
+	Description string
+	MessageID   string
+	CreatedAt   string
+}{
+	ID:          "id",
+	Channel:     "channel",
+	Recipient:   "recipient",
+	Reason:      "reason",
+	Description: "description",
+	MessageID:   "message_id",
+	CreatedAt:   "created_at",
+}
+
+var SuppressionTableColumns = struct {
+	ID          string
+	Channel     string
+	Recipient   string
+	Reason      string
+	Description string
+	MessageID   string
+	CreatedAt   string
+}{
+	ID:          "suppression.id",
+	Channel:     "suppression.channel",
+	Recipient:   "suppression.recipient",
+	Reason:      "suppression.reason",
+	Description: "suppression.description",
+	MessageID:   "suppression.message_id",
+	CreatedAt:   "suppression.created_at",
+}
+
+// Generated where
+
+var SuppressionWhere = struct {
+	ID          whereHelperint64
+	Channel     whereHelperint16
+	Recipient   whereHelperstring
+	Reason      whereHelperstring
+	Description whereHelperstring
+	MessageID   whereHelperstring
+	CreatedAt   whereHelpertime_Time
+}{
+	ID:          whereHelperint64{field: "\"suppression\".\"id\""},
+	Channel:     whereHelperint16{field: "\"suppression\".\"channel\""},
+	Recipient:   whereHelperstring{field: "\"suppression\".\"recipient\""},
+	Reason:      whereHelperstring{field: "\"suppression\".\"reason\""},
+	Description: whereHelperstring{field: "\"suppression\".\"description\""},
+	MessageID:   whereHelperstring{field: "\"suppression\".\"message_id\""},
+	CreatedAt:   whereHelpertime_Time{field: "\"suppression\".\"created_at\""},
+}
+
+// SuppressionRels is where relationship names are stored.
+var SuppressionRels = struct {
+}{}
+
+// suppressionR is where relationships are stored.
+type suppressionR struct {
+}
+
+// NewStruct creates a new relationship struct
+func (*suppressionR) NewStruct() *suppressionR {
+	return &suppressionR{}
+}
+
+// suppressionL is where Load methods for each relationship are stored.
+type suppressionL struct{}
+
+var (
+	suppressionAllColumns            = []string{"id", "channel", "recipient", "reason", "description", "message_id", "created_at"}
+	suppressionColumnsWithoutDefault = []string{"channel", "recipient", "reason"}
+	suppressionColumnsWithDefault    = []string{"id", "description", "message_id", "created_at"}
+	suppressionPrimaryKeyColumns     = []string{"id"}
+	suppressionGeneratedColumns      = []string{}
+)
+
+type (
+	// SuppressionSlice is an alias for a slice of pointers to Suppression.
+	// This should almost always be used instead of []Suppression.
+	SuppressionSlice []*Suppression
+	// SuppressionHook is the signature for custom Suppression hook methods
+	SuppressionHook func(context.Context, boil.ContextExecutor, *Suppression) error
+
+	suppressionQuery struct {
+		*queries.Query
+	}
+)
+
+// Cache for insert, update and upsert
+var (
+	suppressionType                 = reflect.TypeOf(&Suppression{})
+	suppressionMapping              = queries.MakeStructMapping(suppressionType)
+	suppressionPrimaryKeyMapping, _ = queries.BindMapping(suppressionType, suppressionMapping, suppressionPrimaryKeyColumns)
+	suppressionInsertCacheMut       sync.RWMutex
+	suppressionInsertCache          = make(map[string]insertCache)
+	suppressionUpdateCacheMut       sync.RWMutex
+	suppressionUpdateCache          = make(map[string]updateCache)
+	suppressionUpsertCacheMut       sync.RWMutex
+	suppressionUpsertCache          = make(map[string]insertCache)
+)
+
+var (
+	// Force time package dependency for automated UpdatedAt/CreatedAt.
+	_ = time.Second
+	// Force qmhelper dependency for where clause generation (which doesn't
+	// always happen)
+	_ = qmhelper.Where
+)
+
+var suppressionAfterSelectHooks []SuppressionHook
+
+var suppressionBeforeInsertHooks []SuppressionHook
+var suppressionAfterInsertHooks []SuppressionHook
+
+var suppressionBeforeUpdateHooks []SuppressionHook
+var suppressionAfterUpdateHooks []SuppressionHook
+
+var suppressionBeforeDeleteHooks []SuppressionHook
+var suppressionAfterDeleteHooks []SuppressionHook
+
+var suppressionBeforeUpsertHooks []SuppressionHook
+var suppressionAfterUpsertHooks []SuppressionHook
+
+// doAfterSelectHooks executes all "after Select" hooks.
+func (o *Suppression) doAfterSelectHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range suppressionAfterSelectHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeInsertHooks executes all "before insert" hooks.
+func (o *Suppression) doBeforeInsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range suppressionBeforeInsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterInsertHooks executes all "after Insert" hooks.
+func (o *Suppression) doAfterInsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range suppressionAfterInsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpdateHooks executes all "before Update" hooks.
+func (o *Suppression) doBeforeUpdateHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range suppressionBeforeUpdateHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpdateHooks executes all "after Update" hooks.
+func (o *Suppression) doAfterUpdateHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range suppressionAfterUpdateHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeDeleteHooks executes all "before Delete" hooks.
+func (o *Suppression) doBeforeDeleteHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range suppressionBeforeDeleteHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterDeleteHooks executes all "after Delete" hooks.
+func (o *Suppression) doAfterDeleteHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range suppressionAfterDeleteHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpsertHooks executes all "before Upsert" hooks.
+func (o *Suppression) doBeforeUpsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range suppressionBeforeUpsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpsertHooks executes all "after Upsert" hooks.
+func (o *Suppression) doAfterUpsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range suppressionAfterUpsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddSuppressionHook registers your hook function for all future operations.
+func AddSuppressionHook(hookPoint boil.HookPoint, suppressionHook SuppressionHook) {
+	switch hookPoint {
+	case boil.AfterSelectHook:
+		suppressionAfterSelectHooks = append(suppressionAfterSelectHooks, suppressionHook)
+	case boil.BeforeInsertHook:
+		suppressionBeforeInsertHooks = append(suppressionBeforeInsertHooks, suppressionHook)
+	case boil.AfterInsertHook:
+		suppressionAfterInsertHooks = append(suppressionAfterInsertHooks, suppressionHook)
+	case boil.BeforeUpdateHook:
+		suppressionBeforeUpdateHooks = append(suppressionBeforeUpdateHooks, suppressionHook)
+	case boil.AfterUpdateHook:
+		suppressionAfterUpdateHooks = append(suppressionAfterUpdateHooks, suppressionHook)
+	case boil.BeforeDeleteHook:
+		suppressionBeforeDeleteHooks = append(suppressionBeforeDeleteHooks, suppressionHook)
+	case boil.AfterDeleteHook:
+		suppressionAfterDeleteHooks = append(suppressionAfterDeleteHooks, suppressionHook)
+	case boil.BeforeUpsertHook:
+		suppressionBeforeUpsertHooks = append(suppressionBeforeUpsertHooks, suppressionHook)
+	case boil.AfterUpsertHook:
+		suppressionAfterUpsertHooks = append(suppressionAfterUpsertHooks, suppressionHook)
+	}
+}
+
+// One returns a single suppression record from the query.
+func (q suppressionQuery) One(ctx context.Context, exec boil.ContextExecutor) (*Suppression, error) {
+	o := &Suppression{}
+
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Bind(ctx, exec, o)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "models: failed to execute a one query for suppression")
+	}
+
+	if err := o.doAfterSelectHooks(ctx, exec); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// All returns all Suppression records from the query.
+func (q suppressionQuery) All(ctx context.Context, exec boil.ContextExecutor) (SuppressionSlice, error) {
+	var o []*Suppression
+
+	err := q.Bind(ctx, exec, &o)
+	if err != nil {
+		return nil, errors.Wrap(err, "models: failed to assign all query results to Suppression slice")
+	}
+
+	if len(suppressionAfterSelectHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterSelectHooks(ctx, exec); err != nil {
+				return o, err
+			}
+		}
+	}
+
+	return o, nil
+}
+
+// Count returns the count of all Suppression records in the query.
+func (q suppressionQuery) Count(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+
+	err := q.Query.QueryRowContext(ctx, exec).Scan(&count)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to count suppression rows")
+	}
+
+	return count, nil
+}
+
+// Exists checks if the row exists in the table.
+func (q suppressionQuery) Exists(ctx context.Context, exec boil.ContextExecutor) (bool, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Query.QueryRowContext(ctx, exec).Scan(&count)
+	if err != nil {
+		return false, errors.Wrap(err, "models: failed to check if suppression exists")
+	}
+
+	return count > 0, nil
+}
+
+// Suppressions retrieves all the records using an executor.
+func Suppressions(mods ...qm.QueryMod) suppressionQuery {
+	mods = append(mods, qm.From("\"suppression\""))
+	q := NewQuery(mods...)
+	if len(queries.GetSelect(q)) == 0 {
+		queries.SetSelect(q, []string{"\"suppression\".*"})
+	}
+
+	return suppressionQuery{q}
+}
+
+// FindSuppression retrieves a single record by ID with an executor.
+// If selectCols is empty Find will return all columns.
+func FindSuppression(ctx context.Context, exec boil.ContextExecutor, iD int64, selectCols ...string) (*Suppression, error) {
+	suppressionObj := &Suppression{}
+
+	sel := "*"
+	if len(selectCols) > 0 {
+		sel = strings.Join(strmangle.IdentQuoteSlice(dialect.LQ, dialect.RQ, selectCols), ",")
+	}
+	query := fmt.Sprintf(
+		"select %s from \"suppression\" where \"id\"=$1", sel,
+	)
+
+	q := queries.Raw(query, iD)
+
+	err := q.Bind(ctx, exec, suppressionObj)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "models: unable to select from suppression")
+	}
+
+	if err = suppressionObj.doAfterSelectHooks(ctx, exec); err != nil {
+		return suppressionObj, err
+	}
+
+	return suppressionObj, nil
+}
+
+// Insert a single record using an executor.
+// See boil.Columns.InsertColumnSet documentation to understand column list inference for inserts.
+func (o *Suppression) Insert(ctx context.Context, exec boil.ContextExecutor, columns boil.Columns) error {
+	if o == nil {
+		return errors.New("models: no suppression provided for insertion")
+	}
+
+	var err error
+	if !boil.TimestampsAreSkipped(ctx) {
+		currTime := time.Now().In(boil.GetLocation())
+
+		if o.CreatedAt.IsZero() {
+			o.CreatedAt = currTime
+		}
+	}
+
+	if err := o.doBeforeInsertHooks(ctx, exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(suppressionColumnsWithDefault, o)
+
+	key := makeCacheKey(columns, nzDefaults)
+	suppressionInsertCacheMut.RLock()
+	cache, cached := suppressionInsertCache[key]
+	suppressionInsertCacheMut.RUnlock()
+
+	if !cached {
+		wl, returnColumns := columns.InsertColumnSet(
+			suppressionAllColumns,
+			suppressionColumnsWithDefault,
+			suppressionColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		cache.valueMapping, err = queries.BindMapping(suppressionType, suppressionMapping, wl)
+		if err != nil {
+			return err
+		}
+		cache.retMapping, err = queries.BindMapping(suppressionType, suppressionMapping, returnColumns)
+		if err != nil {
+			return err
+		}
+		if len(wl) != 0 {
+			cache.query = fmt.Sprintf("INSERT INTO \"suppression\" (\"%s\") %%sVALUES (%s)%%s", strings.Join(wl, "\",\""), strmangle.Placeholders(dialect.UseIndexPlaceholders, len(wl), 1, 1))
+		} else {
+			cache.query = "INSERT INTO \"suppression\" %sDEFAULT VALUES%s"
+		}
+
+		var queryOutput, queryReturning string
+
+		if len(cache.retMapping) != 0 {
+			queryReturning = fmt.Sprintf(" RETURNING \"%s\"", strings.Join(returnColumns, "\",\""))
+		}
+
+		cache.query = fmt.Sprintf(cache.query, queryOutput, queryReturning)
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, vals)
+	}
+
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRowContext(ctx, cache.query, vals...).Scan(queries.PtrsFromMapping(value, cache.retMapping)...)
+	} else {
+		_, err = exec.ExecContext(ctx, cache.query, vals...)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "models: unable to insert into suppression")
+	}
+
+	if !cached {
+		suppressionInsertCacheMut.Lock()
+		suppressionInsertCache[key] = cache
+		suppressionInsertCacheMut.Unlock()
+	}
+
+	return o.doAfterInsertHooks(ctx, exec)
+}
+
+// Update uses an executor to update the Suppression.
+// See boil.Columns.UpdateColumnSet documentation to understand column list inference for updates.
+// Update does not automatically update the record in case of default values. Use .Reload() to refresh the records.
+func (o *Suppression) Update(ctx context.Context, exec boil.ContextExecutor, columns boil.Columns) (int64, error) {
+	var err error
+	if err = o.doBeforeUpdateHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+	key := makeCacheKey(columns, nil)
+	suppressionUpdateCacheMut.RLock()
+	cache, cached := suppressionUpdateCache[key]
+	suppressionUpdateCacheMut.RUnlock()
+
+	if !cached {
+		wl := columns.UpdateColumnSet(
+			suppressionAllColumns,
+			suppressionPrimaryKeyColumns,
+		)
+
+		if !columns.IsWhitelist() {
+			wl = strmangle.SetComplement(wl, []string{"created_at"})
+		}
+		if len(wl) == 0 {
+			return 0, errors.New("models: unable to update suppression, could not build whitelist")
+		}
+
+		cache.query = fmt.Sprintf("UPDATE \"suppression\" SET %s WHERE %s",
+			strmangle.SetParamNames("\"", "\"", 1, wl),
+			strmangle.WhereClause("\"", "\"", len(wl)+1, suppressionPrimaryKeyColumns),
+		)
+		cache.valueMapping, err = queries.BindMapping(suppressionType, suppressionMapping, append(wl, suppressionPrimaryKeyColumns...))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	values := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), cache.valueMapping)
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, values)
+	}
+	var result sql.Result
+	result, err = exec.ExecContext(ctx, cache.query, values...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update suppression row")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by update for suppression")
+	}
+
+	if !cached {
+		suppressionUpdateCacheMut.Lock()
+		suppressionUpdateCache[key] = cache
+		suppressionUpdateCacheMut.Unlock()
+	}
+
+	return rowsAff, o.doAfterUpdateHooks(ctx, exec)
+}
+
+// UpdateAll updates all rows with the specified column values.
+func (q suppressionQuery) UpdateAll(ctx context.Context, exec boil.ContextExecutor, cols M) (int64, error) {
+	queries.SetUpdate(q.Query, cols)
+
+	result, err := q.Query.ExecContext(ctx, exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update all for suppression")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to retrieve rows affected for suppression")
+	}
+
+	return rowsAff, nil
+}
+
+// UpdateAll updates all rows with the specified column values, using an executor.
+func (o SuppressionSlice) UpdateAll(ctx context.Context, exec boil.ContextExecutor, cols M) (int64, error) {
+	ln := int64(len(o))
+	if ln == 0 {
+		return 0, nil
+	}
+
+	if len(cols) == 0 {
+		return 0, errors.New("models: update all requires at least one column argument")
+	}
+
+	colNames := make([]string, len(cols))
+	args := make([]interface{}, len(cols))
+
+	i := 0
+	for name, value := range cols {
+		colNames[i] = name
+		args[i] = value
+		i++
+	}
+
+	// Append all of the primary key values for each column
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), suppressionPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := fmt.Sprintf("UPDATE \"suppression\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, colNames),
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), len(colNames)+1, suppressionPrimaryKeyColumns, len(o)))
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args...)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update all in suppression slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to retrieve rows affected all in update all suppression")
+	}
+	return rowsAff, nil
+}
+
+// Upsert attempts an insert using an executor, and does an update or ignore on conflict.
+// See boil.Columns documentation for how to properly use updateColumns and insertColumns.
+func (o *Suppression) Upsert(ctx context.Context, exec boil.ContextExecutor, updateOnConflict bool, conflictColumns []string, updateColumns, insertColumns boil.Columns) error {
+	if o == nil {
+		return errors.New("models: no suppression provided for upsert")
+	}
+	if !boil.TimestampsAreSkipped(ctx) {
+		currTime := time.Now().In(boil.GetLocation())
+
+		if o.CreatedAt.IsZero() {
+			o.CreatedAt = currTime
+		}
+	}
+
+	if err := o.doBeforeUpsertHooks(ctx, exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(suppressionColumnsWithDefault, o)
+
+	// Build cache key in-line uglily - mysql vs psql problems
+	buf := strmangle.GetBuffer()
+	if updateOnConflict {
+		buf.WriteByte('t')
+	} else {
+		buf.WriteByte('f')
+	}
+	buf.WriteByte('.')
+	for _, c := range conflictColumns {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(updateColumns.Kind))
+	for _, c := range updateColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(insertColumns.Kind))
+	for _, c := range insertColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	for _, c := range nzDefaults {
+		buf.WriteString(c)
+	}
+	key := buf.String()
+	strmangle.PutBuffer(buf)
+
+	suppressionUpsertCacheMut.RLock()
+	cache, cached := suppressionUpsertCache[key]
+	suppressionUpsertCacheMut.RUnlock()
+
+	var err error
+
+	if !cached {
+		insert, ret := insertColumns.InsertColumnSet(
+			suppressionAllColumns,
+			suppressionColumnsWithDefault,
+			suppressionColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		update := updateColumns.UpdateColumnSet(
+			suppressionAllColumns,
+			suppressionPrimaryKeyColumns,
+		)
+
+		if updateOnConflict && len(update) == 0 {
+			return errors.New("models: unable to upsert suppression, could not build update column list")
+		}
+
+		conflict := conflictColumns
+		if len(conflict) == 0 {
+			conflict = make([]string, len(suppressionPrimaryKeyColumns))
+			copy(conflict, suppressionPrimaryKeyColumns)
+		}
+		cache.query = buildUpsertQueryPostgres(dialect, "\"suppression\"", updateOnConflict, ret, update, conflict, insert)
+
+		cache.valueMapping, err = queries.BindMapping(suppressionType, suppressionMapping, insert)
+		if err != nil {
+			return err
+		}
+		if len(ret) != 0 {
+			cache.retMapping, err = queries.BindMapping(suppressionType, suppressionMapping, ret)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+	var returns []interface{}
+	if len(cache.retMapping) != 0 {
+		returns = queries.PtrsFromMapping(value, cache.retMapping)
+	}
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, vals)
+	}
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRowContext(ctx, cache.query, vals...).Scan(returns...)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = nil // Postgres doesn't return anything when there's no update
+		}
+	} else {
+		_, err = exec.ExecContext(ctx, cache.query, vals...)
+	}
+	if err != nil {
+		return errors.Wrap(err, "models: unable to upsert suppression")
+	}
+
+	if !cached {
+		suppressionUpsertCacheMut.Lock()
+		suppressionUpsertCache[key] = cache
+		suppressionUpsertCacheMut.Unlock()
+	}
+
+	return o.doAfterUpsertHooks(ctx, exec)
+}
+
+// Delete deletes a single Suppression record with an executor.
+// Delete will match against the primary key column to find the record to delete.
+func (o *Suppression) Delete(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if o == nil {
+		return 0, errors.New("models: no Suppression provided for delete")
+	}
+
+	if err := o.doBeforeDeleteHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+
+	args := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), suppressionPrimaryKeyMapping)
+	sql := "DELETE FROM \"suppression\" WHERE \"id\"=$1"
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args...)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete from suppression")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by delete for suppression")
+	}
+
+	if err := o.doAfterDeleteHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAll deletes all matching rows.
+func (q suppressionQuery) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if q.Query == nil {
+		return 0, errors.New("models: no suppressionQuery provided for delete all")
+	}
+
+	queries.SetDelete(q.Query)
+
+	result, err := q.Query.ExecContext(ctx, exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete all from suppression")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by deleteall for suppression")
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAll deletes all rows in the slice, using an executor.
+func (o SuppressionSlice) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if len(o) == 0 {
+		return 0, nil
+	}
+
+	if len(suppressionBeforeDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doBeforeDeleteHooks(ctx, exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	var args []interface{}
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), suppressionPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "DELETE FROM \"suppression\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, suppressionPrimaryKeyColumns, len(o))
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete all from suppression slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by deleteall for suppression")
+	}
+
+	if len(suppressionAfterDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterDeleteHooks(ctx, exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return rowsAff, nil
+}
+
+// Reload refetches the object from the database
+// using the primary keys with an executor.
+func (o *Suppression) Reload(ctx context.Context, exec boil.ContextExecutor) error {
+	ret, err := FindSuppression(ctx, exec, o.ID)
+	if err != nil {
+		return err
+	}
+
+	*o = *ret
+	return nil
+}
+
+// ReloadAll refetches every row with matching primary key column values
+// and overwrites the original object slice with the newly updated slice.
+func (o *SuppressionSlice) ReloadAll(ctx context.Context, exec boil.ContextExecutor) error {
+	if o == nil || len(*o) == 0 {
+		return nil
+	}
+
+	slice := SuppressionSlice{}
+	var args []interface{}
+	for _, obj := range *o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), suppressionPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "SELECT \"suppression\".* FROM \"suppression\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, suppressionPrimaryKeyColumns, len(*o))
+
+	q := queries.Raw(sql, args...)
+
+	err := q.Bind(ctx, exec, &slice)
+	if err != nil {
+		return errors.Wrap(err, "models: unable to reload all in SuppressionSlice")
+	}
+
+	*o = slice
+
+	return nil
+}
+
+// SuppressionExists checks if the Suppression row exists.
+func SuppressionExists(ctx context.Context, exec boil.ContextExecutor, iD int64) (bool, error) {
+	var exists bool
+	sql := "select exists(select 1 from \"suppression\" where \"id\"=$1 limit 1)"
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, iD)
+	}
+	row := exec.QueryRowContext(ctx, sql, iD)
+
+	err := row.Scan(&exists)
+	if err != nil {
+		return false, errors.Wrap(err, "models: unable to check if suppression exists")
+	}
+
+	return exists, nil
+}
@@ -0,0 +1,1161 @@
+// Code generated by SQLBoiler 4.13.0 (https://github.com/volatiletech/sqlboiler). DO NOT EDIT.
+// This file is meant to be re-generated in place and/or deleted at any time.
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/sqlboiler/v4/queries/qmhelper"
+	"github.com/volatiletech/sqlboiler/v4/types"
+	"github.com/volatiletech/strmangle"
+)
+
+// TemplateVersion is an object representing the database table.
+type TemplateVersion struct {
+	ID            int64      `boil:"id" json:"id" toml:"id" yaml:"id"`
+	TemplateID    int16      `boil:"template_id" json:"template_id" toml:"template_id" yaml:"template_id"`
+	Version       int        `boil:"version" json:"version" toml:"version" yaml:"version"`
+	ParamsSchema  types.JSON `boil:"params_schema" json:"params_schema" toml:"params_schema" yaml:"params_schema"`
+	Bodies        types.JSON `boil:"bodies" json:"bodies" toml:"bodies" yaml:"bodies"`
+	CreatedAt     time.Time  `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	Catalog       types.JSON `boil:"catalog" json:"catalog" toml:"catalog" yaml:"catalog"`
+	ExampleParams null.JSON  `boil:"example_params" json:"example_params,omitempty" toml:"example_params" yaml:"example_params,omitempty"`
+
+	R *templateVersionR `boil:"-" json:"-" toml:"-" yaml:"-"`
+	L templateVersionL  `boil:"-" json:"-" toml:"-" yaml:"-"`
+}
+
+var TemplateVersionColumns = struct {
+	ID            string
+	TemplateID    string
+	Version       string
+	ParamsSchema  string
+	Bodies        string
+	CreatedAt     string
+	Catalog       string
+	ExampleParams string
+}{
+	ID:            "id",
+	TemplateID:    "template_id",
+	Version:       "version",
+	ParamsSchema:  "params_schema",
+	Bodies:        "bodies",
+	CreatedAt:     "created_at",
+	Catalog:       "catalog",
+	ExampleParams: "example_params",
+}
+
+var TemplateVersionTableColumns = struct {
+	ID            string
+	TemplateID    string
+	Version       string
+	ParamsSchema  string
+	Bodies        string
+	CreatedAt     string
+	Catalog       string
+	ExampleParams string
+}{
+	ID:            "template_version.id",
+	TemplateID:    "template_version.template_id",
+	Version:       "template_version.version",
+	ParamsSchema:  "template_version.params_schema",
+	Bodies:        "template_version.bodies",
+	CreatedAt:     "template_version.created_at",
+	Catalog:       "template_version.catalog",
+	ExampleParams: "template_version.example_params",
+}
+
+// Generated where
+
+type whereHelpernull_JSON struct{ field string }
+
+func (w whereHelpernull_JSON) EQ(x null.JSON) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, false, x)
+}
+func (w whereHelpernull_JSON) NEQ(x null.JSON) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, true, x)
+}
+func (w whereHelpernull_JSON) LT(x null.JSON) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LT, x)
+}
+func (w whereHelpernull_JSON) LTE(x null.JSON) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LTE, x)
+}
+func (w whereHelpernull_JSON) GT(x null.JSON) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GT, x)
+}
+func (w whereHelpernull_JSON) GTE(x null.JSON) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GTE, x)
+}
+
+func (w whereHelpernull_JSON) IsNull() qm.QueryMod    { return qmhelper.WhereIsNull(w.field) }
+func (w whereHelpernull_JSON) IsNotNull() qm.QueryMod { return qmhelper.WhereIsNotNull(w.field) }
+
+var TemplateVersionWhere = struct {
+	ID            whereHelperint64
+	TemplateID    whereHelperint16
+	Version       whereHelperint
+	ParamsSchema  whereHelpertypes_JSON
+	Bodies        whereHelpertypes_JSON
+	CreatedAt     whereHelpertime_Time
+	Catalog       whereHelpertypes_JSON
+	ExampleParams whereHelpernull_JSON
+}{
+	ID:            whereHelperint64{field: "\"template_version\".\"id\""},
+	TemplateID:    whereHelperint16{field: "\"template_version\".\"template_id\""},
+	Version:       whereHelperint{field: "\"template_version\".\"version\""},
+	ParamsSchema:  whereHelpertypes_JSON{field: "\"template_version\".\"params_schema\""},
+	Bodies:        whereHelpertypes_JSON{field: "\"template_version\".\"bodies\""},
+	CreatedAt:     whereHelpertime_Time{field: "\"template_version\".\"created_at\""},
+	Catalog:       whereHelpertypes_JSON{field: "\"template_version\".\"catalog\""},
+	ExampleParams: whereHelpernull_JSON{field: "\"template_version\".\"example_params\""},
+}
+
+// TemplateVersionRels is where relationship names are stored.
+var TemplateVersionRels = struct {
+	Template string
+}{
+	Template: "Template",
+}
+
+// templateVersionR is where relationships are stored.
+type templateVersionR struct {
+	Template *Template `boil:"Template" json:"Template" toml:"Template" yaml:"Template"`
+}
+
+// NewStruct creates a new relationship struct
+func (*templateVersionR) NewStruct() *templateVersionR {
+	return &templateVersionR{}
+}
+
+func (r *templateVersionR) GetTemplate() *Template {
+	if r == nil {
+		return nil
+	}
+	return r.Template
+}
+
+// templateVersionL is where Load methods for each relationship are stored.
+type templateVersionL struct{}
+
+var (
+	templateVersionAllColumns            = []string{"id", "template_id", "version", "params_schema", "bodies", "created_at", "catalog", "example_params"}
+	templateVersionColumnsWithoutDefault = []string{"template_id", "version"}
+	templateVersionColumnsWithDefault    = []string{"id", "params_schema", "bodies", "created_at", "catalog", "example_params"}
+	templateVersionPrimaryKeyColumns     = []string{"id"}
+	templateVersionGeneratedColumns      = []string{}
+)
+
+type (
+	// TemplateVersionSlice is an alias for a slice of pointers to TemplateVersion.
+	// This should almost always be used instead of []TemplateVersion.
+	TemplateVersionSlice []*TemplateVersion
+	// TemplateVersionHook is the signature for custom TemplateVersion hook methods
+	TemplateVersionHook func(context.Context, boil.ContextExecutor, *TemplateVersion) error
+
+	templateVersionQuery struct {
+		*queries.Query
+	}
+)
+
+// Cache for insert, update and upsert
+var (
+	templateVersionType                 = reflect.TypeOf(&TemplateVersion{})
+	templateVersionMapping              = queries.MakeStructMapping(templateVersionType)
+	templateVersionPrimaryKeyMapping, _ = queries.BindMapping(templateVersionType, templateVersionMapping, templateVersionPrimaryKeyColumns)
+	templateVersionInsertCacheMut       sync.RWMutex
+	templateVersionInsertCache          = make(map[string]insertCache)
+	templateVersionUpdateCacheMut       sync.RWMutex
+	templateVersionUpdateCache          = make(map[string]updateCache)
+	templateVersionUpsertCacheMut       sync.RWMutex
+	templateVersionUpsertCache          = make(map[string]insertCache)
+)
+
+var (
+	// Force time package dependency for automated UpdatedAt/CreatedAt.
+	_ = time.Second
+	// Force qmhelper dependency for where clause generation (which doesn't
+	// always happen)
+	_ = qmhelper.Where
+)
+
+var templateVersionAfterSelectHooks []TemplateVersionHook
+
+var templateVersionBeforeInsertHooks []TemplateVersionHook
+var templateVersionAfterInsertHooks []TemplateVersionHook
+
+var templateVersionBeforeUpdateHooks []TemplateVersionHook
+var templateVersionAfterUpdateHooks []TemplateVersionHook
+
+var templateVersionBeforeDeleteHooks []TemplateVersionHook
+var templateVersionAfterDeleteHooks []TemplateVersionHook
+
+var templateVersionBeforeUpsertHooks []TemplateVersionHook
+var templateVersionAfterUpsertHooks []TemplateVersionHook
+
+// doAfterSelectHooks executes all "after Select" hooks.
+func (o *TemplateVersion) doAfterSelectHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range templateVersionAfterSelectHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeInsertHooks executes all "before insert" hooks.
+func (o *TemplateVersion) doBeforeInsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range templateVersionBeforeInsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterInsertHooks executes all "after Insert" hooks.
+func (o *TemplateVersion) doAfterInsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range templateVersionAfterInsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpdateHooks executes all "before Update" hooks.
+func (o *TemplateVersion) doBeforeUpdateHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range templateVersionBeforeUpdateHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpdateHooks executes all "after Update" hooks.
+func (o *TemplateVersion) doAfterUpdateHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range templateVersionAfterUpdateHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeDeleteHooks executes all "before Delete" hooks.
+func (o *TemplateVersion) doBeforeDeleteHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range templateVersionBeforeDeleteHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterDeleteHooks executes all "after Delete" hooks.
+func (o *TemplateVersion) doAfterDeleteHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range templateVersionAfterDeleteHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpsertHooks executes all "before Upsert" hooks.
+func (o *TemplateVersion) doBeforeUpsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range templateVersionBeforeUpsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpsertHooks executes all "after Upsert" hooks.
+func (o *TemplateVersion) doAfterUpsertHooks(ctx context.Context, exec boil.ContextExecutor) (err error) {
+	if boil.HooksAreSkipped(ctx) {
+		return nil
+	}
+
+	for _, hook := range templateVersionAfterUpsertHooks {
+		if err := hook(ctx, exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddTemplateVersionHook registers your hook function for all future operations.
+func AddTemplateVersionHook(hookPoint boil.HookPoint, templateVersionHook TemplateVersionHook) {
+	switch hookPoint {
+	case boil.AfterSelectHook:
+		templateVersionAfterSelectHooks = append(templateVersionAfterSelectHooks, templateVersionHook)
+	case boil.BeforeInsertHook:
+		templateVersionBeforeInsertHooks = append(templateVersionBeforeInsertHooks, templateVersionHook)
+	case boil.AfterInsertHook:
+		templateVersionAfterInsertHooks = append(templateVersionAfterInsertHooks, templateVersionHook)
+	case boil.BeforeUpdateHook:
+		templateVersionBeforeUpdateHooks = append(templateVersionBeforeUpdateHooks, templateVersionHook)
+	case boil.AfterUpdateHook:
+		templateVersionAfterUpdateHooks = append(templateVersionAfterUpdateHooks, templateVersionHook)
+	case boil.BeforeDeleteHook:
+		templateVersionBeforeDeleteHooks = append(templateVersionBeforeDeleteHooks, templateVersionHook)
+	case boil.AfterDeleteHook:
+		templateVersionAfterDeleteHooks = append(templateVersionAfterDeleteHooks, templateVersionHook)
+	case boil.BeforeUpsertHook:
+		templateVersionBeforeUpsertHooks = append(templateVersionBeforeUpsertHooks, templateVersionHook)
+	case boil.AfterUpsertHook:
+		templateVersionAfterUpsertHooks = append(templateVersionAfterUpsertHooks, templateVersionHook)
+	}
+}
+
+// One returns a single templateVersion record from the query.
+func (q templateVersionQuery) One(ctx context.Context, exec boil.ContextExecutor) (*TemplateVersion, error) {
+	o := &TemplateVersion{}
+
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Bind(ctx, exec, o)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "models: failed to execute a one query for template_version")
+	}
+
+	if err := o.doAfterSelectHooks(ctx, exec); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// All returns all TemplateVersion records from the query.
+func (q templateVersionQuery) All(ctx context.Context, exec boil.ContextExecutor) (TemplateVersionSlice, error) {
+	var o []*TemplateVersion
+
+	err := q.Bind(ctx, exec, &o)
+	if err != nil {
+		return nil, errors.Wrap(err, "models: failed to assign all query results to TemplateVersion slice")
+	}
+
+	if len(templateVersionAfterSelectHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterSelectHooks(ctx, exec); err != nil {
+				return o, err
+			}
+		}
+	}
+
+	return o, nil
+}
+
+// Count returns the count of all TemplateVersion records in the query.
+func (q templateVersionQuery) Count(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+
+	err := q.Query.QueryRowContext(ctx, exec).Scan(&count)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to count template_version rows")
+	}
+
+	return count, nil
+}
+
+// Exists checks if the row exists in the table.
+func (q templateVersionQuery) Exists(ctx context.Context, exec boil.ContextExecutor) (bool, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Query.QueryRowContext(ctx, exec).Scan(&count)
+	if err != nil {
+		return false, errors.Wrap(err, "models: failed to check if template_version exists")
+	}
+
+	return count > 0, nil
+}
+
+// Template pointed to by the foreign key.
+func (o *TemplateVersion) Template(mods ...qm.QueryMod) templateQuery {
+	queryMods := []qm.QueryMod{
+		qm.Where("\"id\" = ?", o.TemplateID),
+	}
+
+	queryMods = append(queryMods, mods...)
+
+	return Templates(queryMods...)
+}
+
+// LoadTemplate allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for an N-1 relationship.
+func (templateVersionL) LoadTemplate(ctx context.Context, e boil.ContextExecutor, singular bool, maybeTemplateVersion interface{}, mods queries.Applicator) error {
+	var slice []*TemplateVersion
+	var object *TemplateVersion
+
+	if singular {
+		var ok bool
+		object, ok = maybeTemplateVersion.(*TemplateVersion)
+		if !ok {
+			object = new(TemplateVersion)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybeTemplateVersion)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybeTemplateVersion))
+			}
+		}
+	} else {
+		s, ok := maybeTemplateVersion.(*[]*TemplateVersion)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybeTemplateVersion)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybeTemplateVersion))
+			}
+		}
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &templateVersionR{}
+		}
+		args = append(args, object.TemplateID)
+
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &templateVersionR{}
+			}
+
+			for _, a := range args {
+				if a == obj.TemplateID {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.TemplateID)
+
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`template`),
+		qm.WhereIn(`template.id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load Template")
+	}
+
+	var resultSlice []*Template
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice Template")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results of eager load for template")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for template")
+	}
+
+	if len(templateVersionAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(resultSlice) == 0 {
+		return nil
+	}
+
+	if singular {
+		foreign := resultSlice[0]
+		object.R.Template = foreign
+		if foreign.R == nil {
+			foreign.R = &templateR{}
+		}
+		foreign.R.TemplateVersions = append(foreign.R.TemplateVersions, object)
+		return nil
+	}
+
+	for _, local := range slice {
+		for _, foreign := range resultSlice {
+			if local.TemplateID == foreign.ID {
+				local.R.Template = foreign
+				if foreign.R == nil {
+					foreign.R = &templateR{}
+				}
+				foreign.R.TemplateVersions = append(foreign.R.TemplateVersions, local)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetTemplate of the templateVersion to the related item.
+// Sets o.R.Template to related.
+// Adds o to related.R.TemplateVersions.
+func (o *TemplateVersion) SetTemplate(ctx context.Context, exec boil.ContextExecutor, insert bool, related *Template) error {
+	var err error
+	if insert {
+		if err = related.Insert(ctx, exec, boil.Infer()); err != nil {
+			return errors.Wrap(err, "failed to insert into foreign table")
+		}
+	}
+
+	updateQuery := fmt.Sprintf(
+		"UPDATE \"template_version\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, []string{"template_id"}),
+		strmangle.WhereClause("\"", "\"", 2, templateVersionPrimaryKeyColumns),
+	)
+	values := []interface{}{related.ID, o.ID}
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, updateQuery)
+		fmt.Fprintln(writer, values)
+	}
+	if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	o.TemplateID = related.ID
+	if o.R == nil {
+		o.R = &templateVersionR{
+			Template: related,
+		}
+	} else {
+		o.R.Template = related
+	}
+
+	if related.R == nil {
+		related.R = &templateR{
+			TemplateVersions: TemplateVersionSlice{o},
+		}
+	} else {
+		related.R.TemplateVersions = append(related.R.TemplateVersions, o)
+	}
+
+	return nil
+}
+
+// TemplateVersions retrieves all the records using an executor.
+func TemplateVersions(mods ...qm.QueryMod) templateVersionQuery {
+	mods = append(mods, qm.From("\"template_version\""))
+	q := NewQuery(mods...)
+	if len(queries.GetSelect(q)) == 0 {
+		queries.SetSelect(q, []string{"\"template_version\".*"})
+	}
+
+	return templateVersionQuery{q}
+}
+
+// FindTemplateVersion retrieves a single record by ID with an executor.
+// If selectCols is empty Find will return all columns.
+func FindTemplateVersion(ctx context.Context, exec boil.ContextExecutor, iD int64, selectCols ...string) (*TemplateVersion, error) {
+	templateVersionObj := &TemplateVersion{}
+
+	sel := "*"
+	if len(selectCols) > 0 {
+		sel = strings.Join(strmangle.IdentQuoteSlice(dialect.LQ, dialect.RQ, selectCols), ",")
+	}
+	query := fmt.Sprintf(
+		"select %s from \"template_version\" where \"id\"=$1", sel,
+	)
+
+	q := queries.Raw(query, iD)
+
+	err := q.Bind(ctx, exec, templateVersionObj)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "models: unable to select from template_version")
+	}
+
+	if err = templateVersionObj.doAfterSelectHooks(ctx, exec); err != nil {
+		return templateVersionObj, err
+	}
+
+	return templateVersionObj, nil
+}
+
+// Insert a single record using an executor.
+// See boil.Columns.InsertColumnSet documentation to understand column list inference for inserts.
+func (o *TemplateVersion) Insert(ctx context.Context, exec boil.ContextExecutor, columns boil.Columns) error {
+	if o == nil {
+		return errors.New("models: no template_version provided for insertion")
+	}
+
+	var err error
+	if !boil.TimestampsAreSkipped(ctx) {
+		currTime := time.Now().In(boil.GetLocation())
+
+		if o.CreatedAt.IsZero() {
+			o.CreatedAt = currTime
+		}
+	}
+
+	if err := o.doBeforeInsertHooks(ctx, exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(templateVersionColumnsWithDefault, o)
+
+	key := makeCacheKey(columns, nzDefaults)
+	templateVersionInsertCacheMut.RLock()
+	cache, cached := templateVersionInsertCache[key]
+	templateVersionInsertCacheMut.RUnlock()
+
+	if !cached {
+		wl, returnColumns := columns.InsertColumnSet(
+			templateVersionAllColumns,
+			templateVersionColumnsWithDefault,
+			templateVersionColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		cache.valueMapping, err = queries.BindMapping(templateVersionType, templateVersionMapping, wl)
+		if err != nil {
+			return err
+		}
+		cache.retMapping, err = queries.BindMapping(templateVersionType, templateVersionMapping, returnColumns)
+		if err != nil {
+			return err
+		}
+		if len(wl) != 0 {
+			cache.query = fmt.Sprintf("INSERT INTO \"template_version\" (\"%s\") %%sVALUES (%s)%%s", strings.Join(wl, "\",\""), strmangle.Placeholders(dialect.UseIndexPlaceholders, len(wl), 1, 1))
+		} else {
+			cache.query = "INSERT INTO \"template_version\" %sDEFAULT VALUES%s"
+		}
+
+		var queryOutput, queryReturning string
+
+		if len(cache.retMapping) != 0 {
+			queryReturning = fmt.Sprintf(" RETURNING \"%s\"", strings.Join(returnColumns, "\",\""))
+		}
+
+		cache.query = fmt.Sprintf(cache.query, queryOutput, queryReturning)
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, vals)
+	}
+
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRowContext(ctx, cache.query, vals...).Scan(queries.PtrsFromMapping(value, cache.retMapping)...)
+	} else {
+		_, err = exec.ExecContext(ctx, cache.query, vals...)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "models: unable to insert into template_version")
+	}
+
+	if !cached {
+		templateVersionInsertCacheMut.Lock()
+		templateVersionInsertCache[key] = cache
+		templateVersionInsertCacheMut.Unlock()
+	}
+
+	return o.doAfterInsertHooks(ctx, exec)
+}
+
+// Update uses an executor to update the TemplateVersion.
+// See boil.Columns.UpdateColumnSet documentation to understand column list inference for updates.
+// Update does not automatically update the record in case of default values. Use .Reload() to refresh the records.
+func (o *TemplateVersion) Update(ctx context.Context, exec boil.ContextExecutor, columns boil.Columns) (int64, error) {
+	var err error
+	if err = o.doBeforeUpdateHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+	key := makeCacheKey(columns, nil)
+	templateVersionUpdateCacheMut.RLock()
+	cache, cached := templateVersionUpdateCache[key]
+	templateVersionUpdateCacheMut.RUnlock()
+
+	if !cached {
+		wl := columns.UpdateColumnSet(
+			templateVersionAllColumns,
+			templateVersionPrimaryKeyColumns,
+		)
+
+		if !columns.IsWhitelist() {
+			wl = strmangle.SetComplement(wl, []string{"created_at"})
+		}
+		if len(wl) == 0 {
+			return 0, errors.New("models: unable to update template_version, could not build whitelist")
+		}
+
+		cache.query = fmt.Sprintf("UPDATE \"template_version\" SET %s WHERE %s",
+			strmangle.SetParamNames("\"", "\"", 1, wl),
+			strmangle.WhereClause("\"", "\"", len(wl)+1, templateVersionPrimaryKeyColumns),
+		)
+		cache.valueMapping, err = queries.BindMapping(templateVersionType, templateVersionMapping, append(wl, templateVersionPrimaryKeyColumns...))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	values := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), cache.valueMapping)
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, values)
+	}
+	var result sql.Result
+	result, err = exec.ExecContext(ctx, cache.query, values...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update template_version row")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by update for template_version")
+	}
+
+	if !cached {
+		templateVersionUpdateCacheMut.Lock()
+		templateVersionUpdateCache[key] = cache
+		templateVersionUpdateCacheMut.Unlock()
+	}
+
+	return rowsAff, o.doAfterUpdateHooks(ctx, exec)
+}
+
+// UpdateAll updates all rows with the specified column values.
+func (q templateVersionQuery) UpdateAll(ctx context.Context, exec boil.ContextExecutor, cols M) (int64, error) {
+	queries.SetUpdate(q.Query, cols)
+
+	result, err := q.Query.ExecContext(ctx, exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update all for template_version")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to retrieve rows affected for template_version")
+	}
+
+	return rowsAff, nil
+}
+
+// UpdateAll updates all rows with the specified column values, using an executor.
+func (o TemplateVersionSlice) UpdateAll(ctx context.Context, exec boil.ContextExecutor, cols M) (int64, error) {
+	ln := int64(len(o))
+	if ln == 0 {
+		return 0, nil
+	}
+
+	if len(cols) == 0 {
+		return 0, errors.New("models: update all requires at least one column argument")
+	}
+
+	colNames := make([]string, len(cols))
+	args := make([]interface{}, len(cols))
+
+	i := 0
+	for name, value := range cols {
+		colNames[i] = name
+		args[i] = value
+		i++
+	}
+
+	// Append all of the primary key values for each column
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), templateVersionPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := fmt.Sprintf("UPDATE \"template_version\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, colNames),
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), len(colNames)+1, templateVersionPrimaryKeyColumns, len(o)))
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args...)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to update all in templateVersion slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to retrieve rows affected all in update all templateVersion")
+	}
+	return rowsAff, nil
+}
+
+// Upsert attempts an insert using an executor, and does an update or ignore on conflict.
+// See boil.Columns documentation for how to properly use updateColumns and insertColumns.
+func (o *TemplateVersion) Upsert(ctx context.Context, exec boil.ContextExecutor, updateOnConflict bool, conflictColumns []string, updateColumns, insertColumns boil.Columns) error {
+	if o == nil {
+		return errors.New("models: no template_version provided for upsert")
+	}
+	if !boil.TimestampsAreSkipped(ctx) {
+		currTime := time.Now().In(boil.GetLocation())
+
+		if o.CreatedAt.IsZero() {
+			o.CreatedAt = currTime
+		}
+	}
+
+	if err := o.doBeforeUpsertHooks(ctx, exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(templateVersionColumnsWithDefault, o)
+
+	// Build cache key in-line uglily - mysql vs psql problems
+	buf := strmangle.GetBuffer()
+	if updateOnConflict {
+		buf.WriteByte('t')
+	} else {
+		buf.WriteByte('f')
+	}
+	buf.WriteByte('.')
+	for _, c := range conflictColumns {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(updateColumns.Kind))
+	for _, c := range updateColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(insertColumns.Kind))
+	for _, c := range insertColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	for _, c := range nzDefaults {
+		buf.WriteString(c)
+	}
+	key := buf.String()
+	strmangle.PutBuffer(buf)
+
+	templateVersionUpsertCacheMut.RLock()
+	cache, cached := templateVersionUpsertCache[key]
+	templateVersionUpsertCacheMut.RUnlock()
+
+	var err error
+
+	if !cached {
+		insert, ret := insertColumns.InsertColumnSet(
+			templateVersionAllColumns,
+			templateVersionColumnsWithDefault,
+			templateVersionColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		update := updateColumns.UpdateColumnSet(
+			templateVersionAllColumns,
+			templateVersionPrimaryKeyColumns,
+		)
+
+		if updateOnConflict && len(update) == 0 {
+			return errors.New("models: unable to upsert template_version, could not build update column list")
+		}
+
+		conflict := conflictColumns
+		if len(conflict) == 0 {
+			conflict = make([]string, len(templateVersionPrimaryKeyColumns))
+			copy(conflict, templateVersionPrimaryKeyColumns)
+		}
+		cache.query = buildUpsertQueryPostgres(dialect, "\"template_version\"", updateOnConflict, ret, update, conflict, insert)
+
+		cache.valueMapping, err = queries.BindMapping(templateVersionType, templateVersionMapping, insert)
+		if err != nil {
+			return err
+		}
+		if len(ret) != 0 {
+			cache.retMapping, err = queries.BindMapping(templateVersionType, templateVersionMapping, ret)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+	var returns []interface{}
+	if len(cache.retMapping) != 0 {
+		returns = queries.PtrsFromMapping(value, cache.retMapping)
+	}
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, cache.query)
+		fmt.Fprintln(writer, vals)
+	}
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRowContext(ctx, cache.query, vals...).Scan(returns...)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = nil // Postgres doesn't return anything when there's no update
+		}
+	} else {
+		_, err = exec.ExecContext(ctx, cache.query, vals...)
+	}
+	if err != nil {
+		return errors.Wrap(err, "models: unable to upsert template_version")
+	}
+
+	if !cached {
+		templateVersionUpsertCacheMut.Lock()
+		templateVersionUpsertCache[key] = cache
+		templateVersionUpsertCacheMut.Unlock()
+	}
+
+	return o.doAfterUpsertHooks(ctx, exec)
+}
+
+// Delete deletes a single TemplateVersion record with an executor.
+// Delete will match against the primary key column to find the record to delete.
+func (o *TemplateVersion) Delete(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if o == nil {
+		return 0, errors.New("models: no TemplateVersion provided for delete")
+	}
+
+	if err := o.doBeforeDeleteHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+
+	args := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), templateVersionPrimaryKeyMapping)
+	sql := "DELETE FROM \"template_version\" WHERE \"id\"=$1"
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args...)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete from template_version")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by delete for template_version")
+	}
+
+	if err := o.doAfterDeleteHooks(ctx, exec); err != nil {
+		return 0, err
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAll deletes all matching rows.
+func (q templateVersionQuery) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if q.Query == nil {
+		return 0, errors.New("models: no templateVersionQuery provided for delete all")
+	}
+
+	queries.SetDelete(q.Query)
+
+	result, err := q.Query.ExecContext(ctx, exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete all from template_version")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by deleteall for template_version")
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAll deletes all rows in the slice, using an executor.
+func (o TemplateVersionSlice) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	if len(o) == 0 {
+		return 0, nil
+	}
+
+	if len(templateVersionBeforeDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doBeforeDeleteHooks(ctx, exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	var args []interface{}
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), templateVersionPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "DELETE FROM \"template_version\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, templateVersionPrimaryKeyColumns, len(o))
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, args)
+	}
+	result, err := exec.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "models: unable to delete all from templateVersion slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "models: failed to get rows affected by deleteall for template_version")
+	}
+
+	if len(templateVersionAfterDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterDeleteHooks(ctx, exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return rowsAff, nil
+}
+
+// Reload refetches the object from the database
+// using the primary keys with an executor.
+func (o *TemplateVersion) Reload(ctx context.Context, exec boil.ContextExecutor) error {
+	ret, err := FindTemplateVersion(ctx, exec, o.ID)
+	if err != nil {
+		return err
+	}
+
+	*o = *ret
+	return nil
+}
+
+// ReloadAll refetches every row with matching primary key column values
+// and overwrites the original object slice with the newly updated slice.
+func (o *TemplateVersionSlice) ReloadAll(ctx context.Context, exec boil.ContextExecutor) error {
+	if o == nil || len(*o) == 0 {
+		return nil
+	}
+
+	slice := TemplateVersionSlice{}
+	var args []interface{}
+	for _, obj := range *o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), templateVersionPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "SELECT \"template_version\".* FROM \"template_version\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, templateVersionPrimaryKeyColumns, len(*o))
+
+	q := queries.Raw(sql, args...)
+
+	err := q.Bind(ctx, exec, &slice)
+	if err != nil {
+		return errors.Wrap(err, "models: unable to reload all in TemplateVersionSlice")
+	}
+
+	*o = slice
+
+	return nil
+}
+
+// TemplateVersionExists checks if the TemplateVersion row exists.
+func TemplateVersionExists(ctx context.Context, exec boil.ContextExecutor, iD int64) (bool, error) {
+	var exists bool
+	sql := "select exists(select 1 from \"template_version\" where \"id\"=$1 limit 1)"
+
+	if boil.IsDebug(ctx) {
+		writer := boil.DebugWriterFrom(ctx)
+		fmt.Fprintln(writer, sql)
+		fmt.Fprintln(writer, iD)
+	}
+	row := exec.QueryRowContext(ctx, sql, iD)
+
+	err := row.Scan(&exists)
+	if err != nil {
+		return false, errors.Wrap(err, "models: unable to check if template_version exists")
+	}
+
+	return exists, nil
+}
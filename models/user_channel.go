@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/null/v8"
 	"github.com/volatiletech/sqlboiler/v4/boil"
 	"github.com/volatiletech/sqlboiler/v4/queries"
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
@@ -23,58 +24,103 @@ import (
 
 // UserChannel is an object representing the database table.
 type UserChannel struct {
-	ID        int64  `boil:"id" json:"id" toml:"id" yaml:"id"`
-	UserID    int64  `boil:"user_id" json:"user_id" toml:"user_id" yaml:"user_id"`
-	Channel   int16  `boil:"channel" json:"channel" toml:"channel" yaml:"channel"`
-	Recipient string `boil:"recipient" json:"recipient" toml:"recipient" yaml:"recipient"`
-	CanNotify bool   `boil:"can_notify" json:"can_notify" toml:"can_notify" yaml:"can_notify"`
+	ID             int64     `boil:"id" json:"id" toml:"id" yaml:"id"`
+	UserID         int64     `boil:"user_id" json:"user_id" toml:"user_id" yaml:"user_id"`
+	Channel        int16     `boil:"channel" json:"channel" toml:"channel" yaml:"channel"`
+	Recipient      string    `boil:"recipient" json:"recipient" toml:"recipient" yaml:"recipient"`
+	CanNotify      bool      `boil:"can_notify" json:"can_notify" toml:"can_notify" yaml:"can_notify"`
+	DisabledReason string    `boil:"disabled_reason" json:"disabled_reason" toml:"disabled_reason" yaml:"disabled_reason"`
+	DisabledAt     null.Time `boil:"disabled_at" json:"disabled_at,omitempty" toml:"disabled_at" yaml:"disabled_at,omitempty"`
+	TrackingOptOut bool      `boil:"tracking_opt_out" json:"tracking_opt_out" toml:"tracking_opt_out" yaml:"tracking_opt_out"`
 
 	R *userChannelR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L userChannelL  `boil:"-" json:"-" toml:"-" yaml:"-"`
 }
 
 var UserChannelColumns = struct {
-	ID        string
-	UserID    string
-	Channel   string
-	Recipient string
-	CanNotify string
+	ID             string
+	UserID         string
+	Channel        string
+	Recipient      string
+	CanNotify      string
+	DisabledReason string
+	DisabledAt     string
+	TrackingOptOut string
 }{
-	ID:        "id",
-	UserID:    "user_id",
-	Channel:   "channel",
-	Recipient: "recipient",
-	CanNotify: "can_notify",
+	ID:             "id",
+	UserID:         "user_id",
+	Channel:        "channel",
+	Recipient:      "recipient",
+	CanNotify:      "can_notify",
+	DisabledReason: "disabled_reason",
+	DisabledAt:     "disabled_at",
+	TrackingOptOut: "tracking_opt_out",
 }
 
 var UserChannelTableColumns = struct {
-	ID        string
-	UserID    string
-	Channel   string
-	Recipient string
-	CanNotify string
+	ID             string
+	UserID         string
+	Channel        string
+	Recipient      string
+	CanNotify      string
+	DisabledReason string
+	DisabledAt     string
+	TrackingOptOut string
 }{
-	ID:        "user_channel.id",
-	UserID:    "user_channel.user_id",
-	Channel:   "user_channel.channel",
-	Recipient: "user_channel.recipient",
-	CanNotify: "user_channel.can_notify",
+	ID:             "user_channel.id",
+	UserID:         "user_channel.user_id",
+	Channel:        "user_channel.channel",
+	Recipient:      "user_channel.recipient",
+	CanNotify:      "user_channel.can_notify",
+	DisabledReason: "user_channel.disabled_reason",
+	DisabledAt:     "user_channel.disabled_at",
+	TrackingOptOut: "user_channel.tracking_opt_out",
 }
 
 // Generated where
 
+type whereHelpernull_Time struct{ field string }
+
+func (w whereHelpernull_Time) EQ(x null.Time) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, false, x)
+}
+func (w whereHelpernull_Time) NEQ(x null.Time) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, true, x)
+}
+func (w whereHelpernull_Time) LT(x null.Time) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LT, x)
+}
+func (w whereHelpernull_Time) LTE(x null.Time) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LTE, x)
+}
+func (w whereHelpernull_Time) GT(x null.Time) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GT, x)
+}
+func (w whereHelpernull_Time) GTE(x null.Time) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GTE, x)
+}
+
+func (w whereHelpernull_Time) IsNull() qm.QueryMod    { return qmhelper.WhereIsNull(w.field) }
+func (w whereHelpernull_Time) IsNotNull() qm.QueryMod { return qmhelper.WhereIsNotNull(w.field) }
+
 var UserChannelWhere = struct {
-	ID        whereHelperint64
-	UserID    whereHelperint64
-	Channel   whereHelperint16
-	Recipient whereHelperstring
-	CanNotify whereHelperbool
+	ID             whereHelperint64
+	UserID         whereHelperint64
+	Channel        whereHelperint16
+	Recipient      whereHelperstring
+	CanNotify      whereHelperbool
+	DisabledReason whereHelperstring
+	DisabledAt     whereHelpernull_Time
+	TrackingOptOut whereHelperbool
 }{
-	ID:        whereHelperint64{field: "\"user_channel\".\"id\""},
-	UserID:    whereHelperint64{field: "\"user_channel\".\"user_id\""},
-	Channel:   whereHelperint16{field: "\"user_channel\".\"channel\""},
-	Recipient: whereHelperstring{field: "\"user_channel\".\"recipient\""},
-	CanNotify: whereHelperbool{field: "\"user_channel\".\"can_notify\""},
+	ID:             whereHelperint64{field: "\"user_channel\".\"id\""},
+	UserID:         whereHelperint64{field: "\"user_channel\".\"user_id\""},
+	Channel:        whereHelperint16{field: "\"user_channel\".\"channel\""},
+	Recipient:      whereHelperstring{field: "\"user_channel\".\"recipient\""},
+	CanNotify:      whereHelperbool{field: "\"user_channel\".\"can_notify\""},
+	DisabledReason: whereHelperstring{field: "\"user_channel\".\"disabled_reason\""},
+	DisabledAt:     whereHelpernull_Time{field: "\"user_channel\".\"disabled_at\""},
+	TrackingOptOut: whereHelperbool{field: "\"user_channel\".\"tracking_opt_out\""},
 }
 
 // UserChannelRels is where relationship names are stored.
@@ -94,9 +140,9 @@ func (*userChannelR) NewStruct() *userChannelR {
 type userChannelL struct{}
 
 var (
-	userChannelAllColumns            = []string{"id", "user_id", "channel", "recipient", "can_notify"}
+	userChannelAllColumns            = []string{"id", "user_id", "channel", "recipient", "can_notify", "disabled_reason", "disabled_at", "tracking_opt_out"}
 	userChannelColumnsWithoutDefault = []string{"user_id", "channel", "recipient"}
-	userChannelColumnsWithDefault    = []string{"id", "can_notify"}
+	userChannelColumnsWithDefault    = []string{"id", "can_notify", "disabled_reason", "disabled_at", "tracking_opt_out"}
 	userChannelPrimaryKeyColumns     = []string{"id"}
 	userChannelGeneratedColumns      = []string{}
 )
@@ -3,11 +3,14 @@ package utils
 import (
 	"errors"
 	"github.com/golang/mock/gomock"
+	"github.com/keweegen/notification/config"
 	"github.com/keweegen/notification/internal/channel"
 	mockChannel "github.com/keweegen/notification/internal/channel/mock"
+	mockTelegram "github.com/keweegen/notification/internal/channel/telegram/mock"
 	"github.com/keweegen/notification/internal/entity"
 	"github.com/keweegen/notification/internal/messagetemplate"
 	mockRepository "github.com/keweegen/notification/internal/repository/mock"
+	mockWebhook "github.com/keweegen/notification/internal/webhook/mock"
 	mockLogger "github.com/keweegen/notification/logger/mock"
 	"time"
 )
@@ -15,26 +18,59 @@ import (
 var FakeDatabaseError = errors.New("database error :(")
 
 type MockedInstances struct {
-	QuitCh            chan struct{}
-	Logger            *mockLogger.MockLogger
-	ChannelDriver     *mockChannel.MockDriver
-	RepositoryMessage *mockRepository.MockMessage
-	RepositoryUser    *mockRepository.MockUser
+	QuitCh                chan struct{}
+	Logger                *mockLogger.MockLogger
+	ChannelDriver         *mockChannel.MockDriver
+	TelegramBot           *mockTelegram.MockBot
+	RepositoryMessage     *mockRepository.MockMessage
+	RepositoryUser        *mockRepository.MockUser
+	RepositoryLinkToken   *mockRepository.MockLinkToken
+	RepositoryFile        *mockRepository.MockFile
+	RepositorySuppression *mockRepository.MockSuppression
+	RepositoryPreference  *mockRepository.MockPreference
+	RepositoryTemplate    *mockRepository.MockTemplate
+	Webhook               *mockWebhook.MockSender
 }
 
 func NewMockedInstances(controller *gomock.Controller) *MockedInstances {
 	return &MockedInstances{
-		Logger:            mockLogger.NewMockLogger(controller),
-		ChannelDriver:     mockChannel.NewMockDriver(controller),
-		RepositoryMessage: mockRepository.NewMockMessage(controller),
-		RepositoryUser:    mockRepository.NewMockUser(controller),
-		QuitCh:            make(chan struct{}),
+		Logger:                mockLogger.NewMockLogger(controller),
+		ChannelDriver:         mockChannel.NewMockDriver(controller),
+		TelegramBot:           mockTelegram.NewMockBot(controller),
+		RepositoryMessage:     mockRepository.NewMockMessage(controller),
+		RepositoryUser:        mockRepository.NewMockUser(controller),
+		RepositoryLinkToken:   mockRepository.NewMockLinkToken(controller),
+		RepositoryFile:        mockRepository.NewMockFile(controller),
+		RepositorySuppression: mockRepository.NewMockSuppression(controller),
+		RepositoryPreference:  mockRepository.NewMockPreference(controller),
+		RepositoryTemplate:    mockRepository.NewMockTemplate(controller),
+		Webhook:               mockWebhook.NewMockSender(controller),
+		QuitCh:                make(chan struct{}),
 	}
 }
 
 func (m *MockedInstances) ExpectLoggerWithServices() {
+	m.Logger.EXPECT().With("service", "preference").Return(m.Logger)
+	m.Logger.EXPECT().With("service", "template").Return(m.Logger)
 	m.Logger.EXPECT().With("service", "message").Return(m.Logger)
 	m.Logger.EXPECT().With("service", "messageChecker").Return(m.Logger)
+	m.Logger.EXPECT().With("service", "telegram").Return(m.Logger)
+	m.Logger.EXPECT().With("service", "suppression").Return(m.Logger)
+	m.Logger.EXPECT().With("service", "mailbox").Return(m.Logger)
+}
+
+func (m *MockedInstances) FakeConfig() *config.Config {
+	return &config.Config{
+		NotificationChannels: config.NotificationChannels{
+			Telegram: config.Telegram{
+				BotName: "ns_test_bot",
+				Link:    config.TelegramLink{Secret: "secret", TTL: time.Minute},
+			},
+		},
+		Actions:     config.Actions{BaseURL: "https://ns.example.com", Secret: "secret"},
+		Unsubscribe: config.Unsubscribe{Secret: "secret", TTL: time.Hour},
+		Locales:     config.Locales{Default: "ru", Fallbacks: map[string]string{"kk": "ru", "ru": "en"}},
+	}
 }
 
 func (m *MockedInstances) WriteQuitChannel() {
@@ -59,6 +95,6 @@ func (m *MockedInstances) FakeMessage() *entity.Message {
 		MessageTemplate: messagetemplate.Receipt,
 		Timestamp:       time.Now().UnixMilli(),
 		ExternalID:      1234567890,
-		Params:          []byte(`{"orderId": 123, "commissionAmount": "1 KZT", "totalAmount": "1001 KZT"}`),
+		Params:          []byte(`{"orderId": 123, "commissionAmount": 1, "totalAmount": 1001}`),
 	}
 }